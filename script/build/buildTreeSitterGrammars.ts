@@ -0,0 +1,49 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+// Builds the vendored grammars, see `vendoredGrammars`, from their pinned packages, e.g., after bumping one of their versions.
+// Run with `npm run build:tree-sitter-grammars`; it needs emscripten or docker, which `npm install` doesn't.
+
+import { execFile } from 'child_process';
+import * as fs from 'fs';
+import * as os from 'os';
+import * as path from 'path';
+import { promisify } from 'util';
+import { WASMLanguage } from '../../src/platform/parser/node/treeSitterLanguages';
+import { grammarFilenameOf, vendoredGrammars, vendoredGrammarsDir } from '../../src/platform/parser/node/vendoredGrammars';
+
+const REPO_ROOT = path.join(__dirname, '..', '..');
+
+/** Version of `tree-sitter-cli` the grammars are built with, which must produce grammars `web-tree-sitter` can load */
+const TREE_SITTER_CLI_VERSION = '0.23.2';
+
+async function main() {
+	const npm = process.platform === 'win32' ? 'npm.cmd' : 'npm';
+	const npx = process.platform === 'win32' ? 'npx.cmd' : 'npx';
+	const outDir = path.join(REPO_ROOT, vendoredGrammarsDir);
+	await fs.promises.mkdir(outDir, { recursive: true });
+
+	// one at a time, since each build may start a docker container of its own
+	for (const [language, grammar] of Object.entries(vendoredGrammars)) {
+		const tmpDir = await fs.promises.mkdtemp(path.join(os.tmpdir(), 'tree-sitter-grammar-'));
+		try {
+			// installed with its dependencies, since some grammars extend others, e.g., objc extends c
+			await promisify(execFile)(npm, ['install', '--prefix', tmpDir, '--no-save', '--no-package-lock', '--ignore-scripts', `${grammar.module}@${grammar.version}`]);
+			const projectPath = path.join(tmpDir, 'node_modules', grammar.module, grammar.projectPath ?? '');
+			const output = path.join(outDir, grammarFilenameOf(language as WASMLanguage));
+			await promisify(execFile)(npx, ['--yes', `tree-sitter-cli@${TREE_SITTER_CLI_VERSION}`, 'build', '--wasm', '--output', output, projectPath]);
+			console.log(`Built ${path.relative(REPO_ROOT, output)} from ${grammar.module}@${grammar.version}`);
+		} catch (err) {
+			throw new Error(`Failed to build the grammar of ${language} from ${grammar.module}@${grammar.version}, building tree-sitter grammars needs emscripten or docker: ${err}`);
+		} finally {
+			await fs.promises.rm(tmpDir, { recursive: true, force: true });
+		}
+	}
+}
+
+main().catch(err => {
+	console.error(err);
+	process.exit(1);
+});
@@ -6,6 +6,8 @@
 import { downloadZMQ } from '@vscode/zeromq';
 import * as fs from 'fs';
 import * as path from 'path';
+import { WASMLanguage } from '../src/platform/parser/node/treeSitterLanguages';
+import { grammarFilenameOf, vendoredGrammars, vendoredGrammarsDir } from '../src/platform/parser/node/vendoredGrammars';
 import { compressTikToken } from './build/compressTikToken';
 import { copyStaticAssets } from './build/copyStaticAssets';
 
@@ -54,7 +56,7 @@ const treeSitterGrammars: ITreeSitterGrammar[] = [
 	},
 	{
 		name: 'tree-sitter-rust',
-	}
+	},
 ];
 
 const REPO_ROOT = path.join(__dirname, '..');
@@ -68,9 +70,17 @@ async function main() {
 		await compressTikToken(tokens, `dist/${path.basename(tokens)}`);
 	}
 
+	const vendoredGrammarPaths = Object.keys(vendoredGrammars).map(language => `${vendoredGrammarsDir}/${grammarFilenameOf(language as WASMLanguage)}`);
+	const missingGrammarPaths = vendoredGrammarPaths.filter(grammarPath => !fs.existsSync(path.join(REPO_ROOT, grammarPath)));
+	if (missingGrammarPaths.length > 0) {
+		throw new Error(`Vendored tree-sitter grammars are missing: ${missingGrammarPaths.join(', ')}. Build them with \`npm run build:tree-sitter-grammars\`, which needs emscripten or docker.`);
+	}
+
 	// copy static assets to dist
 	await copyStaticAssets([
 		...treeSitterGrammars.map(grammar => `node_modules/@vscode/tree-sitter-wasm/wasm/${grammar.name}.wasm`),
+		// grammars `@vscode/tree-sitter-wasm` doesn't ship, see `script/build/buildTreeSitterGrammars.ts`
+		...vendoredGrammarPaths,
 		'node_modules/@vscode/tree-sitter-wasm/wasm/tree-sitter.wasm',
 	], 'dist');
 
@@ -0,0 +1,47 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { ConfigKey, IConfigurationService } from '../../../platform/configuration/common/configurationService';
+import { ILogService } from '../../../platform/log/common/logService';
+import { IParserService } from '../../../platform/parser/node/parserService';
+import { getWasmLanguage } from '../../../platform/parser/node/treeSitterLanguages';
+import { Event } from '../../../util/vs/base/common/event';
+import { Disposable, DisposableStore } from '../../../util/vs/base/common/lifecycle';
+
+/**
+ * Registers the extension-to-language overrides of the `chat.parser.extensionLanguages` setting with the parser service.
+ */
+export class ParserExtensionLanguagesContribution extends Disposable {
+
+	private readonly _registrations = this._register(new DisposableStore());
+
+	constructor(
+		@IConfigurationService private readonly _configurationService: IConfigurationService,
+		@IParserService private readonly _parserService: IParserService,
+		@ILogService private readonly _logService: ILogService,
+	) {
+		super();
+
+		this._register(Event.runAndSubscribe(this._configurationService.onDidChangeConfiguration, e => {
+			if (!e || e.affectsConfiguration(ConfigKey.ParserExtensionLanguages.fullyQualifiedId)) {
+				this._registerExtensionLanguages();
+			}
+		}));
+	}
+
+	private _registerExtensionLanguages(): void {
+		this._registrations.clear();
+
+		const extensionLanguages = this._configurationService.getConfig(ConfigKey.ParserExtensionLanguages);
+		for (const [extension, languageId] of Object.entries(extensionLanguages)) {
+			const language = typeof languageId === 'string' ? getWasmLanguage(languageId) : undefined;
+			if (language === undefined) {
+				this._logService.warn(`[ParserExtensionLanguages] Ignoring ${extension}: ${languageId} isn't a language the parser supports`);
+				continue;
+			}
+			this._registrations.add(this._parserService.registerExtensionLanguage(extension, language));
+		}
+	}
+}
@@ -350,7 +350,7 @@ export class SemanticSearchTextSearchProvider implements vscode.AITextSearchProv
 			report: async (result: vscode.TextSearchMatch) => {
 				const docContainingRef = await this.workspaceService.openTextDocumentAndSnapshot(result.uri);
 				const resultAST = this._parserService.getTreeSitterAST(
-					{ languageId: docContainingRef.languageId, getText: () => docContainingRef.getText() });
+					{ languageId: docContainingRef.languageId, uri: docContainingRef.uri, getText: () => docContainingRef.getText() });
 				const symbolsToHighlight = await resultAST?.getSymbols({
 					startIndex: docContainingRef.offsetAt(result.ranges instanceof Array ? result.ranges[0].start : result.ranges.start),
 					endIndex: docContainingRef.offsetAt(result.ranges instanceof Array ? result.ranges[0].end : result.ranges.end),
@@ -399,7 +399,7 @@ export class SemanticSearchTextSearchProvider implements vscode.AITextSearchProv
 		for (const chunk of combinedChunks.slice(rankingResults.length)) {
 			const docContainingRef = await this.workspaceService.openTextDocumentAndSnapshot(chunk.file);
 			const resultAST = this._parserService.getTreeSitterAST(
-				{ languageId: docContainingRef.languageId, getText: () => docContainingRef.getText() });
+				{ languageId: docContainingRef.languageId, uri: docContainingRef.uri, getText: () => docContainingRef.getText() });
 			const symbolsToHighlight = await resultAST?.getSymbols({
 				startIndex: docContainingRef.offsetAt(new Position(chunk.range.startLineNumber, chunk.range.startColumn)),
 				endIndex: docContainingRef.offsetAt(new Position(chunk.range.endLineNumber, chunk.range.endColumn)),
@@ -428,7 +428,7 @@ export class SemanticSearchTextSearchProvider implements vscode.AITextSearchProv
 		for (const chunk of chunks) {
 			const docContainingRef = await this.workspaceService.openTextDocumentAndSnapshot(chunk.file);
 			const resultAST = this._parserService.getTreeSitterAST(
-				{ languageId: docContainingRef.languageId, getText: () => docContainingRef.getText() });
+				{ languageId: docContainingRef.languageId, uri: docContainingRef.uri, getText: () => docContainingRef.getText() });
 			const symbolsToHighlight = await resultAST?.getSymbols({
 				startIndex: docContainingRef.offsetAt(new Position(chunk.range.startLineNumber, chunk.range.startColumn)),
 				endIndex: docContainingRef.offsetAt(new Position(chunk.range.endLineNumber, chunk.range.endColumn)),
@@ -0,0 +1,131 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo } from './util';
+
+export const cStructure: StructureLanguage = {
+	describe: describeCNode,
+};
+
+export function describeCNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	// c++: a template's declaration keeps its `template <...>` prefix in its detail, e.g., `template <typename T>\nT max(T a, T b)`
+	const template = syntaxNode.parent?.type === 'template_declaration' ? syntaxNode.parent : undefined;
+	const start = template?.startIndex ?? syntaxNode.startIndex;
+	if (template) {
+		overlayNode.typeParameters = template.childForFieldName('parameters')?.text;
+	}
+	switch (syntaxNode.type) {
+		case 'function_definition': {
+			const declarator = cFunctionDeclaratorOf(syntaxNode);
+			describeCFunctionName(declarator?.childForFieldName('declarator') ?? null, overlayNode, source);
+			// up to the end of the declarator, i.e., without the member initializers of a constructor, e.g., `: radius_(r)`
+			overlayNode.detail = declarator ? source.substring(start, declarator.endIndex) : textUpTo(syntaxNode, syntaxNode.childForFieldName('body'), source);
+			break;
+		}
+		case 'declaration': {
+			// only a function declared without its body, e.g., the prototype `int add(int a, int b);`, declares a symbol
+			if (!isCFunctionDeclaration(syntaxNode)) {
+				break;
+			}
+			const declarator = cFunctionDeclaratorOf(syntaxNode)!;
+			describeCFunctionName(declarator.childForFieldName('declarator'), overlayNode, source);
+			overlayNode.detail = source.substring(start, declarator.endIndex);
+			overlayNode.isForwardDeclaration = true;
+			break;
+		}
+		case 'enumerator': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.value = syntaxNode.childForFieldName('value')?.text;
+			break;
+		}
+		case 'class_specifier':
+		case 'struct_specifier':
+		case 'union_specifier':
+		case 'enum_specifier':
+		case 'namespace_definition': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			const body = syntaxNode.childForFieldName('body');
+			overlayNode.detail = source.substring(start, body ? body.startIndex : syntaxNode.endIndex).trimEnd();
+			if (body === null && syntaxNode.nextSibling?.type === ';') {
+				// e.g., `struct node;` or `class Shape;`, but not `struct node` in `struct node *next;`
+				overlayNode.isForwardDeclaration = true;
+			}
+			break;
+		}
+		case 'type_definition': {
+			overlayNode.name = cDeclaredNameOf(syntaxNode.childForFieldName('declarator'));
+			// without the body of the named type, e.g., `typedef struct point point_t` for `typedef struct point { ... } point_t;`
+			const body = syntaxNode.childForFieldName('type')?.childForFieldName('body');
+			const text = body ? source.substring(syntaxNode.startIndex, body.startIndex) + source.substring(body.endIndex, syntaxNode.endIndex) : syntaxNode.text;
+			overlayNode.detail = text.replace(/;$/, '');
+			break;
+		}
+		case 'preproc_def': {
+			// an object-like macro, e.g., `#define BUFFER_SIZE 64`
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.detail = syntaxNode.text.trimEnd();
+			break;
+		}
+		case 'preproc_function_def': {
+			// a function-like macro, e.g., `#define MIN(a, b)` for `#define MIN(a, b) ((a) < (b) ? (a) : (b))`
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			const parameters = syntaxNode.childForFieldName('parameters');
+			overlayNode.detail = parameters ? source.substring(syntaxNode.startIndex, parameters.endIndex) : syntaxNode.text.trimEnd();
+			break;
+		}
+	}
+}
+
+/**
+ * @returns the `function_declarator` of a function definition within the declarators of its result, e.g., of `char *make_buffer(size_t n)`
+ */
+/**
+ * Sets the name of a function and, for a qualified name, e.g., `geo::Shape::area`, its qualifier, i.e., `geo::Shape`.
+ */
+function describeCFunctionName(name: SyntaxNode | null, overlayNode: OverlayNode, source: string): void {
+	if (name?.type === 'qualified_identifier') {
+		// the innermost name is the function's
+		let innermost = name;
+		while (innermost.childForFieldName('name')?.type === 'qualified_identifier') {
+			innermost = innermost.childForFieldName('name')!;
+		}
+		overlayNode.name = innermost.childForFieldName('name')?.text;
+		const scope = innermost.childForFieldName('scope');
+		overlayNode.qualifier = scope ? source.substring(name.startIndex, scope.endIndex) : undefined;
+	} else {
+		overlayNode.name = name?.text;
+	}
+}
+
+/**
+ * @returns whether a `declaration` declares a function without defining it, e.g., `int add(int a, int b);`, rather than a function pointer
+ */
+export function isCFunctionDeclaration(declaration: SyntaxNode): boolean {
+	const name = cFunctionDeclaratorOf(declaration)?.childForFieldName('declarator');
+	return name !== null && name !== undefined && /identifier$|^(destructor_name|operator_name|template_function)$/.test(name.type);
+}
+
+function cFunctionDeclaratorOf(functionDefinition: SyntaxNode): SyntaxNode | undefined {
+	let declarator = functionDefinition.childForFieldName('declarator');
+	while (declarator && declarator.type !== 'function_declarator') {
+		// a `reference_declarator`, e.g., `&operator[](size_t i)`, has no `declarator` field
+		declarator = declarator.childForFieldName('declarator') ?? declarator.namedChildren.find(c => c.type.endsWith('declarator')) ?? null;
+	}
+	return declarator ?? undefined;
+}
+
+/**
+ * @returns the name declared by a declarator, e.g., `cmp_fn` for `(*cmp_fn)(const void *, const void *)`
+ */
+function cDeclaredNameOf(declarator: SyntaxNode | null): string | undefined {
+	while (declarator && declarator.type.endsWith('declarator')) {
+		// neither a `parenthesized_declarator` nor a `reference_declarator` has a `declarator` field
+		declarator = declarator.childForFieldName('declarator') ?? declarator.namedChildren.find(c => c.type !== 'type_qualifier') ?? null;
+	}
+	return declarator?.text;
+}
@@ -0,0 +1,138 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { EnclosingDeclaration, toEnclosingDeclaration } from './nodeContextParsing';
+import { TreeSitterOffsetRange } from './nodes';
+import { _parse } from './parserWithCaching';
+import { runQueries } from './querying';
+import { WASMLanguage } from './treeSitterLanguages';
+import { callReferenceQueries, enclosingDeclarationTypes } from './treeSitterQueries';
+
+export interface CallReference extends TreeSitterOffsetRange {
+	/**
+	 * Name of the called function or method
+	 * @example `Println` in `fmt.Println(err)`
+	 */
+	callee: string;
+	/**
+	 * Text of the expression the method is accessed on; `undefined` for calls of plain functions
+	 * @example `fmt` in `fmt.Println(err)`, `a.b()` in `a.b().c()`
+	 */
+	receiver?: string;
+	argumentCount: number;
+}
+
+export interface CallSite extends TreeSitterOffsetRange {
+	/**
+	 * Callee as written at the call site, i.e., qualified with its receiver if any
+	 * @example `fmt.Println`, `i.MethodExample`
+	 */
+	callee: string;
+}
+
+export interface CallGraphEntry {
+	caller: EnclosingDeclaration;
+	/**
+	 * Calls in the body of the caller, including those in anonymous functions within it, ordered like {@link _getCallReferences}
+	 */
+	calls: CallSite[];
+}
+
+/**
+ * @returns calls within `range` ordered by the positions of their callees, i.e., in a chain like `a.b().c()`,
+ * the call of `b` comes before the call of `c`, which encloses it; empty if the language isn't supported
+ */
+export async function _getCallReferences(language: WASMLanguage, source: string, range: TreeSitterOffsetRange): Promise<CallReference[]> {
+	const queries = callReferenceQueries[language];
+	if (!queries) {
+		return [];
+	}
+
+	const treeRef = await _parse(language, source);
+
+	try {
+		return matchCalls(queries, treeRef.tree.rootNode)
+			.filter(c => TreeSitterOffsetRange.doesContain(range, c.call))
+			.map(c => c.call);
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+/**
+ * @returns named functions and methods in document order with the calls in their bodies; functions without calls are included with no calls.
+ * Calls outside of named functions, e.g., in top-level statements, aren't part of the call graph.
+ */
+export async function _getCallGraph(language: WASMLanguage, source: string): Promise<CallGraphEntry[]> {
+	const queries = callReferenceQueries[language];
+	if (!queries) {
+		return [];
+	}
+
+	const treeRef = await _parse(language, source);
+
+	try {
+		const functionTypes = enclosingDeclarationTypes[language].function;
+		const entries = new Map<number /* node id */, CallGraphEntry>();
+
+		for (const functionNode of treeRef.tree.rootNode.descendantsOfType(functionTypes)) {
+			const caller = toEnclosingDeclaration(language, functionNode);
+			if (caller) {
+				entries.set(functionNode.id, { caller, calls: [] });
+			}
+		}
+
+		for (const { node, call, calleeEndIndex } of matchCalls(queries, treeRef.tree.rootNode)) {
+			let entry: CallGraphEntry | undefined;
+			for (let ancestor = node.parent; ancestor !== null && !entry; ancestor = ancestor.parent) {
+				entry = entries.get(ancestor.id);
+			}
+			entry?.calls.push({ callee: source.substring(call.startIndex, calleeEndIndex), startIndex: call.startIndex, endIndex: call.endIndex });
+		}
+
+		return [...entries.values()];
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+interface MatchedCall {
+	node: SyntaxNode;
+	call: CallReference;
+	calleeStartIndex: number;
+	calleeEndIndex: number;
+}
+
+/**
+ * @returns calls matched by `queries` ordered by the positions of their callees
+ */
+function matchCalls(queries: string[], rootNode: SyntaxNode): MatchedCall[] {
+	const calls: MatchedCall[] = [];
+
+	for (const { captures } of runQueries(queries, rootNode)) {
+		const callNode = captures.find(c => c.name === 'call')?.node;
+		const calleeNode = captures.find(c => c.name === 'callee')?.node;
+		const argumentsNode = captures.find(c => c.name === 'arguments')?.node;
+		if (!callNode || !calleeNode || !argumentsNode) {
+			continue;
+		}
+		const receiver = captures.find(c => c.name === 'receiver')?.node.text;
+		calls.push({
+			node: callNode,
+			call: {
+				startIndex: callNode.startIndex,
+				endIndex: callNode.endIndex,
+				callee: calleeNode.text,
+				...(receiver !== undefined ? { receiver } : {}),
+				argumentCount: argumentsNode.namedChildren.filter(c => c.type !== 'comment').length,
+			},
+			calleeStartIndex: calleeNode.startIndex,
+			calleeEndIndex: calleeNode.endIndex,
+		});
+	}
+
+	return calls.sort((a, b) => a.calleeStartIndex - b.calleeStartIndex);
+}
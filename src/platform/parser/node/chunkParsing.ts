@@ -0,0 +1,146 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { isLowSurrogate } from '../../../util/vs/base/common/strings';
+import { OverlayNode, TreeSitterOffsetRange } from './nodes';
+import { structureComputer } from './structure';
+import { WASMLanguage } from './treeSitterLanguages';
+import { chunkContainerKinds } from './treeSitterQueries';
+
+export interface SemanticChunk extends TreeSitterOffsetRange {
+	/**
+	 * Names of the declarations the chunk is part of, outermost first; Go methods are qualified with their receiver type.
+	 * Empty for chunks of top-level code or of multiple declarations.
+	 * @example `['StructExample', 'MethodExample']`
+	 */
+	symbolPath: string[];
+}
+
+/**
+ * Splits the source into chunks of at most `maxLength` UTF-16 code units, e.g., to embed them for retrieval.
+ *
+ * Chunks end at declaration boundaries: adjacent declarations are packed into a chunk while they fit, and a declaration
+ * that doesn't fit is split at the boundaries of its members or statements if it's a container, see {@link chunkContainerKinds}.
+ * What still doesn't fit is split at line breaks.
+ *
+ * @param containerKinds kinds of structure nodes that are split at their children instead of those of {@link chunkContainerKinds}, e.g., to tune the chunks of a language
+ * @returns contiguous chunks covering the whole source in order
+ */
+export async function _getSemanticChunks(language: WASMLanguage, source: string, maxLength: number, containerKinds?: readonly string[]): Promise<SemanticChunk[]> {
+	if (maxLength < 1) {
+		throw new Error(`maxLength must be at least 1, got ${maxLength}`);
+	}
+	const structure = await structureComputer.getStructure(language, source);
+	if (!structure) {
+		return splitAtLineBreaks(source, { startIndex: 0, endIndex: source.length }, [], maxLength);
+	}
+	return chunk(source, structure, [], maxLength, new Set(containerKinds ?? chunkContainerKinds[language]));
+}
+
+interface Piece extends TreeSitterOffsetRange {
+	/** `undefined` for text between children */
+	node?: OverlayNode;
+}
+
+function chunk(source: string, node: OverlayNode, symbolPath: string[], maxLength: number, containerKinds: ReadonlySet<string>): SemanticChunk[] {
+	if (node.endIndex - node.startIndex <= maxLength) {
+		return [{ startIndex: node.startIndex, endIndex: node.endIndex, symbolPath }];
+	}
+	if (node.children.length === 0 || (node.kind !== 'root' && !containerKinds.has(node.kind))) {
+		return splitAtLineBreaks(source, node, symbolPath, maxLength);
+	}
+
+	const pieces: Piece[] = [];
+	let offset = node.startIndex;
+	for (const child of node.children) {
+		if (offset < child.startIndex) {
+			pieces.push({ startIndex: offset, endIndex: child.startIndex });
+		}
+		pieces.push({ startIndex: child.startIndex, endIndex: child.endIndex, node: child });
+		offset = child.endIndex;
+	}
+	if (offset < node.endIndex) {
+		pieces.push({ startIndex: offset, endIndex: node.endIndex });
+	}
+
+	const chunks: SemanticChunk[] = [];
+	let packed: Piece[] = [];
+	const flush = () => {
+		if (packed.length > 0) {
+			// a chunk is labeled with the declaration it holds unless it holds several
+			const declarations = packed.filter(p => p.node && isDeclaration(p.node));
+			chunks.push({
+				startIndex: packed[0].startIndex,
+				endIndex: packed[packed.length - 1].endIndex,
+				symbolPath: declarations.length === 1 ? symbolPathOf(declarations[0].node!, symbolPath) : symbolPath,
+			});
+			packed = [];
+		}
+	};
+
+	for (const piece of pieces) {
+		if (piece.endIndex - piece.startIndex > maxLength) {
+			flush();
+			chunks.push(...(piece.node
+				? chunk(source, piece.node, isDeclaration(piece.node) ? symbolPathOf(piece.node, symbolPath) : symbolPath, maxLength, containerKinds)
+				: splitAtLineBreaks(source, piece, symbolPath, maxLength)));
+			continue;
+		}
+		if (packed.length > 0 && piece.endIndex - packed[0].startIndex > maxLength) {
+			flush();
+		}
+		// whitespace after a split declaration, e.g., the trailing line break of a file, sticks to its last chunk
+		const lastChunk = chunks.at(-1);
+		if (packed.length === 0 && !piece.node && lastChunk && lastChunk.endIndex === piece.startIndex
+			&& piece.endIndex - lastChunk.startIndex <= maxLength && source.substring(piece.startIndex, piece.endIndex).trim() === '') {
+			lastChunk.endIndex = piece.endIndex;
+			continue;
+		}
+		packed.push(piece);
+	}
+	flush();
+
+	return chunks;
+}
+
+function isDeclaration(node: OverlayNode): boolean {
+	return node.symbolKind !== undefined && node.name !== undefined;
+}
+
+function symbolPathOf(declaration: OverlayNode, enclosingPath: string[]): string[] {
+	// go: methods are declared outside of their receiver type, e.g., `func (s StructExample) MethodExample()`
+	const receiverType = declaration.receiver?.typeName;
+	const path = receiverType !== undefined && enclosingPath.at(-1) !== receiverType ? [...enclosingPath, receiverType] : enclosingPath;
+	return [...path, declaration.name!];
+}
+
+/**
+ * Packs whole lines into chunks while they fit; a single line that doesn't fit is cut at `maxLength`.
+ */
+function splitAtLineBreaks(source: string, range: TreeSitterOffsetRange, symbolPath: string[], maxLength: number): SemanticChunk[] {
+	const chunks: SemanticChunk[] = [];
+	let chunkStart = range.startIndex;
+	while (chunkStart < range.endIndex) {
+		let chunkEnd = chunkStart;
+		while (chunkEnd < range.endIndex) {
+			const lineBreak = source.indexOf('\n', chunkEnd);
+			const lineEnd = lineBreak === -1 || lineBreak >= range.endIndex ? range.endIndex : lineBreak + 1;
+			if (lineEnd - chunkStart > maxLength) {
+				break;
+			}
+			chunkEnd = lineEnd;
+		}
+		if (chunkEnd === chunkStart) {
+			chunkEnd = chunkStart + maxLength;
+			// don't split a surrogate pair, e.g., an emoji
+			if (chunkEnd - 1 > chunkStart && isLowSurrogate(source.charCodeAt(chunkEnd))) {
+				chunkEnd--;
+			}
+		}
+		chunks.push({ startIndex: chunkStart, endIndex: chunkEnd, symbolPath });
+		chunkStart = chunkEnd;
+	}
+	return chunks;
+}
@@ -0,0 +1,83 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { OverlayNode, TreeSitterOffsetRange } from './nodes';
+import { _parse } from './parserWithCaching';
+import { WASMLanguage } from './treeSitterLanguages';
+
+export type CommentMarker = 'TODO' | 'FIXME' | 'XXX' | 'HACK' | 'NOTE';
+
+export interface CommentNode extends TreeSitterOffsetRange {
+	/**
+	 * Raw text of the comment including its delimiters
+	 * @example `// TODO: handle the empty list`
+	 */
+	text: string;
+	/**
+	 * Whether the comment is a block comment, i.e., it starts with `/*` or Ruby's `=begin`, rather than a line comment, e.g., `// ...` or `# ...`
+	 */
+	isBlock: boolean;
+	/**
+	 * Marker the comment starts with, if any
+	 * @example `FIXME` for `// FIXME(joe): escape the separator`
+	 */
+	marker?: CommentMarker;
+}
+
+/**
+ * Types of comment nodes across the grammars, e.g., Rust's `line_comment` and `block_comment` or Kotlin's `multiline_comment`
+ */
+export const commentNodeTypes = ['comment', 'line_comment', 'block_comment', 'multiline_comment', 'documentation_comment'];
+
+/**
+ * @returns comments of the given source in document order; comment-like text within string literals, e.g., `'// not a comment'`, isn't a comment
+ */
+export async function _getComments(language: WASMLanguage, source: string): Promise<CommentNode[]> {
+	const treeRef = await _parse(language, source);
+
+	try {
+		return treeRef.tree.rootNode.descendantsOfType(commentNodeTypes).map(node => {
+			const text = node.text;
+			const comment: CommentNode = { startIndex: node.startIndex, endIndex: node.endIndex, text, isBlock: isBlockComment(text) };
+			const marker = markerOf(text);
+			if (marker) {
+				comment.marker = marker;
+			}
+			return comment;
+		});
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+function isBlockComment(text: string): boolean {
+	return text.startsWith('/*') || text.startsWith('=begin');
+}
+
+/**
+ * @returns marker at the start of the comment's content, e.g., `TODO` for `// TODO: ...` or for a doc comment whose first line is `* TODO ...`;
+ * markers are only recognized in upper case, so that, e.g., `// note that ...` isn't a note
+ */
+function markerOf(text: string): CommentMarker | undefined {
+	return markerMatchOf(text)?.[1] as CommentMarker | undefined;
+}
+
+/**
+ * @returns the marker the comment starts with, see {@link markerOf}, with the rest of its line after an optional name and separator,
+ * e.g., `{ marker: 'FIXME', text: 'wrap the error' }` for `// FIXME(ana): wrap the error`
+ */
+export function todoAnnotationOf(text: string): OverlayNode['annotation'] {
+	const match = markerMatchOf(text);
+	if (!match) {
+		return undefined;
+	}
+	// e.g., the end of a block comment, i.e., `*/`, on the same line
+	return { marker: match[1] as CommentMarker, text: match[2].replace(/\*+\/\s*$/, '').trim() };
+}
+
+function markerMatchOf(text: string): RegExpExecArray | null {
+	const content = text.replace(/^(\/\/+|\/\*+|#+|--+|;+|=begin)[!\s*]*/, '');
+	return /^(TODO|FIXME|XXX|HACK|NOTE)\b(?:\([^)\n]*\))?[:\- \t]*(.*)/.exec(content);
+}
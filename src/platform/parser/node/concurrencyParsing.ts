@@ -0,0 +1,104 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { TreeSitterOffsetRange } from './nodes';
+import { _parse } from './parserWithCaching';
+import { WASMLanguage } from './treeSitterLanguages';
+
+export interface ConcurrencyConstruct extends TreeSitterOffsetRange {
+	/**
+	 * @example `go worker(jobs, results)` or `make(chan int, 8)`
+	 */
+	text: string;
+}
+
+export interface ChannelOperation extends ConcurrencyConstruct {
+	/**
+	 * `send` for `ch <- v`, `receive` for `<-ch`, and `close` for `close(ch)`
+	 */
+	kind: 'send' | 'receive' | 'close';
+	/**
+	 * Text of the channel operated on, e.g., `ch` or `s.results`
+	 */
+	channel: string;
+}
+
+export interface ConcurrencyInfo {
+	goStatements: ConcurrencyConstruct[];
+	/**
+	 * Calls that create channels, e.g., `make(chan int)`, but not `make([]int, 3)`
+	 */
+	channelMakes: ConcurrencyConstruct[];
+	channelOps: ChannelOperation[];
+	selects: ConcurrencyConstruct[];
+}
+
+/**
+ * Get the goroutines, channels, and `select` blocks of the Go source, e.g., to point out concurrency when reviewing a change;
+ * the whole tree is walked, so that constructs within parse errors are found too, e.g., a `go func() { ... }()` at the top level of a file.
+ *
+ * @returns constructs of each category in document order
+ */
+export async function _getConcurrencyInfo(source: string): Promise<ConcurrencyInfo> {
+	const treeRef = await _parse(WASMLanguage.Go, source);
+
+	try {
+		const info: ConcurrencyInfo = { goStatements: [], channelMakes: [], channelOps: [], selects: [] };
+		const visit = (node: SyntaxNode) => {
+			switch (node.type) {
+				case 'go_statement':
+					info.goStatements.push(constructOf(node.startIndex, node.endIndex, source));
+					break;
+				case 'go': {
+					// within a parse error, the keyword is a sibling of the call it starts rather than part of a `go_statement`
+					const call = node.parent?.type === 'ERROR' ? node.nextNamedSibling : null;
+					if (call?.type === 'call_expression') {
+						info.goStatements.push(constructOf(node.startIndex, call.endIndex, source));
+					}
+					break;
+				}
+				case 'select_statement':
+					info.selects.push(constructOf(node.startIndex, node.endIndex, source));
+					break;
+				case 'send_statement': {
+					const channel = node.childForFieldName('channel');
+					if (channel) {
+						info.channelOps.push({ ...constructOf(node.startIndex, node.endIndex, source), kind: 'send', channel: channel.text });
+					}
+					break;
+				}
+				case 'unary_expression': {
+					const operand = node.childForFieldName('operand');
+					if (node.childForFieldName('operator')?.type === '<-' && operand) {
+						info.channelOps.push({ ...constructOf(node.startIndex, node.endIndex, source), kind: 'receive', channel: operand.text });
+					}
+					break;
+				}
+				case 'call_expression': {
+					const name = node.childForFieldName('function');
+					const first = node.childForFieldName('arguments')?.firstNamedChild;
+					if (name?.type === 'identifier' && name.text === 'make' && first?.type === 'channel_type') {
+						info.channelMakes.push(constructOf(node.startIndex, node.endIndex, source));
+					} else if (name?.type === 'identifier' && name.text === 'close' && first) {
+						info.channelOps.push({ ...constructOf(node.startIndex, node.endIndex, source), kind: 'close', channel: first.text });
+					}
+					break;
+				}
+			}
+			for (const child of node.children) {
+				visit(child);
+			}
+		};
+		visit(treeRef.tree.rootNode);
+		return info;
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+function constructOf(startIndex: number, endIndex: number, source: string): ConcurrencyConstruct {
+	return { startIndex, endIndex, text: source.substring(startIndex, endIndex) };
+}
@@ -0,0 +1,50 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { cStructure } from './cStructure';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { SymbolKind } from './symbolKinds';
+
+export const cppStructure: StructureLanguage = {
+	...cStructure,
+	createLinker: () => {
+		// class, struct, and union declarations by the names of the types they declare
+		const typeDeclarations = new Map<string, OverlayNode>();
+		return {
+			register: (node, syntaxNode) => {
+				// not forward declarations, e.g., `class Shape;`
+				if (cppTypeKinds.has(node.kind) && node.name !== undefined && syntaxNode.childForFieldName('body') !== null) {
+					typeDeclarations.set(node.name, node);
+				}
+			},
+			link: root => {
+				if (typeDeclarations.size > 0) {
+					linkCppMethods(root, typeDeclarations);
+				}
+			},
+		};
+	},
+};
+
+const cppTypeKinds = new Set(['class_specifier', 'struct_specifier', 'union_specifier']);
+
+/**
+ * Makes the functions defined outside of their classes, e.g., `double Shape::area() const { ... }`, {@link OverlayNode.methods} of their classes
+ * if those are declared in the same source.
+ */
+function linkCppMethods(node: OverlayNode, typeDeclarations: Map<string, OverlayNode>) {
+	for (const child of node.children) {
+		// the class of `geo::Shape::area` or `Stack<T>::push` is `Shape` or `Stack`, respectively
+		const className = child.kind === 'function_definition' ? child.qualifier?.replace(/<.*>$/, '').split('::').pop() : undefined;
+		const typeDeclaration = className !== undefined ? typeDeclarations.get(className) : undefined;
+		if (typeDeclaration) {
+			(typeDeclaration.methods ??= []).push(child);
+			child.symbolKind = SymbolKind.Method;
+		} else {
+			linkCppMethods(child, typeDeclarations);
+		}
+	}
+}
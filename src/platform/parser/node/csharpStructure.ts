@@ -0,0 +1,148 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo } from './util';
+
+export const csharpStructure: StructureLanguage = {
+	describe: describeCsharpNode,
+	// a file-scoped namespace, i.e., `namespace Foo;`, encloses the rest of the file, which not all grammar versions reflect
+	endIndexOf: (_syntaxNode, nodeKind, source) => nodeKind === 'file_scoped_namespace_declaration' ? source.length : undefined,
+	createLinker: () => {
+		// `partial` type declarations by whether they declare their accessibility
+		const partialDeclarations = new Map<OverlayNode, boolean>();
+		return {
+			register: (node, syntaxNode) => {
+				if (csharpTypeDeclarations.has(node.kind) && syntaxNode.children.some(c => c.type === 'modifier' && c.text === 'partial')) {
+					partialDeclarations.set(node, syntaxNode.children.some(c => c.type === 'modifier' && csharpAccessModifiers.has(c.text)));
+				}
+			},
+			link: root => {
+				if (partialDeclarations.size > 0) {
+					mergePartialDeclarations(root, partialDeclarations);
+				}
+			},
+		};
+	},
+};
+
+function describeCsharpNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	const visibility = csharpVisibilityOf(syntaxNode);
+	if (visibility) {
+		overlayNode.visibility = visibility;
+	}
+	const attributeLists = syntaxNode.namedChildren.filter(c => c.type === 'attribute_list');
+	if (attributeLists.length > 0) {
+		// e.g., `['[Serializable]', '[Obsolete("use Order")]']`
+		overlayNode.decorators = attributeLists.map(a => a.text);
+	}
+	switch (syntaxNode.type) {
+		case 'namespace_declaration':
+		case 'file_scoped_namespace_declaration':
+		case 'class_declaration':
+		case 'interface_declaration':
+		case 'struct_declaration':
+		case 'record_declaration':
+		case 'enum_declaration':
+		case 'method_declaration':
+		case 'constructor_declaration': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			// e.g., `public partial class Order : IOrder`; a file-scoped namespace has no body
+			const body = syntaxNode.childForFieldName('body') ?? syntaxNode.children.find(c => c.type === ';') ?? null;
+			overlayNode.detail = textUpTo(syntaxNode, body, source);
+			break;
+		}
+		case 'enum_member_declaration': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.value = syntaxNode.childForFieldName('value')?.text;
+			break;
+		}
+		case 'property_declaration': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.type = syntaxNode.childForFieldName('type')?.text;
+			// `undefined` for an expression-bodied property, e.g., `public int Count => items.Count;`
+			overlayNode.accessors = syntaxNode.childForFieldName('accessors')?.namedChildren
+				.filter(c => c.type === 'accessor_declaration')
+				.map(c => textUpTo(c, c.childForFieldName('body') ?? c.children.find(c => c.type === ';') ?? null, source));
+			break;
+		}
+	}
+}
+
+const csharpMemberTypes = new Set([
+	'class_declaration', 'interface_declaration', 'struct_declaration', 'record_declaration', 'enum_declaration', 'delegate_declaration',
+	'method_declaration', 'constructor_declaration', 'property_declaration', 'field_declaration', 'event_field_declaration', 'enum_member_declaration',
+]);
+
+/**
+ * @returns visibility of a type or member by its access modifiers, or by where it's declared if it has none; `undefined` for a namespace
+ */
+function csharpVisibilityOf(syntaxNode: SyntaxNode): OverlayNode['visibility'] {
+	if (!csharpMemberTypes.has(syntaxNode.type)) {
+		return undefined;
+	}
+	const modifiers = new Set(syntaxNode.children.filter(c => c.type === 'modifier').map(c => c.text));
+	for (const modifier of ['public', 'private', 'protected', 'internal'] as const) {
+		if (modifiers.has(modifier)) {
+			return modifier;
+		}
+	}
+	if (syntaxNode.type === 'enum_member_declaration') {
+		return 'public';
+	}
+	// the `declaration_list` of a type holds its members, and that of a namespace its top-level types
+	const container = syntaxNode.parent?.type === 'declaration_list' ? syntaxNode.parent.parent : null;
+	if (container === null || container.type === 'namespace_declaration') {
+		return 'internal';
+	}
+	return container.type === 'interface_declaration' ? 'public' : 'private';
+}
+
+const csharpTypeDeclarations = new Set(['class_declaration', 'struct_declaration', 'interface_declaration', 'record_declaration']);
+
+const csharpAccessModifiers = new Set(['public', 'private', 'protected', 'internal']);
+
+/**
+ * Merges the parts of a `partial` type declared next to each other, i.e., with at most comments in between, into a single node
+ * with the attributes, comments, and annotations of all parts and the accessibility of the part that declares it.
+ */
+function mergePartialDeclarations(node: OverlayNode, partialDeclarations: Map<OverlayNode, boolean>) {
+	const children: OverlayNode[] = [];
+	for (const child of node.children) {
+		mergePartialDeclarations(child, partialDeclarations);
+
+		let prevIdx = children.length - 1;
+		while (prevIdx >= 0 && children[prevIdx].kind === 'comment') {
+			prevIdx--;
+		}
+		const prev = children[prevIdx];
+		if (prev === undefined || !partialDeclarations.has(prev) || !partialDeclarations.has(child) || prev.kind !== child.kind || prev.name !== child.name) {
+			children.push(child);
+			continue;
+		}
+
+		// comments in between become children of the merged node
+		const merged = new OverlayNode(prev.startIndex, child.endIndex, prev.kind, [...prev.children, ...children.slice(prevIdx + 1), ...child.children]);
+		merged.name = prev.name;
+		merged.detail = prev.detail;
+		merged.leadingComment = prev.leadingComment !== undefined && child.leadingComment !== undefined ? `${prev.leadingComment}\n${child.leadingComment}` : prev.leadingComment ?? child.leadingComment;
+		merged.visibility = partialDeclarations.get(prev) || !partialDeclarations.get(child) ? prev.visibility : child.visibility;
+		if (prev.decorators || child.decorators) {
+			merged.decorators = [...prev.decorators ?? [], ...child.decorators ?? []];
+		}
+		if (prev.annotations || child.annotations) {
+			merged.annotations = [...prev.annotations ?? [], ...child.annotations ?? []];
+		}
+		merged.symbolKind = prev.symbolKind;
+		merged.syntaxNode = prev.syntaxNode;
+		merged.range = prev.range && child.range && { start: prev.range.start, end: child.range.end };
+		merged.byteRange = prev.byteRange && child.byteRange && { start: prev.byteRange.start, end: child.byteRange.end };
+		partialDeclarations.set(merged, partialDeclarations.get(prev)! || partialDeclarations.get(child)!);
+		children.splice(prevIdx, children.length - prevIdx, merged);
+	}
+	node.children.splice(0, node.children.length, ...children);
+}
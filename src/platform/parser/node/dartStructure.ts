@@ -0,0 +1,71 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { leadingCommentOf, textUpTo } from './util';
+
+export const dartStructure: StructureLanguage = {
+	describe: describeDartNode,
+	extentOf: capture => {
+		// members are wrapped in a `method_signature` or `declaration` with their modifiers, and annotations, e.g., `@override`, are preceding siblings
+		// of declarations; the body of a function or method is the next sibling of its signature, e.g., `void main()` followed by `{ ... }`
+		let first = capture.name === 'member' ? capture.node.parent! : capture.node;
+		let last = first;
+		while (first.previousSibling?.type.endsWith('annotation')) { // `annotation` or `marker_annotation`
+			first = first.previousSibling;
+		}
+		if (last.nextSibling?.type === 'function_body') {
+			last = last.nextSibling;
+		}
+		return [first, last];
+	},
+};
+
+function describeDartNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	// members are wrapped in a `method_signature` or `declaration` along with their modifiers, e.g., `static`
+	const declaration = syntaxNode.parent?.type === 'method_signature' || syntaxNode.parent?.type === 'declaration' ? syntaxNode.parent : syntaxNode;
+	switch (syntaxNode.type) {
+		case 'class_definition':
+		case 'mixin_declaration':
+		case 'extension_declaration':
+		case 'enum_declaration': {
+			// mixins have no name field; extensions may be unnamed, e.g., `extension on String { ... }`
+			overlayNode.name = (syntaxNode.childForFieldName('name') ?? (syntaxNode.type === 'mixin_declaration' ? syntaxNode.namedChildren.find(c => c.type === 'identifier') : undefined))?.text;
+			const body = syntaxNode.childForFieldName('body') ?? syntaxNode.namedChildren.find(c => c.type === 'class_body') ?? null;
+			overlayNode.detail = textUpTo(syntaxNode, body, source);
+			break;
+		}
+		case 'function_signature':
+		case 'getter_signature':
+		case 'setter_signature': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.detail = source.substring(declaration.startIndex, syntaxNode.endIndex);
+			break;
+		}
+		case 'constructor_signature':
+		case 'constant_constructor_signature':
+		case 'factory_constructor_signature':
+		case 'redirecting_factory_constructor_signature':
+		case 'operator_signature': {
+			// e.g., `Foo.named` for `Foo.named(this.x)` and `Foo.fromJson` for `factory Foo.fromJson(Map json)`
+			const parameters = syntaxNode.namedChildren.find(c => c.type === 'formal_parameter_list') ?? null;
+			overlayNode.name = textUpTo(syntaxNode, parameters, source).replace(/^(factory|const)\s+/, '');
+			overlayNode.detail = source.substring(declaration.startIndex, parameters ? parameters.endIndex : syntaxNode.endIndex);
+			break;
+		}
+		case 'enum_constant': {
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'identifier')?.text;
+			break;
+		}
+	}
+	// annotations, e.g., `@override`, are the preceding siblings of a declaration and precede its doc comment
+	let first = declaration;
+	while (first.previousNamedSibling?.type.endsWith('annotation')) {
+		first = first.previousNamedSibling;
+	}
+	overlayNode.leadingComment = leadingCommentOf(first, source);
+}
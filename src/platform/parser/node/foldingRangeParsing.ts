@@ -0,0 +1,71 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { _parse } from './parserWithCaching';
+import { runQueries } from './querying';
+import { WASMLanguage } from './treeSitterLanguages';
+import { foldingRangeQueries } from './treeSitterQueries';
+import { isFirstOnItsLine } from './util';
+
+export interface FoldingRange {
+	/**
+	 * Zero-based line the range starts at, i.e., the line that stays visible when the range is folded
+	 */
+	startLine: number;
+	/**
+	 * Zero-based, inclusive line the range ends at
+	 */
+	endLine: number;
+}
+
+/**
+ * @returns folding ranges for block-like nodes sorted by their start lines, at most one per start line;
+ * empty if the language isn't supported
+ */
+export async function _getFoldingRanges(language: WASMLanguage, source: string): Promise<FoldingRange[]> {
+	const queries = foldingRangeQueries[language];
+	if (!queries) {
+		return [];
+	}
+
+	const treeRef = await _parse(language, source);
+
+	try {
+		// multiple nodes may start on the same line, e.g., a function and its body; the largest one wins
+		const rangeByStartLine = new Map<number, FoldingRange>();
+
+		for (const { captures } of runQueries(queries, treeRef.tree.rootNode)) {
+			for (const { node } of captures) {
+				const range = toFoldingRange(node, source);
+				if (range.endLine <= range.startLine) {
+					// single-line constructs can't be folded
+					continue;
+				}
+				const existing = rangeByStartLine.get(range.startLine);
+				if (!existing || existing.endLine < range.endLine) {
+					rangeByStartLine.set(range.startLine, range);
+				}
+			}
+		}
+
+		return [...rangeByStartLine.values()].sort((a, b) => a.startLine - b.startLine);
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+function toFoldingRange(node: SyntaxNode, source: string): FoldingRange {
+	const startLine = node.startPosition.row;
+	let endLine = node.endPosition.row;
+
+	// a closing bracket on its own line stays visible, e.g., `}` or `} else {`
+	const lastChild = node.lastChild;
+	if (lastChild && (lastChild.type === '}' || lastChild.type === ')' || lastChild.type === ']') && isFirstOnItsLine(lastChild, source)) {
+		endLine = lastChild.startPosition.row - 1;
+	}
+
+	return { startLine, endLine };
+}
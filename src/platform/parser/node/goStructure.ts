@@ -0,0 +1,821 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { QueryCapture, SyntaxNode } from 'web-tree-sitter';
+import { findLast } from '../../../util/vs/base/common/arraysFind';
+import { OverlayNode, TreeSitterOffsetRange } from './nodes';
+import type { StructureOptions } from './structure';
+import type { StructureLanguage } from './structureDetails';
+import { leadingCommentOf, leadingCommentsOf, textUpTo } from './util';
+
+export const goStructure: StructureLanguage = {
+	describe: describeGoNode,
+	describeRoot: (root, rootSyntaxNode, source) => {
+		const fileDirectives = goFileDirectivesOf(rootSyntaxNode, source);
+		if (fileDirectives) {
+			root.directives = fileDirectives;
+		}
+		root.packageName = goPackageNameOf(rootSyntaxNode);
+	},
+	skipsCapture: (capture, options) => {
+		const node = capture.node;
+		// what tree-sitter couldn't parse, e.g., a broken `switch` body, but not the well-formed nodes around and within it
+		return isUnrecoverable(node)
+			// e.g., a comment within an interface
+			|| (capture.name === 'interface_element' && goInterfaceElementKind(node) === undefined)
+			// the declaration of a single constant or variable, e.g., `var x int`, already stands for it
+			|| ((node.type === 'const_spec' || node.type === 'var_spec') && node.parent?.namedChildren.filter(c => c.type === node.type).length === 1)
+			// e.g., `default:`, whose statements are nested in the switch statement instead
+			|| ((capture.name === 'default_case' || capture.name === 'type_case') && !options?.includeGoCaseClauses);
+	},
+	nodeKindOf: (capture, nodeKind) => {
+		// e.g., `go_statement` -> `goroutine`, `io.Reader` within an interface -> `embedded_interface`
+		const kind = (capture.name === 'interface_element' ? goInterfaceElementKind(capture.node) : goConcurrencyKind(capture)) ?? nodeKind;
+		if (capture.name === 'anonymous_struct') {
+			return 'anonymous_struct'; // e.g., `Meta struct { ... }`
+		}
+		if (kind === 'defer_statement') {
+			return 'deferred_call';
+		}
+		if (kind === 'field_declaration' && capture.node.childrenForFieldName('name').length === 0) {
+			return 'embedded_field'; // e.g., `*Base` within a struct
+		}
+		return kind;
+	},
+	attach: (node, syntaxNode, ancestors) => {
+		// the nearest enclosing function or method, e.g., for a `defer` within an `if` body
+		const declaration = node.kind === 'deferred_call' && isDeferredByDeclaration(syntaxNode)
+			? findLast(ancestors, n => n.kind === 'function_declaration' || n.kind === 'method_declaration')
+			: undefined;
+		if (declaration) {
+			(declaration.defers ??= []).push(node);
+		}
+	},
+	createLinker: () => {
+		// type declarations by the names of the types they declare
+		const typeDeclarations = new Map<string, OverlayNode>();
+		return {
+			register: (node, syntaxNode) => {
+				if (node.kind === 'type_declaration') {
+					for (const spec of syntaxNode.namedChildren.filter(c => c.type === 'type_spec' || c.type === 'type_alias')) {
+						const name = spec.childForFieldName('name')?.text;
+						if (name !== undefined) {
+							typeDeclarations.set(name, node);
+						}
+					}
+				}
+			},
+			link: (root, options) => linkGoDeclarations(root, typeDeclarations, options),
+		};
+	},
+};
+
+function describeGoNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	if (goDirectiveTargets.has(syntaxNode.type)) {
+		const directives = goDirectivesOf(syntaxNode, source);
+		if (directives.length > 0) {
+			overlayNode.directives = directives;
+		}
+		const lintDirectives = goLintDirectivesOf(syntaxNode);
+		if (lintDirectives.length > 0) {
+			overlayNode.lintDirectives = lintDirectives;
+		}
+	}
+	switch (syntaxNode.type) {
+		case 'function_declaration':
+		case 'method_declaration': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.childForFieldName('body'), source);
+			overlayNode.typeParameters = syntaxNode.childForFieldName('type_parameters')?.text; // methods can't declare type parameters
+			overlayNode.parameters = goParameters(syntaxNode.childForFieldName('parameters'));
+			overlayNode.results = goResults(syntaxNode.childForFieldName('result'));
+			if (syntaxNode.type === 'function_declaration') {
+				overlayNode.role = goTestRole(syntaxNode);
+			} else {
+				overlayNode.receiver = goReceiver(syntaxNode);
+			}
+			break;
+		}
+		case 'method_spec':
+		case 'method_elem': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.detail = syntaxNode.text;
+			overlayNode.leadingComment = leadingCommentOf(syntaxNode, source);
+			break;
+		}
+		case 'interface_type_name':
+		case 'type_elem': {
+			if (overlayNode.kind === 'embedded_interface') {
+				overlayNode.name = syntaxNode.text; // e.g., `io.Reader`
+			}
+			break;
+		}
+		case 'field_declaration': {
+			const tag = syntaxNode.childForFieldName('tag');
+			const names = syntaxNode.childrenForFieldName('name');
+			if (names.length === 0) {
+				// embedded field, e.g., `*pkg.Base`, is named after its unqualified type name, i.e., `Base`
+				overlayNode.type = textUpTo(syntaxNode, tag, source);
+				overlayNode.name = overlayNode.type.replace(/^\*/, '').replace(/\[.*$/s, '').split('.').pop();
+			} else {
+				// a field that declares multiple names, e.g., `X, Y int`, doesn't declare a single symbol
+				if (names.length === 1) {
+					overlayNode.name = names[0].text;
+				}
+				overlayNode.type = syntaxNode.childForFieldName('type')?.text;
+			}
+			overlayNode.tag = tag?.text;
+			overlayNode.detail = syntaxNode.text;
+			break;
+		}
+		case 'defer_statement':
+		case 'go_statement': {
+			const fn = syntaxNode.namedChildren[0]?.childForFieldName('function'); // of the call, e.g., `f.Close()` or `func() { ... }()`
+			overlayNode.callee = fn?.type === 'func_literal' ? undefined : fn?.text;
+			break;
+		}
+		case 'func_literal': {
+			// a closure assigned to a variable, e.g., `handler := func(...) { ... }`, is named after it; others are labeled after the names Go gives them
+			overlayNode.name = goAssignedName(syntaxNode);
+			if (overlayNode.name === undefined) {
+				overlayNode.label = goClosureLabel(syntaxNode);
+			}
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.childForFieldName('body'), source);
+			break;
+		}
+		case 'expression_case':
+		case 'communication_case':
+		case 'type_case':
+		case 'default_case': {
+			// labeled after its case up to the colon, e.g., `case 0`, `case v := <-ch`, or `default`
+			const colon = syntaxNode.children.find(c => c.type === ':');
+			overlayNode.label = textUpTo(syntaxNode, colon ?? null, source).replace(/\s+/g, ' ');
+			break;
+		}
+		case 'labeled_statement': {
+			overlayNode.label = syntaxNode.childForFieldName('label')?.text; // e.g., `Outer` for `Outer: for ...`
+			break;
+		}
+		case 'break_statement':
+		case 'continue_statement':
+		case 'goto_statement': {
+			// resolved once the labeled statements of the function are known, see `resolveGoJumpTargets`
+			const label = syntaxNode.namedChildren.find(c => c.type === 'label_name');
+			if (label) {
+				overlayNode.jumpTarget = { label: label.text, isResolved: false };
+			}
+			break;
+		}
+		case 'import_spec': {
+			// path is an interpreted or raw string literal, e.g., `"fmt"`
+			overlayNode.path = syntaxNode.childForFieldName('path')?.text.slice(1, -1);
+			overlayNode.alias = syntaxNode.childForFieldName('name')?.text; // e.g., `f`, `.`, or `_`
+			break;
+		}
+		case 'const_spec':
+		case 'var_spec': {
+			describeGoValueSpec(syntaxNode, overlayNode);
+			break;
+		}
+		case 'short_var_declaration': {
+			// e.g., `mapExample := map[string]int{ ... }`, but not `a, b := 1, 2`
+			const names = syntaxNode.childForFieldName('left')?.namedChildren ?? [];
+			const values = syntaxNode.childForFieldName('right')?.namedChildren ?? [];
+			if (names.length === 1 && values.length === 1) {
+				overlayNode.typeCategory = goValueTypeCategory(values[0]);
+			}
+			break;
+		}
+		case 'const_declaration':
+		case 'var_declaration': {
+			// only single-spec declarations, e.g., `const ConstExample = "const"`, stand for their spec
+			const specs = syntaxNode.namedChildren.filter(c => c.type === 'const_spec' || c.type === 'var_spec');
+			if (specs.length === 1) {
+				describeGoValueSpec(specs[0], overlayNode);
+			}
+			break;
+		}
+		case 'type_declaration': {
+			// only single-spec declarations, e.g., `type Stack[T any] struct { ... }`, declare a single symbol
+			const specs = syntaxNode.namedChildren.filter(c => c.type === 'type_spec' || c.type === 'type_alias');
+			if (specs.length !== 1) {
+				break;
+			}
+			const spec = specs[0];
+			overlayNode.name = spec.childForFieldName('name')?.text;
+			overlayNode.typeParameters = spec.childForFieldName('type_parameters')?.text;
+			const type = spec.childForFieldName('type');
+			overlayNode.type = type?.text;
+			if (spec.type === 'type_alias') {
+				overlayNode.isAlias = true;
+			}
+			overlayNode.detail = textUpTo(syntaxNode, type ? goTypeBody(type) : null, source);
+			break;
+		}
+	}
+}
+
+function goReceiver(methodDeclaration: SyntaxNode): OverlayNode['receiver'] {
+	const type = methodDeclaration.childForFieldName('receiver')?.namedChildren.find(c => c.type === 'parameter_declaration')?.childForFieldName('type');
+	if (!type) {
+		return undefined;
+	}
+	// e.g., `*Stack[T]` -> `Stack`
+	const typeName = type.text.replace(/^\*/, '').replace(/\[.*$/s, '').trim();
+	return { typeName, isPointer: type.type === 'pointer_type' };
+}
+
+/**
+ * @returns parameters of a `parameter_list`, e.g., two parameters for `a, b int`
+ */
+function goParameters(parameterList: SyntaxNode | null): OverlayNode['parameters'] {
+	const parameters: NonNullable<OverlayNode['parameters']> = [];
+	for (const declaration of parameterList?.namedChildren ?? []) {
+		if (declaration.type !== 'parameter_declaration' && declaration.type !== 'variadic_parameter_declaration') {
+			continue; // e.g., a comment
+		}
+		const typeNode = declaration.childForFieldName('type');
+		const type = declaration.type === 'variadic_parameter_declaration' ? `...${typeNode?.text ?? ''}` : typeNode?.text ?? '';
+		const names = declaration.childrenForFieldName('name');
+		if (names.length === 0) {
+			parameters.push({ type }); // e.g., `func(int, string)`
+		}
+		for (const name of names) {
+			parameters.push({ name: name.text, type });
+		}
+	}
+	return parameters;
+}
+
+/**
+ * @returns results of a function, which are either a `parameter_list`, e.g., `(n int, err error)`, or a single unnamed type, e.g., `error`
+ */
+function goResults(result: SyntaxNode | null): OverlayNode['results'] {
+	if (!result) {
+		return [];
+	}
+	return result.type === 'parameter_list' ? goParameters(result) : [{ type: result.text }];
+}
+
+/**
+ * Name prefixes of functions run by `go test` and the parameter types they must declare; examples don't declare any parameters.
+ */
+const goTestFunctionKinds: readonly { prefix: string; parameterType: string | undefined; role: NonNullable<OverlayNode['role']> }[] = [
+	{ prefix: 'Test', parameterType: '*testing.T', role: 'test' },
+	{ prefix: 'Benchmark', parameterType: '*testing.B', role: 'benchmark' },
+	{ prefix: 'Example', parameterType: undefined, role: 'example' },
+	{ prefix: 'Fuzz', parameterType: '*testing.F', role: 'fuzz' },
+];
+
+/**
+ * @returns how `go test` runs the function; `undefined` if it's not a test function, e.g., `Testify(t *testing.T)`
+ */
+export function goTestRole(functionDeclaration: SyntaxNode): OverlayNode['role'] {
+	const name = functionDeclaration.childForFieldName('name')?.text ?? '';
+	// the prefix must not be followed by a lower-case letter, i.e., `Test` and `Test_foo` are tests but `Testify` isn't
+	const kind = goTestFunctionKinds.find(k => name.startsWith(k.prefix) && !/^\p{Ll}/u.test(name.substring(k.prefix.length)));
+	if (!kind || functionDeclaration.childForFieldName('type_parameters') || functionDeclaration.childForFieldName('result')) {
+		return undefined;
+	}
+	const parameters = functionDeclaration.childForFieldName('parameters')?.namedChildren.filter(c => c.type !== 'comment') ?? [];
+	if (kind.parameterType === undefined) {
+		return parameters.length === 0 ? kind.role : undefined;
+	}
+	// e.g., `t *testing.T` but not `t, u *testing.T`
+	const isSingleParameter = parameters.length === 1 && parameters[0].type === 'parameter_declaration' && parameters[0].childrenForFieldName('name').length <= 1;
+	return isSingleParameter && parameters[0].childForFieldName('type')?.text.replace(/\s+/g, '') === kind.parameterType ? kind.role : undefined;
+}
+
+/**
+ * A constant without a value repeats the type and value of the nearest preceding one in its group.
+ */
+function describeGoValueSpec(spec: SyntaxNode, overlayNode: OverlayNode): void {
+	// a spec that declares multiple names, e.g., `X, Y = 1, 2`, doesn't declare a single symbol
+	const names = spec.childrenForFieldName('name');
+	if (names.length === 1) {
+		overlayNode.name = names[0].text;
+	}
+	let valueSpec: SyntaxNode | null = spec;
+	if (spec.type === 'const_spec') {
+		while (valueSpec !== null && (valueSpec.type !== 'const_spec' || valueSpec.childForFieldName('value') === null)) {
+			valueSpec = valueSpec.previousNamedSibling;
+		}
+	}
+	const value = valueSpec?.childForFieldName('value');
+	overlayNode.type = (valueSpec ?? spec).childForFieldName('type')?.text;
+	overlayNode.value = value?.text;
+	if (spec.type === 'var_spec' && names.length === 1) {
+		const type = spec.childForFieldName('type');
+		const values = value?.namedChildren ?? [];
+		overlayNode.typeCategory = type ? goTypeCategory(type) : values.length === 1 ? goValueTypeCategory(values[0]) : undefined;
+	}
+	if (spec.type === 'const_spec' && value?.descendantsOfType(['iota', 'identifier']).some(n => n.text === 'iota')) {
+		overlayNode.usesIota = true;
+		// `iota` is the index of the spec in its group, e.g., `2` for `C` in `const ( A = iota; _; C )`; blank constants have no value to speak of
+		const iota = spec.parent?.namedChildren.filter(c => c.type === 'const_spec').findIndex(c => c.id === spec.id) ?? -1;
+		if (names.length === 1 && names[0].text !== '_' && iota !== -1 && value.namedChildCount === 1) {
+			overlayNode.computedValue = goIotaExpressionValue(value.namedChildren[0], iota);
+		}
+	}
+}
+
+const goIntegerOperators: { [operator: string]: (left: number, right: number) => number } = {
+	'+': (left, right) => left + right,
+	'-': (left, right) => left - right,
+	'*': (left, right) => left * right,
+	'<<': (left, right) => right < 0 ? NaN : left * 2 ** right, // a negative shift count doesn't compile
+};
+
+/**
+ * @returns value of an integer expression of `iota` and integer literals, e.g., `13` for `iota + 10` given `iota` is `3`;
+ * `undefined` if it's made of anything else or too large to be exact
+ */
+function goIotaExpressionValue(expression: SyntaxNode, iota: number): number | undefined {
+	let value: number | undefined;
+	switch (expression.type) {
+		case 'iota':
+		case 'identifier':
+			value = expression.text === 'iota' ? iota : undefined;
+			break;
+		case 'int_literal': {
+			const literal = expression.text.replace(/_/g, '');
+			value = /^0[0-7]+$/.test(literal) ? parseInt(literal, 8) : Number(literal); // e.g., `017` but not `0o17`, `0x1F`, or `0b101`
+			break;
+		}
+		case 'parenthesized_expression':
+			value = expression.namedChildCount === 1 ? goIotaExpressionValue(expression.namedChildren[0], iota) : undefined;
+			break;
+		case 'unary_expression': {
+			const operand = expression.childForFieldName('operand');
+			const operator = expression.childForFieldName('operator')?.type;
+			const operandValue = operand ? goIotaExpressionValue(operand, iota) : undefined;
+			value = operandValue === undefined ? undefined : operator === '-' ? -operandValue : operator === '+' ? operandValue : undefined;
+			break;
+		}
+		case 'binary_expression': {
+			const left = expression.childForFieldName('left');
+			const right = expression.childForFieldName('right');
+			const operator = goIntegerOperators[expression.childForFieldName('operator')?.type ?? ''];
+			const leftValue = left ? goIotaExpressionValue(left, iota) : undefined;
+			const rightValue = right ? goIotaExpressionValue(right, iota) : undefined;
+			value = operator && leftValue !== undefined && rightValue !== undefined ? operator(leftValue, rightValue) : undefined;
+			break;
+		}
+	}
+	return value !== undefined && Number.isSafeInteger(value) ? value : undefined;
+}
+
+/**
+ * @returns name of the variable a value is assigned to, e.g., `handler` for `handler := func() { ... }`; `undefined` if it's not a plain identifier
+ */
+function goAssignedName(value: SyntaxNode): string | undefined {
+	const values = value.parent;
+	const assignment = values?.parent;
+	if (values?.type !== 'expression_list' || !assignment) {
+		return undefined;
+	}
+	let names: SyntaxNode[];
+	switch (assignment.type) {
+		case 'short_var_declaration':
+		case 'assignment_statement':
+			names = assignment.childForFieldName('left')?.namedChildren ?? [];
+			break;
+		case 'var_spec':
+			names = assignment.childrenForFieldName('name');
+			break;
+		default:
+			return undefined;
+	}
+	const name = names[values.namedChildren.findIndex(c => c.startIndex === value.startIndex)];
+	return name?.type === 'identifier' && name.text !== '_' ? name.text : undefined;
+}
+
+/**
+ * @returns label of a function literal after the name the compiler gives it, e.g., `main.func2` or `(*Server).Start.func1`
+ */
+function goClosureLabel(funcLiteral: SyntaxNode): string {
+	const enclosing = goEnclosingFunction(funcLiteral);
+	const index = goFunctionLiteralsOf(enclosing ?? funcLiteral.tree.rootNode).findIndex(n => n.startIndex === funcLiteral.startIndex) + 1;
+	switch (enclosing?.type) {
+		case undefined:
+			return `glob..func${index}`;
+		case 'func_literal':
+			return `${goClosureLabel(enclosing)}.${index}`;
+		case 'method_declaration': {
+			const receiver = goReceiver(enclosing);
+			return `${receiver?.isPointer ? `(*${receiver.typeName})` : receiver?.typeName}.${enclosing.childForFieldName('name')?.text}.func${index}`;
+		}
+		default:
+			return `${enclosing.childForFieldName('name')?.text}.func${index}`;
+	}
+}
+
+/**
+ * @returns innermost function declaration, method declaration, or function literal that `node` is within
+ */
+function goEnclosingFunction(node: SyntaxNode): SyntaxNode | undefined {
+	for (let n = node.parent; n !== null; n = n.parent) {
+		if (n.type === 'func_literal' || n.type === 'function_declaration' || n.type === 'method_declaration') {
+			return n;
+		}
+	}
+	return undefined;
+}
+
+/**
+ * @returns function literals whose innermost enclosing function is `node`, or that aren't within one if `node` is the root
+ */
+function goFunctionLiteralsOf(node: SyntaxNode): SyntaxNode[] {
+	return node.descendantsOfType('func_literal').filter(n => goEnclosingFunction(n)?.startIndex === (node.parent === null ? undefined : node.startIndex));
+}
+
+/**
+ * Declarations that `//go:` directives, e.g., `//go:embed` or `//go:noinline`, apply to if they directly precede them
+ */
+const goDirectiveTargets = new Set(['function_declaration', 'method_declaration', 'const_declaration', 'var_declaration', 'type_declaration', 'var_spec']);
+
+/**
+ * @example `//go:embed static/*` or `//go:generate stringer -type=Weekday`
+ */
+function isGoDirective(comment: SyntaxNode): boolean {
+	return /^\/\/go:[a-z]/.test(comment.text) && !isGoBuildConstraint(comment);
+}
+
+/**
+ * @example `//go:build linux && amd64` or the legacy `// +build linux,amd64`
+ */
+function isGoBuildConstraint(comment: SyntaxNode): boolean {
+	return /^\/\/go:build\b|^\/\/ \+build\b/.test(comment.text);
+}
+
+/**
+ * @returns directives among the comments that directly precede `declaration`, which may be mixed with its doc comment
+ */
+function goDirectivesOf(declaration: SyntaxNode, source: string): string[] {
+	return leadingCommentsOf(declaration, source).filter(isGoDirective).map(c => c.text);
+}
+
+/**
+ * @returns `//nolint` and `//lint:ignore` suppressions on the line above `declaration` or trailing its first line
+ */
+function goLintDirectivesOf(declaration: SyntaxNode): NonNullable<OverlayNode['lintDirectives']> {
+	const row = declaration.startPosition.row;
+	const prev = declaration.previousSibling;
+	const comments = prev?.type === 'comment' && prev.endPosition.row === row - 1 ? [prev] : [];
+	// a trailing comment is within a body that starts on the first line, e.g., a block, or follows a declaration on a single line
+	const next = declaration.nextSibling;
+	const trailing = [
+		...declaration.endPosition.row === row ? [] : declaration.descendantsOfType('comment', declaration.startPosition, { row: row + 1, column: 0 }),
+		...next?.type === 'comment' && next.startPosition.row === declaration.endPosition.row && declaration.endPosition.row === row ? [next] : [],
+	];
+	return [...comments, ...trailing.filter(c => c.startPosition.row === row)].flatMap(c => {
+		const directive = goLintDirectiveOf(c.text);
+		return directive ? [directive] : [];
+	});
+}
+
+/**
+ * @returns text and linters of a lint suppression, e.g., `['govet', 'errcheck']` for `//nolint:govet,errcheck`; none for a bare `//nolint`
+ */
+function goLintDirectiveOf(text: string): NonNullable<OverlayNode['lintDirectives']>[number] | undefined {
+	const nolint = /^\/\/nolint(?::([\w-]+(?:\s*,\s*[\w-]+)*))?(?=\s|$)/.exec(text);
+	if (nolint) {
+		return { text: nolint[0], linters: nolint[1]?.split(',').map(l => l.trim()) ?? [] };
+	}
+	const ignore = /^\/\/lint:ignore ([\w,-]+)/.exec(text);
+	return ignore ? { text: text.trimEnd(), linters: ignore[1].split(',') } : undefined;
+}
+
+/**
+ * @returns build constraints of a source file and the directives that don't precede a declaration; `undefined` if there are none
+ */
+function goFileDirectivesOf(sourceFile: SyntaxNode, source: string): string[] | undefined {
+	const attached = new Set<number>();
+	for (const declaration of sourceFile.namedChildren) {
+		if (goDirectiveTargets.has(declaration.type)) {
+			for (const comment of leadingCommentsOf(declaration, source)) {
+				attached.add(comment.startIndex);
+			}
+		}
+	}
+	const directives = sourceFile.namedChildren
+		.filter(c => c.type === 'comment' && (isGoBuildConstraint(c) || (isGoDirective(c) && !attached.has(c.startIndex))))
+		.map(c => c.text);
+	return directives.length > 0 ? directives : undefined;
+}
+
+/**
+ * @returns package of a source file, e.g., `main` for `package main`; `undefined` for a snippet without one
+ */
+function goPackageNameOf(sourceFile: SyntaxNode): string | undefined {
+	return sourceFile.namedChildren.find(c => c.type === 'package_clause')?.namedChildren.find(c => c.type === 'package_identifier')?.text;
+}
+
+/**
+ * @returns category of a type as written, e.g., `map` for `map[string]int`; `undefined` for an interface type
+ */
+function goTypeCategory(type: SyntaxNode): OverlayNode['typeCategory'] {
+	switch (type.type) {
+		case 'array_type':
+		case 'implicit_length_array_type': // e.g., `[...]int{1, 2, 3}`
+			return 'array';
+		case 'slice_type':
+			return 'slice';
+		case 'map_type':
+			return 'map';
+		case 'channel_type':
+			return 'chan';
+		case 'pointer_type':
+			return 'pointer';
+		case 'struct_type':
+			return 'struct';
+		case 'function_type':
+			return 'func';
+		case 'type_identifier':
+		case 'qualified_type':
+		case 'generic_type': // e.g., `Stack[int]`
+			return 'named';
+		case 'parenthesized_type':
+			return type.namedChildren[0] ? goTypeCategory(type.namedChildren[0]) : undefined;
+		default:
+			return undefined;
+	}
+}
+
+/**
+ * @returns category of the type of an expression if it's evident from the expression alone, e.g., `chan` for `make(chan int)`
+ */
+function goValueTypeCategory(value: SyntaxNode): OverlayNode['typeCategory'] {
+	switch (value.type) {
+		case 'composite_literal': {
+			const type = value.childForFieldName('type');
+			return type ? goTypeCategory(type) : undefined;
+		}
+		case 'func_literal':
+			return 'func';
+		case 'unary_expression':
+			return value.childForFieldName('operator')?.type === '&' ? 'pointer' : undefined;
+		case 'slice_expression': {
+			// a string sliced is a string, e.g., `"hello"[1:]`
+			const operand = value.childForFieldName('operand');
+			return operand?.type === 'interpreted_string_literal' || operand?.type === 'raw_string_literal' ? undefined : 'slice';
+		}
+		case 'type_conversion_expression': { // e.g., `[]byte(s)`
+			const type = value.childForFieldName('type');
+			return type ? goTypeCategory(type) : undefined;
+		}
+		case 'call_expression': {
+			const fn = value.childForFieldName('function');
+			const type = value.childForFieldName('arguments')?.namedChildren[0];
+			if (fn?.type === 'identifier' && fn.text === 'make' && type) {
+				return goTypeCategory(type);
+			}
+			if (fn?.type === 'identifier' && fn.text === 'new') {
+				return 'pointer';
+			}
+			// depending on the grammar version, a conversion to a type that isn't a name is a call, e.g., `[]byte(s)`
+			return fn && fn.type.endsWith('_type') ? goTypeCategory(fn) : undefined;
+		}
+		case 'parenthesized_expression':
+			return value.namedChildren[0] ? goValueTypeCategory(value.namedChildren[0]) : undefined;
+		default:
+			return undefined;
+	}
+}
+
+/**
+ * @returns the part of a struct or interface type that holds its members, e.g., `{ Name string }` in `struct { Name string }`
+ */
+function goTypeBody(type: SyntaxNode): SyntaxNode | null {
+	switch (type.type) {
+		case 'struct_type':
+			return type.namedChildren.find(c => c.type === 'field_declaration_list') ?? null;
+		case 'interface_type':
+			return type.children.find(c => c.type === '{') ?? null;
+		default:
+			return null;
+	}
+}
+
+/**
+ * @returns whether `node` is an `ERROR` or `MISSING` node or has errors while within an `ERROR` node
+ */
+function isUnrecoverable(node: SyntaxNode): boolean {
+	if (node.isError || node.isMissing) {
+		return true;
+	}
+	if (!node.hasError) {
+		return false;
+	}
+	for (let n = node.parent; n !== null; n = n.parent) {
+		if (n.isError) {
+			return true;
+		}
+	}
+	return false;
+}
+
+/**
+ * @returns kind of an element of an interface, whose node types differ between grammar versions; `undefined` for a comment
+ */
+function goInterfaceElementKind(node: SyntaxNode): string | undefined {
+	switch (node.type) {
+		case 'method_spec':
+		case 'method_elem':
+			return 'method_spec';
+		case 'interface_type_name':
+			return 'embedded_interface';
+		case 'type_elem': {
+			// a single type name, e.g., `io.Reader`, as opposed to a constraint, e.g., `~int | ~string`
+			const types = node.namedChildren;
+			return types.length === 1 && ['type_identifier', 'qualified_type', 'generic_type'].includes(types[0].type) ? 'embedded_interface' : 'type_constraint';
+		}
+		case 'constraint_elem':
+			return 'type_constraint';
+		default:
+			return undefined;
+	}
+}
+
+function goConcurrencyKind(capture: QueryCapture): string | undefined {
+	switch (capture.node.type) {
+		case 'go_statement':
+			return 'goroutine';
+		case 'send_statement':
+			return 'channel_operation';
+	}
+	// receive expressions, `close(ch)`, and `make(chan T)` are captured by their kind
+	return capture.name === 'channel_operation' || capture.name === 'channel_creation' ? capture.name : undefined;
+}
+
+/**
+ * @returns whether the `defer` statement `node` belongs to a function or method declaration rather than a function literal
+ */
+function isDeferredByDeclaration(node: SyntaxNode): boolean {
+	for (let n = node.parent; n !== null; n = n.parent) {
+		switch (n.type) {
+			case 'func_literal':
+				return false;
+			case 'function_declaration':
+			case 'method_declaration':
+				return true;
+		}
+	}
+	return false;
+}
+
+function linkGoDeclarations(root: OverlayNode, typeDeclarations: Map<string, OverlayNode>, options: StructureOptions | undefined) {
+	flagGoExports(root);
+	resolveGoJumpTargets(root);
+	if (typeDeclarations.size > 0) {
+		linkGoMethods(root, typeDeclarations);
+		if (options?.inlineEmbeddedInterfaces) {
+			inlineGoEmbeddedInterfaces(root, typeDeclarations);
+		}
+		if (options?.nestGoMethods) {
+			nestGoMethods(root, typeDeclarations);
+		}
+	}
+	if (options?.exportedOnly) {
+		removeUnexportedGoDeclarations(root);
+	}
+}
+
+/**
+ * Flags the named top-level declarations as exported or not and sets the visibility of the named nodes outside of function bodies.
+ */
+function flagGoExports(root: OverlayNode) {
+	for (const node of root.children) {
+		if (node.name === undefined) {
+			continue;
+		}
+		node.exported = isGoExportedName(node.name);
+	}
+	const visit = (node: OverlayNode) => {
+		for (const child of node.children) {
+			if (child.name !== undefined && child.kind !== 'package_clause' && child.kind !== 'import_spec') {
+				child.visibility = isGoExportedName(child.name) ? 'public' : 'private';
+			}
+			if (child.kind !== 'function_declaration' && child.kind !== 'method_declaration' && child.kind !== 'func_literal') {
+				visit(child);
+			}
+		}
+	};
+	visit(root);
+}
+
+/**
+ * Exported names start with an upper-case letter of any script.
+ */
+function isGoExportedName(name: string): boolean {
+	return /^\p{Lu}/u.test(name);
+}
+
+/**
+ * Removes the declarations with unexported names outside of function bodies, see {@link StructureOptions.exportedOnly}.
+ */
+function removeUnexportedGoDeclarations(root: OverlayNode) {
+	const isKept = (node: OverlayNode): boolean => {
+		if (node.name === undefined) {
+			// a group of constants or variables, e.g., `var ( a int; b int )`, is left out if all of its specs are
+			return (node.kind !== 'const_declaration' && node.kind !== 'var_declaration') || node.children.length === 0 || node.children.some(isKept);
+		}
+		return node.kind === 'package_clause' || (isGoExportedName(node.name) && (node.receiver === undefined || isGoExportedName(node.receiver.typeName)));
+	};
+	const visit = (node: OverlayNode) => {
+		node.children.splice(0, node.children.length, ...node.children.filter(isKept));
+		if (node.methods) {
+			node.methods = node.methods.filter(isKept);
+		}
+		for (const child of node.children) {
+			if (child.kind !== 'function_declaration' && child.kind !== 'method_declaration' && child.kind !== 'func_literal') {
+				visit(child);
+			}
+		}
+	};
+	visit(root);
+}
+
+/**
+ * Resolves the labels of `break`, `continue`, and `goto` statements to the labeled statements of the functions they're in.
+ */
+function resolveGoJumpTargets(root: OverlayNode) {
+	const isFunction = (node: OverlayNode) => node.kind === 'function_declaration' || node.kind === 'method_declaration' || node.kind === 'func_literal';
+	// nodes within the body of a function, but not within nested function literals, which are visited on their own
+	const statementsOf = (node: OverlayNode): OverlayNode[] => node.children.flatMap(c => isFunction(c) ? [] : [c, ...statementsOf(c)]);
+	const visit = (node: OverlayNode) => {
+		if (isFunction(node)) {
+			const statements = statementsOf(node);
+			const labeled = statements.filter(s => s.kind === 'labeled_statement' && s.label !== undefined);
+			for (const statement of statements) {
+				const jumpTarget = statement.jumpTarget;
+				if (jumpTarget === undefined) {
+					continue;
+				}
+				// `break` and `continue` leave a statement they're in, `goto` may jump anywhere in the function
+				const target = labeled.find(l => l.label === jumpTarget.label && (statement.kind === 'goto_statement' || TreeSitterOffsetRange.doesContain(l, statement)));
+				if (target) {
+					statement.jumpTarget = { label: jumpTarget.label, isResolved: true, startIndex: target.startIndex };
+				}
+			}
+		}
+		node.children.forEach(visit);
+	};
+	visit(root);
+}
+
+/**
+ * Adds top-level methods to {@link OverlayNode.methods} of the declarations of their receiver types, if they're in the same source.
+ */
+function linkGoMethods(root: OverlayNode, typeDeclarations: Map<string, OverlayNode>) {
+	for (const node of root.children) {
+		const typeDeclaration = node.receiver && typeDeclarations.get(node.receiver.typeName);
+		if (typeDeclaration) {
+			(typeDeclaration.methods ??= []).push(node);
+		}
+	}
+}
+
+/**
+ * Moves the methods linked by {@link linkGoMethods} from the top level to the children of their types' declarations.
+ */
+function nestGoMethods(root: OverlayNode, typeDeclarations: Map<string, OverlayNode>) {
+	const nested = new Set<OverlayNode>();
+	// a group of type declarations, e.g., `type ( A int; B int )`, is a single node for all of its types
+	for (const typeDeclaration of new Set(typeDeclarations.values())) {
+		for (const method of typeDeclaration.methods ?? []) {
+			typeDeclaration.children.push(method);
+			nested.add(method);
+		}
+	}
+	root.children.splice(0, root.children.length, ...root.children.filter(c => !nested.has(c)));
+}
+
+/**
+ * Sets {@link OverlayNode.methods} of embedded interfaces to the method sets of the interfaces they refer to, if they're in the same source.
+ */
+function inlineGoEmbeddedInterfaces(node: OverlayNode, typeDeclarations: Map<string, OverlayNode>) {
+	const methodSetOf = (typeDeclaration: OverlayNode, visited: Set<OverlayNode>): OverlayNode[] => {
+		if (visited.has(typeDeclaration)) {
+			return []; // invalid but parseable, e.g., `type A interface { B }; type B interface { A }`
+		}
+		visited.add(typeDeclaration);
+		return typeDeclaration.children.flatMap(c => {
+			if (c.kind === 'method_spec') {
+				return [c];
+			}
+			const embedded = c.kind === 'embedded_interface' && c.name !== undefined ? typeDeclarations.get(c.name) : undefined;
+			return embedded ? methodSetOf(embedded, visited) : [];
+		});
+	};
+	for (const child of node.children) {
+		const typeDeclaration = child.kind === 'embedded_interface' && child.name !== undefined ? typeDeclarations.get(child.name) : undefined;
+		if (typeDeclaration) {
+			child.methods = methodSetOf(typeDeclaration, new Set([node]));
+		} else {
+			inlineGoEmbeddedInterfaces(child, typeDeclarations);
+		}
+	}
+}
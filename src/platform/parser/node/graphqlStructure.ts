@@ -0,0 +1,119 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo } from './util';
+
+export const graphqlStructure: StructureLanguage = {
+	describe: describeGraphqlNode,
+	createLinker: () => ({ link: markGraphqlRootOperationTypes }),
+};
+
+function describeGraphqlNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	switch (syntaxNode.type) {
+		case 'object_type_definition':
+		case 'interface_type_definition':
+		case 'input_object_type_definition':
+		case 'enum_type_definition':
+		case 'union_type_definition':
+		case 'scalar_type_definition': {
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'name')?.text;
+			// e.g., `type User implements Node & Entity @key(fields: "id")` without the description and the fields
+			const body = syntaxNode.namedChildren.find(c => /^(fields|input_fields|enum_values)_definition$/.test(c.type)) ?? null;
+			overlayNode.detail = graphqlTextUpTo(syntaxNode, body, source);
+			// e.g., `['Node', 'Entity']` for `implements Node & Entity`, which nests an `implements_interfaces` per `&`
+			const interfaces = syntaxNode.namedChildren.find(c => c.type === 'implements_interfaces');
+			if (interfaces) {
+				overlayNode.interfaces = interfaces.descendantsOfType('named_type').map(t => t.text);
+			}
+			break;
+		}
+		case 'schema_definition': {
+			overlayNode.detail = graphqlTextUpTo(syntaxNode, syntaxNode.children.find(c => c.type === '{') ?? null, source);
+			break;
+		}
+		case 'root_operation_type_definition': {
+			// e.g., name `query` and type `RootQuery` for `query: RootQuery`
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'operation_type')?.text;
+			overlayNode.type = syntaxNode.namedChildren.find(c => c.type === 'named_type')?.text;
+			overlayNode.detail = syntaxNode.text;
+			break;
+		}
+		case 'directive_definition': {
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'name')?.text;
+			overlayNode.detail = graphqlTextUpTo(syntaxNode, null, source);
+			break;
+		}
+		case 'field_definition': {
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'name')?.text;
+			overlayNode.detail = graphqlTextUpTo(syntaxNode, null, source);
+			overlayNode.type = syntaxNode.namedChildren.find(c => c.type === 'type')?.text; // e.g., `[Post!]!`
+			const args = syntaxNode.namedChildren.find(c => c.type === 'arguments_definition');
+			if (args) {
+				// e.g., `[{ name: 'first', type: 'Int' }]` for `posts(first: Int = 10): [Post!]!`
+				overlayNode.parameters = args.namedChildren.filter(c => c.type === 'input_value_definition').map(arg => ({
+					name: arg.namedChildren.find(c => c.type === 'name')?.text,
+					type: arg.namedChildren.find(c => c.type === 'type')?.text ?? '',
+				}));
+			}
+			break;
+		}
+		case 'input_value_definition': {
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'name')?.text;
+			overlayNode.detail = graphqlTextUpTo(syntaxNode, null, source);
+			overlayNode.type = syntaxNode.namedChildren.find(c => c.type === 'type')?.text;
+			break;
+		}
+		case 'enum_value_definition': {
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'enum_value')?.text;
+			overlayNode.detail = graphqlTextUpTo(syntaxNode, null, source);
+			break;
+		}
+		case 'operation_definition': {
+			// anonymous operations, e.g., `{ viewer { id } }`, have no name
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'name')?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.namedChildren.find(c => c.type === 'selection_set') ?? null, source) || undefined;
+			break;
+		}
+		case 'fragment_definition': {
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'fragment_name')?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.namedChildren.find(c => c.type === 'selection_set') ?? null, source);
+			break;
+		}
+	}
+}
+
+/**
+ * @returns text of a definition up to where `end` starts like {@link textUpTo} does, but without its description
+ */
+function graphqlTextUpTo(node: SyntaxNode, end: SyntaxNode | null, source: string): string {
+	const description = node.namedChildren[0]?.type === 'description' ? node.namedChildren[0] : undefined;
+	const start = description?.nextSibling?.startIndex ?? node.startIndex;
+	return source.substring(start, end ? end.startIndex : node.endIndex).trimEnd();
+}
+
+type GraphqlOperationType = NonNullable<OverlayNode['operationType']>;
+
+const graphqlDefaultRootOperationTypes = new Map<string, GraphqlOperationType>([['Query', 'query'], ['Mutation', 'mutation'], ['Subscription', 'subscription']]);
+
+/**
+ * Sets {@link OverlayNode.operationType} of the root operation types, i.e., those of the `schema` definition or, without one, `Query`, `Mutation`, and `Subscription`.
+ */
+function markGraphqlRootOperationTypes(root: OverlayNode) {
+	const schema = root.children.find(n => n.kind === 'schema_definition');
+	const operationTypes = schema
+		? new Map(schema.children
+			.filter(n => n.kind === 'root_operation_type_definition' && n.type !== undefined)
+			.map((n): [string, GraphqlOperationType] => [n.type!, n.name as GraphqlOperationType]))
+		: graphqlDefaultRootOperationTypes;
+	for (const node of root.children) {
+		const operationType = node.kind === 'object_type_definition' && node.name !== undefined ? operationTypes.get(node.name) : undefined;
+		if (operationType) {
+			node.operationType = operationType;
+		}
+	}
+}
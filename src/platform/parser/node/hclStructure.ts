@@ -0,0 +1,29 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo, unquote } from './util';
+
+export const hclStructure: StructureLanguage = { describe: describeHclNode };
+
+function describeHclNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	switch (syntaxNode.type) {
+		case 'block': {
+			// named after its labels like Terraform addresses them, e.g., `aws_instance.web` for `resource "aws_instance" "web"`, or after its type without labels, e.g., `lifecycle`
+			const blockStart = syntaxNode.namedChildren.findIndex(c => c.type === 'block_start');
+			const [type, ...labels] = syntaxNode.namedChildren.slice(0, blockStart === -1 ? undefined : blockStart);
+			overlayNode.name = labels.length > 0 ? labels.map(l => l.type === 'string_lit' ? unquote(l.text) : l.text).join('.') : type?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.namedChildren[blockStart] ?? null, source);
+			break;
+		}
+		case 'attribute': {
+			overlayNode.name = syntaxNode.namedChildren[0]?.text;
+			overlayNode.detail = syntaxNode.text;
+			break;
+		}
+	}
+}
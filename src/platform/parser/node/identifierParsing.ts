@@ -0,0 +1,154 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { TreeSitterOffsetRange } from './nodes';
+import { _parse } from './parserWithCaching';
+import { WASMLanguage } from './treeSitterLanguages';
+import { _isScope } from './treeSitterQueries';
+
+export interface IdentifierAtOffset {
+	/**
+	 * @example `MethodExample`
+	 */
+	name: string;
+	range: TreeSitterOffsetRange;
+	/**
+	 * Whether the identifier is the name of a declaration, e.g., `MethodExample` in `func (s StructExample) MethodExample()`,
+	 * or refers to something declared elsewhere, e.g., `MethodExample` in `i.MethodExample()`
+	 */
+	kind: 'definition' | 'reference';
+}
+
+/**
+ * @returns the identifier at `offset` or right before it, i.e., `offset` may be where the identifier ends as for a cursor after it;
+ * `undefined` if there's no identifier there, e.g., on a keyword or within a string literal
+ */
+export async function _getIdentifierAt(language: WASMLanguage, source: string, offset: number): Promise<IdentifierAtOffset | undefined> {
+	const treeRef = await _parse(language, source);
+
+	try {
+		const node = identifierAt(treeRef.tree.rootNode, source, offset);
+		if (node === undefined) {
+			return undefined;
+		}
+		return {
+			name: node.text,
+			range: { startIndex: node.startIndex, endIndex: node.endIndex },
+			kind: isDefinition(node) ? 'definition' : 'reference',
+		};
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+/**
+ * @returns range of the name of the nearest binding of the identifier at `offset`, see {@link _getIdentifierAt}, that's declared before it
+ * in a scope enclosing it, e.g., a parameter, a local variable, or a Go `:=` declaration, or the identifier's own range if it's a definition;
+ * `undefined` if there's no identifier at `offset` or it isn't bound within its top-level declaration, e.g., a package-level variable
+ * or an import, which can't be resolved without the rest of the package
+ */
+export async function _findLocalDefinition(language: WASMLanguage, source: string, offset: number): Promise<TreeSitterOffsetRange | undefined> {
+	const treeRef = await _parse(language, source);
+
+	try {
+		const identifier = identifierAt(treeRef.tree.rootNode, source, offset);
+		if (identifier === undefined) {
+			return undefined;
+		}
+		if (isDefinition(identifier)) {
+			return TreeSitterOffsetRange.ofSyntaxNode(identifier);
+		}
+
+		// nothing outside of the top-level declaration the identifier is in is local to it
+		let topLevel = identifier;
+		while (topLevel.parent !== null && topLevel.parent.parent !== null) {
+			topLevel = topLevel.parent;
+		}
+
+		let nearest: { definition: SyntaxNode; scope: SyntaxNode } | undefined;
+		// of the same type, so that, e.g., the field in `s.Name` isn't resolved to a variable `Name`
+		for (const candidate of topLevel.descendantsOfType(identifier.type)) {
+			if (candidate.startIndex >= identifier.startIndex || candidate.text !== identifier.text || !isDefinition(candidate)) {
+				continue;
+			}
+			const scope = scopeOf(language, candidate);
+			if (scope === undefined || !TreeSitterOffsetRange.doesContain(scope, identifier)) {
+				continue;
+			}
+			// the innermost scope wins, and within the same scope the last binding, e.g., of a redeclared `err`
+			if (nearest === undefined || TreeSitterOffsetRange.doesContain(nearest.scope, scope)) {
+				nearest = { definition: candidate, scope };
+			}
+		}
+		return nearest && TreeSitterOffsetRange.ofSyntaxNode(nearest.definition);
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+function identifierAt(rootNode: SyntaxNode, source: string, offset: number): SyntaxNode | undefined {
+	return [offset, offset - 1]
+		.filter(i => i >= 0 && i < source.length)
+		.map(i => rootNode.descendantForIndex(i))
+		.find(isIdentifier);
+}
+
+/**
+ * identifier tokens that don't end with `identifier`, e.g., Ruby's `Foo` in `class Foo`, PHP's names, and shell variables
+ */
+const otherIdentifierTypes = new Set(['constant', 'name', 'variable_name']);
+
+/**
+ * blocks and functions that scope what's declared within them besides the scopes of `_isScope`, e.g., Go's `{ ... }` and function literals
+ */
+const localScopeTypes = new Set(['block', 'statement_block', 'compound_statement', 'func_literal', 'function_expression', 'arrow_function', 'lambda', 'closure_expression']);
+
+/**
+ * @returns innermost scope that `definition` is bound in, e.g., the function of a parameter or the block of a local variable;
+ * `undefined` if it's bound in the root, i.e., it isn't local
+ */
+function scopeOf(language: WASMLanguage, definition: SyntaxNode): SyntaxNode | undefined {
+	const isScope = (node: SyntaxNode) => _isScope(language, node) || localScopeTypes.has(node.type);
+	// a declaration that's a scope itself, e.g., a nested function, is bound in the scope around it, unlike its parameters
+	let node = definition.parent;
+	if (node !== null && isScope(node)) {
+		node = node.parent;
+	}
+	for (; node !== null && node.parent !== null; node = node.parent) {
+		if (isScope(node)) {
+			return node;
+		}
+	}
+	return undefined;
+}
+
+function isIdentifier(node: SyntaxNode): boolean {
+	// e.g., `identifier`, `field_identifier`, `type_identifier`, `property_identifier`, or `simple_identifier`
+	return node.childCount === 0 && (node.type.endsWith('identifier') || otherIdentifierTypes.has(node.type));
+}
+
+/**
+ * @returns whether `identifier` names the declaration it's in, e.g., a function, type, variable, field, or parameter,
+ * which is approximated by the fields of the declaration; identifiers in qualified names, e.g., C++'s `Shape::area`, are always references
+ */
+function isDefinition(identifier: SyntaxNode): boolean {
+	const parent = identifier.parent;
+	if (parent === null) {
+		return false;
+	}
+	// go: the names before a `:=`, e.g., of a `short_var_declaration`, of a `range_clause`, or of a type switch's alias, are in an `expression_list`
+	if (parent.type === 'expression_list' && parent.parent?.children.some(c => c.type === ':=' && c.startIndex >= parent.endIndex)) {
+		return true;
+	}
+	// e.g., `class_specifier` is a definition with a body but a reference in `class Foo *p;`
+	const isDeclaration = /declaration|definition|declarator|_spec$|_elem$|_item$|signature|parameter/.test(parent.type)
+		|| (parent.type.endsWith('_specifier') && parent.childForFieldName('body') !== null);
+	if (!isDeclaration) {
+		return false;
+	}
+	// e.g., `name` of a Go `const_spec`, which may declare several names, `declarator` of a C `init_declarator`, or `pattern` of a Rust `let`
+	return ['name', 'declarator', 'pattern'].some(field => parent.childrenForFieldName(field).some(c => c.id === identifier.id));
+}
@@ -0,0 +1,134 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode, Tree } from 'web-tree-sitter';
+import { TreeSitterOffsetRange } from './nodes';
+import { _parse } from './parserWithCaching';
+import { runQueries } from './querying';
+import { WASMLanguage } from './treeSitterLanguages';
+import { importQueries } from './treeSitterQueries';
+
+export interface ImportStatement extends TreeSitterOffsetRange {
+	/**
+	 * Raw text of the import
+	 * @example `import * as fs from 'fs';`
+	 */
+	text: string;
+	/**
+	 * Imported module or path without quotes
+	 * @example `fs`
+	 */
+	path: string;
+	/**
+	 * Name the whole module is bound to, if any
+	 * @example `fs` in `import * as fs from 'fs'`, `f` in Go's `import f "fmt"`
+	 */
+	alias?: string;
+}
+
+/**
+ * @returns imports of the given source in document order; empty if the language isn't supported
+ */
+export async function _getImports(language: WASMLanguage, source: string): Promise<ImportStatement[]> {
+	const queries = importQueries[language];
+	if (!queries) {
+		return [];
+	}
+
+	const treeRef = await _parse(language, source);
+
+	try {
+		return importsOf(queries, treeRef.tree.rootNode);
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+function importsOf(queries: string[], rootNode: SyntaxNode): ImportStatement[] {
+	// same import may be matched by multiple patterns, e.g., one with and one without an alias
+	const imports = new Map</* import start : path start */string, ImportStatement>();
+
+	for (const { captures } of runQueries(queries, rootNode)) {
+		const importNode = captures.find(c => c.name === 'import')?.node;
+		const pathNode = captures.find(c => c.name === 'path')?.node;
+		if (!importNode || !pathNode) {
+			continue;
+		}
+		const key = `${importNode.startIndex}:${pathNode.startIndex}`;
+		const alias = captures.find(c => c.name === 'alias')?.node.text ?? imports.get(key)?.alias;
+		imports.set(key, {
+			startIndex: importNode.startIndex,
+			endIndex: importNode.endIndex,
+			text: importNode.text,
+			path: unquote(pathNode.text),
+			...(alias !== undefined ? { alias } : {}),
+		});
+	}
+
+	return [...imports.values()].sort(TreeSitterOffsetRange.compare);
+}
+
+/**
+ * @returns imports of the given Go source that are never referenced, see {@link findUnusedGoImports}
+ */
+export async function _getUnusedGoImports(source: string): Promise<ImportStatement[]> {
+	const treeRef = await _parse(WASMLanguage.Go, source);
+
+	try {
+		return findUnusedGoImports(treeRef.tree);
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+/**
+ * Package qualifiers, e.g., `fmt` in `fmt.Println(...)` and in the type `fmt.Stringer`.
+ */
+const goQualifierQuery = `[
+	(selector_expression
+		operand: (identifier) @qualifier)
+	(qualified_type
+		package: (package_identifier) @qualifier)
+]`;
+
+/**
+ * Finds the imports of a Go parse tree whose package name, i.e., the alias or else the name guessed from the path, is never used as
+ * a qualifier, e.g., `strings` if there's no `strings.X` in the file. Blank (`_`) and dot (`.`) imports are never reported
+ * since they're used by importing them. A local variable shadowing the package name counts as a use.
+ *
+ * @returns the unused imports in document order
+ */
+export function findUnusedGoImports(tree: Tree): ImportStatement[] {
+	const rootNode = tree.rootNode;
+	const qualifiers = new Set(runQueries([goQualifierQuery], rootNode).flatMap(({ captures }) => captures.map(c => c.node.text)));
+
+	return importsOf(importQueries[WASMLanguage.Go], rootNode).filter(i => {
+		if (i.alias === '_' || i.alias === '.') {
+			return false;
+		}
+		return !qualifiers.has(i.alias ?? goPackageNameOf(i.path));
+	});
+}
+
+/**
+ * @returns the conventional name of the package at the import path, i.e., its last element without a major version or a `go-` prefix or `-go` suffix,
+ * e.g., `yaml` for `gopkg.in/yaml.v3`, `chi` for `github.com/go-chi/chi/v5`, and `isatty` for `github.com/mattn/go-isatty`
+ */
+function goPackageNameOf(path: string): string {
+	const elements = path.split('/');
+	let name = elements.pop() ?? path;
+	if (/^v\d+$/.test(name) && elements.length > 0) {
+		name = elements.pop()!;
+	}
+	return name.replace(/\.v\d+$/, '').replace(/^go-|-go$/g, '');
+}
+
+function unquote(text: string): string {
+	const quote = text[0];
+	if (text.length >= 2 && (quote === '"' || quote === '\'' || quote === '`') && text[text.length - 1] === quote) {
+		return text.slice(1, -1);
+	}
+	return text;
+}
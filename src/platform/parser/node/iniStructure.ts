@@ -0,0 +1,30 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { keyPathWith } from './util';
+
+export const iniStructure: StructureLanguage = {
+	describe: describeIniNode,
+	keyPathOf: (_syntaxNode, overlayNode, parentPath) => keyPathWith(parentPath, overlayNode.name),
+};
+
+function describeIniNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode): void {
+	switch (overlayNode.kind) {
+		case 'section': {
+			// e.g., `database` for `[database]`
+			const name = syntaxNode.namedChildren.find(c => c.type === 'section_name');
+			overlayNode.name = name?.namedChildren.find(c => c.type === 'text')?.text.trim() ?? name?.text.replace(/^\[|\]\s*$/g, '').trim();
+			break;
+		}
+		case 'pair': {
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'setting_name')?.text.trim();
+			overlayNode.value = syntaxNode.namedChildren.find(c => c.type === 'setting_value')?.text.trim();
+			break;
+		}
+	}
+}
@@ -0,0 +1,132 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+
+export const javaStructure: StructureLanguage = {
+	describe: describeJavaNode,
+	// a top-level type declaration is a statement as well, and is captured as a declaration, too
+	skipsCapture: capture => capture.name === 'statement' && javaTypeDeclarations.has(capture.node.type),
+};
+
+function describeJavaNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	const annotations = syntaxNode.namedChildren.find(c => c.type === 'modifiers')?.namedChildren.filter(isJavaAnnotation);
+	if (annotations?.length) {
+		// e.g., `['@Override']` or `['@GetMapping("/{id}")']`
+		overlayNode.decorators = annotations.map(a => a.text);
+	}
+	const visibility = javaVisibilityOf(syntaxNode);
+	if (visibility) {
+		overlayNode.visibility = visibility;
+	}
+	switch (syntaxNode.type) {
+		case 'class_declaration':
+		case 'interface_declaration':
+		case 'enum_declaration':
+		case 'record_declaration':
+		case 'annotation_type_declaration': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			// e.g., `public static final class Builder<T> extends Base implements Runnable` without the annotations and the body
+			overlayNode.detail = javaTextUpTo(syntaxNode, syntaxNode.childForFieldName('body'), source);
+			break;
+		}
+		case 'method_declaration':
+		case 'constructor_declaration': {
+			const name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.name = name;
+			// an abstract or interface method has no body, e.g., `void run();`
+			overlayNode.detail = javaTextUpTo(syntaxNode, syntaxNode.childForFieldName('body') ?? syntaxNode.children.find(c => c.type === ';') ?? null, source);
+			overlayNode.parameters = javaParameters(syntaxNode.childForFieldName('parameters'));
+			if (name !== undefined) {
+				// overloads share their name, e.g., `add(int, int)` and `add(double, double)`
+				overlayNode.label = `${name}(${overlayNode.parameters.map(p => p.type).join(', ')})`;
+			}
+			break;
+		}
+		case 'field_declaration': {
+			// e.g., `x` for `private int x, y;`
+			overlayNode.name = syntaxNode.childForFieldName('declarator')?.childForFieldName('name')?.text;
+			overlayNode.type = syntaxNode.childForFieldName('type')?.text;
+			break;
+		}
+		case 'enum_constant': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			break;
+		}
+		case 'package_declaration': {
+			// e.g., `com.example.math`
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'scoped_identifier' || c.type === 'identifier')?.text;
+			break;
+		}
+	}
+}
+
+const javaMemberTypes = new Set([
+	'class_declaration', 'interface_declaration', 'enum_declaration', 'record_declaration', 'annotation_type_declaration',
+	'method_declaration', 'constructor_declaration', 'field_declaration', 'constant_declaration', 'enum_constant',
+]);
+
+/**
+ * Types of the parents of top-level types and of members; a record's body is a `class_body`
+ */
+const javaMemberParentTypes = new Set(['program', 'class_body', 'interface_body', 'enum_body', 'enum_body_declarations', 'annotation_type_body']);
+
+/**
+ * @returns visibility of a type or member by its access modifier, or `package` if it has none; `undefined` for a class declared within a method
+ */
+function javaVisibilityOf(syntaxNode: SyntaxNode): OverlayNode['visibility'] {
+	const parent = syntaxNode.parent?.type;
+	if (!javaMemberTypes.has(syntaxNode.type) || parent === undefined || !javaMemberParentTypes.has(parent)) {
+		return undefined;
+	}
+	if (syntaxNode.type === 'enum_constant' || parent === 'interface_body' || parent === 'annotation_type_body') {
+		return 'public';
+	}
+	const modifier = syntaxNode.namedChildren.find(c => c.type === 'modifiers')?.children.find(c => c.type === 'public' || c.type === 'private' || c.type === 'protected');
+	return (modifier?.type as 'public' | 'private' | 'protected' | undefined) ?? 'package';
+}
+
+function isJavaAnnotation(node: SyntaxNode): boolean {
+	return node.type === 'annotation' || node.type === 'marker_annotation';
+}
+
+/**
+ * @returns text of a declaration up to where `end` starts like `textUpTo` does, but without its annotations
+ */
+function javaTextUpTo(node: SyntaxNode, end: SyntaxNode | null, source: string): string {
+	const modifiers = node.namedChildren.find(c => c.type === 'modifiers');
+	// annotations may be interspersed with modifiers, e.g., `public @Nullable String name()`, but rarely are
+	const start = modifiers?.children.find(c => !isJavaAnnotation(c))?.startIndex ?? modifiers?.nextSibling?.startIndex ?? node.startIndex;
+	return source.substring(start, end ? end.startIndex : node.endIndex).trimEnd();
+}
+
+/**
+ * @returns parameters of a method, constructor, or record without its receiver parameter, e.g., `Outer this`
+ */
+function javaParameters(parameters: SyntaxNode | null): { name?: string; type: string }[] {
+	return (parameters?.namedChildren ?? []).flatMap(p => {
+		switch (p.type) {
+			case 'formal_parameter': {
+				// e.g., `String[]` for `String args[]`, whose dimensions follow the name
+				const dimensions = p.childForFieldName('dimensions')?.text ?? '';
+				return [{ name: p.childForFieldName('name')?.text, type: `${p.childForFieldName('type')?.text ?? ''}${dimensions}` }];
+			}
+			case 'spread_parameter': {
+				const type = p.namedChildren.find(c => c.type !== 'modifiers' && c.type !== 'variable_declarator');
+				const declarator = p.namedChildren.find(c => c.type === 'variable_declarator');
+				return [{ name: declarator?.childForFieldName('name')?.text, type: `${type?.text ?? ''}...` }];
+			}
+			default:
+				return [];
+		}
+	});
+}
+
+/**
+ * Type declarations that are captured wherever they're declared, see `syntacticallyValidAtoms`
+ */
+const javaTypeDeclarations = new Set(['class_declaration', 'interface_declaration', 'enum_declaration', 'record_declaration', 'annotation_type_declaration']);
@@ -0,0 +1,138 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo } from './util';
+
+export const jsStructure: StructureLanguage = {
+	describe: describeJsNode,
+	nodeKindOf: (capture, nodeKind) => {
+		// kind `method_definition` named `constructor` -> kind `constructor`
+		if (nodeKind === 'method_definition' && capture.node.namedChildren.some(c => c.type === 'property_identifier' && c.text === 'constructor')) {
+			return 'constructor';
+		}
+		return undefined;
+	},
+	extentOf: capture => {
+		// decorators of a class member may be its preceding siblings, e.g., `@HostListener('click')` before a method
+		let first = capture.node;
+		while (first.previousSibling?.type === 'decorator') {
+			first = first.previousSibling;
+		}
+		return [first, capture.node];
+	},
+};
+
+export const tsxStructure: StructureLanguage = {
+	...jsStructure,
+	skipsCapture: (capture, options) => options?.includeJsxElements
+		? capture.name === 'jsx_expression' // e.g., `{items.map(...)}`, whose elements are nested in the enclosing element instead
+		: capture.name === 'jsx_self_closing_element', // e.g., `<br />`; elements with children are captured regardless
+	nodeKindOf: (capture, nodeKind, options) => {
+		// a `jsx_element` of a fragment, i.e., `<>...</>` -> kind `jsx_fragment`
+		if (options?.includeJsxElements && isJsxFragment(capture.node)) {
+			return 'jsx_fragment';
+		}
+		return jsStructure.nodeKindOf!(capture, nodeKind, options);
+	},
+};
+
+function describeJsNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	const visibility = jsVisibilityOf(syntaxNode, overlayNode);
+	if (visibility) {
+		overlayNode.visibility = visibility;
+	}
+	// depending on the grammar version, decorators of class members are children of the member or its preceding siblings
+	const decorators = syntaxNode.namedChildren.filter(c => c.type === 'decorator');
+	for (let prev = syntaxNode.previousNamedSibling; prev?.type === 'decorator'; prev = prev.previousNamedSibling) {
+		decorators.unshift(prev);
+	}
+	if (decorators.length > 0) {
+		// decorators of an `export_statement` come before those of the declaration it's merged with
+		overlayNode.decorators = [...overlayNode.decorators ?? [], ...decorators.map(d => d.text)];
+	}
+	if (overlayNode.kind === 'parameter_property') {
+		overlayNode.name = syntaxNode.childForFieldName('pattern')?.text;
+		overlayNode.detail = syntaxNode.text;
+	}
+	if (overlayNode.kind === 'arrow_function' || overlayNode.kind === 'function_expression') {
+		// e.g., `handler` for `const handler = () => { ... }` or `retry` for `setTimeout(function retry() { ... })`; callbacks are usually anonymous
+		const variable = syntaxNode.parent?.type === 'variable_declarator' ? syntaxNode.parent.childForFieldName('name') : null;
+		overlayNode.name = (variable?.type === 'identifier' ? variable : syntaxNode.childForFieldName('name'))?.text;
+		if (overlayNode.name === undefined) {
+			overlayNode.label = '<anonymous>';
+		}
+		overlayNode.detail = textUpTo(syntaxNode, syntaxNode.childForFieldName('body'), source);
+	}
+	if (syntaxNode.type === 'enum_assignment') {
+		overlayNode.name = enumMemberName(syntaxNode.childForFieldName('name'));
+		overlayNode.value = syntaxNode.childForFieldName('value')?.text;
+	}
+	if (overlayNode.kind === 'enum_member') {
+		overlayNode.name = enumMemberName(syntaxNode); // e.g., `Green` in `enum Color { Red = 1, Green }`
+	}
+	if (syntaxNode.type === 'jsx_element' || syntaxNode.type === 'jsx_self_closing_element') {
+		overlayNode.label = jsxElementLabel(syntaxNode);
+	}
+}
+
+const jsTopLevelDeclarationTypes = new Set([
+	'function_declaration', 'generator_function_declaration', 'function_signature', 'class_declaration', 'abstract_class_declaration',
+	'interface_declaration', 'type_alias_declaration', 'enum_declaration', 'lexical_declaration', 'variable_declaration', 'internal_module', 'module',
+]);
+
+/**
+ * @returns visibility of a top-level declaration by whether it's exported, or of a class member by its accessibility modifier
+ */
+function jsVisibilityOf(syntaxNode: SyntaxNode, overlayNode: OverlayNode): OverlayNode['visibility'] {
+	const parent = syntaxNode.parent?.type;
+	if (jsTopLevelDeclarationTypes.has(syntaxNode.type) && (parent === 'program' || parent === 'export_statement')) {
+		return parent === 'export_statement' ? 'public' : 'private';
+	}
+	if (parent === 'interface_body') {
+		return 'public';
+	}
+	if (parent === 'class_body' || overlayNode.kind === 'parameter_property') {
+		// a JS `field_definition` names its field by `property`
+		const name = syntaxNode.childForFieldName('name') ?? syntaxNode.childForFieldName('property');
+		if (name?.type === 'private_property_identifier') { // e.g., `#validate()` or `#count = 0`
+			return 'private';
+		}
+		const modifier = syntaxNode.namedChildren.find(c => c.type === 'accessibility_modifier')?.text;
+		return modifier === 'private' || modifier === 'protected' ? modifier : 'public';
+	}
+	return undefined;
+}
+
+/**
+ * @returns name of a TS enum member, which may be quoted, e.g., `Up` for `'Up' = 1`
+ */
+function enumMemberName(name: SyntaxNode | null): string | undefined {
+	return name?.type === 'string' ? name.text.slice(1, -1) : name?.text;
+}
+
+/**
+ * @returns label of a JSX element after its tag and its `key` and `id` props, e.g., `li key={item.id}`
+ */
+function jsxElementLabel(element: SyntaxNode): string {
+	const tag = element.type === 'jsx_self_closing_element' ? element : element.childForFieldName('open_tag');
+	const name = tag?.childForFieldName('name');
+	if (!tag || !name) {
+		return '<>';
+	}
+	const props = tag.namedChildren
+		.filter(c => c.type === 'jsx_attribute' && (c.namedChildren[0]?.text === 'key' || c.namedChildren[0]?.text === 'id'))
+		.map(c => c.text);
+	return [name.text, ...props].join(' ');
+}
+
+/**
+ * @returns whether `element` is a fragment, i.e., `<>...</>`, whose tags have no name
+ */
+function isJsxFragment(element: SyntaxNode): boolean {
+	return element.type === 'jsx_element' && element.childForFieldName('open_tag')?.childForFieldName('name') === null;
+}
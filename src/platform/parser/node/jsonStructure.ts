@@ -0,0 +1,57 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { keyPathWith, unquote } from './util';
+
+export const jsonStructure: StructureLanguage = {
+	describe: describeJsonNode,
+	// a value tree-sitter inserts to recover from a trailing comma, which jsonc allows, e.g., `["src", "test",]`
+	skipsCapture: capture => capture.node.isMissing,
+	keyPathOf: (_syntaxNode, overlayNode, parentPath) => keyPathWith(parentPath, overlayNode.name),
+};
+
+function describeJsonNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode): void {
+	switch (overlayNode.kind) {
+		case 'pair': {
+			const key = syntaxNode.childForFieldName('key');
+			overlayNode.name = key ? unquote(key.text) : undefined;
+			const value = syntaxNode.childForFieldName('value');
+			if (value && value.type !== 'object' && value.type !== 'array') {
+				overlayNode.value = value.text;
+			}
+			overlayNode.valueType = jsonValueTypeOf(value);
+			break;
+		}
+		case 'array_element': {
+			// comments, which jsonc allows, aren't elements
+			overlayNode.name = `[${syntaxNode.parent!.namedChildren.filter(c => c.type !== 'comment').findIndex(c => c.startIndex === syntaxNode.startIndex)}]`;
+			if (syntaxNode.type !== 'object' && syntaxNode.type !== 'array') {
+				overlayNode.value = syntaxNode.text;
+			}
+			overlayNode.valueType = jsonValueTypeOf(syntaxNode);
+			break;
+		}
+	}
+}
+
+const jsonValueTypes: { [nodeType: string]: NonNullable<OverlayNode['valueType']> } = {
+	object: 'object',
+	array: 'array',
+	string: 'string',
+	number: 'number',
+	true: 'boolean',
+	false: 'boolean',
+	null: 'null',
+};
+
+/**
+ * @returns type of a value, e.g., `boolean` for `false`; `undefined` if there's none, e.g., `"key":` in a document that's being edited
+ */
+function jsonValueTypeOf(value: SyntaxNode | null): OverlayNode['valueType'] {
+	return value ? jsonValueTypes[value.type] : undefined;
+}
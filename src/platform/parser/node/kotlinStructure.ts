@@ -0,0 +1,53 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo } from './util';
+
+export const kotlinStructure: StructureLanguage = {
+	describe: describeKotlinNode,
+	// kind `class_declaration` is used for classes and interfaces -> kind `interface_declaration` for interfaces
+	nodeKindOf: (capture, nodeKind) => nodeKind === 'class_declaration' && capture.node.children.some(c => c.type === 'interface') ? 'interface_declaration' : undefined,
+};
+
+function describeKotlinNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	// kotlin grammar has barely any fields, so children are looked up by their types
+	const body = syntaxNode.namedChildren.find(c => c.type === 'class_body' || c.type === 'enum_class_body' || c.type === 'function_body') ?? null;
+	switch (syntaxNode.type) {
+		case 'class_declaration': // also interfaces
+		case 'object_declaration': {
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'type_identifier')?.text;
+			overlayNode.detail = textUpTo(syntaxNode, body, source);
+			break;
+		}
+		case 'companion_object': {
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'type_identifier')?.text ?? 'Companion';
+			overlayNode.detail = textUpTo(syntaxNode, body, source);
+			break;
+		}
+		case 'function_declaration': {
+			const nameIdx = syntaxNode.namedChildren.findIndex(c => c.type === 'simple_identifier');
+			if (nameIdx === -1) {
+				break;
+			}
+			// extension functions are named after their receiver type to be distinguishable, e.g., `String.shout`
+			const receiver = syntaxNode.namedChildren.slice(0, nameIdx).find(c => c.type === 'user_type' || c.type === 'nullable_type' || c.type === 'parenthesized_type');
+			const name = syntaxNode.namedChildren[nameIdx].text;
+			overlayNode.name = receiver ? `${receiver.text}.${name}` : name;
+			overlayNode.detail = textUpTo(syntaxNode, body, source);
+			break;
+		}
+		case 'secondary_constructor': {
+			overlayNode.name = 'constructor';
+			break;
+		}
+		case 'property_declaration': {
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'variable_declaration')?.namedChildren.find(c => c.type === 'simple_identifier')?.text;
+			break;
+		}
+	}
+}
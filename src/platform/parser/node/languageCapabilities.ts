@@ -0,0 +1,56 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { getLanguageRegistration } from './languageRegistry';
+import { stringLiteralTypes } from './stringParsing';
+import { WASMLanguage } from './treeSitterLanguages';
+import { callReferenceQueries, docCommentQueries, foldingRangeQueries, importQueries, signatureQueries, syntacticallyValidAtoms, testableNodeQueries, testDeclarationQueries, typeDeclarationQuery } from './treeSitterQueries';
+
+/**
+ * Which features of the parser service are implemented for a language, e.g., to disable a feature in the UI instead of getting empty results;
+ * requests for a feature that isn't implemented for the language return what they return for an unsupported language, e.g., an empty array.
+ */
+export interface LanguageCapabilities {
+	/** `getStructure` and what builds on it, e.g., semantic chunks, symbols in a range, outlines, and extracting symbols */
+	readonly structure: boolean;
+	/** `getSignatures` and `getSkeleton` */
+	readonly signatures: boolean;
+	/** `getImports` */
+	readonly imports: boolean;
+	/** `getFoldingRanges` */
+	readonly folding: boolean;
+	/** `getTests`, i.e., the tests and suites of the language's test frameworks */
+	readonly tests: boolean;
+	/** `getTestableNode` and `getTestableNodes` */
+	readonly testableNodes: boolean;
+	/** `getNodeToDocument` and `getDocumentableNodeIfOnIdentifier` */
+	readonly docComments: boolean;
+	/** `getCallReferences` and `getCallGraph` */
+	readonly callReferences: boolean;
+	/** `getTypeDeclarations` */
+	readonly typeDeclarations: boolean;
+	/** `getStringLiterals` */
+	readonly stringLiterals: boolean;
+}
+
+/**
+ * @returns features implemented for `language`, derived from the queries and tables the features use, so that they can't get out of sync;
+ * a language registered with `registerLanguage` only brings a structure query, and an unknown language supports nothing
+ */
+export function _getLanguageCapabilities(language: WASMLanguage): LanguageCapabilities {
+	const hasQueries = (queries: { [language: string]: string[] | undefined }) => (queries[language]?.length ?? 0) > 0;
+	return {
+		structure: hasQueries(syntacticallyValidAtoms) || getLanguageRegistration(language) !== undefined,
+		signatures: hasQueries(signatureQueries),
+		imports: hasQueries(importQueries),
+		folding: hasQueries(foldingRangeQueries),
+		tests: hasQueries(testDeclarationQueries),
+		testableNodes: hasQueries(testableNodeQueries),
+		docComments: hasQueries(docCommentQueries),
+		callReferences: hasQueries(callReferenceQueries),
+		typeDeclarations: hasQueries(typeDeclarationQuery),
+		stringLiterals: stringLiteralTypes[language] !== undefined,
+	};
+}
@@ -3,38 +3,202 @@
  *  Licensed under the MIT License. See License.txt in the project root for license information.
  *--------------------------------------------------------------------------------------------*/
 
+import { IDisposable, toDisposable } from '../../../util/vs/base/common/lifecycle';
 import * as path from '../../../util/vs/base/common/path';
-import { WASMLanguage } from './treeSitterLanguages';
+import { describeGrammarSource, getLanguageRegistration, grammarSourceOf, takeValidatedGrammar } from './languageRegistry';
+import { compileQueries, deleteQueries } from './querying';
+import { TreeSitterUnknownLanguageError, WASMLanguage } from './treeSitterLanguages';
+import { syntacticallyValidAtoms } from './treeSitterQueries';
+import { grammarFilenameOf, vendoredGrammars } from './vendoredGrammars';
 import Parser = require('web-tree-sitter');
 
+/**
+ * Version of `@vscode/tree-sitter-wasm`, which the grammars that aren't vendored are loaded from, see {@link grammarVersionOf}.
+ */
+export const GRAMMARS_VERSION: string = require('@vscode/tree-sitter-wasm/package.json').version;
+
+/**
+ * @returns package and version the grammar of `language` is loaded from, which changes whenever the grammar is upgraded, e.g., to invalidate
+ * what was derived from parse trees of an older grammar
+ */
+export function grammarVersionOf(language: WASMLanguage): string {
+	const vendored = vendoredGrammars[language];
+	return vendored ? `${vendored.module}@${vendored.version}` : `@vscode/tree-sitter-wasm@${GRAMMARS_VERSION}`;
+}
+
+/**
+ * Thrown if the grammar of a known language can't be loaded, e.g., because reading its `.wasm` file failed, as opposed to
+ * a {@link TreeSitterUnknownLanguageError} for a language without a grammar.
+ */
+export class GrammarLoadError extends Error {
+	constructor(readonly language: WASMLanguage, readonly grammarPath: string, err: unknown) {
+		super(`Failed to load the grammar of ${language} from ${grammarPath}: ${err instanceof Error ? err.message : String(err)}`);
+	}
+}
+
+interface ResidentLanguage {
+	readonly language: Promise<Parser.Language>;
+	/** number of undisposed pins, see {@link LanguageLoader.pin} */
+	pins: number;
+}
+
+/**
+ * Loads grammars on first use and keeps what's created for the `maxResidentLanguages` most recently used ones, i.e., their compiled queries and idle parsers.
+ *
+ * @remarks web-tree-sitter can't free the memory of a loaded grammar, so the grammars themselves are kept, and one that's used again isn't loaded again;
+ * the parser service reclaims their memory by replacing its worker once it has loaded too many, see {@link loadedLanguageCount}.
+ */
 export class LanguageLoader {
 
-	private readonly loadedLanguagesCache;
+	/** Default number of grammars whose queries and idle parsers are kept */
+	static MAX_RESIDENT_LANGUAGES = 6;
 
-	constructor() {
-		this.loadedLanguagesCache = new Map<WASMLanguage, Promise<Parser.Language>>();
+	/** Number of times loading a grammar is attempted, e.g., to get past a transiently failing file read, before giving up */
+	static LOAD_ATTEMPTS = 2;
+
+	/** ordered from least to most recently used */
+	private readonly residentLanguages = new Map<WASMLanguage, ResidentLanguage>();
+
+	/** all grammars that were loaded successfully or are being loaded, resident or not */
+	private readonly loadedLanguages = new Map<WASMLanguage, Promise<Parser.Language>>();
+
+	/**
+	 * @param onDidUnload called for every grammar that's no longer resident to release what was created for it
+	 */
+	constructor(
+		private readonly maxResidentLanguages = LanguageLoader.MAX_RESIDENT_LANGUAGES,
+		private readonly onDidUnload?: (wasmLanguage: WASMLanguage) => void,
+	) {
+		if (maxResidentLanguages < 1) {
+			throw new Error(`maxResidentLanguages must be at least 1, got ${maxResidentLanguages}`);
+		}
 	}
 
+	/**
+	 * @throws {TreeSitterUnknownLanguageError} if there's no grammar for `wasmLanguage`, or a {@link GrammarLoadError} if loading it failed;
+	 * a failed load isn't remembered, i.e., the next use of the grammar tries again
+	 */
 	loadLanguage(wasmLanguage: WASMLanguage): Promise<Parser.Language> {
-		if (!this.loadedLanguagesCache.has(wasmLanguage)) {
-			this.loadedLanguagesCache.set(wasmLanguage, this._doLoadLanguage(wasmLanguage));
-		}
-		return this.loadedLanguagesCache.get(wasmLanguage)!;
+		return this.use(wasmLanguage).language;
+	}
+
+	/**
+	 * Keeps the grammar of `wasmLanguage` resident until the returned disposable is disposed, e.g., for the duration of a parse.
+	 * Pinned grammars stay resident even if more than `maxResidentLanguages` are.
+	 */
+	pin(wasmLanguage: WASMLanguage): IDisposable {
+		const resident = this.use(wasmLanguage);
+		resident.pins++;
+		let disposed = false;
+		return toDisposable(() => {
+			if (!disposed) {
+				disposed = true;
+				resident.pins--;
+				this.unloadExcess();
+			}
+		});
+	}
+
+	isResident(wasmLanguage: WASMLanguage): boolean {
+		return this.residentLanguages.has(wasmLanguage);
+	}
+
+	/** Number of grammars that were loaded or are being loaded, resident or not */
+	get loadedLanguageCount(): number {
+		return this.loadedLanguages.size;
 	}
 
-	private _doLoadLanguage(language: WASMLanguage): Promise<Parser.Language> {
-		// construct a path that works both for the TypeScript source, which lives under `/src`, and for
-		// the transpiled JavaScript, which lives under `/dist`
-		const wasmFileLang = language === 'csharp' ? 'c-sharp' : language;
+	private use(wasmLanguage: WASMLanguage): ResidentLanguage {
+		let resident = this.residentLanguages.get(wasmLanguage);
+		if (resident) {
+			// move to the end, i.e., make it the most recently used
+			this.residentLanguages.delete(wasmLanguage);
+		} else {
+			const loading: ResidentLanguage = { language: this._makeResident(wasmLanguage), pins: 0 };
+			loading.language.catch(() => {
+				if (this.residentLanguages.get(wasmLanguage) === loading) {
+					this.residentLanguages.delete(wasmLanguage);
+				}
+			});
+			resident = loading;
+		}
+		this.residentLanguages.set(wasmLanguage, resident);
+		this.unloadExcess();
+		return resident;
+	}
 
-		const wasmFilename = `tree-sitter-${wasmFileLang}.wasm`;
+	private unloadExcess() {
+		// the most recently used grammar stays resident, e.g., one that is about to be pinned
+		for (const [wasmLanguage, resident] of [...this.residentLanguages].slice(0, -1)) {
+			if (this.residentLanguages.size <= this.maxResidentLanguages) {
+				return;
+			}
+			if (resident.pins === 0) {
+				this.residentLanguages.delete(wasmLanguage);
+				resident.language.then(deleteQueries, () => { /* nothing was compiled for a grammar that failed to load */ });
+				this.onDidUnload?.(wasmLanguage);
+			}
+		}
+	}
 
-		// depending on if file is being run from the webpack bundle or source, change the relative path
-		const wasmFile =
-			path.basename(__dirname) === 'dist'
-				? path.resolve(__dirname, wasmFilename)
-				: path.resolve(__dirname, '../../../../dist', wasmFilename);
+	private async _makeResident(wasmLanguage: WASMLanguage): Promise<Parser.Language> {
+		let loaded = this.loadedLanguages.get(wasmLanguage);
+		if (!loaded) {
+			const loading = this._doLoadLanguage(wasmLanguage);
+			loading.catch(() => {
+				if (this.loadedLanguages.get(wasmLanguage) === loading) {
+					this.loadedLanguages.delete(wasmLanguage);
+				}
+			});
+			this.loadedLanguages.set(wasmLanguage, loading);
+			loaded = loading;
+		}
+		const grammar = await loaded;
+		const registration = getLanguageRegistration(wasmLanguage);
+		try {
+			// compiling the structure queries may take longer than parsing a small source, so it's done once the grammar is resident rather than on first use
+			compileQueries(grammar, registration ? [registration.structureQuery] : syntacticallyValidAtoms[wasmLanguage] ?? []);
+		} catch (err) {
+			console.error(err); // the structure of the language isn't available, but its grammar is
+		}
+		return grammar;
+	}
 
-		return Parser.Language.load(wasmFile);
+	private async _doLoadLanguage(language: WASMLanguage): Promise<Parser.Language> {
+		const registration = getLanguageRegistration(language);
+		if (!registration && !(Object.values(WASMLanguage) as string[]).includes(language)) {
+			throw new TreeSitterUnknownLanguageError(language);
+		}
+		const validated = registration && takeValidatedGrammar(language);
+		if (validated) {
+			return validated;
+		}
+		const grammarSource = registration ? grammarSourceOf(registration) : grammarPathOf(language);
+		let lastErr: unknown;
+		for (let attempt = 1; attempt <= LanguageLoader.LOAD_ATTEMPTS; attempt++) {
+			try {
+				return await Parser.Language.load(grammarSource);
+			} catch (err) {
+				lastErr = err;
+				if ((err as NodeJS.ErrnoException | undefined)?.code === 'ENOENT') {
+					break; // a missing file won't appear on its own
+				}
+			}
+		}
+		throw new GrammarLoadError(language, registration ? describeGrammarSource(registration) : grammarPathOf(language), lastErr);
 	}
 }
+
+/**
+ * @returns path of the bundled grammar of `language`
+ */
+export function grammarPathOf(language: WASMLanguage): string {
+	// construct a path that works both for the TypeScript source, which lives under `/src`, and for
+	// the transpiled JavaScript, which lives under `/dist`
+	const wasmFilename = grammarFilenameOf(language);
+
+	// depending on if file is being run from the webpack bundle or source, change the relative path
+	return path.basename(__dirname) === 'dist'
+		? path.resolve(__dirname, wasmFilename)
+		: path.resolve(__dirname, '../../../../dist', wasmFilename);
+}
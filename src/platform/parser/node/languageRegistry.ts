@@ -0,0 +1,128 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { TreeSitterQueryError } from './querying';
+import { WASMLanguage } from './treeSitterLanguages';
+import Parser = require('web-tree-sitter');
+
+/**
+ * Grammar that isn't bundled, e.g., of a language a fork of the extension supports, see {@link _registerLanguage}.
+ */
+export interface LanguageRegistration {
+	/**
+	 * Language to pass wherever a {@link WASMLanguage} is expected; mustn't be a built-in one.
+	 * @example `mylang`
+	 */
+	id: string;
+	/**
+	 * VS Code language IDs besides {@link id} that map to the grammar.
+	 */
+	aliases?: string[];
+	/**
+	 * File extensions of the language's files, e.g., `.mylang`, which the parser service resolves to the language, see `registerExtensionLanguage`.
+	 */
+	extensions?: string[];
+	/**
+	 * Absolute path of the grammar's `.wasm` file; exactly one of {@link wasmPath} and {@link wasmBytes} must be given.
+	 */
+	wasmPath?: string;
+	/**
+	 * Contents of the grammar's `.wasm` file, e.g., one that's downloaded or read from a virtual file system rather than from disk.
+	 */
+	wasmBytes?: Uint8Array;
+	/**
+	 * Query whose captured syntax nodes become the nodes of the structure of a source like those of the queries of `syntacticallyValidAtoms`.
+	 * @example `[(function_definition) (class_definition)] @declaration`
+	 */
+	structureQuery: string;
+}
+
+const registrations = new Map<string, LanguageRegistration>();
+
+/** grammars loaded to validate their registrations that the language loader hasn't taken yet, see {@link takeValidatedGrammar} */
+const validatedGrammars = new Map<string, Parser.Language>();
+
+/**
+ * @returns registration of a language registered with {@link _registerLanguage}; `undefined` for built-in and unknown languages
+ */
+export function getLanguageRegistration(language: string): LanguageRegistration | undefined {
+	return registrations.get(language);
+}
+
+/**
+ * @returns grammar of a registered language that was loaded to validate its registration, once, so that the first use of the language doesn't load it again;
+ * `undefined` if it was taken already
+ */
+export function takeValidatedGrammar(language: string): Parser.Language | undefined {
+	const grammar = validatedGrammars.get(language);
+	validatedGrammars.delete(language);
+	return grammar;
+}
+
+/**
+ * @returns what to load the grammar of a registered language from, i.e., its path or its bytes
+ */
+export function grammarSourceOf(registration: LanguageRegistration): string | Uint8Array {
+	return registration.wasmPath ?? registration.wasmBytes!;
+}
+
+/**
+ * @returns where the grammar of a registered language comes from for error messages, e.g., its path
+ */
+export function describeGrammarSource(registration: LanguageRegistration): string {
+	return registration.wasmPath ?? `${registration.wasmBytes!.byteLength} bytes of .wasm`;
+}
+
+function isSameGrammar(a: LanguageRegistration, b: LanguageRegistration): boolean {
+	if (a.wasmPath !== undefined || b.wasmPath !== undefined) {
+		return a.wasmPath === b.wasmPath;
+	}
+	const aBytes = a.wasmBytes!;
+	const bBytes = b.wasmBytes!;
+	return aBytes.byteLength === bBytes.byteLength && aBytes.every((byte, i) => byte === bBytes[i]);
+}
+
+/**
+ * Makes a grammar that isn't bundled available to the parser, i.e., its `id` can be used as a {@link WASMLanguage}, e.g., to get a structure
+ * or run a query. The grammar is loaded to validate it and the structure query, and the language loader uses it on first use of the language.
+ *
+ * Registering the same language again is a no-op, so that every worker can be given all registrations.
+ *
+ * @throws if `id` is a built-in language or already registered differently, if not exactly one of `wasmPath` and `wasmBytes` is given, if the grammar can't be loaded,
+ * or, as a {@link TreeSitterQueryError}, if the structure query doesn't compile
+ */
+export async function _registerLanguage(registration: LanguageRegistration): Promise<void> {
+	const { id, wasmPath, wasmBytes, structureQuery } = registration;
+	if ((Object.values(WASMLanguage) as string[]).includes(id)) {
+		throw new Error(`Cannot register ${id}: it's a built-in language`);
+	}
+	if ((wasmPath === undefined) === (wasmBytes === undefined)) {
+		throw new Error(`Cannot register ${id}: exactly one of wasmPath and wasmBytes must be given`);
+	}
+	const existing = registrations.get(id);
+	if (existing) {
+		if (isSameGrammar(existing, registration) && existing.structureQuery === structureQuery) {
+			return;
+		}
+		throw new Error(`Cannot register ${id}: it's already registered with a different grammar or structure query`);
+	}
+
+	await Parser.init();
+	let language: Parser.Language;
+	try {
+		language = await Parser.Language.load(grammarSourceOf(registration));
+	} catch (err) {
+		throw new Error(`Cannot register ${id}: failed to load its grammar from ${describeGrammarSource(registration)}: ${err instanceof Error ? err.message : String(err)}`);
+	}
+	try {
+		language.query(structureQuery).delete();
+	} catch (err) {
+		throw new TreeSitterQueryError(structureQuery, err);
+	}
+
+	// copied, so that changes of the caller's arrays don't change the registration
+	registrations.set(id, { ...registration, aliases: registration.aliases?.slice(), extensions: registration.extensions?.slice(), wasmBytes: wasmBytes?.slice() });
+	validatedGrammars.set(id, language);
+}
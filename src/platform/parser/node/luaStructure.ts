@@ -0,0 +1,62 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo } from './util';
+
+export const luaStructure: StructureLanguage = { describe: describeLuaNode };
+
+function describeLuaNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	switch (syntaxNode.type) {
+		case 'function_declaration': {
+			// e.g., `trim`, `M.greet`, or `Account:deposit`, which keeps the `:` of a method that takes `self` implicitly
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.detail = luaSignatureOf(syntaxNode, syntaxNode, source);
+			break;
+		}
+		case 'variable_declaration':
+		case 'assignment_statement': {
+			// `local x = 1` wraps an assignment, `local x` doesn't
+			const assignment = syntaxNode.type === 'variable_declaration' ? syntaxNode.namedChildren.find(c => c.type === 'assignment_statement') : syntaxNode;
+			const variables = (assignment ?? syntaxNode).namedChildren.find(c => c.type === 'variable_list')?.childrenForFieldName('name') ?? [];
+			// an assignment of several variables, e.g., `local a, b = 1, 2`, doesn't declare a single symbol
+			if (variables.length === 1) {
+				overlayNode.name = variables[0].text;
+			}
+			const fn = luaFunctionValueOf(syntaxNode);
+			overlayNode.detail = fn ? luaSignatureOf(syntaxNode, fn, source) : syntaxNode.text;
+			break;
+		}
+		case 'field': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			const fn = luaFunctionValueOf(syntaxNode);
+			overlayNode.detail = fn ? luaSignatureOf(syntaxNode, fn, source) : syntaxNode.text;
+			break;
+		}
+	}
+}
+
+/**
+ * @returns the function a variable, assignment, or table field is assigned; `undefined` if it's assigned something else or several values
+ */
+export function luaFunctionValueOf(syntaxNode: SyntaxNode): SyntaxNode | undefined {
+	if (syntaxNode.type === 'field') {
+		const value = syntaxNode.childForFieldName('value');
+		return value?.type === 'function_definition' ? value : undefined;
+	}
+	const assignment = syntaxNode.type === 'variable_declaration' ? syntaxNode.namedChildren.find(c => c.type === 'assignment_statement') : syntaxNode;
+	const values = assignment?.type === 'assignment_statement' ? assignment.namedChildren.find(c => c.type === 'expression_list')?.namedChildren ?? [] : [];
+	return values.length === 1 && values[0].type === 'function_definition' ? values[0] : undefined;
+}
+
+/**
+ * @returns text of `node` up to the end of the parameters of `fn`, e.g., `M.shout = function(text)`; the body of a function may be missing
+ */
+function luaSignatureOf(node: SyntaxNode, fn: SyntaxNode, source: string): string {
+	const parameters = fn.childForFieldName('parameters');
+	return parameters ? source.substring(node.startIndex, parameters.endIndex) : textUpTo(node, fn.childForFieldName('body'), source);
+}
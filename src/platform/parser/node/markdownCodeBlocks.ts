@@ -0,0 +1,98 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { extractCodeBlocks, mdCodeBlockLangToLanguageId } from '../../../util/common/markdown';
+import { LineCharacterPosition, OverlayNode, Utf8Offset } from './nodes';
+import { structureComputer, StructureOptions } from './structure';
+import { getWasmLanguage, WASMLanguage } from './treeSitterLanguages';
+
+export interface MarkdownCodeBlockStructure {
+	readonly language: WASMLanguage;
+	/** Offset of the code of the block in the Markdown document, i.e., of the line after the opening fence */
+	readonly startIndex: number;
+	/** Offset of the end of the code of the block, i.e., of the closing fence or the end of the document if the block isn't closed */
+	readonly endIndex: number;
+	/** Structure of the code of the block with offsets and positions within the Markdown document */
+	readonly structure: OverlayNode;
+}
+
+/**
+ * Computes the structure of each fenced code block of a Markdown document whose language tag is a {@link WASMLanguage},
+ * e.g., ```` ```go ```` or ```` ```ts ````; blocks without a language tag or with an unsupported one, e.g., ```` ```text ````, are skipped.
+ *
+ * @remarks Code of a block is parsed as it appears in the document, i.e., including the indentation of a block within a list item.
+ */
+export async function getMarkdownCodeBlockStructures(markdown: string, options?: StructureOptions): Promise<MarkdownCodeBlockStructure[]> {
+	const lineStarts = [0, ...Array.from(markdown.matchAll(/\r\n|\r|\n/g), m => m.index! + m[0].length)];
+	const positionAt = LineCharacterPosition.converterFor(markdown);
+	const byteOffsetAt = Utf8Offset.converterFor(markdown);
+
+	const result: MarkdownCodeBlockStructure[] = [];
+	for (const block of extractCodeBlocks(markdown)) {
+		const language = wasmLanguageOfTag(block.language);
+		if (language === undefined || block.startLine + 1 >= lineStarts.length) {
+			continue;
+		}
+		const startIndex = lineStarts[block.startLine + 1];
+		// `endLine` is the line after the closing fence, or after the last line if the block isn't closed
+		const lastLine = block.endLine - 1;
+		const isClosed = lastLine > block.startLine && isClosingFence(markdown.slice(lineStarts[lastLine], lineStarts[lastLine + 1] ?? markdown.length), block.startMarkup);
+		const endIndex = isClosed ? lineStarts[lastLine] : (lineStarts[block.endLine] ?? markdown.length);
+
+		const structure = await structureComputer.getStructure(language, markdown.slice(startIndex, endIndex), options);
+		if (structure) {
+			result.push({ language, startIndex, endIndex, structure: withOffset(structure, startIndex, positionAt, byteOffsetAt) });
+		}
+	}
+	return result;
+}
+
+/**
+ * @returns language of a code block's info string, e.g., `Go` for `go` or `golang`, ignoring what follows the tag, e.g., in `ts title="a.ts"`
+ */
+function wasmLanguageOfTag(info: string): WASMLanguage | undefined {
+	const tag = info.split(/\s+/)[0].toLowerCase();
+	if (tag === '') {
+		return undefined;
+	}
+	return getWasmLanguage(mdCodeBlockLangToLanguageId(tag) ?? tag);
+}
+
+function isClosingFence(line: string, startMarkup: string): boolean {
+	const fence = line.trim();
+	return fence.length >= startMarkup.length && fence === startMarkup[0].repeat(fence.length);
+}
+
+/**
+ * @returns copy of `structure` with offsets shifted by `offset` and positions and byte offsets computed with `positionAt` and `byteOffsetAt`;
+ * the cached structure itself is shared, so it mustn't be changed
+ */
+export function withOffset(structure: OverlayNode, offset: number, positionAt: (offset: number) => LineCharacterPosition, byteOffsetAt: (offset: number) => number): OverlayNode {
+	const copies = new Map<OverlayNode, OverlayNode>();
+	const copy = (node: OverlayNode): OverlayNode => {
+		const { startIndex, endIndex, children, ...details } = node;
+		const newNode = Object.assign(new OverlayNode(startIndex + offset, endIndex + offset, node.kind, children.map(copy)), details);
+		OverlayNode.locate(newNode, positionAt, byteOffsetAt);
+		copies.set(node, newNode);
+		return newNode;
+	};
+	const root = copy(structure);
+	// linked methods, deferred calls, companions, and annotations are nodes of the structure as well, so they're replaced by their copies
+	for (const newNode of copies.values()) {
+		if (newNode.methods) {
+			newNode.methods = newNode.methods.map(m => copies.get(m) ?? m);
+		}
+		if (newNode.defers) {
+			newNode.defers = newNode.defers.map(d => copies.get(d) ?? d);
+		}
+		if (newNode.companion) {
+			newNode.companion = copies.get(newNode.companion) ?? newNode.companion;
+		}
+		if (newNode.annotations) {
+			newNode.annotations = newNode.annotations.map(a => copies.get(a) ?? a);
+		}
+	}
+	return root;
+}
@@ -0,0 +1,171 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { TreeSitterOffsetRange } from './nodes';
+import { _parse } from './parserWithCaching';
+import { WASMLanguage } from './treeSitterLanguages';
+import { enclosingDeclarationTypes } from './treeSitterQueries';
+
+export interface EnclosingDeclaration extends TreeSitterOffsetRange {
+	/**
+	 * @example `MethodExample`
+	 */
+	name: string;
+	/**
+	 * Node type of the declaration
+	 * @example `method_declaration`
+	 */
+	kind: string;
+}
+
+export interface NodeContext {
+	enclosingFunction?: EnclosingDeclaration;
+	enclosingType?: EnclosingDeclaration;
+	enclosingNamespace?: EnclosingDeclaration;
+}
+
+/**
+ * Walks from the smallest node containing `offset` up to the root and collects the nearest named function, type, and namespace declarations.
+ *
+ * If `offset` is in whitespace between nodes, the context of the nearest preceding sibling is returned, or nothing if there's no such sibling.
+ */
+export async function _getNodeContext(language: WASMLanguage, source: string, offset: number): Promise<NodeContext> {
+	const treeRef = await _parse(language, source);
+
+	try {
+		const types = enclosingDeclarationTypes[language];
+		const rootNode = treeRef.tree.rootNode;
+
+		let enclosingFunction: EnclosingDeclaration | undefined;
+		let enclosingType: EnclosingDeclaration | undefined;
+		let enclosingNamespace: EnclosingDeclaration | undefined;
+
+		const startNode = nodeAt(rootNode, offset);
+		for (let node: SyntaxNode | null = startNode; node !== null; node = node.parent) {
+			if (!enclosingFunction && types.function.includes(node.type)) {
+				enclosingFunction = toEnclosingDeclaration(language, node);
+			}
+			if (!enclosingType && types.type.includes(node.type)) {
+				enclosingType = toEnclosingDeclaration(language, node);
+			}
+			if (!enclosingNamespace && types.namespace.includes(node.type)) {
+				enclosingNamespace = toEnclosingDeclaration(language, node);
+			}
+		}
+
+		// go: methods are declared outside of their receiver type, e.g., `func (s StructExample) MethodExample()`
+		if (language === WASMLanguage.Go && !enclosingType && enclosingFunction?.kind === 'method_declaration') {
+			enclosingType = goReceiverType(rootNode, startNode);
+		}
+
+		if (!enclosingNamespace && startNode !== null) {
+			const packageNode = rootNode.namedChildren.find(c => types.package.includes(c.type));
+			enclosingNamespace = packageNode ? toEnclosingDeclaration(language, packageNode) : undefined;
+		}
+
+		return {
+			...(enclosingFunction ? { enclosingFunction } : {}),
+			...(enclosingType ? { enclosingType } : {}),
+			...(enclosingNamespace ? { enclosingNamespace } : {}),
+		};
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+/**
+ * @returns smallest node containing `offset`; if `offset` is in whitespace between nodes, the last node of the nearest preceding sibling;
+ * `null` if there's no such sibling
+ */
+function nodeAt(rootNode: SyntaxNode, offset: number): SyntaxNode | null {
+	let node = rootNode.descendantForIndex(offset);
+	if (node.childCount === 0) {
+		if (node.text.trim() !== '') {
+			return node;
+		}
+		// whitespace tokens, e.g., Go's `\n` terminators, are treated like whitespace between nodes
+		node = node.parent ?? node;
+	}
+	const precedingSibling = node.namedChildren.filter(c => c.endIndex <= offset).at(-1);
+	if (precedingSibling === undefined) {
+		return node === rootNode ? null : node;
+	}
+	return rootNode.descendantForIndex(precedingSibling.endIndex - 1);
+}
+
+export function toEnclosingDeclaration(language: WASMLanguage, node: SyntaxNode): EnclosingDeclaration | undefined {
+	const name = declarationName(language, node);
+	// anonymous declarations, e.g., a callback, aren't what one would refer to as the enclosing declaration
+	return name === undefined ? undefined : { name, kind: node.type, startIndex: node.startIndex, endIndex: node.endIndex };
+}
+
+function declarationName(language: WASMLanguage, node: SyntaxNode): string | undefined {
+	const name = node.childForFieldName('name')?.text;
+	if (name !== undefined) {
+		return name;
+	}
+	switch (node.type) {
+		case 'arrow_function':
+		case 'function_expression':
+		case 'class': {
+			// named after the variable they're assigned to, e.g., `const foo = () => { ... }`
+			return node.parent?.type === 'variable_declarator' ? node.parent.childForFieldName('name')?.text : undefined;
+		}
+		case 'function_definition': {
+			// c++: the name is nested in declarators, e.g., `*Foo::bar(int)`
+			let declarator = node.childForFieldName('declarator');
+			while (declarator?.childForFieldName('declarator')) {
+				declarator = declarator.childForFieldName('declarator');
+			}
+			return declarator?.text;
+		}
+		case 'impl_item':
+			return node.childForFieldName('type')?.text;
+		case 'init_declaration':
+			return 'init';
+		case 'secondary_constructor':
+			return 'constructor';
+		case 'package_clause':
+			return node.namedChildren.find(c => c.type === 'package_identifier')?.text;
+		case 'package_declaration':
+			return node.namedChildren.find(c => c.type === 'scoped_identifier' || c.type === 'identifier')?.text;
+		case 'package_header':
+			return node.namedChildren.find(c => c.type === 'identifier')?.text;
+		case 'companion_object':
+			return node.namedChildren.find(c => c.type === 'type_identifier')?.text ?? 'Companion';
+	}
+	if (language === WASMLanguage.Kotlin) {
+		// kotlin grammar has barely any fields
+		return node.namedChildren.find(c => c.type === 'type_identifier' || c.type === 'simple_identifier')?.text;
+	}
+	return undefined;
+}
+
+/**
+ * @returns declaration of the receiver type of the Go method enclosing `node` if it's declared in the same source
+ */
+function goReceiverType(rootNode: SyntaxNode, node: SyntaxNode | null): EnclosingDeclaration | undefined {
+	let method = node;
+	while (method !== null && method.type !== 'method_declaration') {
+		method = method.parent;
+	}
+	const receiverType = method?.childForFieldName('receiver')?.namedChildren.find(c => c.type === 'parameter_declaration')?.childForFieldName('type');
+	if (!receiverType) {
+		return undefined;
+	}
+	// e.g., `*Stack[T]` -> `Stack`
+	const typeName = receiverType.text.replace(/^\*/, '').replace(/\[.*$/s, '');
+	for (const declaration of rootNode.namedChildren) {
+		if (declaration.type !== 'type_declaration') {
+			continue;
+		}
+		const spec = declaration.namedChildren.find(c => (c.type === 'type_spec' || c.type === 'type_alias') && c.childForFieldName('name')?.text === typeName);
+		if (spec) {
+			return toEnclosingDeclaration(WASMLanguage.Go, spec);
+		}
+	}
+	return undefined;
+}
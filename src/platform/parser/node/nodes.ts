@@ -4,9 +4,17 @@
  *--------------------------------------------------------------------------------------------*/
 
 import type { Point, SyntaxNode } from 'web-tree-sitter';
+import { findLastIdxMonotonous } from '../../../util/vs/base/common/arraysFind';
+import { CharCode } from '../../../util/vs/base/common/charCode';
 import { BugIndicatingError } from '../../../util/vs/base/common/errors';
 import { Range, Uri } from '../../../vscodeTypes';
+import type { CommentMarker } from './commentParsing';
+import type { SymbolKind } from './symbolKinds';
 
+/**
+ * Offsets are in UTF-16 code units, i.e., they index into the JS string that was parsed like `TextDocument.offsetAt` does,
+ * because web-tree-sitter parses JS strings as UTF-16; no translation from UTF-8 byte offsets is needed.
+ */
 export interface TreeSitterOffsetRange {
 	startIndex: number;
 	endIndex: number;
@@ -26,6 +34,27 @@ export interface TreeSitterExpressionLocationInfo extends TreeSitterOffsetRange
 	identifier?: string;
 }
 
+export interface TreeSitterQueryCapture extends TreeSitterOffsetRange {
+	/**
+	 * Capture name without the leading `@`
+	 * @example `identifier`
+	 */
+	name: string;
+	text: string;
+}
+
+export interface TreeSitterSyntaxError extends TreeSitterOffsetRange {
+	/**
+	 * `error` for tokens the grammar couldn't make sense of, i.e., an `ERROR` node, `missing` for a token the parser inserted to recover, i.e., a `MISSING` node,
+	 * whose range is empty
+	 */
+	kind: 'error' | 'missing';
+	/**
+	 * @example `missing "}"` or `unexpected "ch"`
+	 */
+	message: string;
+}
+
 export interface TreeSitterPoint {
 	row: number;
 	column: number;
@@ -36,6 +65,27 @@ export interface TreeSitterPointRange {
 	endPosition: TreeSitterPoint;
 }
 
+/**
+ * An edit of a source, expressed in both offsets and points like tree-sitter expects, to apply to its parse tree.
+ */
+export interface TreeSitterEdit {
+	startIndex: number;
+	oldEndIndex: number;
+	newEndIndex: number;
+	startPosition: TreeSitterPoint;
+	oldEndPosition: TreeSitterPoint;
+	newEndPosition: TreeSitterPoint;
+}
+
+/**
+ * Describes how a source was obtained from a previously parsed one, which allows parsing it incrementally.
+ */
+export interface TreeSitterSourceEdits {
+	previousSource: string;
+	/** Edits in the order they were applied to `previousSource` */
+	edits: readonly TreeSitterEdit[];
+}
+
 /** Util functions to deal with `TreeSitterOffsetRange` type */
 export const TreeSitterOffsetRange = {
 	/** check if `container` contains `containee` (non-strict, ie [0, 3] contains [0, 3] */
@@ -68,6 +118,27 @@ export const TreeSitterOffsetRange = {
 	},
 };
 
+export interface NodeTextOptions {
+	/**
+	 * Whitespace to trim from the text, e.g., `true` for the blank line a structure node extends to; nothing is trimmed by default
+	 */
+	readonly trim?: boolean | 'start' | 'end';
+}
+
+/**
+ * Get the text of a node, e.g., a syntax node, a structure node, or a capture, in the source it was parsed from.
+ * Its offsets are UTF-16 offsets into `text`, not UTF-8 byte offsets, so the text of a node after emoji or CJK text isn't shifted; see {@link TreeSitterOffsetRange}.
+ */
+export function getNodeText(text: string, node: TreeSitterOffsetRange, options?: NodeTextOptions): string {
+	const nodeText = text.substring(node.startIndex, node.endIndex);
+	switch (options?.trim) {
+		case true: return nodeText.trim();
+		case 'start': return nodeText.trimStart();
+		case 'end': return nodeText.trimEnd();
+		default: return nodeText;
+	}
+}
+
 export const TreeSitterPoint = {
 
 	isEqual(n: TreeSitterPoint, other: TreeSitterPoint): boolean {
@@ -125,6 +196,93 @@ export const TreeSitterPointRange = {
 	}),
 };
 
+/**
+ * Zero-based position like `vscode.Position`, i.e., `character` is in UTF-16 code units.
+ */
+export interface LineCharacterPosition {
+	line: number;
+	character: number;
+}
+
+export const LineCharacterPosition = {
+
+	/**
+	 * @returns a function converting offsets into `source` to positions; line starts are computed once, so each conversion is a binary search over them,
+	 * i.e., logarithmic in the number of lines rather than linear in the offset, which matters for long minified lines
+	 *
+	 * @remarks Like `vscode.TextDocument.positionAt`, `\r\n`, `\n`, and `\r` are line breaks and an offset within a line break
+	 * is at the end of its line, e.g., the offset of `\n` in `\r\n`.
+	 */
+	converterFor(source: string): (offset: number) => LineCharacterPosition {
+		const lineStarts = [0];
+		// offsets of the line breaks; the last line ends with the source
+		const lineEnds: number[] = [];
+		for (let i = 0; i < source.length; i++) {
+			const char = source.charCodeAt(i);
+			if (char === CharCode.CarriageReturn || char === CharCode.LineFeed) {
+				lineEnds.push(i);
+				if (char === CharCode.CarriageReturn && source.charCodeAt(i + 1) === CharCode.LineFeed) {
+					i++;
+				}
+				lineStarts.push(i + 1);
+			}
+		}
+		lineEnds.push(source.length);
+
+		return offset => {
+			const line = Math.max(findLastIdxMonotonous(lineStarts, start => start <= offset), 0);
+			return { line, character: Math.min(offset, lineEnds[line]) - lineStarts[line] };
+		};
+	},
+};
+
+export const Utf8Offset = {
+
+	/**
+	 * @returns a function converting UTF-16 offsets into `source`, e.g., {@link OverlayNode.startIndex}, to UTF-8 byte offsets;
+	 * byte offsets of checkpoints are computed once, so each conversion only counts the bytes since the last checkpoint
+	 *
+	 * @remarks A surrogate pair has 4 bytes, which are counted at its first code unit.
+	 */
+	converterFor(source: string): (offset: number) => number {
+		const checkpointInterval = 256;
+		// `checkpoints[k]` is the byte length of `source.substring(0, k * checkpointInterval)`
+		const checkpoints = [0];
+		let byteLength = 0;
+		for (let i = 0; i < source.length; i++) {
+			if (i > 0 && i % checkpointInterval === 0) {
+				checkpoints.push(byteLength);
+			}
+			byteLength += utf8Length(source.charCodeAt(i));
+		}
+
+		return offset => {
+			const checkpoint = Math.min(Math.floor(offset / checkpointInterval), checkpoints.length - 1);
+			let byteOffset = checkpoints[checkpoint];
+			for (let i = checkpoint * checkpointInterval; i < offset && i < source.length; i++) {
+				byteOffset += utf8Length(source.charCodeAt(i));
+			}
+			return byteOffset;
+		};
+	},
+};
+
+function utf8Length(charCode: number): number {
+	if (charCode < 0x80) {
+		return 1;
+	}
+	if (charCode < 0x800) {
+		return 2;
+	}
+	if (charCode >= 0xD800 && charCode <= 0xDBFF) {
+		return 4; // high surrogate, i.e., the first code unit of a pair
+	}
+	if (charCode >= 0xDC00 && charCode <= 0xDFFF) {
+		return 0; // low surrogate, which is counted with its high surrogate
+	}
+	return 3;
+}
+
 export interface Node extends TreeSitterOffsetRange {
 	type: string;
 }
@@ -155,6 +313,331 @@ export const TreeSitterChunkHeaderInfo = {
  * Represents a node in the overlay tree.
  */
 export class OverlayNode {
+
+	/**
+	 * Name of the declared symbol; `undefined` if the node doesn't declare a (named) symbol.
+	 * For JSON and YAML, it's the key of a `pair` or the index of an `array_element`, e.g., `[0]`.
+	 * @example `StructExample`
+	 */
+	public name?: string;
+
+	/**
+	 * Language-agnostic kind of the declared symbol, see `symbolKindsByNodeKind`; `undefined` if the node doesn't declare a symbol, e.g., a statement.
+	 * @example `SymbolKind.Struct` for Go's `type StructExample struct { ... }`
+	 */
+	public symbolKind?: SymbolKind;
+
+	/**
+	 * Declaration header, i.e., the declaration without its body.
+	 * @example `func Map[T any, U any](s []T, f func(T) U) []U`
+	 */
+	public detail?: string;
+
+	/**
+	 * Comment run directly preceding the declaration, i.e., its documentation.
+	 * @example `// StructExample holds a name.`
+	 */
+	public leadingComment?: string;
+
+	/**
+	 * Type parameter list of a generic declaration as written in source.
+	 * @example `[K comparable, V Number]`
+	 */
+	public typeParameters?: string;
+
+	/**
+	 * Type of a field, constant, variable, or SQL column as written in source; for a Go type declaration, the underlying type or the aliased type.
+	 * @example `map[string]int`
+	 */
+	public type?: string;
+
+	/**
+	 * Category of the type of a Go variable as far as its declaration tells syntactically, i.e., from its type if it's written
+	 * or else from its value, e.g., a composite literal or `make(chan int)`; `undefined` if neither tells, e.g., for `n := len(s)`,
+	 * or if the declaration declares multiple names. A defined type, e.g., `StructExample`, is `named` regardless of its underlying type.
+	 * @example `slice` for `sliceExample := arrayExample[:2]`
+	 */
+	public typeCategory?: 'array' | 'slice' | 'map' | 'chan' | 'pointer' | 'struct' | 'func' | 'named';
+
+	/**
+	 * `true` for a Go type alias, i.e., `type MyInt = int`, as opposed to a defined type, i.e., `type MyCount int`.
+	 */
+	public isAlias?: boolean;
+
+	/**
+	 * `true` for a C/C++ declaration without a body, e.g., the prototype `int add(int a, int b);` or `struct node;`,
+	 * as opposed to the definition that comes with its body.
+	 */
+	public isForwardDeclaration?: boolean;
+
+	/**
+	 * `true` for a declaration that the end of the source cuts off, e.g., the last function of a file that was only partly downloaded,
+	 * whose closing `}` is missing; its range and children are those of what's there.
+	 */
+	public isIncomplete?: boolean;
+
+	/**
+	 * Number of a Protobuf field, which identifies it in the binary encoding.
+	 * @example `3` for `repeated Item items = 3;`
+	 */
+	public fieldNumber?: number;
+
+	/**
+	 * Initializer of a constant or variable as written in source, the explicit value of an enum member, or the value of a JSON, YAML, or TOML key or array element if it's a scalar, or of an INI setting.
+	 * @example `1 << (10 * iota)` or `0x10` for `Bold = 0x10`
+	 */
+	public value?: string;
+
+	/**
+	 * Type of the value of a JSON key or array element; comments and trailing commas, which jsonc allows, don't change it.
+	 * @example `object` for `"compilerOptions": { ... }` or `boolean` for `"strict": true`
+	 */
+	public valueType?: 'object' | 'array' | 'string' | 'number' | 'boolean' | 'null';
+
+	/**
+	 * Keys from the root of a JSON, YAML, TOML, or INI document to a key or array element, whose indices are written like its {@link name};
+	 * a dotted TOML key has a key per part, and an INI setting is keyed by its section. `undefined` if a key on the way isn't a scalar, e.g., YAML's `? [a, b]`.
+	 * @example `['jobs', 'build', 'steps', '[0]', 'uses']` or `['tool', 'poetry', 'name']` for `name = "app"` within `[tool.poetry]`
+	 */
+	public keyPath?: string[];
+
+	/**
+	 * `true` for a Go constant whose value is derived from `iota`, i.e., the constants of its group are sequential.
+	 */
+	public usesIota?: boolean;
+
+	/**
+	 * Value of a Go constant derived from `iota` if it's a simple integer expression, see {@link usesIota}; `undefined` for blank constants, i.e., `_`,
+	 * and expressions that can't be evaluated without type checking, e.g., ones that refer to other constants.
+	 * @example `13` for `D` in `const ( A = iota; B; C; D = iota + 10 )`
+	 */
+	public computedValue?: number;
+
+	/**
+	 * Struct tag of a field as written in source, i.e., including its quotes.
+	 * @example `` `json:"name,omitempty" db:"name"` ``
+	 */
+	public tag?: string;
+
+	/**
+	 * Imported path of an import, or path of a script included by a bash `source_command`, without quotes.
+	 * @example `net/http`
+	 */
+	public path?: string;
+
+	/**
+	 * Name an import is bound to, including `.` for dot imports and `_` for blank imports.
+	 * @example `f` in `import f "fmt"`
+	 */
+	public alias?: string;
+
+	/**
+	 * What a function is run as by the language's test tooling, e.g., `go test`.
+	 * @example `benchmark` for `func BenchmarkSum(b *testing.B)`
+	 */
+	public role?: 'test' | 'benchmark' | 'example' | 'fuzz';
+
+	/**
+	 * Decorators of the declaration in source order.
+	 * @example `['@Component({ selector: \'app-root\' })']`
+	 */
+	public decorators?: string[];
+
+	/**
+	 * Receiver of a method with its type name stripped of type arguments.
+	 * @example `{ typeName: 'Stack', isPointer: true }` for `func (s *Stack[T]) Push(v T)`
+	 */
+	public receiver?: { typeName: string; isPointer: boolean };
+
+	/**
+	 * Scope of a C++ function defined outside of its class or namespace as written, see {@link methods}.
+	 * @example `Shape` for `double Shape::area() const { ... }`
+	 */
+	public qualifier?: string;
+
+	/**
+	 * Parameters of a Go function or method, arguments of a GraphQL field, or the request of a Protobuf `rpc`, in source order with their types as written;
+	 * grouped parameters, e.g., `a, b int`, are listed one by one.
+	 * @example `[{ name: 'format', type: 'string' }, { name: 'args', type: '...any' }]` for `func Printf(format string, args ...any)`
+	 */
+	public parameters?: { name?: string; type: string }[];
+
+	/**
+	 * Results of a Go function or method in source order like {@link parameters}, or the response of a Protobuf `rpc`; unnamed results have no names.
+	 * @example `[{ name: 'n', type: 'int' }, { name: 'err', type: 'error' }]` for `func Write(p []byte) (n int, err error)`
+	 */
+	public results?: { name?: string; type: string }[];
+
+	/**
+	 * Superclass of a class as written.
+	 * @example `ApplicationRecord` for Ruby's `class Invoice < ApplicationRecord`
+	 */
+	public superclass?: string;
+
+	/**
+	 * Interfaces implemented by a class or extended by an interface as written.
+	 * @example `['JsonSerializable', 'Countable']` for PHP's `class Cart extends Model implements JsonSerializable, Countable`
+	 */
+	public interfaces?: string[];
+
+	/**
+	 * Operation of a GraphQL object type that's a root operation type, i.e., whose fields are those of queries, mutations, or subscriptions,
+	 * as declared by the `schema` definition or, without one, by the type's name, e.g., `type Query { ... }`.
+	 */
+	public operationType?: 'query' | 'mutation' | 'subscription';
+
+	/**
+	 * Trait implemented by a Rust `impl` block, which is noted on the block and its functions.
+	 * @example `fmt::Display` for `impl fmt::Display for Day`
+	 */
+	public trait?: string;
+
+	/**
+	 * Accessors of a C# property with their modifiers but without their bodies; an auto-property's accessors have no bodies either way.
+	 * @example `['get', 'private set']` for `public decimal Total { get; private set; }`
+	 */
+	public accessors?: string[];
+
+	/**
+	 * Methods declared for the type in the same source, e.g., Go's `func (p Point) String()` for `type Point struct { ... }`
+	 * or the functions of Rust's `impl Point { ... }` for `struct Point { ... }`, or C++'s `double Shape::area() const { ... }` for `class Shape { ... }`.
+	 * They aren't {@link children} because they're declared outside the range of the type, unless Go methods are nested with `nestGoMethods`.
+	 * For a Go embedded interface, these are the methods of the interface it refers to, if the structure is computed with `inlineEmbeddedInterfaces`.
+	 */
+	public methods?: OverlayNode[];
+
+	/**
+	 * Companion object of a Scala class or trait, i.e., the object of the same name declared next to it, e.g., `object Circle { ... }` for `case class Circle(radius: Double)`.
+	 * It's only set on the class or trait, not on the object, so that structures stay acyclic.
+	 */
+	public companion?: OverlayNode;
+
+	/**
+	 * Deferred calls of a Go function or method in source order, including those within nested blocks but not those of function literals,
+	 * i.e., the reverse of the order they run in when the function returns. They're {@link children} of the statements they're in as well.
+	 */
+	public defers?: OverlayNode[];
+
+	/**
+	 * Label a Go `break`, `continue`, or `goto` statement jumps to and where the statement with that label starts, i.e., the {@link startIndex}
+	 * of its `labeled_statement` node, within the same function; unresolved, i.e., without `startIndex`, if there's no such label,
+	 * or, for `break` and `continue`, if the labeled statement doesn't enclose the statement, which the compiler rejects. Unset for statements without a label.
+	 * @example `{ label: 'Outer', isResolved: true, startIndex: 120 }` for `continue Outer` within `Outer: for ...`
+	 */
+	public jumpTarget?: { label: string; isResolved: boolean; startIndex?: number };
+
+	/**
+	 * Marker and trailing text of a `todo_annotation` node, i.e., of a comment that starts with a marker like `CommentNode.marker` does,
+	 * if the structure is computed with `includeTodoComments`; the text is the rest of the marker's line.
+	 * @example `{ marker: 'TODO', text: 'handle empty name' }` for `// TODO: handle empty name`
+	 */
+	public annotation?: { marker: CommentMarker; text: string };
+
+	/**
+	 * `todo_annotation` nodes of the comments directly above a declaration in source order, i.e., those whose next sibling, not counting comments, is the declaration.
+	 * They're nodes among the declaration's siblings as well.
+	 */
+	public annotations?: OverlayNode[];
+
+	/**
+	 * Function called by a Go `defer` or `go` statement as written; `undefined` if it's a function literal.
+	 * @example `f.Close` for `defer f.Close()`
+	 */
+	public callee?: string;
+
+	/**
+	 * Go directives of a declaration, i.e., the `//go:` comments that directly precede it, in source order;
+	 * for the root, the build constraints of the file and the directives that don't precede a declaration.
+	 * @example `['//go:embed static/*']` for a variable or `['//go:build linux']` for the root
+	 */
+	public directives?: string[];
+
+	/**
+	 * Lint suppressions of a Go declaration, i.e., `//nolint` or staticcheck's `//lint:ignore` comments on the line above it or trailing its first line,
+	 * with the linters they suppress; the linters of a bare `//nolint`, which suppresses all of them, are empty.
+	 * @example `[{ text: '//nolint:govet,errcheck', linters: ['govet', 'errcheck'] }]`
+	 */
+	public lintDirectives?: { text: string; linters: string[] }[];
+
+	/**
+	 * Package of a Go source as declared by its `package` clause, which is only set for the root.
+	 * @example `main` for `package main`
+	 */
+	public packageName?: string;
+
+	/**
+	 * Whether captures nested deeper than `StructureOptions.maxDepth` were skipped, which is only set for the root;
+	 * e.g., a generated file of deeply nested arrays exceeds the default limit.
+	 */
+	public isTruncated?: boolean;
+
+	/**
+	 * Name qualified with those of the enclosing namespaces, modules, and types, and with the {@link packageName} of a Go source, which are joined
+	 * with the separator of the language, e.g., `::` for C++; a Go method's is qualified with its receiver type.
+	 * @example `main.StructExample.MethodExample` for `func (s StructExample) MethodExample()` or `Shop.Orders.Order` for `class Order` within `namespace Shop.Orders`
+	 */
+	public qualifiedName?: string;
+
+	/**
+	 * ID of the node that doesn't depend on offsets, so that it stays the same across reparses unless the node's kind or qualified name changes,
+	 * e.g., to key what's cached per symbol; it's made of the kind and the {@link qualifiedName}, or the parent's ID for an unnamed node,
+	 * and the number of its occurrence if it's repeated, e.g., for overloads. The root has none.
+	 * @example `type_declaration:main.StructExample` or `method_declaration:main.StructExample.MethodExample`
+	 */
+	public stableId?: string;
+
+	/**
+	 * Whether a named top-level Go declaration is exported, i.e., its name starts with an upper-case letter.
+	 */
+	public exported?: boolean;
+
+	/**
+	 * Visibility of a declaration, e.g., to describe only the public API of a file:
+	 * - Go: `public` for names starting with an upper-case letter and `private` for others, for declarations outside of function bodies
+	 * - JS/TS: `public` for exported top-level declarations and `private` for others; members of classes by their accessibility modifiers, `#` names are `private`
+	 * - Java: by the access modifier, or `package` without one; members of interfaces and enum constants are `public`
+	 * - C#: by the access modifiers, or `internal` for top-level types and `private` for members without one; members of interfaces and enums are `public`
+	 * - Ruby: only for methods that aren't public, as set by the last preceding `private` or `protected` line of their class or module body,
+	 * by a modifier before their definition, e.g., `private def helper`, or by a symbol argument, e.g., `private :helper`
+	 */
+	public visibility?: 'public' | 'private' | 'protected' | 'internal' | 'package';
+
+	/**
+	 * Name of the innermost named declaration of a function, type, or namespace that encloses a closure, i.e., a Go function literal
+	 * or a JS/TS callback, like `getEnclosingSymbol` yields for an offset within it; `undefined` if there's none, e.g., for a package-level variable.
+	 * Unlike parent pointers, it survives the structure being sent from the parser worker.
+	 * @example `main` for `go func() { ... }()` within `func main()` or `Start` for a closure within `func (s *Server) Start()`
+	 */
+	public enclosingSymbol?: string;
+
+	/**
+	 * Label of an anonymous declaration, which has no {@link name}, e.g., of a Go function literal that isn't assigned to a variable
+	 * or `<anonymous>` for a JS/TS callback; for a Java method or constructor, its name with its parameter types, which tells overloads apart;
+	 * for a Go `case` or `default` clause, its case; for a Go labeled statement, its label; for a SQL statement, its kind; for an R S4 method, its generic with its signature;
+	 * for an Objective-C method, its selector after its `-` or `+`, which tells instance and class methods apart.
+	 * @example `main.func1` for `sort.Slice(s, func(i, j int) bool { ... })` within `func main()`, `add(int, int)` for `int add(int a, int b)`, `case 0`, or `CREATE TABLE`
+	 */
+	public label?: string;
+
+	/**
+	 * Positions of {@link startIndex} and {@link endIndex} computed where the source is at hand, so that callers don't need to convert offsets,
+	 * e.g., to create a `vscode.Range`; characters are in UTF-16 code units and `\r\n` is a single line break, see {@link LineCharacterPosition}.
+	 */
+	public range?: { start: LineCharacterPosition; end: LineCharacterPosition };
+
+	/**
+	 * UTF-8 byte offsets of {@link startIndex} and {@link endIndex}, which are UTF-16 offsets, e.g., for tools that address sources by bytes.
+	 * @example `{ start: 5, end: 9 }` for the range `[4, 6)` of `é = 😀`, i.e., for the emoji
+	 */
+	public byteRange?: { start: number; end: number };
+
+	/**
+	 * Syntax node the node was computed from, whose range may be smaller than the node's, e.g., without the trailing line break,
+	 * or larger, e.g., for an `export_statement` merged with its declaration; see `getSyntaxNode` to resolve it in a parse tree of the same source.
+	 * The id is only valid within the tree the structure was computed from, so the type and range identify the node in other trees.
+	 */
+	public syntaxNode?: { id: number; type: string; startIndex: number; endIndex: number };
+
 	constructor(
 		public readonly startIndex: number,
 		public readonly endIndex: number,
@@ -179,6 +662,14 @@ export class OverlayNode {
 		}
 	}
 
+	/**
+	 * Sets {@link range} and {@link byteRange} of `node` from its offsets.
+	 */
+	static locate(node: OverlayNode, positionAt: (offset: number) => LineCharacterPosition, byteOffsetAt: (offset: number) => number): void {
+		node.range = { start: positionAt(node.startIndex), end: positionAt(node.endIndex) };
+		node.byteRange = { start: byteOffsetAt(node.startIndex), end: byteOffsetAt(node.endIndex) };
+	}
+
 	toString() {
 		const printedNodes: string[] = [];
 		function toString(node: OverlayNode, indent = '') {
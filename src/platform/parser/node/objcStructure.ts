@@ -0,0 +1,109 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { describeCNode } from './cStructure';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo } from './util';
+
+export const objcStructure: StructureLanguage = { describe: describeObjcNode };
+
+function describeObjcNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	switch (syntaxNode.type) {
+		case 'class_interface':
+		case 'class_implementation':
+		case 'protocol_declaration': {
+			// e.g., `@interface Widget : NSObject <NSCopying>` or `@interface Widget (Drawing)` of a category, without the members
+			const header = objcHeaderOf(syntaxNode);
+			overlayNode.name = objcNameOf(syntaxNode)?.text;
+			overlayNode.detail = source.substring(syntaxNode.startIndex, header[header.length - 1].endIndex);
+			const colon = header.findIndex(c => c.type === ':');
+			const superclass = colon === -1 ? undefined : header.slice(colon + 1).find(c => c.isNamed);
+			overlayNode.superclass = superclass && objcNameOf(superclass)?.text;
+			const protocols = header.find(c => c.type === 'protocol_reference_list')?.namedChildren.map(c => c.text);
+			if (protocols?.length) {
+				overlayNode.interfaces = protocols;
+			}
+			break;
+		}
+		case 'method_declaration':
+		case 'method_definition': {
+			// instance and class methods are named by their selectors and told apart by their labels, e.g., `-initWithName:count:`
+			overlayNode.name = objcSelectorOf(syntaxNode);
+			overlayNode.label = `${syntaxNode.text.startsWith('+') ? '+' : '-'}${overlayNode.name}`;
+			overlayNode.type = objcMethodTypeText(syntaxNode.namedChildren.find(c => c.type === 'method_type'));
+			overlayNode.parameters = syntaxNode.namedChildren.filter(c => c.type === 'method_parameter').map(p => ({
+				name: p.namedChildren.filter(c => c.type === 'identifier').pop()?.text,
+				type: objcMethodTypeText(p.namedChildren.find(c => c.type === 'method_type')) ?? 'id', // untyped parameters are objects
+			}));
+			const body = syntaxNode.namedChildren.find(c => c.type === 'compound_statement') ?? null;
+			overlayNode.detail = textUpTo(syntaxNode, body, source).replace(/;$/, '');
+			break;
+		}
+		case 'property_declaration': {
+			// e.g., `name` for `@property (nonatomic, copy) NSString *name;`
+			const declaration = syntaxNode.namedChildren.filter(c => c.type !== 'property_attributes_declaration').pop();
+			const names = declaration?.descendantsOfType(['field_identifier', 'identifier']) ?? [];
+			overlayNode.name = names[names.length - 1]?.text;
+			overlayNode.type = declaration?.childForFieldName('type')?.text;
+			overlayNode.detail = syntaxNode.text.replace(/;$/, '');
+			break;
+		}
+		default:
+			// functions, structs, and the like are declared as in C
+			describeCNode(syntaxNode, overlayNode, source);
+	}
+}
+
+/**
+ * Node types of the head of an `@interface`, `@implementation`, or `@protocol`, i.e., its name, superclass, category, and protocols
+ */
+const objcHeaderTypes = new Set(['identifier', 'type_identifier', ':', '(', ')', 'superclass_reference', 'protocol_reference_list', 'parameterized_arguments']);
+
+function objcHeaderOf(syntaxNode: SyntaxNode): SyntaxNode[] {
+	const header = [syntaxNode.children[0]]; // `@interface`, `@implementation`, or `@protocol`
+	for (const child of syntaxNode.children.slice(1)) {
+		if (!objcHeaderTypes.has(child.type)) {
+			break;
+		}
+		header.push(child);
+	}
+	return header;
+}
+
+function objcNameOf(syntaxNode: SyntaxNode): SyntaxNode | undefined {
+	if (syntaxNode.type === 'identifier' || syntaxNode.type === 'type_identifier') {
+		return syntaxNode;
+	}
+	return syntaxNode.childForFieldName('name') ?? syntaxNode.namedChildren.find(c => c.type === 'identifier' || c.type === 'type_identifier');
+}
+
+/**
+ * @returns selector of a method, i.e., the keywords of its parameters, each followed by a colon, e.g., `initWithName:count:`, or its name if it takes none
+ */
+function objcSelectorOf(method: SyntaxNode): string | undefined {
+	let keyword: string | undefined;
+	let selector = '';
+	for (const child of method.namedChildren) {
+		if (child.type === 'identifier') {
+			keyword = child.text;
+		} else if (child.type === 'method_parameter') {
+			// a keyword may be omitted, e.g., `:` in `setPoint:(int)x :(int)y`
+			selector += `${keyword ?? ''}:`;
+			keyword = undefined;
+		} else if (child.type === 'compound_statement') {
+			break;
+		}
+	}
+	return selector || keyword;
+}
+
+/**
+ * @returns type of a method or one of its parameters without the parentheses around it, e.g., `NSString *` for `(NSString *)`
+ */
+function objcMethodTypeText(methodType: SyntaxNode | undefined): string | undefined {
+	return methodType?.text.replace(/^\(\s*|\s*\)$/g, '');
+}
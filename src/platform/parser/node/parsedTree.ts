@@ -0,0 +1,136 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { IDisposable } from '../../../util/vs/base/common/lifecycle';
+import { TreeSitterOffsetRange } from './nodes';
+import { _parse, ParseTreeReference } from './parserWithCaching';
+import { WASMLanguage } from './treeSitterLanguages';
+
+/**
+ * A parse tree for features that need more than the structure of a source, e.g., custom refactors.
+ * The tree lives in WASM memory until the handle is disposed, so it must be disposed when it's no longer needed;
+ * nodes of a disposed handle can't be accessed anymore.
+ *
+ * @remarks Parse trees can't be sent to another thread, so handles are only available where the parser runs, i.e., in the parser worker.
+ */
+export class ParsedTreeHandle implements IDisposable {
+
+	private _treeRef: ParseTreeReference | undefined;
+	private _rootNode: ParsedTreeNode | undefined;
+
+	constructor(treeRef: ParseTreeReference) {
+		this._treeRef = treeRef;
+	}
+
+	get rootNode(): ParsedTreeNode {
+		this.throwIfDisposed();
+		this._rootNode ??= new ParsedTreeNode(this, this._treeRef!.tree.rootNode);
+		return this._rootNode;
+	}
+
+	/**
+	 * @see {@link ParsedTreeNode.descendantsOfType}
+	 */
+	descendantsOfType(types: readonly string[], range?: TreeSitterOffsetRange): ParsedTreeNode[] {
+		return this.rootNode.descendantsOfType(types, range);
+	}
+
+	get isDisposed(): boolean {
+		return this._treeRef === undefined;
+	}
+
+	/**
+	 * @throws if the handle is disposed, i.e., its tree is deleted, so that its nodes mustn't be accessed anymore
+	 */
+	throwIfDisposed(): void {
+		if (this._treeRef === undefined) {
+			throw new Error(`Cannot access disposed ParsedTreeHandle`);
+		}
+	}
+
+	dispose(): void {
+		this._treeRef?.dispose();
+		this._treeRef = undefined;
+	}
+}
+
+/**
+ * Read-only view of a node of a {@link ParsedTreeHandle}, which is checked not to be disposed whenever the node is accessed.
+ */
+export class ParsedTreeNode {
+
+	private _namedChildren: readonly ParsedTreeNode[] | undefined;
+
+	constructor(
+		private readonly _handle: ParsedTreeHandle,
+		private readonly _node: SyntaxNode,
+	) { }
+
+	/**
+	 * @example `function_declaration`
+	 */
+	get type(): string {
+		return this.node.type;
+	}
+
+	get range(): TreeSitterOffsetRange {
+		const node = this.node;
+		return { startIndex: node.startIndex, endIndex: node.endIndex };
+	}
+
+	get text(): string {
+		return this.node.text;
+	}
+
+	/**
+	 * @returns whether the node is or contains a syntax error
+	 */
+	get hasError(): boolean {
+		return this.node.hasError;
+	}
+
+	get namedChildren(): readonly ParsedTreeNode[] {
+		this._namedChildren ??= this.node.namedChildren.map(c => new ParsedTreeNode(this._handle, c));
+		return this._namedChildren;
+	}
+
+	/**
+	 * Like tree-sitter's `descendantsOfType` but only of named nodes, e.g., to find all calls within a function.
+	 *
+	 * @param range if given, only descendants that overlap it are returned, and subtrees outside of it aren't visited
+	 * @returns named descendants, i.e., not the node itself, whose types are among `types` in document order
+	 */
+	descendantsOfType(types: readonly string[], range?: TreeSitterOffsetRange): ParsedTreeNode[] {
+		const typeSet = new Set(types);
+		const descendants: ParsedTreeNode[] = [];
+		const visit = (node: ParsedTreeNode) => {
+			for (const child of node.namedChildren) {
+				const { startIndex, endIndex } = child.range;
+				if (range && (endIndex <= range.startIndex || startIndex >= range.endIndex)) {
+					continue;
+				}
+				if (typeSet.has(child.type)) {
+					descendants.push(child);
+				}
+				visit(child);
+			}
+		};
+		visit(this);
+		return descendants;
+	}
+
+	private get node(): SyntaxNode {
+		this._handle.throwIfDisposed();
+		return this._node;
+	}
+}
+
+/**
+ * @returns handle of the parse tree of `source`, which must be disposed
+ */
+export async function _parseToTree(language: WASMLanguage, source: string): Promise<ParsedTreeHandle> {
+	return new ParsedTreeHandle(await _parse(language, source));
+}
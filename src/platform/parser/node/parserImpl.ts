@@ -5,20 +5,37 @@
 
 import { findInsertionIndexInSortedArray } from '../../../util/common/arrays';
 import { BlockNameDetail, DetailBlock, GenericDetail, MatchGroup, PythonDetail, QueryMatchTree } from './chunkGroupTypes';
-import { Node, OverlayNode, TreeSitterChunkHeaderInfo, TreeSitterExpressionInfo, TreeSitterOffsetRange, TreeSitterPoint, TreeSitterPointRange } from './nodes';
-import { _parse } from './parserWithCaching';
-import { runQueries } from './querying';
+import { Node, OverlayNode, TreeSitterChunkHeaderInfo, TreeSitterExpressionInfo, TreeSitterOffsetRange, TreeSitterPoint, TreeSitterPointRange, TreeSitterQueryCapture, TreeSitterSourceEdits, TreeSitterSyntaxError } from './nodes';
+import { _parse, _reparseWithCache, ParseAbortedError, ParseAbortReason } from './parserWithCaching';
+import { runQueries, runQueryCaptures } from './querying';
 import { _getNodeMatchingSelection } from './selectionParsing';
-import { structureComputer } from './structure';
+import { ExtractedSymbol, extractSymbols, PreviousStructure, StructureCacheStats, structureComputer, StructureOptions } from './structure';
 import { WASMLanguage } from './treeSitterLanguages';
 import { _isFineScope, _isScope, _isStatement, callExpressionQuery, classDeclarationQuery, classReferenceQuery, coarseScopesQuery, functionQuery, semanticChunkingTargetQuery, symbolQueries, typeDeclarationQuery, typeReferenceQuery } from './treeSitterQueries';
 import { extractIdentifier } from './util';
 import Parser = require('web-tree-sitter');
 
+export { _getCallGraph, _getCallReferences, CallGraphEntry, CallReference, CallSite } from './callParsing';
+export { _getSemanticChunks, SemanticChunk } from './chunkParsing';
+export { _getComments, CommentMarker, CommentNode } from './commentParsing';
+export { _getConcurrencyInfo, ChannelOperation, ConcurrencyConstruct, ConcurrencyInfo } from './concurrencyParsing';
 export { _getDocumentableNodeIfOnIdentifier, _getNodeToDocument, NodeToDocumentContext } from './docGenParsing';
-export { _dispose } from './parserWithCaching';
+export { _getFoldingRanges, FoldingRange } from './foldingRangeParsing';
+export { _findLocalDefinition, _getIdentifierAt, IdentifierAtOffset } from './identifierParsing';
+export { _getImports, _getUnusedGoImports, ImportStatement } from './importParsing';
+export { _getLanguageCapabilities, LanguageCapabilities } from './languageCapabilities';
+export { _registerLanguage, LanguageRegistration } from './languageRegistry';
+export { getMarkdownCodeBlockStructures as _getMarkdownCodeBlockStructures, MarkdownCodeBlockStructure } from './markdownCodeBlocks';
+export { _getNodeContext, EnclosingDeclaration, NodeContext } from './nodeContextParsing';
+export { _parseToTree, ParsedTreeHandle, ParsedTreeNode } from './parsedTree';
+export { _dispose, _getLoadedLanguageCount, _preloadLanguages, ParseAbortReason, ParseTimeoutError } from './parserWithCaching';
+export { _expandSelection, _shrinkSelection } from './selectionExpansion';
 export { _getNodeMatchingSelection } from './selectionParsing';
-export { _findLastTest, _getTestableNode, _getTestableNodes } from './testGenParsing';
+export { _getSignatures, Signature } from './signatureParsing';
+export { getSingleFileComponentStructures as _getSingleFileComponentStructures, SingleFileComponentFormat, SingleFileComponentScriptStructure } from './singleFileComponents';
+export { _getSkeleton } from './skeletonParsing';
+export { _getStringLiterals, StringLiteral } from './stringParsing';
+export { _findLastTest, _getTestableNode, _getTestableNodes, _getTests, TestFramework, TestSymbol } from './testGenParsing';
 
 function queryCoarseScopes(language: WASMLanguage, root: Parser.SyntaxNode): Parser.QueryMatch[] {
 	const queries = coarseScopesQuery[language];
@@ -745,8 +762,25 @@ function getDocstringFromBody(body: Parser.SyntaxNode) {
 	return potentialDocstring?.type === 'string' ? potentialDocstring : undefined;
 }
 
-export function _getStructure(lang: WASMLanguage, source: string): Promise<OverlayNode | undefined> {
-	return structureComputer.getStructure(lang, source);
+export function _getStructure(lang: WASMLanguage, source: string, options?: StructureOptions): Promise<OverlayNode | undefined> {
+	return structureComputer.getStructure(lang, source, options);
+}
+
+export function _getStructureIncrementally(lang: WASMLanguage, source: string, previous: PreviousStructure, options?: StructureOptions): Promise<OverlayNode | undefined> {
+	return structureComputer.getStructureIncrementally(lang, source, previous, options);
+}
+
+export function _getStructureForRange(lang: WASMLanguage, source: string, startOffset: number, endOffset: number): Promise<OverlayNode | undefined> {
+	return structureComputer.getStructureForRange(lang, source, startOffset, endOffset);
+}
+
+export async function _extractSymbols(lang: WASMLanguage, source: string, qualifiedName: string, includeLeadingComment: boolean): Promise<ExtractedSymbol[]> {
+	const structure = await structureComputer.getStructure(lang, source);
+	return structure ? extractSymbols(structure, source, qualifiedName, includeLeadingComment) : [];
+}
+
+export function _getStructureCacheStats(): StructureCacheStats {
+	return structureComputer.getCacheStats();
 }
 
 export async function _getParseErrorCount(language: WASMLanguage, source: string): Promise<number> {
@@ -770,3 +804,116 @@ export async function _getParseErrorCount(language: WASMLanguage, source: string
 		treeRef.dispose();
 	}
 }
+
+/**
+ * Lists what the grammar detected as wrong with `source`, i.e., the `ERROR` and `MISSING` nodes of its parse tree, in document order,
+ * e.g., to hint that a file has syntax errors near a position; a well-formed source has none.
+ */
+export async function _getSyntaxErrors(language: WASMLanguage, source: string): Promise<TreeSitterSyntaxError[]> {
+	const treeRef = await _parse(language, source);
+	try {
+		const errors: TreeSitterSyntaxError[] = [];
+		const stack = [treeRef.tree.rootNode];
+		while (stack.length > 0) {
+			const node = stack.pop()!;
+			if (node.isMissing) {
+				// e.g., `}` or `identifier`
+				const message = node.isNamed ? `missing ${node.type}` : `missing "${node.type}"`;
+				errors.push({ kind: 'missing', message, startIndex: node.startIndex, endIndex: node.endIndex });
+			} else if (node.isError) {
+				errors.push({ kind: 'error', message: unexpectedTokenMessage(node), startIndex: node.startIndex, endIndex: node.endIndex });
+			}
+			// only subtrees with errors need to be visited
+			for (let i = node.childCount - 1; i >= 0; i--) {
+				const child = node.child(i)!;
+				if (child.hasError || child.isMissing) {
+					stack.push(child);
+				}
+			}
+		}
+		return errors;
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+/**
+ * @returns e.g., `unexpected "ch"` for an `ERROR` node that starts with `ch := make(chan int)`
+ */
+function unexpectedTokenMessage(error: Parser.SyntaxNode): string {
+	let token = error;
+	while (token.childCount > 0) {
+		token = token.child(0)!;
+	}
+	const text = token.text.split('\n')[0];
+	return text.length === 0 ? 'unexpected token' : `unexpected "${text.length > 20 ? `${text.slice(0, 20)}…` : text}"`;
+}
+
+/**
+ * Counts the named nodes of the parse tree by their types, e.g., `{ function_declaration: 2, ... }` for a source with two functions.
+ */
+export async function _getNodeTypeHistogram(language: WASMLanguage, source: string): Promise<{ [nodeType: string]: number }> {
+	const treeRef = await _parse(language, source);
+	try {
+		const counts = new Map<string, number>();
+		const stack = [treeRef.tree.rootNode];
+		while (stack.length > 0) {
+			const node = stack.pop()!;
+			counts.set(node.type, (counts.get(node.type) ?? 0) + 1);
+			stack.push(...node.namedChildren);
+		}
+		return Object.fromEntries(counts);
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+/**
+ * Runs an arbitrary tree-sitter query against `source`.
+ *
+ * @throws {TreeSitterQueryError} if `query` is malformed
+ */
+export async function _runQuery(language: WASMLanguage, source: string, query: string): Promise<TreeSitterQueryCapture[]> {
+	const treeRef = await _parse(language, source);
+	try {
+		return runQueryCaptures(query, treeRef.tree.rootNode).map(({ name, node }) => ({
+			name,
+			text: node.text,
+			startIndex: node.startIndex,
+			endIndex: node.endIndex,
+		}));
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+/**
+ * Parses the source within a time budget so that subsequent requests for the same source are served from the cache.
+ *
+ * @param timeoutMicros time budget of the parse; defaults to `ParserWithCaching.TIMEOUT_MICROS`
+ * @returns `'parsed'`, or why the parse was aborted
+ */
+export async function _tryParse(language: WASMLanguage, source: string, timeoutMicros?: number): Promise<'parsed' | ParseAbortReason> {
+	try {
+		const treeRef = await _parse(language, source, undefined, { timeoutMicros });
+		treeRef.dispose();
+		return 'parsed';
+	} catch (e) {
+		if (e instanceof ParseAbortedError) {
+			return e.reason;
+		}
+		throw e;
+	}
+}
+
+/**
+ * Parses `source` incrementally from the parse tree of `previous.previousSource` and caches the resulting tree,
+ * so that subsequent queries against `source` don't need to parse it again.
+ *
+ * @returns ranges of `source` whose syntactic structure changed or `undefined` if `source` wasn't parsed incrementally
+ */
+export async function _reparse(language: WASMLanguage, source: string, previous: TreeSitterSourceEdits): Promise<TreeSitterOffsetRange[] | undefined> {
+	const { treeRef, changedRanges } = await _reparseWithCache(language, source, previous);
+	treeRef.dispose();
+	return changedRanges;
+}
@@ -0,0 +1,79 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import Parser = require('web-tree-sitter');
+import { IDisposable } from '../../../util/vs/base/common/lifecycle';
+import { WASMLanguage } from './treeSitterLanguages';
+
+/**
+ * Retains parsers with their language already set, because `Parser.setLanguage` is expensive.
+ *
+ * A parser is exclusively owned by whoever acquired it until it's released, so parses that run
+ * re-entrantly never share a parser.
+ */
+export class ParserPool implements IDisposable {
+
+	static MAX_IDLE_PARSERS_PER_LANGUAGE = 2;
+
+	private readonly idleParsers = new Map<WASMLanguage, Parser[]>();
+
+	constructor(
+		private readonly maxIdleParsersPerLanguage = ParserPool.MAX_IDLE_PARSERS_PER_LANGUAGE
+	) { }
+
+	/**
+	 * @remarks must not be called before `Parser.init()`
+	 * @remarks the returned parser must be given back with {@link release}
+	 */
+	acquire(lang: WASMLanguage, parserLang: Parser.Language): Parser {
+		const idleParser = this.idleParsers.get(lang)?.pop();
+		if (idleParser) {
+			return idleParser;
+		}
+		const parser = new Parser();
+		parser.setLanguage(parserLang);
+		return parser;
+	}
+
+	release(lang: WASMLanguage, parser: Parser): void {
+		let idleParsers = this.idleParsers.get(lang);
+		if (!idleParsers) {
+			idleParsers = [];
+			this.idleParsers.set(lang, idleParsers);
+		}
+		if (idleParsers.length >= this.maxIdleParsersPerLanguage) {
+			parser.delete();
+			return;
+		}
+		parser.reset();
+		idleParsers.push(parser);
+	}
+
+	/**
+	 * Deletes the idle parsers for `lang`, e.g., because its grammar is no longer resident; acquired parsers aren't affected.
+	 */
+	deleteIdleParsers(lang: WASMLanguage): void {
+		for (const parser of this.idleParsers.get(lang) ?? []) {
+			parser.delete();
+		}
+		this.idleParsers.delete(lang);
+	}
+
+	/**
+	 * @returns number of parsers for `lang` ready for reuse
+	 */
+	idleCount(lang: WASMLanguage): number {
+		return this.idleParsers.get(lang)?.length ?? 0;
+	}
+
+	dispose(): void {
+		for (const idleParsers of this.idleParsers.values()) {
+			for (const parser of idleParsers) {
+				parser.delete();
+			}
+		}
+		this.idleParsers.clear();
+	}
+}
@@ -5,12 +5,30 @@
 
 import type * as vscode from 'vscode';
 import { createServiceIdentifier } from '../../../util/common/services';
+import { CancellationToken } from '../../../util/vs/base/common/cancellation';
+import { IDisposable } from '../../../util/vs/base/common/lifecycle';
 import { Range } from '../../../vscodeTypes';
 import { TextDocumentSnapshot } from '../../editing/common/textDocumentSnapshot';
+import { CallGraphEntry, CallReference } from './callParsing';
 import { BlockNameDetail, DetailBlock, QueryMatchTree } from './chunkGroupTypes';
-import { OverlayNode, TreeSitterExpressionInfo, TreeSitterOffsetRange, TreeSitterPointRange } from './nodes';
+import { SemanticChunk } from './chunkParsing';
+import { CommentNode } from './commentParsing';
+import type { ConcurrencyInfo } from './concurrencyParsing';
+import { FoldingRange } from './foldingRangeParsing';
+import { IdentifierAtOffset } from './identifierParsing';
+import { ImportStatement } from './importParsing';
+import type { LanguageCapabilities } from './languageCapabilities';
+import { LanguageRegistration } from './languageRegistry';
+import type { MarkdownCodeBlockStructure } from './markdownCodeBlocks';
+import { NodeContext } from './nodeContextParsing';
+import { OverlayNode, TreeSitterExpressionInfo, TreeSitterOffsetRange, TreeSitterPointRange, TreeSitterQueryCapture, TreeSitterSourceEdits, TreeSitterSyntaxError } from './nodes';
 import type * as parser from './parserImpl';
-import { TestableNode } from './testGenParsing';
+import type { ParseAbortReason } from './parserWithCaching';
+import { Signature } from './signatureParsing';
+import type { SingleFileComponentFormat, SingleFileComponentScriptStructure } from './singleFileComponents';
+import type { StringLiteral } from './stringParsing';
+import type { ExtractedSymbol, ExtractSymbolOptions, PreviousStructure, StructureCacheStats, StructureDiff, StructureOptions } from './structure';
+import { TestableNode, TestSymbol } from './testGenParsing';
 import { WASMLanguage } from './treeSitterLanguages';
 
 export const IParserService = createServiceIdentifier<IParserService>('IParserService');
@@ -83,7 +101,10 @@ export interface TreeSitterAST {
 	 */
 	getFineScopes(range: TreeSitterOffsetRange): Promise<TreeSitterOffsetRange[] | undefined>;
 
-	getStructure(): Promise<OverlayNode | undefined>;
+	/**
+	 * @param options e.g., `{ maxDepth: 2 }` to only get top-level declarations of a large file, or a `transform` that leaves out or relabels nodes
+	 */
+	getStructure(options?: StructureOptions): Promise<OverlayNode | undefined>;
 
 	findLastTest(): Promise<TreeSitterOffsetRange | null>;
 
@@ -91,16 +112,37 @@ export interface TreeSitterAST {
 	 * Get the number of parse errors in the given piece of source code.
 	 */
 	getParseErrorCount(): Promise<number>;
+
+	/**
+	 * Get the `ERROR` and `MISSING` nodes of the parse tree of the given piece of source code in document order, each with a short description,
+	 * e.g., `missing "}"`; this only surfaces what the grammar detected and isn't a diagnostics engine.
+	 */
+	getSyntaxErrors(): Promise<TreeSitterSyntaxError[]>;
+
+	/**
+	 * Run an arbitrary tree-sitter query against the given piece of source code, see {@link IParserService.runQuery}.
+	 */
+	runQuery(query: string): Promise<TreeSitterQueryCapture[]>;
+}
+
+export interface BatchParseFile {
+	readonly language: WASMLanguage;
+	readonly source: string;
 }
 
+export type BatchStructure =
+	| { readonly structure: OverlayNode | undefined }
+	| { readonly error: string };
+
 export interface IParserService {
 
 	readonly _serviceBrand: undefined;
 
 	/**
+	 * A document of a language without a grammar, e.g., plain text, is parsed as the language its file resolves to, see {@link resolveLanguage}.
 	 * @returns an AST for the given document OR `undefined` if the document language is not supported
 	 */
-	getTreeSitterAST(document: { readonly languageId: string; getText(): string }): TreeSitterAST | undefined;
+	getTreeSitterAST(document: { readonly languageId: string; readonly uri?: { readonly path: string }; getText(): string }): TreeSitterAST | undefined;
 
 	/**
 	 * @returns an AST parsing the source with the given language
@@ -115,6 +157,310 @@ export interface IParserService {
 	 * Get a `BlockNameNode` that is the root of a tree of semantic chunk names for a source
 	 */
 	getSemanticChunkNames(language: WASMLanguage, source: string): Promise<QueryMatchTree<BlockNameDetail>>;
+
+	/**
+	 * Split the source into chunks that end at declaration boundaries, e.g., to embed them for retrieval without cutting functions in half.
+	 * Each chunk is labeled with the names of the declarations it's part of, e.g., `StructExample.MethodExample`.
+	 *
+	 * Declarations that don't fit are split at their members or statements, and other nodes at line breaks, see `chunkContainerKinds`.
+	 *
+	 * @param maxLength maximum length of a chunk in UTF-16 code units
+	 * @param containerKinds kinds of structure nodes that are split at their members or statements instead of the language's defaults
+	 * @returns contiguous chunks covering the whole source in order
+	 */
+	getSemanticChunks(language: WASMLanguage, source: string, maxLength: number, containerKinds?: readonly string[]): Promise<SemanticChunk[]>;
+
+	/**
+	 * Run an arbitrary tree-sitter query, e.g., `(comment) @comment`, against the source parsed with the given language.
+	 *
+	 * @returns all captures of the query in document order
+	 * @throws if the query is malformed
+	 */
+	runQuery(language: WASMLanguage, source: string, query: string): Promise<TreeSitterQueryCapture[]>;
+
+	/**
+	 * Count the named nodes of the source's parse tree by their types, e.g., to cheaply decide from the number of
+	 * `function_declaration`s whether a file is worth computing its structure. A cached parse tree of the source is reused.
+	 */
+	getNodeTypeHistogram(language: WASMLanguage, source: string): Promise<{ [nodeType: string]: number }>;
+
+	/**
+	 * Get the nodes of the structure of the source, see {@link TreeSitterAST.getStructure}, that overlap the range `[startOffset, endOffset)`,
+	 * e.g., the function a user cares about within a huge file, without sending the structure of the whole file.
+	 * The whole source is parsed, so the nodes and their offsets are the same as in the structure of the whole source, but for a large source only the structure
+	 * of the top-level declarations around the range is computed, see `StructureComputer.getStructureForRange`.
+	 *
+	 * @returns the root with only the overlapping nodes, but no children if the range is within whitespace between declarations
+	 */
+	getStructureForRange(language: WASMLanguage, source: string, startOffset: number, endOffset: number): Promise<OverlayNode | undefined>;
+
+	/**
+	 * Get the imports, e.g., `import * as fs from 'fs'` or `require('fs')`, of the source in document order.
+	 */
+	getImports(language: WASMLanguage, source: string): Promise<ImportStatement[]>;
+
+	/**
+	 * Get the imports of the Go source whose package is never referenced, e.g., `"strings"` if there's no `strings.X` in the file,
+	 * to suggest removing them. Blank (`_`) and dot (`.`) imports are never reported.
+	 */
+	getUnusedGoImports(source: string): Promise<ImportStatement[]>;
+
+	/**
+	 * Get the `go` statements, channel creations, channel operations, e.g., `ch <- v` or `<-ch`, and `select` blocks of the Go source,
+	 * including those within parse errors, e.g., to point out concurrency when reviewing a change.
+	 */
+	getConcurrencyInfo(source: string): Promise<ConcurrencyInfo>;
+
+	/**
+	 * Get the signatures, i.e., the declarations without their bodies, of the top-level functions and the methods of the source in document order,
+	 * e.g., `func (s StructExample) MethodExample() error`, to describe the API of a file compactly. Supports Go, JavaScript, TypeScript, and Python.
+	 */
+	getSignatures(language: WASMLanguage, source: string): Promise<Signature[]>;
+
+	/**
+	 * Get a skeleton of the source, i.e., the source with the bodies of its top-level functions and methods stubbed,
+	 * e.g., `func (s StructExample) MethodExample() error { ... }`, to give the model a compact overview of a large file.
+	 * Imports, type declarations, and comments are kept as written. Returns the source as is for languages other than Go, JavaScript, TypeScript, and Python.
+	 */
+	getSkeleton(language: WASMLanguage, source: string): Promise<string>;
+
+	/**
+	 * Get the line ranges of block-like nodes, e.g., bodies, composite literals, and grouped declarations, that can be folded.
+	 */
+	getFoldingRanges(language: WASMLanguage, source: string): Promise<FoldingRange[]>;
+
+	/**
+	 * Get the comments of the source in document order with whether they're block comments and their markers, e.g., `TODO` for `// TODO: ...`,
+	 * e.g., to surface the TODOs of a file. Text within string literals that looks like a comment, e.g., `'// not a comment'`, isn't reported.
+	 */
+	getComments(language: WASMLanguage, source: string): Promise<CommentNode[]>;
+
+	/**
+	 * Get the range of the next-larger syntactic node around the range, e.g., the `if` statement around its condition, for "expand selection".
+	 * If no node is larger than the range, e.g., if it's the whole source, the range is returned as it is.
+	 */
+	expandSelection(language: WASMLanguage, source: string, range: TreeSitterOffsetRange): Promise<TreeSitterOffsetRange>;
+
+	/**
+	 * Get the range of the next-smaller syntactic node within the range that contains the offset, i.e., the inverse of {@link expandSelection}
+	 * if the offset is where the expansion started. If there's no smaller node, e.g., for a single token, the range is returned as it is.
+	 *
+	 * @param offset by default, the start of the range
+	 */
+	shrinkSelection(language: WASMLanguage, source: string, range: TreeSitterOffsetRange, offset?: number): Promise<TreeSitterOffsetRange>;
+
+	/**
+	 * Get the calls within the range, e.g., the body of a function to know what it calls, with the called function or method,
+	 * the receiver of a method call, and the number of arguments. Each call in a chain, e.g., `a.b().c()`, is reported once.
+	 *
+	 * @remarks Unlike {@link TreeSitterAST.getCallExpressions}, only calls that are entirely within the range are reported.
+	 */
+	getCallExpressions(language: WASMLanguage, source: string, range: TreeSitterOffsetRange): Promise<CallReference[]>;
+
+	/**
+	 * Get the named functions and methods of the source with the calls in their bodies, e.g., that `main` calls `i.MethodExample` and `fmt.Println`.
+	 * Callees are reported as written, i.e., they aren't resolved to their declarations.
+	 */
+	getCallGraph(language: WASMLanguage, source: string): Promise<CallGraphEntry[]>;
+
+	/**
+	 * Get the nearest named function, type, and namespace or package declarations enclosing the offset, e.g., to know what the cursor is in.
+	 * If the offset is in whitespace between declarations, the context of the nearest preceding one is returned.
+	 */
+	getNodeContext(language: WASMLanguage, source: string, offset: number): Promise<NodeContext>;
+
+	/**
+	 * Get the identifier at the offset or ending at it, e.g., for a hover or a rename preview, with whether it's the name of a declaration
+	 * or a reference; whether it's a definition is derived from the syntax only, so it's approximate for some languages.
+	 *
+	 * @returns `undefined` if the offset isn't on an identifier, e.g., on a keyword or in whitespace
+	 */
+	getIdentifierAt(language: WASMLanguage, source: string, offset: number): Promise<IdentifierAtOffset | undefined>;
+
+	/**
+	 * Find the definition of the local identifier at the offset, e.g., for a lightweight go to definition of parameters and local variables
+	 * without a language server: the nearest preceding binding of its name in a scope enclosing it, e.g., a Go `:=` declaration.
+	 *
+	 * @returns range of the name of the definition; `undefined` if the identifier isn't bound locally, e.g., a package-level variable or an import
+	 */
+	findLocalDefinition(language: WASMLanguage, source: string, offset: number): Promise<TreeSitterOffsetRange | undefined>;
+
+	/**
+	 * Find the tests declared in the source by the conventions of the language's test frameworks, e.g., to run the test at the cursor:
+	 * Go's `TestXxx` and `BenchmarkXxx` functions, JS/TS `describe`, `it`, and `test` blocks, and Python's `test_*` functions and `unittest.TestCase` methods.
+	 *
+	 * @returns tests and the suites nesting them in source order, each with the names of its suites, e.g., `['Calculator', 'add', 'adds two numbers']`
+	 */
+	getTests(language: WASMLanguage, source: string): Promise<TestSymbol[]>;
+
+	/**
+	 * Find the string literals of the source with their values, e.g., to flag hardcoded secrets or to extract translatable strings;
+	 * literals are found in the syntax tree, so `'//'` isn't mistaken for a comment.
+	 *
+	 * @returns literals in document order; interpolated ones, e.g., `` `Hello, ${name}` ``, have the ranges of their interpolations but no values
+	 */
+	getStringLiterals(language: WASMLanguage, source: string): Promise<StringLiteral[]>;
+
+	/**
+	 * Get the structure of the source like {@link TreeSitterAST.getStructure} without blocking for long, e.g., for a generated file of several megabytes.
+	 *
+	 * @remarks Tokens can't be passed to the parser worker, so with the worker, only waiting for the structure is cancelled.
+	 *
+	 * @throws {CancellationError} if the token is cancelled before the structure is computed; no partial structure is returned
+	 */
+	getStructureAsync(language: WASMLanguage, source: string, token: CancellationToken, options?: StructureOptions): Promise<OverlayNode | undefined>;
+
+	/**
+	 * Get the structure of a source that was obtained by editing one whose structure is known, e.g., to update an outline as a user types,
+	 * by computing only the top-level declarations around what changed again; the result is the same as the one of {@link TreeSitterAST.getStructure}.
+	 *
+	 * @param previous the previous source, the edits that turned it into `source`, and its structure, which must have been computed with the same options;
+	 * with a `transform`, the structure is computed from scratch, since the previous nodes are transformed already
+	 */
+	getStructureIncrementally(language: WASMLanguage, source: string, previous: PreviousStructure, options?: StructureOptions): Promise<OverlayNode | undefined>;
+
+	/**
+	 * Get the top-level nodes of the structure of the source, see {@link TreeSitterAST.getStructure}, in source order as soon as they're computed,
+	 * e.g., to render the structure of a large file incrementally. Ending the iteration early stops computing the structure.
+	 *
+	 * @remarks With the parser worker, nodes are only yielded once the whole structure is computed because they can't be streamed from the worker.
+	 *
+	 * @throws {CancellationError} if the token is cancelled before all nodes are yielded
+	 */
+	getStructureStream(language: WASMLanguage, source: string, token?: CancellationToken): AsyncIterable<OverlayNode>;
+
+	/**
+	 * Get the structures of the fenced code blocks of a Markdown document, e.g., of a pasted snippet, with offsets within the document.
+	 * Blocks without a language tag or with one that isn't a {@link WASMLanguage}, e.g., ```` ```text ````, are skipped.
+	 */
+	getMarkdownCodeBlockStructures(markdown: string, options?: StructureOptions): Promise<MarkdownCodeBlockStructure[]>;
+
+	/**
+	 * Get the structures of the script blocks of a single-file component, i.e., a `.vue` or `.svelte` file, with offsets within the file;
+	 * a script is parsed as TypeScript if it has `lang="ts"`, and as JavaScript otherwise.
+	 */
+	getSingleFileComponentStructures(format: SingleFileComponentFormat, source: string, options?: StructureOptions): Promise<SingleFileComponentScriptStructure[]>;
+
+	/**
+	 * Get the structures of many sources at once, e.g., to index a workspace, like {@link TreeSitterAST.getStructure}.
+	 * With the parser worker, the sources are parsed in parallel by a pool of workers with parsers of their own instead of one after another.
+	 *
+	 * @returns structures in the order of `files`; a source that fails to parse yields an error in its place instead of failing the batch
+	 */
+	parseBatch(files: readonly BatchParseFile[], options?: StructureOptions): Promise<BatchStructure[]>;
+
+	/**
+	 * Get how often structures were served from the cache shared by all requests for a source in the same language,
+	 * e.g., by the context providers of one chat turn, since the parser started.
+	 */
+	getStructureCacheStats(): Promise<StructureCacheStats>;
+
+	/**
+	 * Get which features are implemented for a language, e.g., to hide a command for a language without tests instead of showing no results.
+	 */
+	getLanguageCapabilities(language: WASMLanguage): Promise<LanguageCapabilities>;
+
+	/**
+	 * Get the innermost function, type, or namespace declaration of a structure, see {@link TreeSitterAST.getStructure}, that contains the offset.
+	 * Other nodes, e.g., statements, are skipped, so an offset within a loop yields the function containing it.
+	 *
+	 * @returns `undefined` if no declaration contains the offset
+	 */
+	getEnclosingSymbol(tree: OverlayNode, offset: number): OverlayNode | undefined;
+
+	/**
+	 * Get the function, type, and namespace declarations of a structure, see {@link TreeSitterAST.getStructure}, that overlap the range `[startOffset, endOffset)`
+	 * ordered by their start offsets, e.g., the declarations a user is editing. Offsets outside of the source are clamped to it.
+	 *
+	 * @returns empty if the range is within whitespace between declarations
+	 */
+	getSymbolsInRange(tree: OverlayNode, startOffset: number, endOffset: number, source: string): OverlayNode[];
+
+	/**
+	 * Compare the symbols, i.e., the named nodes, of two structures of the same file, see {@link TreeSitterAST.getStructure},
+	 * by their kinds and qualified names, e.g., to reuse what was derived from the symbols that didn't change.
+	 *
+	 * @returns symbols that were added, removed, renamed, i.e., they took the place of a symbol of the same kind and signature apart from its name,
+	 * moved, i.e., their ranges shifted, or changed, i.e., their signatures differ
+	 */
+	diffStructures(oldNodes: readonly OverlayNode[], newNodes: readonly OverlayNode[]): StructureDiff;
+
+	/**
+	 * Index the named nodes of a structure, see {@link TreeSitterAST.getStructure}, including nested members, by their simple names, e.g., `MethodExample`,
+	 * and their qualified names, see {@link OverlayNode.qualifiedName}, e.g., `geo::Shape::area`, to look symbols up by name without walking the structure.
+	 * Go nodes are indexed by their qualified names without the package as well, e.g., `StructExample.MethodExample`.
+	 *
+	 * @returns nodes in source order by name; all declarations of a name that's declared several times
+	 */
+	buildSymbolIndex(structure: OverlayNode): Map<string, OverlayNode[]>;
+
+	/**
+	 * Get the source text of the declaration named `qualifiedName`, see {@link IParserService.buildSymbolIndex}, e.g., `StructExample.MethodExample`
+	 * for a Go method including its receiver and body, to rewrite exactly that declaration.
+	 *
+	 * @returns the first declaration of the name in source order or, with {@link ExtractSymbolOptions.allMatches}, all of them;
+	 * `undefined` or empty if the source doesn't declare the name or its language isn't supported
+	 */
+	extractSymbol(language: WASMLanguage, source: string, qualifiedName: string, options?: ExtractSymbolOptions & { allMatches?: false }): Promise<ExtractedSymbol | undefined>;
+	extractSymbol(language: WASMLanguage, source: string, qualifiedName: string, options: ExtractSymbolOptions & { allMatches: true }): Promise<ExtractedSymbol[]>;
+
+	/**
+	 * Parse the source by applying the edits to the parse tree of the previous source, which is much cheaper than a full parse for large sources.
+	 * The resulting tree is cached, i.e., an AST for the same source obtained afterwards doesn't need to parse it again.
+	 *
+	 * @remarks Parse trees live in the parser worker, so the previous tree is identified by its source;
+	 * if that tree is no longer cached, the source is parsed from scratch.
+	 *
+	 * @returns ranges of the source whose syntactic structure changed or `undefined` if the source wasn't parsed incrementally
+	 */
+	reparse(language: WASMLanguage, source: string, previous: TreeSitterSourceEdits): Promise<TreeSitterOffsetRange[] | undefined>;
+
+	/**
+	 * Parse the source ahead of other requests for it, e.g., for a large file that may take too long to parse.
+	 * Subsequent requests for the same source reuse the parse tree while it's cached.
+	 *
+	 * @param options.token cancels waiting for the parse; a parse that has started still runs until it completes or times out
+	 * @param options.timeoutMicros time budget of the parse; by default, the parse is not limited
+	 *
+	 * @returns `'parsed'`, `'cancelled'`, or `'timedOut'`; aborted parses are not cached
+	 */
+	parse(language: WASMLanguage, source: string, options?: { token?: CancellationToken; timeoutMicros?: number }): Promise<'parsed' | ParseAbortReason>;
+
+	/**
+	 * Load the grammars of the languages ahead of their first use, e.g., at activation, so that the first structure of a source in one of them isn't delayed by loading its grammar.
+	 * Grammars that are already loaded aren't loaded again, so it's fine to call this repeatedly or while other requests are parsing.
+	 *
+	 * @throws if the grammar of a language can't be loaded; the other grammars are still loaded
+	 */
+	preloadLanguages(languages: readonly WASMLanguage[]): Promise<void>;
+
+	/**
+	 * Register a grammar that isn't bundled, e.g., by a fork of the extension, so that its sources can be parsed without changes to {@link WASMLanguage}.
+	 * Its structure is computed with the given query; language-specific details, e.g., names and symbol kinds of structure nodes, aren't available for it.
+	 *
+	 * @returns the registered ID as a {@link WASMLanguage} to pass to the other methods, e.g., {@link runQuery}; {@link getTreeSitterAST} accepts
+	 * documents of the ID and of its aliases, and {@link resolveLanguage} resolves files with its extensions to it
+	 * @throws if the ID is a built-in language or is already registered differently, if neither or both of a `.wasm` path and bytes are given,
+	 * if the grammar can't be loaded, or if the query doesn't compile
+	 */
+	registerLanguage(registration: LanguageRegistration): Promise<WASMLanguage>;
+
+	/**
+	 * Register the language to parse files with the extension as, e.g., Go for `.go.tmpl` or TSX for `.tsx.template`, which takes precedence over the built-in extensions,
+	 * see {@link resolveLanguage}. Extensions are case-insensitive and match the end of a file's name; if several match, the longest wins.
+	 * Registering an extension again replaces its language.
+	 *
+	 * @returns a disposable that clears the registration unless the extension has been registered again since
+	 */
+	registerExtensionLanguage(extension: string, language: WASMLanguage): IDisposable;
+
+	/**
+	 * Resolve the language to parse a file as: the explicit `language` if given, else the language registered for its extension, see {@link registerExtensionLanguage},
+	 * else the language of its built-in extension, and else the language detected from its content, e.g., of a shebang line, see `detectLanguage`.
+	 *
+	 * @returns `undefined` if the language can't be resolved
+	 */
+	resolveLanguage(fileName: string, source: string, language?: WASMLanguage): WASMLanguage | undefined;
 }
 
 export function vscodeToTreeSitterRange(range: vscode.Range): TreeSitterPointRange {
@@ -4,12 +4,21 @@
  *--------------------------------------------------------------------------------------------*/
 
 import { WorkerWithRpcProxy } from '../../../util/node/worker';
+import { defaultWorkerCount, WorkerPool } from '../../../util/node/workerPool';
+import { raceCancellation, raceCancellationError } from '../../../util/vs/base/common/async';
+import { CancellationToken } from '../../../util/vs/base/common/cancellation';
+import { CancellationError } from '../../../util/vs/base/common/errors';
 import { Lazy } from '../../../util/vs/base/common/lazy';
+import { IDisposable, toDisposable } from '../../../util/vs/base/common/lifecycle';
 import * as path from '../../../util/vs/base/common/path';
-import { TreeSitterOffsetRange, TreeSitterPointRange } from './nodes';
+import { LanguageRegistration } from './languageRegistry';
+import { OverlayNode, TreeSitterOffsetRange, TreeSitterPointRange, TreeSitterSourceEdits } from './nodes';
 import * as parser from './parserImpl';
-import { IParserService, TreeSitterAST } from './parserService';
-import { WASMLanguage, getWasmLanguage } from './treeSitterLanguages';
+import { BatchParseFile, BatchStructure, IParserService, TreeSitterAST } from './parserService';
+import type { ParseAbortReason } from './parserWithCaching';
+import type { SingleFileComponentFormat } from './singleFileComponents';
+import { buildSymbolIndex, diffStructures, ExtractedSymbol, ExtractSymbolOptions, getEnclosingSymbol, getSymbolsInRange, PreviousStructure, StructureOptions, structureComputer, transformStructure } from './structure';
+import { WASMLanguage, detectLanguage, getWasmLanguage } from './treeSitterLanguages';
 
 const workerPath = path.join(__dirname, 'worker2.js');
 type ParserType = Omit<typeof parser, '_getNodeMatchingSelection'>;
@@ -18,24 +27,65 @@ export class ParserServiceImpl implements IParserService {
 
 	declare readonly _serviceBrand: undefined;
 
+	/** Default number of grammars the parser worker may load before it's replaced by a new one, which loads the grammars it needs again */
+	static MAX_WORKER_LANGUAGES = 12;
+
 	private _parser: WorkerOrLocal<ParserType>;
+	private _batchWorkers: Lazy<WorkerPool<ParserType>>;
+	private readonly _registrations: LanguageRegistration[] = [];
+	/** registered languages by their IDs and aliases, see {@link registerLanguage} */
+	private readonly _registeredLanguages = new Map<string, WASMLanguage>();
+	/** languages of file extensions by the lower-cased extensions, see {@link registerExtensionLanguage} */
+	private readonly _extensionLanguages = new Map<string, { readonly language: WASMLanguage }>();
+	/** languages each batch worker was told about and loaded the grammar of by the worker's proxy, see {@link parseBatch} */
+	private readonly _batchWorkerLanguages = new WeakMap<Proxied<ParserType>, Map<WASMLanguage, Promise<void>>>();
 
+	/**
+	 * @param batchWorkerCount number of workers that parse the sources of {@link parseBatch} in parallel
+	 * @param maxWorkerLanguages number of grammars the parser worker may load before it's replaced, since web-tree-sitter never frees a loaded grammar;
+	 * the local parser keeps all of them
+	 */
 	constructor(
-		useWorker: boolean
+		private readonly _useWorker: boolean,
+		batchWorkerCount = defaultWorkerCount(),
+		maxWorkerLanguages = ParserServiceImpl.MAX_WORKER_LANGUAGES,
 	) {
-		this._parser = new WorkerOrLocal<ParserType>(parser, workerPath, useWorker);
+		const isLanguage = (arg: unknown): arg is string =>
+			typeof arg === 'string' && ((Object.values(WASMLanguage) as string[]).includes(arg) || this._registrations.some(r => r.id === arg));
+		this._parser = new WorkerOrLocal<ParserType>(parser, workerPath, _useWorker, {
+			maxLoadedLanguages: maxWorkerLanguages,
+			// the language is the first argument, or the languages are, e.g., those to preload
+			languagesOf: args => (Array.isArray(args[0]) ? args[0] : [args[0]]).filter(isLanguage),
+			// a new worker only knows the registered languages it's told about, like the batch workers
+			prepare: async (proxy, language) => {
+				const registration = this._registrations.find(r => r.id === language);
+				if (registration) {
+					await proxy._registerLanguage(registration);
+				}
+			},
+			loadedLanguageCount: proxy => proxy._getLoadedLanguageCount(),
+		});
+		this._batchWorkers = new Lazy(() => new WorkerPool<ParserType>(
+			index => new WorkerWithRpcProxy<ParserType>(workerPath, { name: `Parser batch worker ${index + 1}` }),
+			batchWorkerCount,
+		));
 	}
 
 	dispose(): void {
 		this._parser.dispose();
+		if (this._batchWorkers.hasValue) {
+			this._batchWorkers.value.dispose();
+		}
 	}
 
-	getTreeSitterAST(textDocument: { readonly languageId: string; getText(): string }): TreeSitterAST | undefined {
-		const wasmLanguage = getWasmLanguage(textDocument.languageId);
+	getTreeSitterAST(textDocument: { readonly languageId: string; readonly uri?: { readonly path: string }; getText(): string }): TreeSitterAST | undefined {
+		const language = getWasmLanguage(textDocument.languageId) ?? this._registeredLanguages.get(textDocument.languageId);
+		const source = textDocument.getText();
+		const wasmLanguage = textDocument.uri ? this.resolveLanguage(textDocument.uri.path, source, language) : language;
 		if (!wasmLanguage) {
 			return undefined;
 		}
-		return this.getTreeSitterASTForWASMLanguage(wasmLanguage, textDocument.getText());
+		return this.getTreeSitterASTForWASMLanguage(wasmLanguage, source);
 	}
 
 	getTreeSitterASTForWASMLanguage(wasmLanguage: WASMLanguage, source: string): TreeSitterAST {
@@ -57,9 +107,11 @@ export class ParserServiceImpl implements IParserService {
 			getNodeToExplain: (range: TreeSitterOffsetRange) => parserProxy._getNodeToExplain(wasmLanguage, source, range),
 			getNodeToDocument: (range: TreeSitterOffsetRange) => parserProxy._getNodeToDocument(wasmLanguage, source, range),
 			getFineScopes: (selection: TreeSitterOffsetRange) => parserProxy._getFineScopes(wasmLanguage, source, selection),
-			getStructure: () => parserProxy._getStructure(wasmLanguage, source),
+			getStructure: async (options?: StructureOptions) => transformed(await parserProxy._getStructure(wasmLanguage, source, withoutTransform(options)), options),
 			findLastTest: () => parserProxy._findLastTest(wasmLanguage, source),
 			getParseErrorCount: () => parserProxy._getParseErrorCount(wasmLanguage, source),
+			getSyntaxErrors: () => parserProxy._getSyntaxErrors(wasmLanguage, source),
+			runQuery: (query: string) => parserProxy._runQuery(wasmLanguage, source, query),
 		};
 	}
 
@@ -70,29 +122,330 @@ export class ParserServiceImpl implements IParserService {
 	getSemanticChunkNames(language: WASMLanguage, source: string) {
 		return this._parser.proxy._getSemanticChunkNames(language, source);
 	}
+
+	getSemanticChunks(language: WASMLanguage, source: string, maxLength: number, containerKinds?: readonly string[]) {
+		return this._parser.proxy._getSemanticChunks(language, source, maxLength, containerKinds);
+	}
+
+	runQuery(language: WASMLanguage, source: string, query: string) {
+		return this._parser.proxy._runQuery(language, source, query);
+	}
+
+	getNodeTypeHistogram(language: WASMLanguage, source: string) {
+		return this._parser.proxy._getNodeTypeHistogram(language, source);
+	}
+
+	getStructureForRange(language: WASMLanguage, source: string, startOffset: number, endOffset: number) {
+		return this._parser.proxy._getStructureForRange(language, source, startOffset, endOffset);
+	}
+
+	getImports(language: WASMLanguage, source: string) {
+		return this._parser.proxy._getImports(language, source);
+	}
+
+	getUnusedGoImports(source: string) {
+		return this._parser.proxy._getUnusedGoImports(source);
+	}
+
+	getConcurrencyInfo(source: string) {
+		return this._parser.proxy._getConcurrencyInfo(source);
+	}
+
+	getSignatures(language: WASMLanguage, source: string) {
+		return this._parser.proxy._getSignatures(language, source);
+	}
+
+	getSkeleton(language: WASMLanguage, source: string) {
+		return this._parser.proxy._getSkeleton(language, source);
+	}
+
+	getFoldingRanges(language: WASMLanguage, source: string) {
+		return this._parser.proxy._getFoldingRanges(language, source);
+	}
+
+	getComments(language: WASMLanguage, source: string) {
+		return this._parser.proxy._getComments(language, source);
+	}
+
+	expandSelection(language: WASMLanguage, source: string, range: TreeSitterOffsetRange) {
+		return this._parser.proxy._expandSelection(language, source, range);
+	}
+
+	shrinkSelection(language: WASMLanguage, source: string, range: TreeSitterOffsetRange, offset?: number) {
+		return this._parser.proxy._shrinkSelection(language, source, range, offset);
+	}
+
+	getCallExpressions(language: WASMLanguage, source: string, range: TreeSitterOffsetRange) {
+		return this._parser.proxy._getCallReferences(language, source, range);
+	}
+
+	getCallGraph(language: WASMLanguage, source: string) {
+		return this._parser.proxy._getCallGraph(language, source);
+	}
+
+	getNodeContext(language: WASMLanguage, source: string, offset: number) {
+		return this._parser.proxy._getNodeContext(language, source, offset);
+	}
+
+	getIdentifierAt(language: WASMLanguage, source: string, offset: number) {
+		return this._parser.proxy._getIdentifierAt(language, source, offset);
+	}
+
+	findLocalDefinition(language: WASMLanguage, source: string, offset: number) {
+		return this._parser.proxy._findLocalDefinition(language, source, offset);
+	}
+
+	getTests(language: WASMLanguage, source: string) {
+		return this._parser.proxy._getTests(language, source);
+	}
+
+	getStringLiterals(language: WASMLanguage, source: string) {
+		return this._parser.proxy._getStringLiterals(language, source);
+	}
+
+	async getStructureAsync(language: WASMLanguage, source: string, token: CancellationToken, options?: StructureOptions): Promise<OverlayNode | undefined> {
+		if (!this._useWorker) {
+			// the structure is computed right here, so it can stop as soon as the token is cancelled
+			return viaJSON(await structureComputer.getStructureAsync(language, source, token, options));
+		}
+		if (token.isCancellationRequested) {
+			throw new CancellationError();
+		}
+		return transformed(await raceCancellationError(this._parser.proxy._getStructure(language, source, withoutTransform(options)), token), options);
+	}
+
+	async getStructureIncrementally(language: WASMLanguage, source: string, previous: PreviousStructure, options?: StructureOptions) {
+		if (options?.transform) {
+			// the nodes of the previous structure are transformed already, so they can't be reused for nodes that are transformed afterwards
+			return transformed(await this._parser.proxy._getStructure(language, source, withoutTransform(options)), options);
+		}
+		return this._parser.proxy._getStructureIncrementally(language, source, previous, options);
+	}
+
+	async *getStructureStream(language: WASMLanguage, source: string, token: CancellationToken = CancellationToken.None): AsyncIterable<OverlayNode> {
+		if (!this._useWorker) {
+			for await (const node of structureComputer.getStructureStream(language, source, token)) {
+				yield viaJSON(node);
+			}
+			return;
+		}
+		const structure = await this.getStructureAsync(language, source, token);
+		for (const node of structure?.children ?? []) {
+			if (token.isCancellationRequested) {
+				throw new CancellationError();
+			}
+			yield node;
+		}
+	}
+
+	async getMarkdownCodeBlockStructures(markdown: string, options?: StructureOptions) {
+		const blocks = await this._parser.proxy._getMarkdownCodeBlockStructures(markdown, withoutTransform(options));
+		blocks.forEach(block => transformed(block.structure, options));
+		return blocks;
+	}
+
+	async getSingleFileComponentStructures(format: SingleFileComponentFormat, source: string, options?: StructureOptions) {
+		const scripts = await this._parser.proxy._getSingleFileComponentStructures(format, source, withoutTransform(options));
+		scripts.forEach(script => transformed(script.structure, options));
+		return scripts;
+	}
+
+	async parseBatch(files: readonly BatchParseFile[], options?: StructureOptions): Promise<BatchStructure[]> {
+		const parserOptions = withoutTransform(options);
+		// the files of a language are parsed one after another, so that the language is resolved once rather than for every file
+		const indicesByLanguage = new Map<WASMLanguage, number[]>();
+		files.forEach((file, i) => {
+			const indices = indicesByLanguage.get(file.language);
+			if (indices) {
+				indices.push(i);
+			} else {
+				indicesByLanguage.set(file.language, [i]);
+			}
+		});
+		const order = [...indicesByLanguage.values()].flat();
+
+		const structures = new Array<BatchStructure>(files.length);
+		if (!this._useWorker) {
+			const languages = new Map<WASMLanguage, Promise<void>>();
+			for (const i of order) {
+				const file = files[i];
+				try {
+					await this._resolveBatchLanguage(this._parser.proxy, file.language, languages);
+					structures[i] = { structure: transformed(await this._parser.proxy._getStructure(file.language, file.source, parserOptions), options) };
+				} catch (err) {
+					structures[i] = { error: errorMessage(err) };
+				}
+			}
+			return structures;
+		}
+		const results = await this._batchWorkers.value.map(order, async (proxy, i) => {
+			let languages = this._batchWorkerLanguages.get(proxy);
+			if (!languages) {
+				languages = new Map();
+				this._batchWorkerLanguages.set(proxy, languages);
+			}
+			await this._resolveBatchLanguage(proxy, files[i].language, languages);
+			return transformed(await proxy._getStructure(files[i].language, files[i].source, parserOptions), options);
+		});
+		results.forEach((r, k) => structures[order[k]] = r.status === 'fulfilled' ? { structure: r.value } : { error: errorMessage(r.reason) });
+		return structures;
+	}
+
+	/**
+	 * Registers `language` with the parser of `proxy` if it's a registered one and loads its grammar, unless `resolved` has it already.
+	 */
+	private _resolveBatchLanguage(proxy: Proxied<ParserType>, language: WASMLanguage, resolved: Map<WASMLanguage, Promise<void>>): Promise<void> {
+		let resolution = resolved.get(language);
+		if (!resolution) {
+			// batch workers, including those replacing crashed ones, only know the registered languages they're told about
+			const registration = this._registrations.find(r => r.id === language);
+			resolution = registration ? proxy._registerLanguage(registration) : proxy._preloadLanguages([language]);
+			resolved.set(language, resolution);
+		}
+		return resolution;
+	}
+
+	getStructureCacheStats() {
+		return this._parser.proxy._getStructureCacheStats();
+	}
+
+	getLanguageCapabilities(language: WASMLanguage) {
+		return this._parser.proxy._getLanguageCapabilities(language);
+	}
+
+	getEnclosingSymbol(tree: OverlayNode, offset: number) {
+		// structures are plain data, so there's no need to go through the worker
+		return getEnclosingSymbol(tree, offset);
+	}
+
+	getSymbolsInRange(tree: OverlayNode, startOffset: number, endOffset: number, source: string) {
+		return getSymbolsInRange(tree, startOffset, endOffset, source);
+	}
+
+	diffStructures(oldNodes: readonly OverlayNode[], newNodes: readonly OverlayNode[]) {
+		return diffStructures(oldNodes, newNodes);
+	}
+
+	buildSymbolIndex(structure: OverlayNode) {
+		return buildSymbolIndex(structure);
+	}
+
+	extractSymbol(language: WASMLanguage, source: string, qualifiedName: string, options?: ExtractSymbolOptions & { allMatches?: false }): Promise<ExtractedSymbol | undefined>;
+	extractSymbol(language: WASMLanguage, source: string, qualifiedName: string, options: ExtractSymbolOptions & { allMatches: true }): Promise<ExtractedSymbol[]>;
+	async extractSymbol(language: WASMLanguage, source: string, qualifiedName: string, options?: ExtractSymbolOptions): Promise<ExtractedSymbol | ExtractedSymbol[] | undefined> {
+		const symbols = await this._parser.proxy._extractSymbols(language, source, qualifiedName, options?.includeLeadingComment ?? false);
+		return options?.allMatches ? symbols : symbols[0];
+	}
+
+	reparse(language: WASMLanguage, source: string, previous: TreeSitterSourceEdits) {
+		return this._parser.proxy._reparse(language, source, previous);
+	}
+
+	async parse(language: WASMLanguage, source: string, options?: { token?: CancellationToken; timeoutMicros?: number }): Promise<'parsed' | ParseAbortReason> {
+		const token = options?.token ?? CancellationToken.None;
+		if (token.isCancellationRequested) {
+			return 'cancelled';
+		}
+		// tokens can't be passed to the worker, so only waiting for the parse is cancelled
+		return raceCancellation(this._parser.proxy._tryParse(language, source, options?.timeoutMicros), token, 'cancelled');
+	}
+
+	preloadLanguages(languages: readonly WASMLanguage[]) {
+		return this._parser.proxy._preloadLanguages(languages);
+	}
+
+	async registerLanguage(registration: LanguageRegistration): Promise<WASMLanguage> {
+		await this._parser.proxy._registerLanguage(registration);
+		if (!this._registrations.some(r => r.id === registration.id)) {
+			this._registrations.push(registration);
+		}
+		const language = registration.id as WASMLanguage;
+		for (const languageId of [registration.id, ...registration.aliases ?? []]) {
+			this._registeredLanguages.set(languageId, language);
+		}
+		for (const extension of registration.extensions ?? []) {
+			this.registerExtensionLanguage(extension, language);
+		}
+		return language;
+	}
+
+	registerExtensionLanguage(extension: string, language: WASMLanguage): IDisposable {
+		const key = (extension.startsWith('.') ? extension : `.${extension}`).toLowerCase();
+		const registration = { language };
+		this._extensionLanguages.set(key, registration);
+		return toDisposable(() => {
+			if (this._extensionLanguages.get(key) === registration) {
+				this._extensionLanguages.delete(key);
+			}
+		});
+	}
+
+	resolveLanguage(fileName: string, source: string, language?: WASMLanguage): WASMLanguage | undefined {
+		return language ?? this._registeredExtensionLanguageOf(fileName) ?? detectLanguage(fileName, source);
+	}
+
+	private _registeredExtensionLanguageOf(fileName: string): WASMLanguage | undefined {
+		const baseName = fileName.slice(Math.max(fileName.lastIndexOf('/'), fileName.lastIndexOf('\\')) + 1).toLowerCase();
+		let longest: string | undefined;
+		for (const extension of this._extensionLanguages.keys()) {
+			// a file named like the extension, e.g., `.go.tmpl`, has no extension, like `.gitignore`
+			if (baseName.endsWith(extension) && baseName.length > extension.length && (longest === undefined || extension.length > longest.length)) {
+				longest = extension;
+			}
+		}
+		return longest !== undefined ? this._extensionLanguages.get(longest)!.language : undefined;
+	}
 }
 
 type Proxied<ProxyType> = {
 	[K in keyof ProxyType]: ProxyType[K] extends ((...args: infer Args) => infer R) ? (...args: Args) => Promise<Awaited<R>> : never;
 };
 
+/**
+ * When the parser worker is replaced by a new one to reclaim the memory of the grammars it loaded, which web-tree-sitter never frees, see {@link WorkerOrLocal}.
+ */
+interface WorkerRecycling<T> {
+	/** Number of grammars a worker may load before it's replaced */
+	readonly maxLoadedLanguages: number;
+	/** @returns languages whose grammars a call with `args` may load */
+	languagesOf(args: readonly unknown[]): string[];
+	/** Prepares a worker for its first call with `language`, e.g., tells it about a registered language */
+	prepare(proxy: Proxied<T>, language: string): Promise<void>;
+	loadedLanguageCount(proxy: Proxied<T>): Promise<number>;
+}
+
+interface RecyclableWorker<T> {
+	readonly worker: WorkerWithRpcProxy<T>;
+	readonly proxy: Proxied<T>;
+	/** languages the worker was prepared for, see {@link WorkerRecycling.prepare} */
+	readonly languages: Map<string, Promise<void>>;
+	/** number of calls the worker hasn't answered yet */
+	pending: number;
+	/** whether the worker was replaced, so that it's terminated once it answered all pending calls */
+	isRetired: boolean;
+}
+
 class WorkerOrLocal<T extends object> {
 
 	private readonly _local: T;
 
 	public get proxy(): Proxied<T> {
 		if (this._useWorker) {
-			return this._worker.value.proxy;
+			this._worker ??= this._createWorker();
+			return this._worker.proxy;
 		}
 		return <any>this._local;
 	}
 
-	private _worker: Lazy<WorkerWithRpcProxy<T>>;
+	private _worker: RecyclableWorker<T> | undefined;
+	/** replaced workers that are still answering calls */
+	private readonly _retiredWorkers = new Set<RecyclableWorker<T>>();
 
 	constructor(
 		local: T,
 		private readonly _workerPath: string,
 		private readonly _useWorker: boolean,
+		private readonly _recycling: WorkerRecycling<T>,
 	) {
 		this._local = new Proxy(local, {
 			get: (target, prop, receiver) => {
@@ -107,16 +460,103 @@ class WorkerOrLocal<T extends object> {
 				};
 			},
 		});
-		this._worker = new Lazy(() => new WorkerWithRpcProxy<T>(this._workerPath, { name: 'Parser worker' }));
+	}
+
+	private _createWorker(): RecyclableWorker<T> {
+		const worker = new WorkerWithRpcProxy<T>(this._workerPath, { name: 'Parser worker' });
+		const recyclable: RecyclableWorker<T> = {
+			worker,
+			proxy: new Proxy({}, {
+				get: (_target, fn: string) => async (...args: unknown[]) => {
+					const languages = this._recycling.languagesOf(args);
+					const newLanguages = languages.filter(language => !recyclable.languages.has(language));
+					for (const language of newLanguages) {
+						recyclable.languages.set(language, this._recycling.prepare(worker.proxy, language));
+					}
+					recyclable.pending++;
+					try {
+						await Promise.all(languages.map(language => recyclable.languages.get(language)));
+						return await (worker.proxy as any)[fn](...args);
+					} finally {
+						recyclable.pending--;
+						if (newLanguages.length > 0) {
+							this._recycleIfFull(recyclable);
+						}
+						this._terminateIfRetired(recyclable);
+					}
+				},
+			}) as Proxied<T>,
+			languages: new Map(),
+			pending: 0,
+			isRetired: false,
+		};
+		return recyclable;
+	}
+
+	/**
+	 * Replaces `recyclable` by a new worker if it loaded more than {@link WorkerRecycling.maxLoadedLanguages} grammars;
+	 * calls that are pending are still answered by it, and the next ones load the grammars they need again.
+	 */
+	private async _recycleIfFull(recyclable: RecyclableWorker<T>): Promise<void> {
+		if (recyclable.isRetired) {
+			return;
+		}
+		recyclable.pending++;
+		try {
+			const count = await this._recycling.loadedLanguageCount(recyclable.worker.proxy);
+			if (count > this._recycling.maxLoadedLanguages && !recyclable.isRetired) {
+				recyclable.isRetired = true;
+				this._retiredWorkers.add(recyclable);
+				if (this._worker === recyclable) {
+					this._worker = undefined;
+				}
+			}
+		} catch {
+			// the worker crashed, which its calls report
+		} finally {
+			recyclable.pending--;
+			this._terminateIfRetired(recyclable);
+		}
+	}
+
+	private _terminateIfRetired(recyclable: RecyclableWorker<T>): void {
+		if (recyclable.isRetired && recyclable.pending === 0 && this._retiredWorkers.delete(recyclable)) {
+			recyclable.worker.terminate();
+		}
 	}
 
 	dispose(): void {
-		if (this._worker.hasValue) {
-			this._worker.value.terminate();
+		this._worker?.worker.terminate();
+		this._worker = undefined;
+		for (const recyclable of this._retiredWorkers) {
+			recyclable.worker.terminate();
 		}
+		this._retiredWorkers.clear();
 	}
 }
 
+/**
+ * @returns `options` without their transform, which can't be passed to the parser: the worker clones its arguments, and the local parser passes them as JSON
+ */
+function withoutTransform(options: StructureOptions | undefined): StructureOptions | undefined {
+	return options?.transform ? { ...options, transform: undefined } : options;
+}
+
+/**
+ * Applies the transform of `options` to a structure the parser computed without it, which is a copy of its own, see {@link withoutTransform}.
+ */
+function transformed<T extends OverlayNode | undefined>(structure: T, options: StructureOptions | undefined): T {
+	if (structure && options?.transform) {
+		transformStructure(structure, options.transform);
+	}
+	return structure;
+}
+
+function errorMessage(err: unknown): string {
+	// errors lose their classes on their way from the worker, so only their messages are kept
+	return err instanceof Error ? err.message : String(err);
+}
+
 function viaJSON<T>(obj: T): T {
 	if (typeof obj === 'undefined') {
 		return obj;
@@ -3,86 +3,259 @@
  *  Licensed under the MIT License. See License.txt in the project root for license information.
  *--------------------------------------------------------------------------------------------*/
 
+import { createHash } from 'crypto';
 import Parser = require('web-tree-sitter');
 import { DisposablesLRUCache } from '../../../util/common/cache';
+import { CancellationToken } from '../../../util/vs/base/common/cancellation';
 import { IDisposable } from '../../../util/vs/base/common/lifecycle';
-import { LanguageLoader } from './languageLoader';
+import { grammarVersionOf, LanguageLoader } from './languageLoader';
+import { TreeSitterOffsetRange, TreeSitterSourceEdits } from './nodes';
+import { ParserPool } from './parserPool';
 import { WASMLanguage } from './treeSitterLanguages';
 
 export class ParserWithCaching implements IDisposable {
 
 	public static INSTANCE = new ParserWithCaching();
 
-	static CACHE_SIZE_PER_LANGUAGE = 5;
+	/** Default number of cached parse trees, shared among all languages */
+	static CACHE_SIZE = 50;
 
-	private readonly caches: Map<WASMLanguage, DisposablesLRUCache<CacheableParseTree>>;
+	/** Default time budget of a parse in microseconds; `0` means unlimited */
+	static TIMEOUT_MICROS = 0;
+
+	private _cache: DisposablesLRUCache<CacheableParseTree> | null;
 	private readonly languageLoader: LanguageLoader;
-	private _parser: Parser | null;
+	private readonly parserPool: ParserPool;
 
-	constructor() {
-		this.caches = new Map<WASMLanguage, DisposablesLRUCache<CacheableParseTree>>();
-		this.languageLoader = new LanguageLoader();
-		this._parser = null;
+	/**
+	 * @param cacheSize number of cached parse trees; defaults to {@link ParserWithCaching.CACHE_SIZE} at the time of the first parse
+	 * @param maxResidentLanguages number of grammars whose queries and parsers are kept; defaults to {@link LanguageLoader.MAX_RESIDENT_LANGUAGES}
+	 */
+	constructor(
+		private readonly cacheSize?: number,
+		maxResidentLanguages?: number
+	) {
+		this._cache = null;
+		this.parserPool = new ParserPool();
+		this.languageLoader = new LanguageLoader(maxResidentLanguages, lang => this.parserPool.deleteIdleParsers(lang));
 	}
 
-	/** @remarks must not be called before `Parser.init()` */
-	private get parser() {
-		if (!this._parser) {
-			this._parser = new Parser();
-		}
-		return this._parser;
+	/**
+	 * @param previous if given and the parse tree of `previous.previousSource` is still cached, `source` is parsed incrementally
+	 * by reusing that tree; otherwise, `source` is parsed from scratch
+	 *
+	 * @throws {ParseAbortedError} if the parse is cancelled or, as a {@link ParseTimeoutError}, exceeds its time budget; nothing is cached in that case
+	 *
+	 * @remarks Do not `delete()` the returned parse tree manually.
+	 */
+	async parse(lang: WASMLanguage, source: string, previous?: TreeSitterSourceEdits, options?: ParseOptions): Promise<ParseTreeReference> {
+		return (await this.parseWithChangedRanges(lang, source, previous, options)).treeRef;
 	}
 
 	/**
+	 * Same as {@link parse} but also reports the ranges of `source` whose syntactic structure differs from `previous.previousSource`.
+	 *
 	 * @remarks Do not `delete()` the returned parse tree manually.
 	 */
-	async parse(lang: WASMLanguage, source: string): Promise<ParseTreeReference> {
+	reparse(lang: WASMLanguage, source: string, previous: TreeSitterSourceEdits, options?: ParseOptions): Promise<IncrementalParseResult> {
+		return this.parseWithChangedRanges(lang, source, previous, options);
+	}
+
+	private async parseWithChangedRanges(lang: WASMLanguage, source: string, previous: TreeSitterSourceEdits | undefined, options?: ParseOptions): Promise<IncrementalParseResult> {
 
 		await Parser.init();
+		throwIfCancelled(options?.token);
 
-		const cache = this.getParseTreeCache(lang);
+		const cache = this.cache;
+		const cacheKey = contentKey(lang, source);
 
-		let cacheEntry = cache.get(source);
+		let cacheEntry = cache.get(cacheKey);
 		if (cacheEntry) {
-			return cacheEntry.createReference();
+			return { treeRef: cacheEntry.createReference(), changedRanges: undefined };
 		}
 
-		const parserLang = await this.languageLoader.loadLanguage(lang);
-		this.parser.setLanguage(parserLang);
+		// the grammar must stay resident despite parses of other languages while this one awaits it
+		const languagePin = this.languageLoader.pin(lang);
+		try {
+			const parserLang = await this.languageLoader.loadLanguage(lang);
 
-		// check again the cache, maybe someone else has already parsed the source during the await
-		cacheEntry = cache.get(source);
-		if (cacheEntry) {
-			return cacheEntry.createReference();
-		}
+			// check again the cache, maybe someone else has already parsed the source during the await
+			cacheEntry = cache.get(cacheKey);
+			if (cacheEntry) {
+				return { treeRef: cacheEntry.createReference(), changedRanges: undefined };
+			}
 
-		const parseTree = this.parser.parse(source);
-		cacheEntry = new CacheableParseTree(parseTree);
-		cache.put(source, cacheEntry);
+			// the parse itself is synchronous, so this is the last chance to honor a cancellation
+			throwIfCancelled(options?.token);
 
-		return cacheEntry.createReference();
+			return this.parseWithLanguage(lang, parserLang, source, cacheKey, previous, options);
+		} finally {
+			languagePin.dispose();
+		}
 	}
 
-	dispose() {
-		if (this._parser) {
-			this.parser.delete();
-			this._parser = null;
+	private parseWithLanguage(lang: WASMLanguage, parserLang: Parser.Language, source: string, cacheKey: string, previous: TreeSitterSourceEdits | undefined, options: ParseOptions | undefined): IncrementalParseResult {
+		const cache = this.cache;
+		const previousCacheEntry = previous ? cache.get(contentKey(lang, previous.previousSource)) : undefined;
+		const timeoutMicros = options?.timeoutMs ? options.timeoutMs * 1000 : (options?.timeoutMicros ?? ParserWithCaching.TIMEOUT_MICROS);
+		const parser = this.parserPool.acquire(lang, parserLang);
+		let tree: Parser.Tree;
+		let changedRanges: TreeSitterOffsetRange[] | undefined;
+		parser.setTimeoutMicros(timeoutMicros);
+		try {
+			({ tree, changedRanges } = previous && previousCacheEntry
+				? this.parseIncrementally(parser, parserInputOf(lang, source), previousCacheEntry, previous.edits)
+				: { tree: parser.parse(parserInputOf(lang, source)), changedRanges: undefined });
+		} catch (e) {
+			if (timeoutMicros <= 0) {
+				throw e;
+			}
+			// a halted parse is otherwise resumed by the next parse, which may be of a different source
+			parser.reset();
+			throw new ParseTimeoutError(timeoutMicros / 1000);
+		} finally {
+			parser.setTimeoutMicros(0);
+			this.parserPool.release(lang, parser);
 		}
-		for (const cache of this.caches.values()) {
-			cache.dispose();
+		const cacheEntry = new CacheableParseTree(tree);
+		cache.put(cacheKey, cacheEntry);
+
+		return { treeRef: cacheEntry.createReference(), changedRanges };
+	}
+
+	private parseIncrementally(parser: Parser, source: string, previousCacheEntry: CacheableParseTree, edits: TreeSitterSourceEdits['edits']): { tree: Parser.Tree; changedRanges: TreeSitterOffsetRange[] } {
+		const previousTreeRef = previousCacheEntry.createReference();
+		// edit a copy because the previous tree is cached and may be referenced by others
+		const editedTree = previousTreeRef.tree.copy();
+		try {
+			for (const edit of edits) {
+				editedTree.edit(edit);
+			}
+			const tree = parser.parse(source, editedTree);
+			const changedRanges = editedTree.getChangedRanges(tree).map(r => ({ startIndex: r.startIndex, endIndex: r.endIndex }));
+			return { tree, changedRanges };
+		} finally {
+			editedTree.delete();
+			previousTreeRef.dispose();
 		}
 	}
 
-	private getParseTreeCache(lang: WASMLanguage) {
-		let cache = this.caches.get(lang);
-		if (!cache) {
-			cache = new DisposablesLRUCache<CacheableParseTree>(ParserWithCaching.CACHE_SIZE_PER_LANGUAGE);
-			this.caches.set(lang, cache);
+	/**
+	 * Loads the grammars of `langs` ahead of their first parse, e.g., at activation, so that the first parse doesn't wait for them.
+	 * Grammars that are loaded or being loaded aren't loaded again, so calls may overlap; like any other use,
+	 * preloading more than `maxResidentLanguages` grammars releases what was created for the least recently used ones.
+	 */
+	async preloadLanguages(langs: readonly WASMLanguage[]): Promise<void> {
+		await Parser.init();
+		await Promise.all(langs.map(lang => this.languageLoader.loadLanguage(lang)));
+	}
+
+	/**
+	 * Number of grammars this parser loaded, which web-tree-sitter keeps in memory for as long as the thread it runs in, see {@link LanguageLoader}.
+	 */
+	get loadedLanguageCount(): number {
+		return this.languageLoader.loadedLanguageCount;
+	}
+
+	/**
+	 * Drops all cached parse trees; parse trees that are still referenced are deleted once their references are disposed.
+	 */
+	clearCache() {
+		this._cache?.clear();
+	}
+
+	dispose() {
+		this.parserPool.dispose();
+		this.clearCache();
+	}
+
+	private get cache() {
+		if (!this._cache) {
+			this._cache = new DisposablesLRUCache<CacheableParseTree>(this.cacheSize ?? ParserWithCaching.CACHE_SIZE);
 		}
-		return cache;
+		return this._cache;
+	}
+}
+
+/**
+ * @returns a key identifying `source` parsed as `lang` with the current grammar of `lang`, see {@link grammarVersionOf}, that doesn't retain `source` itself
+ */
+export function contentKey(lang: WASMLanguage, source: string): string {
+	return `${lang}:${grammarVersionOf(lang)}:${createHash('sha256').update(source).digest('hex')}`;
+}
+
+/** Languages whose grammars don't know shebang lines but have `//` line comments, e.g., for Go scripts run with `#!/usr/bin/env gorun` */
+const shebangAsCommentLanguages = new Set([WASMLanguage.Go, WASMLanguage.Java, WASMLanguage.C, WASMLanguage.Cpp, WASMLanguage.Csharp]);
+
+/**
+ * @returns `source` as it's given to the parser: a leading byte order mark is replaced by a space, and for {@link shebangAsCommentLanguages},
+ * the `#!` of a leading shebang line by `//`, which would be syntax errors otherwise. The replacements are as long as what they replace,
+ * so offsets and positions in the parse tree still match `source`, but texts of its nodes, e.g., `rootNode.text`, have the replacements.
+ */
+function parserInputOf(lang: WASMLanguage, source: string): string {
+	const bomLength = source.charCodeAt(0) === 0xFEFF ? 1 : 0;
+	const shebangLength = shebangAsCommentLanguages.has(lang) && source.startsWith('#!', bomLength) ? 2 : 0;
+	if (bomLength === 0 && shebangLength === 0) {
+		return source;
+	}
+	return (bomLength ? ' ' : '') + (shebangLength ? '//' : '') + source.slice(bomLength + shebangLength);
+}
+
+export interface ParseOptions {
+	/**
+	 * Checked before the parse starts; a parse that has started runs until it completes or exceeds `timeoutMicros`.
+	 */
+	readonly token?: CancellationToken;
+	/**
+	 * Time budget of the parse in microseconds; defaults to {@link ParserWithCaching.TIMEOUT_MICROS}, `0` means unlimited.
+	 */
+	readonly timeoutMicros?: number;
+	/**
+	 * Time budget of the parse in milliseconds, e.g., for a pathological source that would otherwise take seconds to parse;
+	 * takes precedence over `timeoutMicros`.
+	 */
+	readonly timeoutMs?: number;
+}
+
+export type ParseAbortReason = 'cancelled' | 'timedOut';
+
+/**
+ * Thrown when a parse is aborted, see {@link ParseOptions}.
+ */
+export class ParseAbortedError extends Error {
+	constructor(readonly reason: ParseAbortReason) {
+		super(reason === 'cancelled' ? 'parse cancelled' : 'parse timed out');
+		this.name = 'ParseAbortedError';
 	}
 }
 
+/**
+ * Thrown when a parse exceeds its time budget; the parser is reset, so the next parse starts over.
+ * Callers can fall back to a structure that doesn't need a parse tree, e.g., `getStructureUsingIndentation`.
+ */
+export class ParseTimeoutError extends ParseAbortedError {
+	constructor(readonly timeoutMs: number) {
+		super('timedOut');
+		this.name = 'ParseTimeoutError';
+		this.message = `parse timed out after ${timeoutMs}ms`;
+	}
+}
+
+function throwIfCancelled(token: CancellationToken | undefined) {
+	if (token?.isCancellationRequested) {
+		throw new ParseAbortedError('cancelled');
+	}
+}
+
+export interface IncrementalParseResult {
+	treeRef: ParseTreeReference;
+	/**
+	 * Ranges of the new source whose syntactic structure changed;
+	 * `undefined` if the source wasn't parsed incrementally, e.g., because its tree was already cached.
+	 */
+	changedRanges: TreeSitterOffsetRange[] | undefined;
+}
+
 /**
  * A parse tree that can be cached (i.e. it can be referenced multiple
  * times and will be disppsed when it is evicted from cache and all
@@ -169,7 +342,30 @@ export function _dispose() {
 
 /**
  * Parses the given source code and returns the root node of the resulting syntax tree.
+ *
+ * @param previous allows parsing incrementally, see {@link ParserWithCaching.parse}
+ */
+export function _parse(language: WASMLanguage, source: string, previous?: TreeSitterSourceEdits, options?: ParseOptions): Promise<ParseTreeReference> {
+	return ParserWithCaching.INSTANCE.parse(language, source, previous, options);
+}
+
+/**
+ * Loads the grammars of the given languages ahead of time, see {@link ParserWithCaching.preloadLanguages}.
+ */
+export function _preloadLanguages(languages: readonly WASMLanguage[]): Promise<void> {
+	return ParserWithCaching.INSTANCE.preloadLanguages(languages);
+}
+
+/**
+ * @returns number of grammars the parser loaded, see {@link ParserWithCaching.loadedLanguageCount}
+ */
+export function _getLoadedLanguageCount(): number {
+	return ParserWithCaching.INSTANCE.loadedLanguageCount;
+}
+
+/**
+ * Parses the given source code incrementally, see {@link ParserWithCaching.reparse}.
  */
-export function _parse(language: WASMLanguage, source: string): Promise<ParseTreeReference> {
-	return ParserWithCaching.INSTANCE.parse(language, source);
+export function _reparseWithCache(language: WASMLanguage, source: string, previous: TreeSitterSourceEdits, options?: ParseOptions): Promise<IncrementalParseResult> {
+	return ParserWithCaching.INSTANCE.reparse(language, source, previous, options);
 }
@@ -0,0 +1,96 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo } from './util';
+
+export const phpStructure: StructureLanguage = {
+	describe: describePhpNode,
+	// kind `method_declaration` named `__construct` -> kind `constructor`
+	nodeKindOf: (capture, nodeKind) => nodeKind === 'method_declaration' && capture.node.childForFieldName('name')?.text.toLowerCase() === '__construct' ? 'constructor' : undefined,
+	endIndexOf: (syntaxNode, nodeKind, source) => {
+		// a namespace without braces, i.e., `namespace App;`, encloses what follows up to the next namespace
+		if (nodeKind !== 'namespace_definition' || syntaxNode.childForFieldName('body') !== null) {
+			return undefined;
+		}
+		let nextNamespace = syntaxNode.nextNamedSibling;
+		while (nextNamespace !== null && nextNamespace.type !== 'namespace_definition') {
+			nextNamespace = nextNamespace.nextNamedSibling;
+		}
+		// up to where the next namespace starts, i.e., after the line break that follows its previous sibling as for any node
+		const prev = nextNamespace?.previousSibling;
+		if (!prev) {
+			return source.length;
+		}
+		const nlIdx = source.substring(prev.endIndex, nextNamespace!.startIndex).indexOf('\n');
+		return nlIdx === -1 ? prev.endIndex : prev.endIndex + nlIdx + 1;
+	},
+};
+
+function describePhpNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	switch (syntaxNode.type) {
+		case 'namespace_definition': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text; // e.g., `App\Models`
+			break;
+		}
+		case 'class_declaration':
+		case 'interface_declaration':
+		case 'trait_declaration':
+		case 'enum_declaration': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.childForFieldName('body'), source);
+			// `extends` of an interface may list several interfaces, e.g., `interface Repository extends Countable, IteratorAggregate`
+			const baseTypes = phpTypeNamesOf(syntaxNode.namedChildren.find(c => c.type === 'base_clause'));
+			const interfaces = phpTypeNamesOf(syntaxNode.namedChildren.find(c => c.type === 'class_interface_clause'));
+			if (syntaxNode.type === 'interface_declaration') {
+				overlayNode.interfaces = baseTypes;
+			} else {
+				overlayNode.superclass = baseTypes?.[0];
+				overlayNode.interfaces = interfaces;
+			}
+			break;
+		}
+		case 'function_definition':
+		case 'method_declaration': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.childForFieldName('body'), source).replace(/;$/, ''); // abstract methods have no body
+			break;
+		}
+		case 'enum_case': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			break;
+		}
+		case 'property_declaration':
+		case 'const_declaration': {
+			// a declaration of several properties or constants, e.g., `private $a, $b;`, doesn't declare a single symbol
+			const elements = syntaxNode.namedChildren.filter(c => c.type === 'property_element' || c.type === 'const_element');
+			if (elements.length === 1) {
+				overlayNode.name = elements[0].namedChildren.find(c => c.type === 'variable_name' || c.type === 'name')?.text; // e.g., `$name` or `MAX`
+			}
+			overlayNode.detail = syntaxNode.text;
+			break;
+		}
+		case 'namespace_use_declaration': {
+			// only a declaration of a single name, e.g., `use App\Models\User as Model;`, imports a path
+			const clauses = syntaxNode.namedChildren.filter(c => c.type === 'namespace_use_clause');
+			if (clauses.length === 1 && !syntaxNode.namedChildren.some(c => c.type === 'namespace_use_group')) {
+				const [clause] = clauses;
+				overlayNode.path = clause.namedChildren.find(c => c.type === 'qualified_name' || c.type === 'name')?.text.replace(/^\\/, '');
+				// the alias is a field in newer grammar versions and within a `namespace_aliasing_clause` in older ones
+				overlayNode.alias = (clause.childForFieldName('alias') ?? clause.namedChildren.find(c => c.type === 'namespace_aliasing_clause')?.namedChildren[0])?.text;
+			}
+			break;
+		}
+	}
+}
+
+/**
+ * @returns names of the types listed by an `extends` or `implements` clause as written, e.g., `['Countable', 'Illuminate\Support\Arrayable']`
+ */
+function phpTypeNamesOf(clause: SyntaxNode | undefined): string[] | undefined {
+	return clause?.namedChildren.filter(c => c.type === 'name' || c.type === 'qualified_name').map(c => c.text);
+}
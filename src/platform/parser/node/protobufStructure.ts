@@ -0,0 +1,96 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo, unquote } from './util';
+
+export const protobufStructure: StructureLanguage = { describe: describeProtobufNode };
+
+function describeProtobufNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	switch (syntaxNode.type) {
+		case 'syntax': {
+			// `proto2` or `proto3`, e.g., to tell whether a field without a label is optional
+			overlayNode.value = /proto[23]/.exec(syntaxNode.text)?.[0];
+			overlayNode.detail = syntaxNode.text;
+			break;
+		}
+		case 'package': {
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'full_ident')?.text;
+			overlayNode.detail = syntaxNode.text;
+			break;
+		}
+		case 'import': {
+			const path = syntaxNode.namedChildren.find(c => c.type === 'string');
+			overlayNode.path = path && unquote(path.text);
+			overlayNode.detail = syntaxNode.text;
+			break;
+		}
+		case 'message':
+		case 'enum':
+		case 'service': {
+			// e.g., `message Order` without its body
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === `${syntaxNode.type}_name`)?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.children.find(c => c.type === '{' || c.type === 'message_body' || c.type === 'enum_body') ?? null, source);
+			break;
+		}
+		case 'rpc': {
+			// e.g., `rpc GetOrder(GetOrderRequest) returns (Order)`, whose request is its parameter and whose response is its result
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'rpc_name')?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.children.find(c => c.type === '{' || c.type === ';') ?? null, source);
+			const [request, response] = protobufRpcTypesOf(syntaxNode);
+			overlayNode.parameters = request === undefined ? [] : [{ type: request }];
+			overlayNode.results = response === undefined ? [] : [{ type: response }];
+			break;
+		}
+		case 'field':
+		case 'map_field':
+		case 'oneof_field': {
+			// e.g., name `items`, type `Item`, and number 3 for `repeated Item items = 3;`; a proto2 field may be `required` or have a default, see the detail
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'identifier')?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.children.find(c => c.type === ';') ?? null, source);
+			if (syntaxNode.type === 'map_field') {
+				const closingBracket = syntaxNode.children.find(c => c.type === '>');
+				overlayNode.type = closingBracket && source.substring(syntaxNode.startIndex, closingBracket.endIndex); // e.g., `map<string, int32>`
+			} else {
+				overlayNode.type = syntaxNode.namedChildren.find(c => c.type === 'type')?.text;
+			}
+			const fieldNumber = Number(syntaxNode.namedChildren.find(c => c.type === 'field_number')?.text);
+			overlayNode.fieldNumber = Number.isInteger(fieldNumber) ? fieldNumber : undefined;
+			break;
+		}
+		case 'oneof': {
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'identifier')?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.children.find(c => c.type === '{') ?? null, source);
+			break;
+		}
+		case 'enum_field': {
+			// e.g., name `PENDING` and value `1` for `PENDING = 1;`
+			overlayNode.name = syntaxNode.namedChildren.find(c => c.type === 'identifier')?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.children.find(c => c.type === ';') ?? null, source);
+			const value = syntaxNode.namedChildren.find(c => c.type === 'int_lit');
+			overlayNode.value = value && (syntaxNode.children.some(c => c.type === '-') ? `-${value.text}` : value.text);
+			break;
+		}
+	}
+}
+
+/**
+ * @returns request and response types of an `rpc`, each after `stream` if it's streamed, e.g., `['stream Chunk', 'UploadStatus']`
+ */
+function protobufRpcTypesOf(rpc: SyntaxNode): string[] {
+	const types: string[] = [];
+	let isStreamed = false;
+	for (const child of rpc.children) {
+		if (child.type === 'stream') {
+			isStreamed = true;
+		} else if (child.type === 'message_or_enum_type') {
+			types.push(isStreamed ? `stream ${child.text}` : child.text);
+			isStreamed = false;
+		}
+	}
+	return types;
+}
@@ -0,0 +1,43 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo } from './util';
+
+export const pythonStructure: StructureLanguage = { describe: describePythonNode };
+
+function describePythonNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	switch (syntaxNode.type) {
+		case 'decorated_definition': {
+			// the decorators and the decorated class or function make up a single declaration
+			overlayNode.decorators = syntaxNode.namedChildren.filter(c => c.type === 'decorator').map(d => d.text);
+			const definition = syntaxNode.childForFieldName('definition');
+			if (definition) {
+				describePythonNode(definition, overlayNode, source);
+			}
+			break;
+		}
+		case 'function_definition':
+		case 'class_definition': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			// e.g., `async def fetch(url: str) -> bytes` without the trailing `:`
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.childForFieldName('body'), source).replace(/:$/, '');
+			overlayNode.typeParameters = syntaxNode.childForFieldName('type_parameters')?.text;
+			break;
+		}
+		case 'expression_statement': {
+			// module-level assignments of a single name, e.g., `timeout: float = 2.5`, declare a variable
+			const assignment = syntaxNode.parent?.type === 'module' ? syntaxNode.namedChildren[0] : undefined;
+			const left = assignment?.type === 'assignment' ? assignment.childForFieldName('left') : undefined;
+			if (left?.type === 'identifier') {
+				overlayNode.name = left.text;
+				overlayNode.type = assignment!.childForFieldName('type')?.text;
+			}
+			break;
+		}
+	}
+}
@@ -3,22 +3,53 @@
  *  Licensed under the MIT License. See License.txt in the project root for license information.
  *--------------------------------------------------------------------------------------------*/
 
-import type { Language, Query, QueryMatch, SyntaxNode } from 'web-tree-sitter';
+import type { Language, Query, QueryCapture, QueryMatch, SyntaxNode } from 'web-tree-sitter';
+import { LRUCache } from '../../../util/common/cache';
+import type { TreeSitterOffsetRange } from './nodes';
 
 
 class LanguageQueryCache {
 
-	private readonly map = new Map<string, Query>();
+	/** Number of compiled ad hoc queries kept per language, see {@link runQueryCaptures}; the least recently used are deleted */
+	static CACHE_SIZE = 100;
+
+	/** the parser's own queries, e.g., the structure queries, of which there are only so many */
+	private readonly queries = new Map<string, Query>();
+
+	private readonly adHocQueries = new LRUCache<Query>(LanguageQueryCache.CACHE_SIZE);
 
 	constructor(
 		private readonly language: Language
 	) { }
 
-	getQuery(query: string): Query {
-		if (!this.map.has(query)) {
-			this.map.set(query, this.language.query(query));
+	/**
+	 * @param isAdHoc whether `query` is one of the caller's, e.g., of `runQuery`, rather than one of the parser's
+	 */
+	getQuery(query: string, isAdHoc: boolean): Query {
+		let compiled = this.queries.get(query);
+		if (compiled) {
+			return compiled;
+		}
+		if (!isAdHoc) {
+			compiled = this.adHocQueries.deleteKey(query) ?? this.language.query(query);
+			this.queries.set(query, compiled);
+			return compiled;
+		}
+		compiled = this.adHocQueries.get(query);
+		if (!compiled) {
+			compiled = this.language.query(query);
+			// an evicted query isn't in use, as queries are run right after they're looked up
+			this.adHocQueries.put(query, compiled)?.[1].delete();
+		}
+		return compiled;
+	}
+
+	dispose(): void {
+		for (const query of [...this.queries.values(), ...this.adHocQueries.getValues()]) {
+			query.delete();
 		}
-		return this.map.get(query)!;
+		this.queries.clear();
+		this.adHocQueries.clear();
 	}
 }
 
@@ -28,20 +59,78 @@ class QueryCache {
 
 	private readonly map = new Map<Language, LanguageQueryCache>();
 
-	getQuery(language: Language, query: string): Query {
+	getQuery(language: Language, query: string, isAdHoc = false): Query {
 		if (!this.map.has(language)) {
 			this.map.set(language, new LanguageQueryCache(language));
 		}
-		return this.map.get(language)!.getQuery(query);
+		return this.map.get(language)!.getQuery(query, isAdHoc);
+	}
+
+	deleteQueries(language: Language): void {
+		this.map.get(language)?.dispose();
+		this.map.delete(language);
 	}
 }
 
-export function runQueries(queries: string[], root: SyntaxNode): QueryMatch[] {
+/**
+ * Compiles `queries` for `language` ahead of their first use, e.g., the structure queries when a grammar is loaded,
+ * so that they aren't compiled while a request waits for them; like the parser's other queries, they're reused until {@link deleteQueries}.
+ *
+ * @throws {TreeSitterQueryError} if a query is malformed; the queries before it are compiled nonetheless
+ */
+export function compileQueries(language: Language, queries: readonly string[]): void {
+	for (const query of queries) {
+		try {
+			QueryCache.INSTANCE.getQuery(language, query);
+		} catch (e) {
+			throw new TreeSitterQueryError(query, e);
+		}
+	}
+}
+
+/**
+ * Deletes the compiled queries of `language`, e.g., when its grammar is no longer resident; queries run for it afterwards are compiled again.
+ */
+export function deleteQueries(language: Language): void {
+	QueryCache.INSTANCE.deleteQueries(language);
+}
+
+/**
+ * @param range if given, only matches that intersect it are returned, e.g., to compute the structure of a part of a source
+ */
+export function runQueries(queries: string[], root: SyntaxNode, range?: TreeSitterOffsetRange): QueryMatch[] {
 	const matches: QueryMatch[] = [];
 	for (const query of queries) {
 		const compiledQuery = QueryCache.INSTANCE.getQuery(root.tree.getLanguage(), query);
-		const queryMatches = compiledQuery.matches(root);
+		const queryMatches = compiledQuery.matches(root, range && { startIndex: range.startIndex, endIndex: range.endIndex });
 		matches.push(...queryMatches);
 	}
 	return matches;
 }
+
+/**
+ * Thrown when a query fails to compile, e.g., because of a syntax error or an unknown node type.
+ */
+export class TreeSitterQueryError extends Error {
+	constructor(
+		public readonly query: string,
+		cause: unknown,
+	) {
+		super(`Invalid tree-sitter query: ${cause instanceof Error ? cause.message : String(cause)}\nQuery: ${query}`);
+		this.name = 'TreeSitterQueryError';
+	}
+}
+
+/**
+ * Runs a query of the caller's, e.g., of `runQuery`, whose compiled query is kept among the {@link LanguageQueryCache.CACHE_SIZE} most recently used of the language.
+ * @throws {TreeSitterQueryError} if `query` is malformed
+ */
+export function runQueryCaptures(query: string, root: SyntaxNode): QueryCapture[] {
+	let compiledQuery: Query;
+	try {
+		compiledQuery = QueryCache.INSTANCE.getQuery(root.tree.getLanguage(), query, /* isAdHoc */ true);
+	} catch (e) {
+		throw new TreeSitterQueryError(query, e);
+	}
+	return compiledQuery.captures(root);
+}
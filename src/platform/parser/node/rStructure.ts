@@ -0,0 +1,84 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo } from './util';
+
+export const rStructure: StructureLanguage = {
+	describe: describeRNode,
+	nodeKindOf: capture => rNodeKind(capture.node),
+};
+
+function describeRNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	switch (syntaxNode.type) {
+		case 'binary_operator': {
+			const { target, value } = rAssignmentOf(syntaxNode);
+			overlayNode.name = target?.type === 'string' ? rStringText(target) : target?.text;
+			if (value?.type === 'function_definition') {
+				// e.g., `area <- function(shape)`
+				overlayNode.detail = textUpTo(syntaxNode, value.childForFieldName('body'), source);
+			} else {
+				overlayNode.value = value?.text;
+			}
+			break;
+		}
+		case 'call': {
+			// S4 declarations are named by their first argument, e.g., `Circle` for `setClass("Circle", representation(radius = "numeric"))`
+			const values = syntaxNode.childForFieldName('arguments')?.namedChildren.filter(c => c.type === 'argument').map(c => c.childForFieldName('value')) ?? [];
+			overlayNode.name = values[0]?.type === 'string' ? rStringText(values[0]) : undefined;
+			if (syntaxNode.childForFieldName('function')?.text === 'setMethod' && overlayNode.name !== undefined && values[1]?.type === 'string') {
+				// methods of a generic are told apart by their signatures, e.g., `area(Circle)`
+				overlayNode.label = `${overlayNode.name}(${rStringText(values[1])})`;
+			}
+			// e.g., `setMethod("area", "Circle", function(shape)`; `setClass(...)` has no function
+			const fn = values.find(v => v?.type === 'function_definition');
+			if (fn) {
+				overlayNode.detail = textUpTo(syntaxNode, fn.childForFieldName('body'), source);
+			}
+			break;
+		}
+	}
+}
+
+const rS4Kinds: { [fn: string]: string } = {
+	setClass: 'class_definition',
+	setRefClass: 'class_definition',
+	setGeneric: 'generic_definition',
+	setMethod: 'method_definition',
+};
+
+/**
+ * @returns `function_definition` or `assignment` for an assignment by whether it assigns a function, or the kind of an S4 call, e.g., `class_definition` for `setClass(...)`
+ */
+function rNodeKind(syntaxNode: SyntaxNode): string | undefined {
+	switch (syntaxNode.type) {
+		case 'binary_operator':
+			return rAssignmentOf(syntaxNode).value?.type === 'function_definition' ? 'function_definition' : 'assignment';
+		case 'call':
+			// only S4 calls are captured, see `syntacticallyValidAtoms`
+			return rS4Kinds[syntaxNode.childForFieldName('function')?.text ?? ''];
+	}
+	return undefined;
+}
+
+/**
+ * @returns assigned variable and value of an assignment, which are swapped for rightward assignments, e.g., `10 -> n`
+ */
+function rAssignmentOf(assignment: SyntaxNode): { target: SyntaxNode | null; value: SyntaxNode | null } {
+	const isRightward = assignment.childForFieldName('operator')?.type.startsWith('->') ?? false;
+	return {
+		target: assignment.childForFieldName(isRightward ? 'rhs' : 'lhs'),
+		value: assignment.childForFieldName(isRightward ? 'lhs' : 'rhs'),
+	};
+}
+
+/**
+ * @returns text of a string literal without its quotes
+ */
+function rStringText(literal: SyntaxNode): string {
+	return literal.text.slice(1, -1);
+}
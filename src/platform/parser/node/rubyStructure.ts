@@ -0,0 +1,100 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+
+export const rubyStructure: StructureLanguage = {
+	describe: describeRubyNode,
+	// kind `call` of an attribute macro, e.g., `attr_reader :name` -> kind `attribute_declaration`
+	nodeKindOf: (capture, nodeKind) => nodeKind === 'call' && isRubyAttributeMacro(capture.node) ? 'attribute_declaration' : undefined,
+};
+
+function describeRubyNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	switch (syntaxNode.type) {
+		case 'module':
+		case 'class': {
+			const name = syntaxNode.childForFieldName('name');
+			const superclass = syntaxNode.childForFieldName('superclass'); // e.g., `< ApplicationRecord`
+			overlayNode.name = name?.text; // e.g., `Billing::Invoice`
+			overlayNode.superclass = superclass?.namedChildren[0]?.text;
+			overlayNode.detail = source.substring(syntaxNode.startIndex, (superclass ?? name ?? syntaxNode).endIndex);
+			break;
+		}
+		case 'singleton_class': {
+			// e.g., `class << self`, whose methods are singleton methods of the enclosing class
+			overlayNode.label = overlayNode.detail = source.substring(syntaxNode.startIndex, (syntaxNode.childForFieldName('value') ?? syntaxNode).endIndex);
+			break;
+		}
+		case 'method':
+		case 'singleton_method': {
+			const name = syntaxNode.childForFieldName('name');
+			overlayNode.name = name?.text;
+			// e.g., `def self.create(attrs)`
+			overlayNode.detail = source.substring(syntaxNode.startIndex, (syntaxNode.childForFieldName('parameters') ?? name ?? syntaxNode).endIndex);
+			if (syntaxNode.type === 'method') {
+				// `private` and its like don't apply to singleton methods
+				overlayNode.visibility = rubyVisibilityOf(syntaxNode);
+			}
+			break;
+		}
+		case 'call': {
+			if (overlayNode.kind === 'attribute_declaration') {
+				// an attribute macro that declares multiple attributes, e.g., `attr_reader :number, :issued_on`, doesn't declare a single symbol
+				const symbols = syntaxNode.childForFieldName('arguments')?.namedChildren ?? [];
+				if (symbols.length === 1 && symbols[0].type === 'simple_symbol') {
+					overlayNode.name = symbols[0].text.slice(1);
+				}
+				overlayNode.detail = syntaxNode.text;
+			}
+			break;
+		}
+	}
+}
+
+const rubyVisibilities = new Set(['public', 'protected', 'private']);
+
+/**
+ * @returns visibility of `method`, e.g., `private` after a `private` line; `undefined` if it's public
+ */
+function rubyVisibilityOf(method: SyntaxNode): 'private' | 'protected' | undefined {
+	let visibility: string | undefined;
+	if (method.parent?.type === 'argument_list') {
+		// e.g., `private def helper`
+		visibility = rubyVisibilityModifierOf(method.parent.parent);
+	} else {
+		// the last `private`, `protected`, or `public` line before it applies to it
+		for (let sibling = method.previousNamedSibling; sibling !== null; sibling = sibling.previousNamedSibling) {
+			if (sibling.type === 'identifier' && rubyVisibilities.has(sibling.text)) {
+				visibility = sibling.text;
+				break;
+			}
+		}
+		// unless a modifier names it, e.g., `private :helper`, which may follow its definition
+		const name = method.childForFieldName('name')?.text;
+		for (const sibling of method.parent?.namedChildren ?? []) {
+			const modifier = rubyVisibilityModifierOf(sibling);
+			if (modifier && sibling.childForFieldName('arguments')?.namedChildren.some(a => a.type === 'simple_symbol' && a.text.slice(1) === name)) {
+				visibility = modifier;
+			}
+		}
+	}
+	return visibility === 'private' || visibility === 'protected' ? visibility : undefined;
+}
+
+/**
+ * @returns `private`, `protected`, or `public` if `node` is a call of it without a receiver, e.g., `private :helper`
+ */
+function rubyVisibilityModifierOf(node: SyntaxNode | null): string | undefined {
+	const method = node?.type === 'call' && node.childForFieldName('receiver') === null ? node.childForFieldName('method')?.text : undefined;
+	return method !== undefined && rubyVisibilities.has(method) ? method : undefined;
+}
+
+const rubyAttributeMacros = new Set(['attr_reader', 'attr_writer', 'attr_accessor']);
+
+function isRubyAttributeMacro(call: SyntaxNode): boolean {
+	return call.childForFieldName('receiver') === null && rubyAttributeMacros.has(call.childForFieldName('method')?.text ?? '');
+}
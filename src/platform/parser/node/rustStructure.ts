@@ -0,0 +1,108 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo } from './util';
+
+export const rustStructure: StructureLanguage = {
+	describe: describeRustNode,
+	// kind `function_item` is used for free functions and those within `impl` and `trait` blocks -> kind `method_item` or `associated_function_item`
+	nodeKindOf: (capture, nodeKind) => nodeKind === 'function_item' || nodeKind === 'function_signature_item' ? rustAssociatedFunctionKind(capture.node) : undefined,
+	createLinker: () => {
+		// struct, enum, and union declarations by the names of the types they declare
+		const typeDeclarations = new Map<string, OverlayNode>();
+		return {
+			register: node => {
+				if (rustTypeKinds.has(node.kind) && node.name !== undefined) {
+					typeDeclarations.set(node.name, node);
+				}
+			},
+			link: root => {
+				if (typeDeclarations.size > 0) {
+					linkRustImpls(root, typeDeclarations);
+				}
+			},
+		};
+	},
+};
+
+function describeRustNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	switch (syntaxNode.type) {
+		case 'function_item':
+		case 'struct_item':
+		case 'union_item':
+		case 'enum_item':
+		case 'trait_item':
+		case 'mod_item': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.childForFieldName('body'), source);
+			overlayNode.typeParameters = syntaxNode.childForFieldName('type_parameters')?.text;
+			// functions of `impl Trait for Type` blocks implement the trait
+			const container = syntaxNode.parent?.type === 'declaration_list' ? syntaxNode.parent.parent : null;
+			if (container?.type === 'impl_item') {
+				overlayNode.trait = container.childForFieldName('trait')?.text;
+			}
+			break;
+		}
+		case 'function_signature_item': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.detail = syntaxNode.text;
+			break;
+		}
+		case 'const_item':
+		case 'static_item':
+		case 'type_item':
+		case 'field_declaration': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.type = syntaxNode.childForFieldName('type')?.text;
+			overlayNode.detail = syntaxNode.text;
+			break;
+		}
+		case 'enum_variant': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			break;
+		}
+		case 'impl_item': {
+			// named after the target type without type arguments, e.g., `Stack` for `impl<T> Stack<T>`
+			const type = syntaxNode.childForFieldName('type');
+			overlayNode.name = (type?.type === 'generic_type' ? type.childForFieldName('type') : type)?.text;
+			overlayNode.trait = syntaxNode.childForFieldName('trait')?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.childForFieldName('body'), source);
+			overlayNode.typeParameters = syntaxNode.childForFieldName('type_parameters')?.text;
+			break;
+		}
+	}
+}
+
+/**
+ * @returns `method_item` or `associated_function_item` for a function within an `impl` or `trait` block by whether it takes `self`
+ */
+function rustAssociatedFunctionKind(node: SyntaxNode): string | undefined {
+	const container = node.parent?.type === 'declaration_list' ? node.parent.parent : null;
+	if (container?.type !== 'impl_item' && container?.type !== 'trait_item') {
+		return undefined;
+	}
+	const takesSelf = node.childForFieldName('parameters')?.namedChildren.some(c => c.type === 'self_parameter');
+	return takesSelf ? 'method_item' : 'associated_function_item';
+}
+
+const rustTypeKinds = new Set(['struct_item', 'enum_item', 'union_item']);
+
+/**
+ * Adds the functions of `impl` blocks to {@link OverlayNode.methods} of the declarations of their target types if they're in the same source.
+ */
+function linkRustImpls(node: OverlayNode, typeDeclarations: Map<string, OverlayNode>) {
+	for (const child of node.children) {
+		const typeDeclaration = child.kind === 'impl_item' && child.name !== undefined ? typeDeclarations.get(child.name) : undefined;
+		if (typeDeclaration) {
+			const functions = child.children.filter(c => c.kind === 'method_item' || c.kind === 'associated_function_item');
+			(typeDeclaration.methods ??= []).push(...functions);
+		} else {
+			linkRustImpls(child, typeDeclarations);
+		}
+	}
+}
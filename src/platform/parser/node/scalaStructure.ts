@@ -0,0 +1,79 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo } from './util';
+
+export const scalaStructure: StructureLanguage = {
+	describe: describeScalaNode,
+	// kinds `class_definition` and `object_definition` with the `case` keyword -> kind `case_class_definition` or `case_object_definition`
+	nodeKindOf: (capture, nodeKind) => (nodeKind === 'class_definition' || nodeKind === 'object_definition') && capture.node.children.some(c => c.type === 'case')
+		? `case_${nodeKind}`
+		: undefined,
+	createLinker: () => ({ link: linkScalaCompanions }),
+};
+
+function describeScalaNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	switch (syntaxNode.type) {
+		case 'package_clause': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			break;
+		}
+		case 'class_definition': // also case classes and objects, see `StructureComputer`
+		case 'object_definition':
+		case 'trait_definition': {
+			// e.g., `case class Circle(radius: Double) extends Shape`; a declaration without a body, e.g., `case object Unit`, has it all
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.childForFieldName('body'), source);
+			break;
+		}
+		case 'function_definition': {
+			// e.g., `def area: Double` for `def area: Double = math.Pi * radius * radius`; scala 2's procedures have no `=`, e.g., `def run() { ... }`
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.children.find(c => c.type === '=') ?? syntaxNode.childForFieldName('body'), source);
+			overlayNode.type = syntaxNode.childForFieldName('return_type')?.text;
+			break;
+		}
+		case 'function_declaration': {
+			// abstract, e.g., `def area: Double` within a trait
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.detail = syntaxNode.text;
+			overlayNode.type = syntaxNode.childForFieldName('return_type')?.text;
+			break;
+		}
+		case 'val_definition':
+		case 'var_definition': {
+			// destructuring patterns, e.g., `val (x, y) = point`, aren't named
+			const pattern = syntaxNode.childForFieldName('pattern');
+			overlayNode.name = pattern?.type === 'identifier' ? pattern.text : undefined;
+			overlayNode.type = syntaxNode.childForFieldName('type')?.text;
+			break;
+		}
+		case 'val_declaration':
+		case 'var_declaration': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.type = syntaxNode.childForFieldName('type')?.text;
+			break;
+		}
+	}
+}
+
+const scalaTypeKinds = new Set(['class_definition', 'case_class_definition', 'trait_definition']);
+
+/**
+ * Sets {@link OverlayNode.companion} of classes and traits to the objects of the same name declared next to them.
+ */
+function linkScalaCompanions(node: OverlayNode) {
+	const objects = new Map(node.children.filter(c => c.kind === 'object_definition' && c.name !== undefined).map(c => [c.name, c]));
+	for (const child of node.children) {
+		const companion = scalaTypeKinds.has(child.kind) ? objects.get(child.name) : undefined;
+		if (companion) {
+			child.companion = companion;
+		}
+		linkScalaCompanions(child);
+	}
+}
@@ -0,0 +1,57 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { TreeSitterOffsetRange } from './nodes';
+import { _parse } from './parserWithCaching';
+import { WASMLanguage } from './treeSitterLanguages';
+
+/**
+ * @returns range of the smallest named node that strictly contains `range`, e.g., the `if` statement for its condition;
+ * `range` itself if no node does, e.g., if it already spans the whole source, so that repeated expansion never shrinks the selection
+ */
+export async function _expandSelection(language: WASMLanguage, source: string, range: TreeSitterOffsetRange): Promise<TreeSitterOffsetRange> {
+	const treeRef = await _parse(language, source);
+	try {
+		for (let node: SyntaxNode | null = treeRef.tree.rootNode.namedDescendantForIndex(range.startIndex, range.endIndex); node !== null; node = node.parent) {
+			// nodes with the same range as the selection, e.g., an identifier that is selected or an expression statement of a call, are skipped
+			if (TreeSitterOffsetRange.doesContain(node, range) && TreeSitterOffsetRange.len(node) > TreeSitterOffsetRange.len(range)) {
+				return TreeSitterOffsetRange.ofSyntaxNode(node);
+			}
+		}
+		return { startIndex: range.startIndex, endIndex: range.endIndex };
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+/**
+ * The inverse of {@link _expandSelection}: shrinking the expansion of a node's range with an offset within that node yields the node's range again.
+ *
+ * @param offset offset the selection shrinks towards, e.g., where the expansion started; by default, the start of `range`
+ * @returns range of the largest named node that is strictly within `range` and contains `offset`; `range` itself if there's none, e.g., for a single token
+ */
+export async function _shrinkSelection(language: WASMLanguage, source: string, range: TreeSitterOffsetRange, offset?: number): Promise<TreeSitterOffsetRange> {
+	// not a default parameter because `undefined` arguments become `null` when they're passed to the worker
+	const anchor = offset ?? range.startIndex;
+	const treeRef = await _parse(language, source);
+	try {
+		let node = treeRef.tree.rootNode.namedDescendantForIndex(range.startIndex, range.endIndex);
+		while (true) {
+			// a node ending at `offset` only contains it if no node starts there, e.g., for a caret at the end of an identifier
+			const child = node.namedChildren.find(c => c.startIndex <= anchor && anchor < c.endIndex)
+				?? node.namedChildren.find(c => c.startIndex <= anchor && anchor <= c.endIndex);
+			if (child === undefined) {
+				return { startIndex: range.startIndex, endIndex: range.endIndex };
+			}
+			if (TreeSitterOffsetRange.doesContain(range, child) && TreeSitterOffsetRange.len(child) < TreeSitterOffsetRange.len(range)) {
+				return TreeSitterOffsetRange.ofSyntaxNode(child);
+			}
+			node = child;
+		}
+	} finally {
+		treeRef.dispose();
+	}
+}
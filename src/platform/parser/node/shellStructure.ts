@@ -0,0 +1,45 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo } from './util';
+
+export const shellStructure: StructureLanguage = {
+	describe: describeShellNode,
+	// kind `command` that includes another script, e.g., `source ./lib.sh` or `. ./lib.sh` -> kind `source_command`
+	nodeKindOf: capture => capture.name === 'source_command' ? 'source_command' : undefined,
+};
+
+function describeShellNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	switch (syntaxNode.type) {
+		case 'function_definition': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.childForFieldName('body'), source); // e.g., `function greet()`
+			break;
+		}
+		case 'variable_assignment': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			break;
+		}
+		case 'declaration_command': {
+			// only single-variable declarations, e.g., `export PATH` or `readonly VERSION=1.0`, declare a single symbol
+			const variables = syntaxNode.namedChildren.filter(c => c.type === 'variable_assignment' || c.type === 'variable_name');
+			if (variables.length === 1) {
+				overlayNode.name = variables[0].type === 'variable_assignment' ? variables[0].childForFieldName('name')?.text : variables[0].text;
+			}
+			break;
+		}
+		case 'command': {
+			// a `source_command`, i.e., `source` or `.`, whose first argument is the included script, e.g., `"$HOME/.profile"` or `'lib.sh'`
+			const script = syntaxNode.childForFieldName('argument');
+			if (script) {
+				overlayNode.path = script.type === 'string' || script.type === 'raw_string' ? script.text.slice(1, -1) : script.text;
+			}
+			break;
+		}
+	}
+}
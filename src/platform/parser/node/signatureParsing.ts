@@ -0,0 +1,134 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { TreeSitterOffsetRange } from './nodes';
+import { _parse } from './parserWithCaching';
+import { runQueries } from './querying';
+import { WASMLanguage } from './treeSitterLanguages';
+import { signatureQueries } from './treeSitterQueries';
+
+export interface Signature extends TreeSitterOffsetRange {
+	/**
+	 * @example `MethodExample`
+	 */
+	name: string;
+	/**
+	 * Declaration up to its body as written, i.e., with modifiers but without decorators
+	 * @example `func (s StructExample) MethodExample() error`
+	 */
+	text: string;
+	/**
+	 * Parameter list as written including its parentheses
+	 * @example `(ctx context.Context, id string)`
+	 */
+	parameters: string;
+	/**
+	 * Return type as written; multiple results of a Go function include their parentheses, e.g., `(n int, err error)`;
+	 * `undefined` if there's none, e.g., for a Go function without results or a TS function without a return type annotation
+	 * @example `Promise<void>`
+	 */
+	returnType?: string;
+	/**
+	 * Receiver of a Go method as written including its parentheses
+	 * @example `(s *StructExample)`
+	 */
+	receiver?: string;
+	/**
+	 * Accessibility of a member, or whether a top-level function is exported, i.e., `public` for a Go name starting with an upper-case letter
+	 * or a TS `export`; Python names starting with `_`, but not `__init__`-like ones, are private by convention
+	 */
+	visibility: 'public' | 'protected' | 'private';
+}
+
+/**
+ * Get the signatures of the top-level functions and the methods of the source in document order, e.g., to describe the API of a file compactly.
+ * The range of a signature is that of the whole declaration, i.e., including its body.
+ *
+ * @returns empty if the language isn't supported
+ */
+export async function _getSignatures(language: WASMLanguage, source: string): Promise<Signature[]> {
+	const queries = signatureQueries[language];
+	if (!queries) {
+		return [];
+	}
+
+	const treeRef = await _parse(language, source);
+
+	try {
+		const signatures: Signature[] = [];
+		for (const { captures } of runQueries(queries, treeRef.tree.rootNode)) {
+			const declaration = captures.find(c => c.name === 'function')?.node;
+			const signature = declaration && signatureOf(language, declaration, source);
+			if (signature) {
+				signatures.push(signature);
+			}
+		}
+		return signatures.sort(TreeSitterOffsetRange.compare);
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+function signatureOf(language: WASMLanguage, declaration: SyntaxNode, source: string): Signature | undefined {
+	const name = declaration.childForFieldName('name');
+	const parameters = declaration.childForFieldName('parameters');
+	const body = declaration.childForFieldName('body');
+	if (!name || !parameters) {
+		return undefined;
+	}
+	const range = { startIndex: declaration.startIndex, endIndex: declaration.endIndex };
+	const textUpToBody = (start: number) => source.substring(start, body ? body.startIndex : declaration.endIndex).trimEnd();
+
+	switch (language) {
+		case WASMLanguage.Go: {
+			const receiver = declaration.childForFieldName('receiver')?.text;
+			return {
+				...range,
+				name: name.text,
+				text: textUpToBody(declaration.startIndex),
+				parameters: parameters.text,
+				returnType: declaration.childForFieldName('result')?.text,
+				...(receiver !== undefined ? { receiver } : {}),
+				// exported names start with an upper-case letter of any script
+				visibility: /^\p{Lu}/u.test(name.text) ? 'public' : 'private',
+			};
+		}
+		case WASMLanguage.JavaScript:
+		case WASMLanguage.TypeScript:
+		case WASMLanguage.TypeScriptTsx: {
+			const exportStatement = declaration.parent?.type === 'export_statement' ? declaration.parent : undefined;
+			// depending on the grammar version, decorators of methods are their children
+			const start = exportStatement?.startIndex ?? declaration.children.find(c => c.type !== 'decorator')?.startIndex ?? declaration.startIndex;
+			return {
+				...range,
+				name: name.text,
+				text: textUpToBody(start),
+				parameters: parameters.text,
+				returnType: declaration.childForFieldName('return_type')?.text.replace(/^:\s*/, ''),
+				visibility: declaration.type === 'method_definition' ? jsMemberVisibility(declaration, name) : exportStatement ? 'public' : 'private',
+			};
+		}
+		case WASMLanguage.Python:
+			return {
+				...range,
+				name: name.text,
+				text: textUpToBody(declaration.startIndex).replace(/:$/, ''),
+				parameters: parameters.text,
+				returnType: declaration.childForFieldName('return_type')?.text,
+				visibility: name.text.startsWith('_') && !/^__.*__$/.test(name.text) ? 'private' : 'public',
+			};
+		default:
+			return undefined;
+	}
+}
+
+function jsMemberVisibility(method: SyntaxNode, name: SyntaxNode): Signature['visibility'] {
+	if (name.type === 'private_property_identifier') { // e.g., `#validate()`
+		return 'private';
+	}
+	const modifier = method.namedChildren.find(c => c.type === 'accessibility_modifier')?.text;
+	return modifier === 'private' || modifier === 'protected' ? modifier : 'public';
+}
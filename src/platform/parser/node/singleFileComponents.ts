@@ -0,0 +1,138 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { withOffset } from './markdownCodeBlocks';
+import { LineCharacterPosition, OverlayNode, Utf8Offset } from './nodes';
+import { structureComputer, StructureOptions } from './structure';
+import { WASMLanguage } from './treeSitterLanguages';
+
+/**
+ * `vue` for a `.vue` file, whose markup is within a `<template>` block, or `svelte` for a `.svelte` file, whose markup is at the top level
+ */
+export type SingleFileComponentFormat = 'vue' | 'svelte';
+
+export interface SingleFileComponentBlock {
+	readonly kind: 'template' | 'script' | 'style';
+	/** Value of the `lang` attribute of the block, e.g., `ts` for `<script setup lang="ts">`, or `undefined` if it has none */
+	readonly lang: string | undefined;
+	/** Offset of the content of the block, i.e., of the end of its start tag */
+	readonly startIndex: number;
+	/** Offset of the end of the content of the block, i.e., of its end tag or the end of the file if the block isn't closed */
+	readonly endIndex: number;
+}
+
+export interface SingleFileComponentScriptStructure {
+	/** `TypeScript` for a script with `lang="ts"`, `JavaScript` for one without a `lang` attribute */
+	readonly language: WASMLanguage;
+	/** Offset of the code of the script in the file, see {@link SingleFileComponentBlock.startIndex} */
+	readonly startIndex: number;
+	/** Offset of the end of the code of the script in the file, see {@link SingleFileComponentBlock.endIndex} */
+	readonly endIndex: number;
+	/** Structure of the code of the script with offsets and positions within the file */
+	readonly structure: OverlayNode;
+}
+
+/**
+ * Start tags of blocks and comments, which are skipped because a commented-out block isn't one; attribute values may contain `>`
+ */
+const blockStartPattern = /<!--[\s\S]*?(?:-->|$)|<(template|script|style)(?=[\s/>])((?:"[^"]*"|'[^']*'|[^>"'])*)>/gi;
+
+/**
+ * Splits a single-file component, e.g., a `.vue` or `.svelte` file, into its top-level `<template>`, `<script>`, and `<style>` blocks,
+ * which are in one file but each in a language of its own; a component may have several script blocks, e.g., `<script>` and `<script setup>`.
+ *
+ * @remarks A Svelte component has no `<template>` block, so its markup isn't a block. Templates nested in a Vue template, e.g., `<template v-if="...">`,
+ * are part of it.
+ */
+export function splitSingleFileComponent(format: SingleFileComponentFormat, source: string): SingleFileComponentBlock[] {
+	const blocks: SingleFileComponentBlock[] = [];
+	blockStartPattern.lastIndex = 0;
+	for (let match = blockStartPattern.exec(source); match !== null; match = blockStartPattern.exec(source)) {
+		const kind = match[1]?.toLowerCase() as SingleFileComponentBlock['kind'] | undefined;
+		// e.g., `<script src="./counter.js" />`, whose code is in another file
+		if (kind === undefined || (kind === 'template' && format === 'svelte') || match[2].endsWith('/')) {
+			continue;
+		}
+		const startIndex = match.index + match[0].length;
+		const end = kind === 'template' ? templateEndOf(source, startIndex) : blockEndOf(source, startIndex, kind);
+		blocks.push({ kind, lang: langAttributeOf(match[2]), startIndex, endIndex: end?.startIndex ?? source.length });
+		blockStartPattern.lastIndex = end?.endIndex ?? source.length;
+	}
+	return blocks;
+}
+
+/**
+ * Computes the structure of each script block of a single-file component, see {@link splitSingleFileComponent}, with the grammar its `lang` attribute selects;
+ * scripts in other languages, e.g., `lang="coffee"`, are skipped.
+ */
+export async function getSingleFileComponentStructures(format: SingleFileComponentFormat, source: string, options?: StructureOptions): Promise<SingleFileComponentScriptStructure[]> {
+	const positionAt = LineCharacterPosition.converterFor(source);
+	const byteOffsetAt = Utf8Offset.converterFor(source);
+
+	const result: SingleFileComponentScriptStructure[] = [];
+	for (const block of splitSingleFileComponent(format, source)) {
+		const language = block.kind === 'script' ? scriptLanguageOf(block.lang) : undefined;
+		if (language === undefined) {
+			continue;
+		}
+		const structure = await structureComputer.getStructure(language, source.slice(block.startIndex, block.endIndex), options);
+		if (structure) {
+			result.push({ language, startIndex: block.startIndex, endIndex: block.endIndex, structure: withOffset(structure, block.startIndex, positionAt, byteOffsetAt) });
+		}
+	}
+	return result;
+}
+
+/**
+ * @returns range of the end tag of a script or style block, i.e., the first one, because its content isn't markup
+ */
+function blockEndOf(source: string, startIndex: number, kind: 'script' | 'style'): { startIndex: number; endIndex: number } | undefined {
+	const endTagPattern = new RegExp(`</${kind}\\s*>`, 'gi');
+	endTagPattern.lastIndex = startIndex;
+	const match = endTagPattern.exec(source);
+	return match ? { startIndex: match.index, endIndex: match.index + match[0].length } : undefined;
+}
+
+/**
+ * @returns range of the end tag of a template block, which may contain templates of its own, e.g., `<template v-if="...">`
+ */
+function templateEndOf(source: string, startIndex: number): { startIndex: number; endIndex: number } | undefined {
+	const tagPattern = /<(\/?)template(?=[\s/>])(?:"[^"]*"|'[^']*'|[^>"'])*>/gi;
+	tagPattern.lastIndex = startIndex;
+	let depth = 1;
+	for (let match = tagPattern.exec(source); match !== null; match = tagPattern.exec(source)) {
+		if (match[1] === '') {
+			depth += match[0].endsWith('/>') ? 0 : 1;
+		} else if (--depth === 0) {
+			return { startIndex: match.index, endIndex: match.index + match[0].length };
+		}
+	}
+	return undefined;
+}
+
+function langAttributeOf(attributes: string): string | undefined {
+	const match = /(?:^|\s)lang\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))/i.exec(attributes);
+	return match ? (match[1] ?? match[2] ?? match[3]).toLowerCase() : undefined;
+}
+
+/**
+ * @returns grammar of a script block with the `lang` attribute, e.g., `TypeScriptTsx` for `tsx`; JavaScript by default
+ */
+function scriptLanguageOf(lang: string | undefined): WASMLanguage | undefined {
+	switch (lang) {
+		case undefined:
+		case 'js':
+		case 'javascript':
+		case 'jsx':
+			return WASMLanguage.JavaScript;
+		case 'ts':
+		case 'typescript':
+			return WASMLanguage.TypeScript;
+		case 'tsx':
+			return WASMLanguage.TypeScriptTsx;
+		default:
+			return undefined;
+	}
+}
@@ -0,0 +1,79 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { TreeSitterOffsetRange } from './nodes';
+import { _parse } from './parserWithCaching';
+import { runQueries } from './querying';
+import { WASMLanguage } from './treeSitterLanguages';
+import { signatureQueries } from './treeSitterQueries';
+
+interface Stub extends TreeSitterOffsetRange {
+	/**
+	 * Text that replaces the range
+	 */
+	text: string;
+}
+
+/**
+ * Get a skeleton of the source, i.e., the source with the bodies of its top-level functions and methods stubbed, e.g., `func main() { ... }`,
+ * to describe a large file compactly. Everything else, e.g., imports, type declarations, and comments, is kept as written,
+ * so that the skeleton is still plausible source of the language; functions assigned to variables keep their bodies.
+ *
+ * @returns the source as is if the language isn't supported, i.e., isn't Go, JavaScript, TypeScript, or Python
+ */
+export async function _getSkeleton(language: WASMLanguage, source: string): Promise<string> {
+	const queries = signatureQueries[language];
+	if (!queries) {
+		return source;
+	}
+
+	const treeRef = await _parse(language, source);
+
+	try {
+		const stubs = runQueries(queries, treeRef.tree.rootNode)
+			.flatMap(({ captures }) => {
+				// e.g., an overload signature or an abstract method has no body
+				const body = captures.find(c => c.name === 'function')?.node.childForFieldName('body');
+				const stub = body && stubOf(language, body, source);
+				return stub ? [stub] : [];
+			})
+			.sort(TreeSitterOffsetRange.compare);
+
+		let skeleton = '';
+		let offset = 0;
+		for (const stub of stubs) {
+			// e.g., a method of a class declared within a function, whose body is stubbed already
+			if (stub.startIndex < offset) {
+				continue;
+			}
+			skeleton += source.substring(offset, stub.startIndex) + stub.text;
+			offset = stub.endIndex;
+		}
+		return skeleton + source.substring(offset);
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+/**
+ * @returns stub of a function body, i.e., `{ ... }` for a block, or `...` for a Python block, which keeps its docstring;
+ * `undefined` if the body is kept as is, e.g., a Python body that's only a docstring
+ */
+function stubOf(language: WASMLanguage, body: SyntaxNode, source: string): Stub | undefined {
+	if (language === WASMLanguage.Python) {
+		const first = body.firstNamedChild;
+		if (first?.type === 'expression_statement' && first.firstNamedChild?.type === 'string') {
+			if (first.nextNamedSibling === null) {
+				return undefined;
+			}
+			// `...` goes on a line of its own below the docstring, indented like it
+			const indentation = source.substring(source.lastIndexOf('\n', first.startIndex - 1) + 1, first.startIndex);
+			return { startIndex: first.endIndex, endIndex: body.endIndex, text: `\n${indentation}...` };
+		}
+		return { startIndex: body.startIndex, endIndex: body.endIndex, text: '...' };
+	}
+	return { startIndex: body.startIndex, endIndex: body.endIndex, text: '{ ... }' };
+}
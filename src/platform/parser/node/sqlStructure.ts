@@ -0,0 +1,79 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo } from './util';
+
+export const sqlStructure: StructureLanguage = {
+	describe: describeSqlNode,
+	// kind `statement` is used for all statements -> kind of the statement it wraps, e.g., `create_table`, `select`, or `delete`
+	nodeKindOf: (capture, nodeKind) => nodeKind === 'statement' ? sqlStatementOf(capture.node)?.type : undefined,
+};
+
+function describeSqlNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	switch (syntaxNode.type) {
+		case 'statement': {
+			// named after the table, view, or function it creates or writes to, e.g., `users` for `CREATE TABLE IF NOT EXISTS public.users (...)` or `INSERT INTO users ...`
+			const statement = sqlStatementOf(syntaxNode);
+			// labeled after the kind of the statement, e.g., `CREATE TABLE` or `SELECT`
+			overlayNode.label = statement?.type.replace(/_/g, ' ').toUpperCase();
+			const target = statement && sqlTargetOf(syntaxNode, statement);
+			if (target) {
+				overlayNode.name = sqlIdentifierText(target.childForFieldName('name'));
+				overlayNode.detail = source.substring(syntaxNode.startIndex, target.endIndex);
+			} else if (statement?.type === 'create_index') {
+				// e.g., `idx_users_email` for `CREATE UNIQUE INDEX idx_users_email ON users (email)`; an index may be unnamed in some dialects
+				const keywordOn = statement.namedChildren.find(c => c.type === 'keyword_on') ?? null;
+				const name = statement.namedChildren.find(c => c.type === 'identifier' && (keywordOn === null || c.startIndex < keywordOn.startIndex));
+				overlayNode.name = sqlIdentifierText(name ?? null);
+				overlayNode.detail = textUpTo(syntaxNode, keywordOn, source);
+			} else {
+				// e.g., `SELECT id, email` for `SELECT id, email FROM users WHERE ...`
+				overlayNode.detail = textUpTo(syntaxNode, syntaxNode.namedChildren.find(c => c.type === 'from') ?? null, source);
+			}
+			break;
+		}
+		case 'column_definition': {
+			overlayNode.name = sqlIdentifierText(syntaxNode.childForFieldName('name'));
+			overlayNode.type = syntaxNode.childForFieldName('type')?.text; // e.g., `VARCHAR(255)`
+			overlayNode.detail = syntaxNode.text;
+			break;
+		}
+	}
+}
+
+/**
+ * @returns what a `statement` wraps, i.e., its first child that isn't a keyword, e.g., `EXPLAIN`, or a common table expression of `WITH`
+ */
+function sqlStatementOf(statement: SyntaxNode): SyntaxNode | undefined {
+	return statement.namedChildren.find(c => !c.type.startsWith('keyword_') && c.type !== 'cte' && c.type !== 'comment' && c.type !== 'marginalia');
+}
+
+/**
+ * @returns reference to the table, view, or function that a statement creates or writes to; `undefined` for queries and indexes
+ */
+function sqlTargetOf(statement: SyntaxNode, wrapped: SyntaxNode): SyntaxNode | undefined {
+	switch (wrapped.type) {
+		case 'insert':
+			return wrapped.namedChildren.find(c => c.type === 'object_reference');
+		case 'update':
+			return wrapped.namedChildren.find(c => c.type === 'relation')?.namedChildren.find(c => c.type === 'object_reference');
+		case 'delete': // e.g., `DELETE FROM sessions`, whose `FROM` is a sibling of `DELETE`
+			return statement.namedChildren.find(c => c.type === 'from')?.namedChildren.find(c => c.type === 'object_reference');
+		case 'create_index':
+			return undefined;
+		default:
+			return wrapped.type.startsWith('create_') ? wrapped.namedChildren.find(c => c.type === 'object_reference') : undefined;
+	}
+}
+
+/**
+ * @returns name of an identifier without its quotes, e.g., `order` for `"order"`, `` `order` ``, or `[order]`
+ */
+function sqlIdentifierText(identifier: SyntaxNode | null): string | undefined {
+	return identifier?.text.replace(/^(?:"(.*)"|`(.*)`|\[(.*)\])$/s, '$1$2$3');
+}
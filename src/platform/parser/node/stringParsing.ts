@@ -0,0 +1,172 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { TreeSitterOffsetRange } from './nodes';
+import { _parse } from './parserWithCaching';
+import { WASMLanguage } from './treeSitterLanguages';
+
+export interface StringLiteral extends TreeSitterOffsetRange {
+	/**
+	 * Raw text of the literal including its prefix and delimiters
+	 * @example `r"C:\temp"` or `` `Hello, ${name}!` ``
+	 */
+	text: string;
+	/**
+	 * Value of the literal with its escape sequences decoded, e.g., `a\tb` for `"a\\tb"`;
+	 * `undefined` if the literal is interpolated, since its value isn't a constant, or its value can't be told from its text, e.g., a Java text block
+	 */
+	value?: string;
+	/**
+	 * Whether the literal is a template or interpolated string, e.g., a JS/TS template literal, a Python f-string, or a C# `$"..."`, even without interpolations
+	 */
+	isTemplate: boolean;
+	/**
+	 * Ranges of the interpolated expressions including their delimiters, e.g., `${name}` or `{count}` of an f-string
+	 */
+	interpolations: TreeSitterOffsetRange[];
+}
+
+/**
+ * Types of string literal nodes by language; literals of other languages aren't extracted
+ */
+export const stringLiteralTypes: { readonly [language in WASMLanguage]?: readonly string[] } = {
+	[WASMLanguage.TypeScript]: ['string', 'template_string'],
+	[WASMLanguage.TypeScriptTsx]: ['string', 'template_string'],
+	[WASMLanguage.JavaScript]: ['string', 'template_string'],
+	[WASMLanguage.Go]: ['interpreted_string_literal', 'raw_string_literal'],
+	[WASMLanguage.Python]: ['string'],
+	[WASMLanguage.Rust]: ['string_literal', 'raw_string_literal'],
+	[WASMLanguage.Java]: ['string_literal'],
+	[WASMLanguage.C]: ['string_literal', 'raw_string_literal'],
+	[WASMLanguage.Cpp]: ['string_literal', 'raw_string_literal'],
+	[WASMLanguage.Csharp]: ['string_literal', 'verbatim_string_literal', 'raw_string_literal', 'interpolated_string_expression'],
+};
+
+/**
+ * Types of the interpolated expressions of template and interpolated strings, e.g., `${name}` of a JS/TS template literal
+ */
+const interpolationTypes = new Set(['template_substitution', 'interpolation']);
+
+/**
+ * Get the string literals of the source, e.g., to flag hardcoded secrets or to extract translatable strings;
+ * literals are found in the syntax tree, so that, e.g., `'//'` isn't mistaken for a comment and `// "quoted"` isn't a literal.
+ *
+ * @returns literals in document order, including those nested in the interpolations of others, e.g., `'s'` in `` `${n} item${n === 1 ? '' : 's'}` ``;
+ * an empty array if the language isn't supported, see {@link stringLiteralTypes}
+ */
+export async function _getStringLiterals(language: WASMLanguage, source: string): Promise<StringLiteral[]> {
+	const types = stringLiteralTypes[language];
+	if (!types) {
+		return [];
+	}
+
+	const treeRef = await _parse(language, source);
+
+	try {
+		return treeRef.tree.rootNode.descendantsOfType([...types]).map(node => stringLiteralOf(language, node));
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+function stringLiteralOf(language: WASMLanguage, node: SyntaxNode): StringLiteral {
+	const text = node.text;
+	const interpolations = node.namedChildren.filter(c => interpolationTypes.has(c.type)).map(c => TreeSitterOffsetRange.ofSyntaxNode(c));
+	const delimiters = delimitersOf(language, text);
+	const isTemplate = node.type === 'template_string' || node.type === 'interpolated_string_expression' || /[fF$]/.test(delimiters.prefix);
+	const literal: StringLiteral = { startIndex: node.startIndex, endIndex: node.endIndex, text, isTemplate, interpolations };
+	if (interpolations.length === 0) {
+		const value = valueOf(language, text, delimiters, isTemplate);
+		if (value !== undefined) {
+			literal.value = value;
+		}
+	}
+	return literal;
+}
+
+interface Delimiters {
+	/**
+	 * e.g., `r`, `f`, or `rb` of Python, `@` or `$` of C#, or `u8` or `R` of C++
+	 */
+	prefix: string;
+	open: string;
+	close: string;
+}
+
+/**
+ * @returns prefix and quotes of a literal, e.g., `{ prefix: 'r', open: '#"', close: '"#' }` for Rust's `r#"..."#`
+ * or `{ prefix: 'R', open: '"x(', close: ')x"' }` for C++'s `R"x(...)x"`
+ */
+function delimitersOf(language: WASMLanguage, text: string): Delimiters {
+	const prefix = /^[A-Za-z0-9@$]*/.exec(text)![0];
+	const rest = text.slice(prefix.length);
+	if ((language === WASMLanguage.C || language === WASMLanguage.Cpp) && prefix.endsWith('R') && rest.startsWith('"')) {
+		const delimiter = rest.slice(1, rest.indexOf('('));
+		return { prefix, open: `"${delimiter}(`, close: `)${delimiter}"` };
+	}
+	const hashes = /^#*/.exec(rest)![0]; // rust's raw strings, e.g., `r#"..."#`
+	const quote = rest.charAt(hashes.length);
+	let count = 0;
+	while (rest.charAt(hashes.length + count) === quote) {
+		count++;
+	}
+	// e.g., python's `"""..."""` or C#'s raw `"""..."""`, but not an empty `""`
+	const quotes = quote.repeat(count < 3 ? 1 : language === WASMLanguage.Csharp ? count : 3);
+	return { prefix, open: hashes + quotes, close: quotes + hashes };
+}
+
+function valueOf(language: WASMLanguage, text: string, { prefix, open, close }: Delimiters, isTemplate: boolean): string | undefined {
+	const content = text.slice(prefix.length + open.length, text.length - close.length);
+	switch (language) {
+		case WASMLanguage.Go:
+			// raw strings, i.e., `` `...` ``, drop their carriage returns
+			return open === '`' ? content.replace(/\r/g, '') : decodeEscapes(content);
+		case WASMLanguage.Python: {
+			const value = /[rR]/.test(prefix) ? content : decodeEscapes(content);
+			// e.g., `{{` of an f-string without interpolations
+			return isTemplate ? value.replace(/\{\{/g, '{').replace(/\}\}/g, '}') : value;
+		}
+		case WASMLanguage.Rust:
+			return prefix.endsWith('r') ? content : decodeEscapes(content);
+		case WASMLanguage.Java:
+			// text blocks, i.e., `"""\n...\n"""`, strip their incidental indentation
+			return open === '"""' ? undefined : decodeEscapes(content);
+		case WASMLanguage.C:
+		case WASMLanguage.Cpp:
+			return prefix.endsWith('R') ? content : decodeEscapes(content);
+		case WASMLanguage.Csharp: {
+			const value = prefix.includes('@') ? content.replace(/""/g, '"') : open.length >= 3 ? content : decodeEscapes(content);
+			return isTemplate ? value.replace(/\{\{/g, '{').replace(/\}\}/g, '}') : value;
+		}
+		default:
+			return decodeEscapes(content);
+	}
+}
+
+const simpleEscapes: { [c: string]: string } = { n: '\n', t: '\t', r: '\r', b: '\b', f: '\f', v: '\v', a: '\x07' };
+
+/**
+ * @returns `content` with the escape sequences common to C-like languages decoded, e.g., `\n`, `\x41`, `\u00e9`, `\u{1F600}`, or `\101`;
+ * an escaped line break continues the line, and other escaped characters stand for themselves, e.g., `\"` or `\$`
+ */
+function decodeEscapes(content: string): string {
+	return content.replace(/\\(u\{[0-9a-fA-F]+\}|u[0-9a-fA-F]{4}|U[0-9a-fA-F]{8}|x[0-9a-fA-F]{2}|[0-7]{1,3}|\r?\n|[\s\S])/g, (_, escape: string) => {
+		if (escape in simpleEscapes) {
+			return simpleEscapes[escape];
+		}
+		switch (escape[0]) {
+			case 'u':
+			case 'U':
+				return String.fromCodePoint(parseInt(escape.replace(/^[uU]\{?|\}$/g, ''), 16));
+			case 'x':
+				return String.fromCharCode(parseInt(escape.slice(1), 16));
+			case '\r':
+			case '\n':
+				return '';
+		}
+		return /^[0-7]+$/.test(escape) ? String.fromCharCode(parseInt(escape, 8)) : escape;
+	});
+}
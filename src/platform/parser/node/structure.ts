@@ -3,45 +3,311 @@
  *  Licensed under the MIT License. See License.txt in the project root for license information.
  *--------------------------------------------------------------------------------------------*/
 
-import { SyntaxNode } from 'web-tree-sitter';
+import { SyntaxNode, Tree } from 'web-tree-sitter';
 import { LRUCache } from '../../../util/common/cache';
-import { OverlayNode, TreeSitterOffsetRange } from './nodes';
-import { _parse } from './parserWithCaching';
+import { coalesce } from '../../../util/vs/base/common/arrays';
+import { findLastIdx } from '../../../util/vs/base/common/arraysFind';
+import { raceCancellationError, timeout } from '../../../util/vs/base/common/async';
+import { CancellationToken } from '../../../util/vs/base/common/cancellation';
+import { CancellationError, isCancellationError } from '../../../util/vs/base/common/errors';
+import { clamp } from '../../../util/vs/base/common/numbers';
+import { commentNodeTypes, todoAnnotationOf } from './commentParsing';
+import { getLanguageRegistration } from './languageRegistry';
+import { getNodeText, LineCharacterPosition, OverlayNode, TreeSitterOffsetRange, TreeSitterSourceEdits, Utf8Offset } from './nodes';
+import { _parse, _reparseWithCache, contentKey, ParseTimeoutError } from './parserWithCaching';
 import { runQueries } from './querying';
+import { describeOverlayNode, normalizeTypeTexts, structureLanguages } from './structureDetails';
+import { symbolKindOf } from './symbolKinds';
 import { WASMLanguage } from './treeSitterLanguages';
-import { syntacticallyValidAtoms } from './treeSitterQueries';
+import { enclosingDeclarationTypes, syntacticallyValidAtoms } from './treeSitterQueries';
+
+export interface StructureOptions {
+	/**
+	 * Maximum number of levels of the returned tree including its root; deeper captures are skipped and the root is marked {@link OverlayNode.isTruncated}.
+	 * Defaults to {@link defaultMaxStructureDepth}.
+	 */
+	readonly maxDepth?: number;
+
+	/**
+	 * Whether embedded Go interfaces get the method sets of the interfaces they refer to as {@link OverlayNode.methods} if those are declared in the same source.
+	 */
+	readonly inlineEmbeddedInterfaces?: boolean;
+
+	/**
+	 * Whether Go methods are nested among the children of their receiver types if those are declared in the same source.
+	 * Nested methods keep their ranges, so range-based lookups, e.g., {@link getEnclosingSymbol}, don't find them.
+	 */
+	readonly nestGoMethods?: boolean;
+
+	/**
+	 * Whether JS/TS function expressions and arrow functions get nodes of their own, named after their variables or labeled `<anonymous>`.
+	 */
+	readonly includeAnonymousFunctions?: boolean;
+
+	/**
+	 * Whether `default` clauses of Go switch and select statements and the cases of type switches get nodes of their own, too.
+	 */
+	readonly includeGoCaseClauses?: boolean;
+
+	/**
+	 * Whether all members of TS, C, C++, and C# enums get nodes of the kind `enum_member`, not just TS members with values.
+	 */
+	readonly includeEnumMembers?: boolean;
+
+	/**
+	 * Whether self-closing TSX elements get nodes, too, fragments get the kind `jsx_fragment`, and the elements within expression containers
+	 * are nested in the elements around them.
+	 */
+	readonly includeJsxElements?: boolean;
+
+	/**
+	 * Whether comments that start with a marker, e.g., `// TODO: handle empty name`, get nodes of the kind `todo_annotation`
+	 * and are added to the {@link OverlayNode.annotations} of the declarations that follow them.
+	 */
+	readonly includeTodoComments?: boolean;
+
+	/**
+	 * Whether declaration headers and parameter, result, and declared types are normalized for comparison, i.e., without comments,
+	 * with runs of whitespace collapsed, and with a single space after each comma.
+	 */
+	readonly normalizeTypes?: boolean;
+
+	/**
+	 * Whether Go declarations with unexported names are left out, except for the package clause, unnamed nodes, and the nodes within exported functions.
+	 */
+	readonly exportedOnly?: boolean;
+
+	/**
+	 * Time budget of parsing the source in milliseconds, see `ParseOptions.timeoutMs`; {@link ParseTimeoutError} is thrown if it's exceeded.
+	 * Errors lose their classes on their way from the parser worker, so there, it's an `Error` with the same message.
+	 */
+	readonly timeoutMs?: number;
+
+	/**
+	 * Called for each node of the computed structure except its root, before its children are, once the nodes are linked and their names qualified, see {@link StructureTransform}.
+	 * Structures computed with a transform aren't cached, and nodes of a previous structure aren't reused for them.
+	 */
+	readonly transform?: StructureTransform;
+}
+
+/**
+ * What becomes of a node passed to {@link StructureOptions.transform}: `'keep'`, or nothing, keeps it, `'drop'` nests its children in its parent instead,
+ * and `'dropWithChildren'` leaves out its whole subtree.
+ */
+export type StructureTransformResult = 'keep' | 'drop' | 'dropWithChildren';
+
+/**
+ * @example `node => node.visibility === 'private' ? 'dropWithChildren' : undefined` to leave out private JS/TS declarations and their members
+ */
+export type StructureTransform = (node: OverlayNode) => StructureTransformResult | void;
+
+/**
+ * A source whose structure is known and the edits that turned it into the source whose structure is requested, see {@link StructureComputer.getStructureIncrementally}.
+ */
+export interface PreviousStructure extends TreeSitterSourceEdits {
+	/** Structure of `previousSource` computed with the same options as the requested one */
+	readonly structure: OverlayNode;
+}
+
+/**
+ * Default of {@link StructureOptions.maxDepth}.
+ */
+export const defaultMaxStructureDepth = 256;
+
+export interface StructureCacheStats {
+	/** Number of requests served from the cache */
+	readonly hits: number;
+	/** Number of requests that computed the structure, including all requests while the cache is disabled */
+	readonly misses: number;
+	/** Number of cached structures */
+	readonly size: number;
+}
 
 export class StructureComputer {
 
-	private _cache = new LRUCache<OverlayNode | undefined>(5);
+	/** Time {@link getStructureAsync} may block the event loop for before yielding to it */
+	static YIELD_INTERVAL_MS = 10;
+
+	/** Length from which {@link getStructureForRange} computes only the structure of the declarations around the range */
+	static MIN_LENGTH_FOR_RANGE_RESTRICTION = 50_000;
 
+	/** Pending computations as well, which concurrent requests for the same source share; `undefined` if caching is disabled */
+	private _cache: LRUCache<Promise<OverlayNode | undefined>> | undefined;
+	private _hits = 0;
+	private _misses = 0;
+
+	/**
+	 * @param cacheSize number of cached structures, e.g., of the files context providers ask for during a chat turn; `0` disables caching
+	 */
+	constructor(cacheSize = 50) {
+		this.setCacheSize(cacheSize);
+	}
+
+	/**
+	 * Replaces the cache by an empty one of the given size; `0` disables caching, e.g., in tests.
+	 */
 	public setCacheSize(size: number) {
-		this._cache = new LRUCache(size);
+		this._cache = size > 0 ? new LRUCache(size) : undefined;
+	}
+
+	public clearCache() {
+		this._cache?.clear();
+	}
+
+	public getCacheStats(): StructureCacheStats {
+		return { hits: this._hits, misses: this._misses, size: this._cache?.keys().length ?? 0 };
+	}
+
+	public getStructure(lang: WASMLanguage, source: string, options?: StructureOptions): Promise<OverlayNode | undefined> {
+		return this._getCachedStructure(structureCacheKey(lang, source, options), options, () => this._getStructure(lang, source, options));
 	}
 
-	public async getStructure(lang: WASMLanguage, source: string): Promise<OverlayNode | undefined> {
-		const cacheKey = `${lang}:${source}`;
-		let cacheValue = this._cache.get(cacheKey);
-		if (!cacheValue) {
-			cacheValue = await this._getStructure(lang, source);
-			this._cache.put(cacheKey, cacheValue);
+	/**
+	 * Like {@link getStructure} for an edited source whose previous structure is known: only the top-level declarations around what changed are computed again.
+	 * The whole structure is computed again if the source isn't parsed incrementally or with options whose nodes depend on the whole source.
+	 */
+	public getStructureIncrementally(lang: WASMLanguage, source: string, previous: PreviousStructure, options?: StructureOptions): Promise<OverlayNode | undefined> {
+		return this._getCachedStructure(structureCacheKey(lang, source, options), options, () => this._getStructure(lang, source, options, undefined, previous));
+	}
+
+	private _getCachedStructure(cacheKey: string, options: StructureOptions | undefined, computeStructure: () => Promise<OverlayNode | undefined>): Promise<OverlayNode | undefined> {
+		const cache = this._cacheFor(options);
+		let cacheValue = cache?.get(cacheKey);
+		if (cacheValue) {
+			this._hits++;
+		} else {
+			this._misses++;
+			const structure = computeStructure();
+			cache?.put(cacheKey, structure);
+			// failures aren't cached, e.g., a grammar that failed to load is loaded again by the next request, and since the time budget
+			// isn't part of the cache key, a request with a larger budget or none at all must parse again
+			structure.catch(() => {
+				if (cache?.get(cacheKey) === structure) {
+					cache.deleteKey(cacheKey);
+				}
+			});
+			cacheValue = structure;
 		}
 		return cacheValue;
 	}
 
-	private async _getStructure(lang: WASMLanguage, source: string): Promise<OverlayNode | undefined> {
-		const queries = syntacticallyValidAtoms[lang];
+	/**
+	 * @returns the cache of structures computed with `options`; `undefined` for those computed with a transform, which isn't part of the cache key
+	 */
+	private _cacheFor(options: StructureOptions | undefined): LRUCache<Promise<OverlayNode | undefined>> | undefined {
+		return options?.transform ? undefined : this._cache;
+	}
+
+	/**
+	 * Like {@link getStructure} but yields to the event loop between top-level declarations.
+	 * @throws {CancellationError} if `token` is cancelled before the structure is computed
+	 */
+	public async getStructureAsync(lang: WASMLanguage, source: string, token: CancellationToken, options?: StructureOptions): Promise<OverlayNode | undefined> {
+		if (token.isCancellationRequested) {
+			throw new CancellationError();
+		}
+		const cache = this._cacheFor(options);
+		const cacheKey = structureCacheKey(lang, source, options);
+		const cacheValue = cache?.get(cacheKey);
+		if (cacheValue) {
+			this._hits++;
+			return raceCancellationError(cacheValue, token);
+		}
+		this._misses++;
+		// cancelled computations mustn't be cached, so the structure is cached only once it's computed
+		const structure = await this._getStructure(lang, source, options, token);
+		cache?.put(cacheKey, Promise.resolve(structure));
+		return structure;
+	}
+
+	/**
+	 * Yields the top-level nodes of the structure in source order as they're computed, before the links that need the whole structure are; it isn't cached.
+	 * With a transform, they're yielded once the whole structure is computed and transformed.
+	 * @throws {CancellationError} if `token` is cancelled before all nodes are yielded
+	 */
+	public async *getStructureStream(lang: WASMLanguage, source: string, token: CancellationToken = CancellationToken.None, options?: StructureOptions): AsyncIterable<OverlayNode> {
+		if (token.isCancellationRequested) {
+			throw new CancellationError();
+		}
+		if (options?.transform) {
+			const structure = await this._getStructure(lang, source, options, token);
+			yield* structure?.children ?? [];
+			return;
+		}
+		yield* this._computeStructure(lang, source, options, token);
+	}
+
+	/**
+	 * Computes the nodes of the structure of `source` that overlap `[startOffset, endOffset)`, see {@link getStructureInRange}, from the top-level declarations around it.
+	 * Short sources and languages whose declarations are linked, see {@link declarationLinkingLanguages}, get the structure of the whole source instead.
+	 */
+	public async getStructureForRange(lang: WASMLanguage, source: string, startOffset: number, endOffset: number): Promise<OverlayNode | undefined> {
+		const isSupported = getLanguageRegistration(lang) !== undefined || (syntacticallyValidAtoms[lang]?.length ?? 0) > 0;
+		if (!isSupported || source.length < StructureComputer.MIN_LENGTH_FOR_RANGE_RESTRICTION || declarationLinkingLanguages.has(lang) || this._cache?.get(structureCacheKey(lang, source, undefined))) {
+			const structure = await this.getStructure(lang, source);
+			return structure && getStructureInRange(structure, startOffset, endOffset, source);
+		}
+		// the tree is cached, so it's parsed only once for both the range and the structure
+		const treeRef = await _parse(lang, source);
+		let range: TreeSitterOffsetRange;
+		try {
+			range = topLevelRangeAround(treeRef.tree, source, startOffset, endOffset);
+		} finally {
+			treeRef.dispose();
+		}
+		this._misses++;
+		const structure = await this._getStructure(lang, source, undefined, undefined, undefined, range);
+		return structure && getStructureInRange(structure, startOffset, endOffset, source);
+	}
+
+	private async _getStructure(lang: WASMLanguage, source: string, options: StructureOptions | undefined, token?: CancellationToken, previous?: PreviousStructure, range?: TreeSitterOffsetRange): Promise<OverlayNode | undefined> {
+		if (options?.transform) {
+			// the nodes of the previous structure are transformed already, so they aren't reused for a structure that's transformed afterwards
+			const structure = await this._getStructure(lang, source, { ...options, transform: undefined }, token, undefined, range);
+			if (structure) {
+				transformStructure(structure, options.transform);
+			}
+			return structure;
+		}
+		const nodes = this._computeStructure(lang, source, options, token, previous, range);
+		let result = await nodes.next();
+		while (!result.done) {
+			result = await nodes.next();
+		}
+		return result.value;
+	}
+
+	/**
+	 * Yields top-level nodes as they're completed and returns the structure, i.e., the root node; nodes of `previous` outside of
+	 * the {@link recomputedRangeOf recomputed range} are reused, and with `range`, only the nodes within it are computed.
+	 */
+	private async *_computeStructure(lang: WASMLanguage, source: string, options: StructureOptions | undefined, token?: CancellationToken, previous?: PreviousStructure, range?: TreeSitterOffsetRange): AsyncGenerator<OverlayNode, OverlayNode | undefined> {
+		const maxDepth = options?.maxDepth ?? defaultMaxStructureDepth;
+		// languages registered at runtime bring a query of their own
+		const registration = getLanguageRegistration(lang);
+		const queries = registration ? [registration.structureQuery] : syntacticallyValidAtoms[lang] ?? [];
 
 		if (queries.length === 0) {
 			// language not supported
 			return undefined;
 		}
 
-		const treeRef = await _parse(lang, source);
+		// only an incremental parse tells what changed
+		const { treeRef, changedRanges } = previous
+			? await _reparseWithCache(lang, source, previous, { timeoutMs: options?.timeoutMs })
+			: { treeRef: await _parse(lang, source, undefined, { timeoutMs: options?.timeoutMs }), changedRanges: undefined };
 
 		try {
-			const captures = runQueries(queries, treeRef.tree.rootNode)
+			const positionAt = LineCharacterPosition.converterFor(source);
+			const byteOffsetAt = Utf8Offset.converterFor(source);
+
+			const reused = previous && changedRanges && reusedStructureOf(lang, previous, source, treeRef.tree, changedRanges, options, positionAt, byteOffsetAt);
+
+			const computedRange = reused?.range ?? range;
+			const captures = runQueries(queries, treeRef.tree.rootNode, computedRange)
 				.flatMap(e => e.captures)
+				.filter(c => !c.name.startsWith('_')) // captures prefixed with `_` are only used in predicates
+				.filter(c => !computedRange || TreeSitterOffsetRange.doesContain(computedRange, c.node))
 				.sort((a, b) => TreeSitterOffsetRange.compare(a.node, b.node));
 
 			// Exclude captures contained in ranges marked with ".exclude_captures"
@@ -52,9 +318,19 @@ export class StructureComputer {
 				}
 			}
 
+			const language = structureLanguages[lang];
 			const root = new OverlayNode(0, source.length, 'root', []);
+			OverlayNode.locate(root, positionAt, byteOffsetAt);
+			language?.describeRoot?.(root, treeRef.tree.rootNode, source);
 
 			const parentStack = [root];
+			let lastYield = Date.now();
+			let completedCount = 0;
+			let linker = language?.createLinker?.();
+
+			if (reused) {
+				root.children.push(...reused.before);
+			}
 
 			for (let i = 0; i < captures.length; ++i) {
 				const currentCapture = captures[i];
@@ -65,12 +341,48 @@ export class StructureComputer {
 					continue;
 				}
 
+				if (language?.skipsCapture?.(currentCapture, options)) {
+					// e.g., what tree-sitter couldn't parse in Go, or a value it inserted to recover from a trailing comma in JSON
+					continue;
+				}
+
+				if (currentCapture.name === 'anonymous_function' && !options?.includeAnonymousFunctions) {
+					// js/ts: e.g., `setTimeout(() => { ... })`; the statements of the body are nested in the enclosing statement instead
+					continue;
+				}
+
+				if (currentCapture.name === 'enum_member' && !options?.includeEnumMembers) {
+					// e.g., `Red` in `enum Color { Red, Green }`; the enum stands for them
+					continue;
+				}
+
+				if (currentCapture.name === 'parameter_property' && !currentNode.children.some(c => c.type === 'accessibility_modifier' || c.type === 'readonly')) {
+					// a plain parameter, e.g., `foo: Foo` in `constructor(foo: Foo)`, doesn't declare a field
+					continue;
+				}
+
 				// find a parent node that contains the current capture
 				let currentParent: OverlayNode;
 				do {
 					currentParent = parentStack.pop()!; // ! because we know there will be the root node
 				} while (currentParent && !TreeSitterOffsetRange.doesContain(currentParent, currentNode));
 
+				if (currentParent === root) {
+					// between top-level declarations
+					if (token) {
+						if (Date.now() - lastYield >= StructureComputer.YIELD_INTERVAL_MS) {
+							await timeout(0);
+							lastYield = Date.now();
+						}
+						if (token.isCancellationRequested) {
+							throw new CancellationError();
+						}
+					}
+					for (; completedCount < root.children.length; completedCount++) {
+						yield root.children[completedCount];
+					}
+				}
+
 				// nodes that need to be merged with their child, e.g.,
 				// Given `export const foo = 1;`, we can't just remove `const foo = 1;` because then syntax doesn't make sense
 				const ambientParents = new Set(['export_statement', 'ambient_declaration']);
@@ -78,22 +390,44 @@ export class StructureComputer {
 				if (ambientParents.has(currentParent.kind)) { // merge the parent with the child
 
 					currentParent.kind = currentNode.type;
+					currentParent.symbolKind = symbolKindOf(lang, currentParent.kind, currentNode);
+					currentParent.syntaxNode = syntaxNodeRefOf(currentNode);
+					describeOverlayNode(lang, currentNode, currentParent, source);
+					if (options?.normalizeTypes) {
+						normalizeTypeTexts(lang, currentParent);
+					}
 					parentStack.push(currentParent);
 
 				} else {
+					// the stack holds the ancestors of `currentParent`, so the new node would be at level `parentStack.length + 2`
+					if (parentStack.length + 2 > maxDepth) {
+						root.isTruncated = true;
+						parentStack.push(currentParent);
+						continue;
+					}
+
 					// get a more specific node kind
-					// js/ts/tsx: kind `method_definition` with identifier "constructor" -> kind `constructor`
 					let nodeKind = currentNode.type;
-					if ((lang === WASMLanguage.TypeScript || lang === WASMLanguage.TypeScriptTsx || lang === WASMLanguage.JavaScript) &&
-						nodeKind === 'method_definition' && currentNode.namedChildren.some(c => c.type === 'property_identifier' && c.text === 'constructor')) {
-						nodeKind = 'constructor';
+					// ts/tsx: members of enums without values, c/c++: kind `enumerator`, c#: kind `enum_member_declaration` -> kind `enum_member`
+					if (currentCapture.name === 'enum_member') {
+						nodeKind = 'enum_member';
+					}
+					// ts/tsx: parameters with modifiers, e.g., `private foo: Foo` in a constructor -> kind `parameter_property`
+					if (currentCapture.name === 'parameter_property') {
+						nodeKind = 'parameter_property';
 					}
+					// json/yaml/toml/ini: keys and array elements get the same kinds in all of them, e.g., `block_mapping_pair` -> kind `pair`, `block_sequence_item` -> kind `array_element`
+					if (currentCapture.name === 'pair' || currentCapture.name === 'array_element') {
+						nodeKind = currentCapture.name;
+					}
+					nodeKind = language?.nodeKindOf?.(currentCapture, nodeKind, options) ?? nodeKind;
 
-					let startIndex = currentNode.startIndex;
+					const [firstSyntaxNode, lastSyntaxNode] = language?.extentOf?.(currentCapture) ?? [currentNode, currentNode];
+					let startIndex = firstSyntaxNode.startIndex;
 
-					const prevSibling = currentNode.previousSibling;
+					const prevSibling = firstSyntaxNode.previousSibling;
 					if (prevSibling !== null) {
-						const textBetweenNodes = source.substring(prevSibling.endIndex, currentNode.startIndex);
+						const textBetweenNodes = source.substring(prevSibling.endIndex, firstSyntaxNode.startIndex);
 						const nlIdx = textBetweenNodes.indexOf('\n');
 						if (nlIdx === -1) {
 							startIndex = prevSibling.endIndex;
@@ -102,15 +436,15 @@ export class StructureComputer {
 						}
 					}
 
-					let endIndex = currentNode.endIndex;
+					let endIndex = lastSyntaxNode.endIndex;
 
 					// currentNode should subsume sibling nodes that are trivial, eg `;`, `,`, same-line comment
 					// if trivial sibling node is itself captured as a separate node, then it becomes a child node of currentNode, ie currentNode would have a comment as a child
 					// else it's just part of the node
-					if (currentNode.nextSibling !== null) {
-						let nextSibling: SyntaxNode | null = currentNode.nextSibling;
+					if (lastSyntaxNode.nextSibling !== null) {
+						let nextSibling: SyntaxNode | null = lastSyntaxNode.nextSibling;
 
-						if (lang === WASMLanguage.TypeScript || lang === WASMLanguage.TypeScriptTsx || lang === WASMLanguage.JavaScript || lang === WASMLanguage.Cpp) {
+						if (trivialSiblingLanguages.has(lang)) {
 							while (nextSibling &&
 								(nextSibling.type === ';' ||
 									nextSibling.type === ',' ||
@@ -131,15 +465,77 @@ export class StructureComputer {
 						}
 					}
 
+					endIndex = language?.endIndexOf?.(currentNode, nodeKind, source) ?? endIndex;
+
 					const newNode = new OverlayNode(startIndex, endIndex, nodeKind, []);
+					OverlayNode.locate(newNode, positionAt, byteOffsetAt);
+					newNode.symbolKind = symbolKindOf(lang, nodeKind, currentNode);
+					newNode.syntaxNode = syntaxNodeRefOf(currentNode);
+					describeOverlayNode(lang, currentNode, newNode, source);
+					if (options?.normalizeTypes) {
+						normalizeTypeTexts(lang, newNode);
+					}
+					if (language?.keyPathOf) {
+						newNode.keyPath = language.keyPathOf(currentNode, newNode, currentParent.kind === 'root' || currentParent.kind === 'document' ? [] : currentParent.keyPath);
+					}
+					if (isCutOff(currentNode, source)) {
+						newNode.isIncomplete = true;
+					}
+					linker?.register?.(newNode, currentNode);
+					// go: function literals, js/ts: callbacks, which are captured with `includeAnonymousFunctions`
+					if (currentCapture.name === 'func_literal' || currentCapture.name === 'anonymous_function') {
+						newNode.enclosingSymbol = [...parentStack, currentParent].reverse().find(n => n !== root && n.name !== undefined && symbolKinds.has(n.kind))?.name;
+					}
+					language?.attach?.(newNode, currentNode, [...parentStack, currentParent]);
 					currentParent.children.push(newNode);
 					parentStack.push(currentParent, newNode);
 				}
 			}
 
+			if (reused) {
+				root.children.push(...reused.after);
+				if (root.children.some((c, i) => i > 0 && c.startIndex < root.children[i - 1].endIndex)) {
+					// the nodes computed again extend beyond the range, e.g., a body that became a sibling of its declaration, so they don't fit in between the reused ones
+					return yield* this._computeStructure(lang, source, options, token);
+				}
+				// the declarations are registered again in source order, i.e., the order of their captures, so that among those of the same name,
+				// the same ones are linked as if the whole structure was computed, and the calls deferred by reused functions are their copies
+				linker = language?.createLinker?.();
+				const ancestors: OverlayNode[] = [];
+				const visit = (node: OverlayNode) => {
+					const syntaxNode = getSyntaxNode(treeRef.tree, node);
+					if (syntaxNode) {
+						linker?.register?.(node, syntaxNode);
+						if (reused.copies.has(node)) {
+							language?.attach?.(node, syntaxNode, ancestors);
+						}
+					}
+					ancestors.push(node);
+					node.children.forEach(visit);
+					ancestors.pop();
+				};
+				root.children.forEach(visit);
+			}
+
+			for (; completedCount < root.children.length; completedCount++) {
+				yield root.children[completedCount];
+			}
+
+			if (options?.includeTodoComments) {
+				insertTodoAnnotations(root, treeRef.tree.rootNode, source, positionAt, byteOffsetAt);
+			}
+
+			linker?.link(root, options);
+
+			qualifyNames(lang, root);
+			assignStableIds(root);
+
 			return root;
 
 		} catch (e) {
+			if (isCancellationError(e)) {
+				throw e;
+			}
 			console.error(e instanceof Error ? e : new Error(e));
 		} finally {
 			treeRef.dispose();
@@ -148,3 +544,673 @@ export class StructureComputer {
 }
 
 export const structureComputer = new StructureComputer();
+
+/**
+ * Languages whose declarations are linked to those elsewhere in the source or may enclose those that follow them, e.g., Go methods and their types
+ */
+const declarationLinkingLanguages = new Set([
+	WASMLanguage.Go, WASMLanguage.Rust, WASMLanguage.Cpp, WASMLanguage.Csharp, WASMLanguage.Php, WASMLanguage.Scala, WASMLanguage.Graphql,
+]);
+
+/**
+ * Languages whose nodes subsume the trivial siblings that follow them, e.g., `;`, `,`, or a comment on the same line
+ */
+const trivialSiblingLanguages = new Set([
+	WASMLanguage.TypeScript, WASMLanguage.TypeScriptTsx, WASMLanguage.JavaScript, WASMLanguage.C, WASMLanguage.Cpp, WASMLanguage.Rust, WASMLanguage.Dart,
+	WASMLanguage.Json, WASMLanguage.Lua, WASMLanguage.Sql, WASMLanguage.Toml, WASMLanguage.R, WASMLanguage.ObjectiveC,
+]);
+
+function structureCacheKey(lang: WASMLanguage, source: string, options: StructureOptions | undefined): string {
+	return `${contentKey(lang, source)}:${options?.maxDepth ?? defaultMaxStructureDepth}:${options?.inlineEmbeddedInterfaces ?? false}:${options?.nestGoMethods ?? false}:${options?.includeAnonymousFunctions ?? false}:${options?.includeGoCaseClauses ?? false}:${options?.includeJsxElements ?? false}:${options?.includeEnumMembers ?? false}:${options?.includeTodoComments ?? false}:${options?.normalizeTypes ?? false}:${options?.exportedOnly ?? false}`;
+}
+
+/**
+ * Nodes of a previous structure that are reused for the structure of an edited source, see {@link StructureComputer.getStructureIncrementally}.
+ */
+interface ReusedStructure {
+	/** Range of the edited source whose nodes are computed again, see {@link recomputedRangeOf} */
+	readonly range: TreeSitterOffsetRange;
+	/** Copies of the top-level nodes before {@link range}, whose offsets are the same in both sources */
+	readonly before: OverlayNode[];
+	/** Copies of the top-level nodes after {@link range}, whose offsets are shifted by the difference of the lengths of the sources */
+	readonly after: OverlayNode[];
+	/** All copied nodes, including the nested ones */
+	readonly copies: ReadonlySet<OverlayNode>;
+}
+
+/**
+ * @returns nodes of `previous.structure` that aren't affected by the edits, copied for `source`, whose parse tree is `tree`;
+ * `undefined` if the whole structure is computed again, e.g., if a top-level node of the previous structure extends into the range that's computed again
+ */
+function reusedStructureOf(
+	lang: WASMLanguage,
+	previous: PreviousStructure,
+	source: string,
+	tree: Tree,
+	changedRanges: readonly TreeSitterOffsetRange[],
+	options: StructureOptions | undefined,
+	positionAt: (offset: number) => LineCharacterPosition,
+	byteOffsetAt: (offset: number) => number,
+): ReusedStructure | undefined {
+	// todo annotations and nested or inlined go methods are added to the declarations around them, c++ methods defined outside of their classes and c# partial declarations
+	// change the declarations they're linked to, and php namespaces without braces enclose the declarations that follow them
+	if (options?.includeTodoComments || options?.nestGoMethods || options?.inlineEmbeddedInterfaces || previous.structure.isTruncated
+		|| lang === WASMLanguage.Cpp || lang === WASMLanguage.Csharp || lang === WASMLanguage.Php) {
+		return undefined;
+	}
+	const range = recomputedRangeOf(tree, previous.previousSource, source, changedRanges);
+	// the sources are the same after the range, so offsets there are shifted by the difference of their lengths
+	const shift = source.length - previous.previousSource.length;
+	const before: OverlayNode[] = [];
+	const after: OverlayNode[] = [];
+	for (const node of previous.structure.children) {
+		if (node.endIndex <= range.startIndex) {
+			before.push(node);
+		} else if (node.startIndex + shift >= range.endIndex) {
+			after.push(node);
+		} else if (node.syntaxNode === undefined || node.syntaxNode.startIndex < range.startIndex || node.syntaxNode.endIndex + shift > range.endIndex) {
+			return undefined;
+		}
+	}
+	if (before.length === 0 && after.length === 0) {
+		return undefined;
+	}
+	const copies = new Map<OverlayNode, OverlayNode>();
+	const beforeCopies = copyForEditedSource(before, 0, tree, positionAt, byteOffsetAt, copies);
+	const afterCopies = beforeCopies && copyForEditedSource(after, shift, tree, positionAt, byteOffsetAt, copies);
+	return afterCopies && { range, before: beforeCopies, after: afterCopies, copies: new Set(copies.values()) };
+}
+
+/**
+ * @returns range of `source` whose structure is computed again after `previousSource` was edited into it, i.e., the {@link topLevelRangeAround top-level syntax nodes around}
+ * what changed, textually or syntactically, see `changedRanges`
+ */
+export function recomputedRangeOf(tree: Tree, previousSource: string, source: string, changedRanges: readonly TreeSitterOffsetRange[]): TreeSitterOffsetRange {
+	// later edits may overlap or undo earlier ones, so what changed is what's between the common prefix and suffix of the sources
+	const maxLength = Math.min(previousSource.length, source.length);
+	let prefixLength = 0;
+	while (prefixLength < maxLength && previousSource.charCodeAt(prefixLength) === source.charCodeAt(prefixLength)) {
+		prefixLength++;
+	}
+	let suffixLength = 0;
+	while (suffixLength < maxLength - prefixLength && previousSource.charCodeAt(previousSource.length - 1 - suffixLength) === source.charCodeAt(source.length - 1 - suffixLength)) {
+		suffixLength++;
+	}
+	const changeStart = changedRanges.reduce((start, r) => Math.min(start, r.startIndex), prefixLength);
+	const changeEnd = changedRanges.reduce((end, r) => Math.max(end, r.endIndex), source.length - suffixLength);
+	return topLevelRangeAround(tree, source, changeStart, changeEnd);
+}
+
+/**
+ * @returns range of the top-level syntax nodes that overlap or touch `[start, end]` and one more on either side, extended over the nodes that attach to their neighbors,
+ * e.g., comments, a trailing `;`, or a decorator
+ */
+function topLevelRangeAround(tree: Tree, source: string, start: number, end: number): TreeSitterOffsetRange {
+	const children = tree.rootNode.children;
+	const attachesToPrevious = (node: SyntaxNode) => node.type === ';' || node.type === ',' || node.type === 'function_body' || commentNodeTypes.includes(node.type);
+	const attachesToNext = (node: SyntaxNode) => node.type === 'decorator' || node.type.endsWith('annotation') || commentNodeTypes.includes(node.type);
+
+	const firstOverlapping = children.findIndex(c => c.endIndex >= start);
+	let first = (firstOverlapping === -1 ? children.length : firstOverlapping) - 1;
+	while (first > 0 && (attachesToPrevious(children[first]) || attachesToNext(children[first - 1]))) {
+		first--;
+	}
+	let last = findLastIdx(children, c => c.startIndex <= end) + 1;
+	while (last >= 0 && last < children.length - 1 && (attachesToPrevious(children[last + 1]) || attachesToNext(children[last]))) {
+		last++;
+	}
+	return {
+		startIndex: first <= 0 ? 0 : children[first].startIndex,
+		endIndex: last < 0 || last >= children.length - 1 ? source.length : children[last].endIndex,
+	};
+}
+
+/**
+ * @returns copies of `nodes` with offsets shifted by `shift`, without the links computed for the whole structure, which are added to `copies`;
+ * `undefined` if a syntax node isn't found in `tree`
+ */
+function copyForEditedSource(
+	nodes: readonly OverlayNode[],
+	shift: number,
+	tree: Tree,
+	positionAt: (offset: number) => LineCharacterPosition,
+	byteOffsetAt: (offset: number) => number,
+	copies: Map<OverlayNode, OverlayNode>,
+): OverlayNode[] | undefined {
+	const copy = (node: OverlayNode): OverlayNode | undefined => {
+		const { startIndex, endIndex, children, methods, companion, defers, operationType, qualifiedName, stableId, exported, syntaxNode, jumpTarget, ...details } = node;
+		const childCopies: OverlayNode[] = [];
+		for (const child of children) {
+			const childCopy = copy(child);
+			if (childCopy === undefined) {
+				return undefined;
+			}
+			childCopies.push(childCopy);
+		}
+		const newNode = Object.assign(new OverlayNode(startIndex + shift, endIndex + shift, node.kind, childCopies), details);
+		OverlayNode.locate(newNode, positionAt, byteOffsetAt);
+		if (syntaxNode) {
+			newNode.syntaxNode = { ...syntaxNode, startIndex: syntaxNode.startIndex + shift, endIndex: syntaxNode.endIndex + shift };
+			const newSyntaxNode = getSyntaxNode(tree, newNode);
+			if (newSyntaxNode === undefined) {
+				return undefined;
+			}
+			newNode.syntaxNode = syntaxNodeRefOf(newSyntaxNode);
+		}
+		if (jumpTarget) {
+			newNode.jumpTarget = jumpTarget.startIndex === undefined ? { ...jumpTarget } : { ...jumpTarget, startIndex: jumpTarget.startIndex + shift };
+		}
+		copies.set(node, newNode);
+		return newNode;
+	};
+	const nodeCopies: OverlayNode[] = [];
+	for (const node of nodes) {
+		const nodeCopy = copy(node);
+		if (nodeCopy === undefined) {
+			return undefined;
+		}
+		nodeCopies.push(nodeCopy);
+	}
+	return nodeCopies;
+}
+
+/**
+ * Kinds of structure nodes that declare a function, type, or namespace, i.e., the node types of {@link enclosingDeclarationTypes}
+ * and the more specific kinds {@link StructureComputer} assigns to some of them
+ */
+const symbolKinds = new Set([
+	...Object.values(enclosingDeclarationTypes).flatMap(types => [...types.function, ...types.type, ...types.namespace]),
+	'constructor', // js/ts/tsx, php
+	'struct_declaration', 'enum_declaration', 'actor_declaration', 'extension_declaration', // swift
+	'interface_declaration', // kotlin
+	'type_declaration', 'method_spec', // go: type specs aren't structure nodes of their own
+	'method_item', 'associated_function_item', // rust
+	'case_class_definition', 'case_object_definition', // scala
+	'generic_definition', // r
+]);
+
+/**
+ * Applies `transform` to the nodes of a computed structure in place, see {@link StructureOptions.transform}, and links the nodes that are kept in place of
+ * the methods, deferred calls, companions, and annotations they were linked to, leaving out those that were dropped.
+ */
+export function transformStructure(structure: OverlayNode, transform: StructureTransform): void {
+	// links are matched by stable ID, since those of a structure that was passed as JSON are copies of its nodes, and by the IDs from before the transform
+	const stableIds = new Map<OverlayNode, string | undefined>();
+	const record = (node: OverlayNode) => {
+		stableIds.set(node, node.stableId);
+		node.children.forEach(record);
+	};
+	record(structure);
+	const kept = new Map<string, OverlayNode>();
+	const visit = (node: OverlayNode) => {
+		const children: OverlayNode[] = [];
+		for (const child of node.children) {
+			const transformed = transform(child);
+			if (transformed === 'dropWithChildren') {
+				continue;
+			}
+			visit(child);
+			if (transformed === 'drop') {
+				children.push(...child.children);
+			} else {
+				children.push(child);
+				const stableId = stableIds.get(child);
+				if (stableId !== undefined) {
+					kept.set(stableId, child);
+				}
+			}
+		}
+		node.children.splice(0, node.children.length, ...children);
+	};
+	visit(structure);
+	const keptOf = (link: OverlayNode) => {
+		const stableId = stableIds.has(link) ? stableIds.get(link) : link.stableId;
+		return stableId === undefined ? undefined : kept.get(stableId);
+	};
+	const relink = (node: OverlayNode) => {
+		if (node.methods) {
+			node.methods = coalesce(node.methods.map(keptOf));
+		}
+		if (node.defers) {
+			node.defers = coalesce(node.defers.map(keptOf));
+		}
+		if (node.companion) {
+			node.companion = keptOf(node.companion);
+		}
+		if (node.annotations) {
+			node.annotations = coalesce(node.annotations.map(keptOf));
+		}
+		node.children.forEach(relink);
+	};
+	relink(structure);
+}
+
+/**
+ * @returns innermost node of `tree` that declares a function, type, or namespace and contains `offset`,
+ * e.g., the function for an offset within a loop in its body; `undefined` if there's no such node
+ */
+export function getEnclosingSymbol(tree: OverlayNode, offset: number): OverlayNode | undefined {
+	let enclosingSymbol: OverlayNode | undefined;
+	// siblings don't overlap but may touch, so at most one child contains the offset
+	for (let node: OverlayNode | undefined = tree; node !== undefined; node = node.children.find(c => c.startIndex <= offset && offset < c.endIndex)) {
+		if (node !== tree && symbolKinds.has(node.kind)) {
+			enclosingSymbol = node;
+		}
+	}
+	return enclosingSymbol;
+}
+
+/**
+ * @returns nodes of `tree` that declare a function, type, or namespace and overlap `[startOffset, endOffset)` without their leading and trailing whitespace,
+ * ordered by their start offsets
+ */
+export function getSymbolsInRange(tree: OverlayNode, startOffset: number, endOffset: number, source: string): OverlayNode[] {
+	const start = clamp(startOffset, tree.startIndex, tree.endIndex);
+	const end = clamp(endOffset, tree.startIndex, tree.endIndex);
+	const symbols: OverlayNode[] = [];
+	const visit = (node: OverlayNode) => {
+		for (const child of node.children) {
+			if (child.startIndex >= end) {
+				break; // siblings are ordered and don't overlap
+			}
+			if (child.endIndex <= start) {
+				continue;
+			}
+			if (symbolKinds.has(child.kind)) {
+				const content = contentRangeOf(child, source);
+				if (content.startIndex < end && start < content.endIndex) {
+					symbols.push(child);
+				}
+			}
+			visit(child);
+		}
+	};
+	visit(tree);
+	return symbols;
+}
+
+/**
+ * @returns a copy of `tree` with only the nodes that overlap `[startOffset, endOffset)`, e.g., a function and the statements of it within the range,
+ * whose nodes keep their offsets in the whole source; without children if the range is within whitespace between nodes, see {@link getSymbolsInRange}
+ */
+export function getStructureInRange(tree: OverlayNode, startOffset: number, endOffset: number, source: string): OverlayNode {
+	const start = clamp(startOffset, tree.startIndex, tree.endIndex);
+	const end = clamp(endOffset, tree.startIndex, tree.endIndex);
+	const copies = new Map<OverlayNode, OverlayNode>();
+	const copy = (node: OverlayNode): OverlayNode => {
+		const { startIndex, endIndex, children, ...details } = node;
+		const overlapping = children.filter(c => {
+			const content = contentRangeOf(c, source);
+			return content.startIndex < end && start < content.endIndex;
+		});
+		const newNode = Object.assign(new OverlayNode(startIndex, endIndex, node.kind, overlapping.map(copy)), details);
+		copies.set(node, newNode);
+		return newNode;
+	};
+	const root = copy(tree);
+	// linked methods, deferred calls, companions, and annotations that are within the range are replaced by their copies, see `withOffset` of markdownCodeBlocks.ts
+	for (const newNode of copies.values()) {
+		if (newNode.methods) {
+			newNode.methods = newNode.methods.map(m => copies.get(m) ?? m);
+		}
+		if (newNode.defers) {
+			newNode.defers = newNode.defers.map(d => copies.get(d) ?? d);
+		}
+		if (newNode.companion) {
+			newNode.companion = copies.get(newNode.companion) ?? newNode.companion;
+		}
+		if (newNode.annotations) {
+			newNode.annotations = newNode.annotations.map(a => copies.get(a) ?? a);
+		}
+	}
+	return root;
+}
+
+/**
+ * @returns range of `node` without the leading and trailing whitespace it extends to, e.g., the blank line before a function
+ */
+function contentRangeOf(node: OverlayNode, source: string): TreeSitterOffsetRange {
+	const text = getNodeText(source, node);
+	return { startIndex: node.startIndex + (text.length - text.trimStart().length), endIndex: node.startIndex + text.trimEnd().length };
+}
+
+/**
+ * Symbols of two structures that have the same identity, see {@link diffStructures}.
+ */
+export interface MatchedSymbol {
+	readonly oldNode: OverlayNode;
+	readonly newNode: OverlayNode;
+}
+
+/**
+ * How the symbols, i.e., the named nodes, of a structure differ from those of a previous structure of the same file, each list in source order.
+ */
+export interface StructureDiff {
+	/** Symbols of the new structure without a counterpart in the old one */
+	readonly added: OverlayNode[];
+	/** Symbols of the old structure without a counterpart in the new one */
+	readonly removed: OverlayNode[];
+	/**
+	 * Symbols whose qualified names differ but that take the place of each other between the same symbols with the same kinds and signatures
+	 * apart from their names, e.g., after renaming them or the types they belong to
+	 */
+	readonly renamed: MatchedSymbol[];
+	/** Symbols with the same signature whose range shifted, e.g., because a declaration above them grew */
+	readonly moved: MatchedSymbol[];
+	/** Symbols whose signatures, i.e., {@link OverlayNode.detail}, differ; their ranges may have shifted, too */
+	readonly changed: MatchedSymbol[];
+}
+
+/**
+ * Matches the symbols of two structures of the same file by their kinds and qualified names, or as renamed if they're between the same matched symbols
+ * and have the same kinds and signatures apart from their names; symbols that are neither moved nor changed aren't listed.
+ */
+export function diffStructures(oldNodes: readonly OverlayNode[], newNodes: readonly OverlayNode[]): StructureDiff {
+	const oldSymbols = symbolsByIdentity(oldNodes);
+	const newSymbols = symbolsByIdentity(newNodes);
+	const diff = { added: [] as OverlayNode[], removed: [] as OverlayNode[], renamed: [] as MatchedSymbol[], moved: [] as MatchedSymbol[], changed: [] as MatchedSymbol[] };
+
+	// unmatched symbols by the identity of the last matched symbol before them, if any
+	const unmatchedOld: (QualifiedSymbol & { after: string | undefined })[] = [];
+	let after: string | undefined;
+	for (const [identity, oldSymbol] of oldSymbols) {
+		if (newSymbols.has(identity)) {
+			after = identity;
+		} else {
+			unmatchedOld.push({ ...oldSymbol, after });
+		}
+	}
+
+	// qualified names of renamed symbols, whose members are renamed along with them
+	const renamedQualifiers = new Map<string, string>();
+	after = undefined;
+	for (const [identity, { node: newNode, qualifiedName, qualifier }] of newSymbols) {
+		const oldNode = oldSymbols.get(identity)?.node;
+		if (oldNode === undefined) {
+			const index = unmatchedOld.findIndex(o => o.after === after && o.node.kind === newNode.kind
+				&& (o.qualifier !== undefined ? renamedQualifiers.get(o.qualifier) ?? o.qualifier : undefined) === qualifier
+				&& signatureWithoutName(o.node) === signatureWithoutName(newNode));
+			if (index === -1) {
+				diff.added.push(newNode);
+			} else {
+				const [renamed] = unmatchedOld.splice(index, 1);
+				renamedQualifiers.set(renamed.qualifiedName, qualifiedName);
+				diff.renamed.push({ oldNode: renamed.node, newNode });
+			}
+			continue;
+		}
+		after = identity;
+		if (oldNode.detail !== newNode.detail) {
+			diff.changed.push({ oldNode, newNode });
+		} else if (oldNode.startIndex !== newNode.startIndex || oldNode.endIndex !== newNode.endIndex) {
+			diff.moved.push({ oldNode, newNode });
+		}
+	}
+	diff.removed.push(...unmatchedOld.map(o => o.node));
+	return diff;
+}
+
+/**
+ * @returns {@link OverlayNode.detail} of the named `node` with its name left out, e.g., `func (s StructExample) () error`
+ */
+function signatureWithoutName(node: OverlayNode): string | undefined {
+	return node.detail?.split(node.name!).join('');
+}
+
+/**
+ * Indexes the named nodes of a structure and their descendants by their simple and {@link OverlayNode.qualifiedName qualified} names,
+ * and Go nodes by their qualified names without the package, too; a name declared several times lists all of its declarations.
+ */
+export function buildSymbolIndex(structure: OverlayNode): Map<string, OverlayNode[]> {
+	const index = new Map<string, OverlayNode[]>();
+	const add = (name: string, node: OverlayNode) => {
+		const nodes = index.get(name);
+		if (nodes === undefined) {
+			index.set(name, [node]);
+		} else if (nodes[nodes.length - 1] !== node) {
+			nodes.push(node);
+		}
+	};
+	const packagePrefix = structure.packageName !== undefined ? `${structure.packageName}.` : undefined;
+	// a structure that wasn't qualified, e.g., one that was computed from indentation, falls back to names joined with `.`
+	forEachQualifiedSymbol(structure.children, (node, joinedName) => {
+		const qualifiedName = node.qualifiedName ?? joinedName;
+		add(node.name!, node);
+		add(qualifiedName, node);
+		if (packagePrefix !== undefined && qualifiedName.startsWith(packagePrefix)) {
+			add(qualifiedName.slice(packagePrefix.length), node);
+		}
+	});
+	return index;
+}
+
+export interface ExtractSymbolOptions {
+	/**
+	 * Whether the text starts with the doc comment of the declaration, see {@link OverlayNode.leadingComment}.
+	 * @default false
+	 */
+	includeLeadingComment?: boolean;
+
+	/**
+	 * Whether to get all declarations of a name that's declared several times, e.g., overloads, rather than the first one in source order.
+	 * @default false
+	 */
+	allMatches?: boolean;
+}
+
+/**
+ * Source text of a declaration, see {@link extractSymbols}.
+ */
+export interface ExtractedSymbol {
+	/**
+	 * Declaration as written in source, e.g., a Go method with its receiver and body, and its leading comment if requested.
+	 */
+	readonly text: string;
+
+	/**
+	 * Range of {@link text} in the source.
+	 */
+	readonly range: TreeSitterOffsetRange;
+}
+
+/**
+ * @returns declarations of a structure named `qualifiedName` in source order without the whitespace their nodes extend to,
+ * and with their {@link OverlayNode.leadingComment}s if `includeLeadingComment`
+ */
+export function extractSymbols(structure: OverlayNode, source: string, qualifiedName: string, includeLeadingComment: boolean): ExtractedSymbol[] {
+	return (buildSymbolIndex(structure).get(qualifiedName) ?? []).map(node => {
+		const content = contentRangeOf(node, source);
+		let startIndex = content.startIndex;
+		const endIndex = content.endIndex;
+		if (includeLeadingComment && node.leadingComment !== undefined) {
+			// the comment run precedes the declaration with nothing but whitespace in between
+			const commentStart = source.lastIndexOf(node.leadingComment, startIndex);
+			if (commentStart !== -1) {
+				startIndex = commentStart;
+			}
+		}
+		return { text: source.substring(startIndex, endIndex), range: { startIndex, endIndex } };
+	});
+}
+
+/**
+ * @returns syntax node `overlayNode` was computed from, see {@link OverlayNode.syntaxNode}; `undefined` if it wasn't computed from `tree`
+ */
+export function getSyntaxNode(tree: Tree, overlayNode: OverlayNode): SyntaxNode | undefined {
+	const ref = overlayNode.syntaxNode;
+	if (ref === undefined) {
+		return undefined;
+	}
+	// the smallest node that spans the range and its ancestors of the same range, e.g., an `expression_statement` and the `call_expression` it wraps
+	const candidates: SyntaxNode[] = [];
+	for (let node: SyntaxNode | null = tree.rootNode.descendantForIndex(ref.startIndex, ref.endIndex); node !== null; node = node.parent) {
+		if (node.startIndex !== ref.startIndex || node.endIndex !== ref.endIndex) {
+			break;
+		}
+		candidates.push(node);
+	}
+	return candidates.find(n => n.id === ref.id && n.type === ref.type) ?? candidates.find(n => n.type === ref.type);
+}
+
+function syntaxNodeRefOf(node: SyntaxNode): NonNullable<OverlayNode['syntaxNode']> {
+	return { id: node.id, type: node.type, startIndex: node.startIndex, endIndex: node.endIndex };
+}
+
+interface QualifiedSymbol {
+	readonly node: OverlayNode;
+	/** e.g., `Point.String` */
+	readonly qualifiedName: string;
+	/** Qualified name of the named ancestor or receiver type, e.g., `Point`; `undefined` for a top-level symbol */
+	readonly qualifier: string | undefined;
+}
+
+/**
+ * @returns named nodes in source order by their kinds and names qualified by their named ancestors or receiver types, e.g., `method_declaration:Point.String`;
+ * a repeated identity, e.g., of overloads or duplicate keys, gets the number of its occurrence appended, e.g., `#2`
+ */
+function symbolsByIdentity(nodes: readonly OverlayNode[]): Map<string, QualifiedSymbol> {
+	const symbols = new Map<string, QualifiedSymbol>();
+	forEachQualifiedSymbol(nodes, (node, qualifiedName) => {
+		const baseIdentity = `${node.kind}:${qualifiedName}`;
+		let identity = baseIdentity;
+		for (let occurrence = 2; symbols.has(identity); occurrence++) {
+			identity = `${baseIdentity}#${occurrence}`;
+		}
+		const qualifier = qualifiedName === node.name ? undefined : qualifiedName.slice(0, -node.name!.length - 1);
+		symbols.set(identity, { node, qualifiedName, qualifier });
+	});
+	return symbols;
+}
+
+/**
+ * Calls `callback` for the named nodes among `nodes` and their descendants in source order with their names qualified by their named ancestors
+ * or receiver types, e.g., `Point.String`, see {@link buildSymbolIndex}
+ */
+function forEachQualifiedSymbol(nodes: readonly OverlayNode[], callback: (node: OverlayNode, qualifiedName: string) => void): void {
+	const visit = (node: OverlayNode, qualifier: string | undefined) => {
+		let qualifiedName = qualifier;
+		if (node.name !== undefined) {
+			qualifiedName = node.receiver ? `${node.receiver.typeName}.${node.name}` : qualifier === undefined ? node.name : `${qualifier}.${node.name}`;
+			callback(node, qualifiedName);
+		}
+		for (const child of node.children) {
+			visit(child, qualifiedName);
+		}
+	};
+	for (const node of nodes) {
+		visit(node, undefined);
+	}
+}
+
+/**
+ * Separators of the names of a {@link OverlayNode.qualifiedName} by language family, e.g., `::` for `geo::Shape::area`; others use `.`.
+ */
+export const qualifiedNameSeparators: { readonly [language in WASMLanguage]?: string } = {
+	[WASMLanguage.C]: '::',
+	[WASMLanguage.Cpp]: '::',
+	[WASMLanguage.Rust]: '::',
+	[WASMLanguage.Php]: '\\',
+};
+
+/**
+ * Sets {@link OverlayNode.qualifiedName} of the named nodes, i.e., their names prefixed with those of their named ancestors, e.g., namespaces and types,
+ * and with the package of a Go source; a Go method is qualified with its receiver type and a C++ function defined outside of its class with its scope instead.
+ */
+function qualifyNames(lang: WASMLanguage, root: OverlayNode) {
+	const separator = qualifiedNameSeparators[lang] ?? '.';
+	const join = (qualifier: string | undefined, name: string) => qualifier === undefined ? name : `${qualifier}${separator}${name}`;
+	const visit = (node: OverlayNode, qualifier: string | undefined) => {
+		let childQualifier = qualifier;
+		if (node.name !== undefined) {
+			if (node.receiver) {
+				node.qualifiedName = join(join(root.packageName, node.receiver.typeName), node.name);
+			} else {
+				// e.g., `Shape::area` within `namespace geo { ... }` is `geo::Shape::area`
+				node.qualifiedName = join(node.qualifier !== undefined ? join(qualifier, node.qualifier) : qualifier, node.name);
+			}
+			childQualifier = node.qualifiedName;
+		}
+		for (const child of node.children) {
+			visit(child, childQualifier);
+		}
+	};
+	for (const child of root.children) {
+		visit(child, root.packageName);
+	}
+}
+
+/**
+ * Sets {@link OverlayNode.stableId} of all nodes but the root to the kind and qualified name of a named node or the kind of an unnamed one after
+ * the ID of its parent; a repeated ID gets the number of its occurrence appended, e.g., `#2`.
+ */
+function assignStableIds(root: OverlayNode) {
+	const ids = new Set<string>();
+	const visit = (node: OverlayNode, parentId: string | undefined) => {
+		const baseId = node.qualifiedName !== undefined ? `${node.kind}:${node.qualifiedName}` : parentId !== undefined ? `${parentId}/${node.kind}` : node.kind;
+		let id = baseId;
+		for (let occurrence = 2; ids.has(id); occurrence++) {
+			id = `${baseId}#${occurrence}`;
+		}
+		ids.add(id);
+		node.stableId = id;
+		for (const child of node.children) {
+			visit(child, id);
+		}
+	};
+	for (const child of root.children) {
+		visit(child, undefined);
+	}
+}
+
+/**
+ * Gives every comment that starts with a marker, e.g., `// TODO: handle empty name`, a `todo_annotation` node, see {@link StructureOptions.includeTodoComments},
+ * and adds it to the {@link OverlayNode.annotations} of its next sibling that declares a symbol, not counting comments, e.g., the method below it.
+ */
+function insertTodoAnnotations(root: OverlayNode, rootSyntaxNode: SyntaxNode, source: string, positionAt: (offset: number) => LineCharacterPosition, byteOffsetAt: (offset: number) => number) {
+	for (const comment of rootSyntaxNode.descendantsOfType(commentNodeTypes)) {
+		const annotation = todoAnnotationOf(comment.text);
+		if (!annotation) {
+			continue;
+		}
+		// from the root to the innermost node that contains the comment
+		const contains = (n: OverlayNode) => n.startIndex <= comment.startIndex && comment.endIndex <= n.endIndex;
+		const path = [root];
+		for (let child = root.children.find(contains); child !== undefined; child = child.children.find(contains)) {
+			path.push(child);
+		}
+		let node = path[path.length - 1];
+		// the comment's own node, e.g., of a language whose comments are captured, which extends to the line break after it
+		const content = contentRangeOf(node, source);
+		if (node !== root && commentNodeTypes.includes(node.kind) && content.startIndex === comment.startIndex && content.endIndex === comment.endIndex) {
+			node.kind = 'todo_annotation';
+			path.pop();
+		} else {
+			const parent = node;
+			node = new OverlayNode(comment.startIndex, comment.endIndex, 'todo_annotation', []);
+			OverlayNode.locate(node, positionAt, byteOffsetAt);
+			const index = parent.children.findIndex(c => c.startIndex >= comment.endIndex);
+			parent.children.splice(index === -1 ? parent.children.length : index, 0, node);
+		}
+		node.annotation = annotation;
+		const siblings = path[path.length - 1].children;
+		const declaration = siblings.slice(siblings.indexOf(node) + 1).find(c => c.kind !== 'todo_annotation' && !commentNodeTypes.includes(c.kind));
+		if (declaration?.symbolKind !== undefined) {
+			(declaration.annotations ??= []).push(node);
+		}
+	}
+}
+
+/**
+ * @returns whether `node` ends with a token that tree-sitter inserted at the end of the source to recover, e.g., the `MISSING "}"` of a function
+ * of a truncated file, as opposed to one missing in the middle of the source, after which the source goes on
+ */
+function isCutOff(node: SyntaxNode, source: string): boolean {
+	if (!node.hasError) {
+		return false;
+	}
+	let last = node.lastChild;
+	while (last !== null && !last.isMissing) {
+		last = last.lastChild;
+	}
+	return last !== null && source.substring(last.endIndex).trim() === '';
+}
@@ -0,0 +1,178 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { QueryCapture, SyntaxNode } from 'web-tree-sitter';
+import { cppStructure } from './cppStructure';
+import { csharpStructure } from './csharpStructure';
+import { cStructure } from './cStructure';
+import { dartStructure } from './dartStructure';
+import { goStructure } from './goStructure';
+import { graphqlStructure } from './graphqlStructure';
+import { hclStructure } from './hclStructure';
+import { iniStructure } from './iniStructure';
+import { javaStructure } from './javaStructure';
+import { jsonStructure } from './jsonStructure';
+import { jsStructure, tsxStructure } from './jsStructure';
+import { kotlinStructure } from './kotlinStructure';
+import { luaStructure } from './luaStructure';
+import { OverlayNode } from './nodes';
+import { objcStructure } from './objcStructure';
+import { phpStructure } from './phpStructure';
+import { protobufStructure } from './protobufStructure';
+import { pythonStructure } from './pythonStructure';
+import { rStructure } from './rStructure';
+import { rubyStructure } from './rubyStructure';
+import { rustStructure } from './rustStructure';
+import { scalaStructure } from './scalaStructure';
+import { shellStructure } from './shellStructure';
+import { sqlStructure } from './sqlStructure';
+import type { StructureOptions } from './structure';
+import { swiftStructure } from './swiftStructure';
+import { tomlStructure } from './tomlStructure';
+import { WASMLanguage } from './treeSitterLanguages';
+import { isDocumentableNode, leadingCommentOf } from './util';
+import { yamlStructure } from './yamlStructure';
+
+/**
+ * Language-specific steps of computing a structure, see `StructureComputer`; each of them is optional.
+ */
+export interface StructureLanguage {
+	/** Populates details of `overlayNode` computed from `syntaxNode`, e.g., its name and declaration header */
+	describe?(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void;
+	/** Populates details of the root of a structure, e.g., the package of a Go source */
+	describeRoot?(root: OverlayNode, rootSyntaxNode: SyntaxNode, source: string): void;
+	/** @returns whether `capture` doesn't get a node of its own */
+	skipsCapture?(capture: QueryCapture, options: StructureOptions | undefined): boolean;
+	/** @returns kind of the node of `capture` if it's more specific than `nodeKind` */
+	nodeKindOf?(capture: QueryCapture, nodeKind: string, options: StructureOptions | undefined): string | undefined;
+	/** @returns first and last syntax node the node of `capture` spans, e.g., with the decorators before it */
+	extentOf?(capture: QueryCapture): [first: SyntaxNode, last: SyntaxNode];
+	/** @returns end of the node of `syntaxNode` if it encloses what follows it, e.g., a namespace without braces */
+	endIndexOf?(syntaxNode: SyntaxNode, nodeKind: string, source: string): number | undefined;
+	/** @returns {@link OverlayNode.keyPath} of `overlayNode` given the one of its parent */
+	keyPathOf?(syntaxNode: SyntaxNode, overlayNode: OverlayNode, parentPath: readonly string[] | undefined): string[] | undefined;
+	/** Called for each node that's added to the structure, including reused ones, with its ancestors from the outermost one */
+	attach?(node: OverlayNode, syntaxNode: SyntaxNode, ancestors: readonly OverlayNode[]): void;
+	/** @returns linker of the declarations of a structure that's being computed */
+	createLinker?(): DeclarationLinker;
+}
+
+/**
+ * Links the declarations of a structure to each other once all of its nodes are computed, e.g., Go methods to their types.
+ */
+export interface DeclarationLinker {
+	/** Called for each node of the structure in source order */
+	register?(node: OverlayNode, syntaxNode: SyntaxNode): void;
+	link(root: OverlayNode, options: StructureOptions | undefined): void;
+}
+
+export const structureLanguages: { readonly [language in WASMLanguage]?: StructureLanguage } = {
+	[WASMLanguage.TypeScript]: jsStructure,
+	[WASMLanguage.TypeScriptTsx]: tsxStructure,
+	[WASMLanguage.JavaScript]: jsStructure,
+	[WASMLanguage.Go]: goStructure,
+	[WASMLanguage.Swift]: swiftStructure,
+	[WASMLanguage.Kotlin]: kotlinStructure,
+	[WASMLanguage.Shell]: shellStructure,
+	[WASMLanguage.Csharp]: csharpStructure,
+	[WASMLanguage.Java]: javaStructure,
+	[WASMLanguage.Rust]: rustStructure,
+	[WASMLanguage.Python]: pythonStructure,
+	[WASMLanguage.Ruby]: rubyStructure,
+	[WASMLanguage.Dart]: dartStructure,
+	[WASMLanguage.Json]: jsonStructure,
+	[WASMLanguage.Yaml]: yamlStructure,
+	[WASMLanguage.Php]: phpStructure,
+	[WASMLanguage.Lua]: luaStructure,
+	[WASMLanguage.C]: cStructure,
+	[WASMLanguage.Cpp]: cppStructure,
+	[WASMLanguage.Hcl]: hclStructure,
+	[WASMLanguage.Sql]: sqlStructure,
+	[WASMLanguage.Graphql]: graphqlStructure,
+	[WASMLanguage.Toml]: tomlStructure,
+	[WASMLanguage.Scala]: scalaStructure,
+	[WASMLanguage.R]: rStructure,
+	[WASMLanguage.ObjectiveC]: objcStructure,
+	[WASMLanguage.Protobuf]: protobufStructure,
+	[WASMLanguage.Ini]: iniStructure,
+};
+
+/**
+ * Populates language-specific details, e.g., name and declaration header, of `overlayNode` computed from `syntaxNode`.
+ */
+export function describeOverlayNode(lang: WASMLanguage, syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	structureLanguages[lang]?.describe?.(syntaxNode, overlayNode, source);
+	// top-level and member declarations, e.g., a method with a `/** ... */` comment above it
+	if (overlayNode.leadingComment === undefined && isDocumentableNode(syntaxNode, lang)) {
+		overlayNode.leadingComment = leadingCommentOf(syntaxNode, source);
+	}
+	if (overlayNode.detail !== undefined) {
+		overlayNode.detail = normalizeWhitespace(overlayNode.detail);
+	}
+}
+
+/**
+ * String literals of most languages in a capturing group, i.e., splitting by it yields them at odd indices
+ */
+const stringLiteralPattern = /("(?:[^"\\]|\\.)*"|'(?:[^'\\\n]|\\.)*'|`[^`]*`)/;
+
+/**
+ * @returns `detail` on a single line with runs of whitespace collapsed and without the trailing commas of parameter lists broken across lines;
+ * string literals are kept as they are
+ */
+function normalizeWhitespace(detail: string): string {
+	return detail.split(stringLiteralPattern).map((part, i) => i % 2 === 1
+		? part // a string literal, see the capturing group
+		: part
+			.replace(/,\s*\n\s*([)\]])/g, '$1') // e.g., `,\n)` but not `(1,)`
+			.replace(/\s+/g, ' ')
+			.replace(/([([]) /g, '$1')
+			.replace(/ ([)\]])/g, '$1')
+	).join('').trim();
+}
+
+/**
+ * Languages whose comments are `// ...` and `/* ... *\/`; in others, `//` may be an operator, e.g., python's floor division
+ */
+const cStyleCommentLanguages = new Set([
+	WASMLanguage.TypeScript, WASMLanguage.TypeScriptTsx, WASMLanguage.JavaScript, WASMLanguage.Go, WASMLanguage.C, WASMLanguage.Cpp, WASMLanguage.Csharp,
+	WASMLanguage.Java, WASMLanguage.Rust, WASMLanguage.Swift, WASMLanguage.Kotlin, WASMLanguage.Dart, WASMLanguage.Php, WASMLanguage.Scala,
+	WASMLanguage.ObjectiveC, WASMLanguage.Protobuf,
+]);
+
+/**
+ * Normalizes the texts of the declaration header, type parameters, and parameter, result, and declared types of `overlayNode`,
+ * see `StructureOptions.normalizeTypes`.
+ */
+export function normalizeTypeTexts(lang: WASMLanguage, overlayNode: OverlayNode): void {
+	const stripComments = cStyleCommentLanguages.has(lang);
+	const normalize = (text: string) => normalizeTypeText(text, stripComments);
+	if (overlayNode.detail !== undefined) {
+		overlayNode.detail = normalize(overlayNode.detail);
+	}
+	if (overlayNode.typeParameters !== undefined) {
+		overlayNode.typeParameters = normalize(overlayNode.typeParameters);
+	}
+	if (overlayNode.type !== undefined) {
+		overlayNode.type = normalize(overlayNode.type);
+	}
+	overlayNode.parameters = overlayNode.parameters?.map(p => ({ ...p, type: normalize(p.type) }));
+	overlayNode.results = overlayNode.results?.map(r => ({ ...r, type: normalize(r.type) }));
+}
+
+/**
+ * @returns `text` as {@link normalizeWhitespace} does, without comments if `stripComments`, and with a single space after each comma
+ */
+function normalizeTypeText(text: string, stripComments: boolean): string {
+	const withoutComments = stripComments
+		? text.split(stringLiteralPattern).map((part, i) => i % 2 === 1 ? part : part.replace(/\/\*[\s\S]*?\*\/|\/\/[^\n]*/g, ' ')).join('')
+		: text;
+	return normalizeWhitespace(withoutComments).split(stringLiteralPattern).map((part, i) => i % 2 === 1
+		? part
+		: part
+			.replace(/ ?, ?/g, ', ')
+			.replace(/, ([)\]])/g, ',$1') // e.g., `(1,)`
+	).join('').trim();
+}
@@ -0,0 +1,66 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { OverlayNode, TreeSitterOffsetRange } from './nodes';
+import { SymbolKind } from './symbolKinds';
+
+/** Maximum number of members of a struct or interface that are listed in its summary; bodies of larger ones are elided */
+const maxSummaryMembers = 5;
+
+export interface SignatureSummaryOptions {
+	/**
+	 * Maximum number of members of a struct or interface that are listed in its summary, e.g., to fit a prompt budget for a language
+	 * whose types have hundreds of fields; the members of a larger body are listed up to it, one per line, followed by a `// ... (K more)` marker.
+	 * Unlimited by default, i.e., bodies with few members are listed and larger ones are elided.
+	 */
+	maxMembers?: number;
+}
+
+/**
+ * Summarizes a declaration of the structure of `text` in a single line without its implementation, e.g., for prompts:
+ * its {@link OverlayNode.detail}, i.e., its header, and for a struct or interface with a body in braces, its members if there are few of them,
+ * e.g., `type StructExample struct { Name string }`; bodies with more members, or members that span lines, are elided as `{ ... }`,
+ * unless {@link SignatureSummaryOptions.maxMembers} lists the first members of larger bodies.
+ *
+ * @param text the source whose structure `node` belongs to
+ * @param options e.g., `{ maxMembers: 3 }` to list the first members of large bodies instead of eliding them
+ * @returns `undefined` if `node` has no header, e.g., a statement
+ * @example `func main()` or `func (s StructExample) MethodExample() error`
+ */
+export function getSignatureSummary(node: OverlayNode, text: string, options?: SignatureSummaryOptions): string | undefined {
+	if (node.detail === undefined) {
+		return undefined;
+	}
+	// e.g., a parameter list that spans lines, whose trailing comma is dropped
+	const header = node.detail.replace(/\(\s*\n\s*/g, '(').replace(/,?\s*\n\s*\)/g, ')').replace(/\s*\n\s*/g, ' ');
+	if (node.symbolKind !== SymbolKind.Struct && node.symbolKind !== SymbolKind.Interface) {
+		return header;
+	}
+	const headerStart = text.indexOf(node.detail, node.startIndex);
+	const brace = /\s*\{/y;
+	brace.lastIndex = headerStart + node.detail.length;
+	if (headerStart === -1 || !brace.test(text)) {
+		// e.g., a Python class, which has no braces
+		return header;
+	}
+	// Go methods nested among the members of their receiver types aren't within their ranges
+	const members = node.children.filter(c => c.kind !== 'comment' && TreeSitterOffsetRange.doesContain(node, c)).map(c => c.detail ?? c.name);
+	if (members.length === 0) {
+		return `${header} {}`;
+	}
+	if (members.some(m => m === undefined || m.includes('\n'))) {
+		return `${header} { ... }`;
+	}
+	const listed = members.map(m => m!.replace(/[;,]\s*$/, ''));
+	if (options?.maxMembers !== undefined && listed.length > options.maxMembers) {
+		// on lines of their own, so that the marker doesn't comment out the closing brace
+		const more = `// ... (${listed.length - options.maxMembers} more)`;
+		return `${header} {\n${[...listed.slice(0, options.maxMembers), more].map(m => `\t${m}\n`).join('')}}`;
+	}
+	if (options?.maxMembers === undefined && listed.length > maxSummaryMembers) {
+		return `${header} { ... }`;
+	}
+	return `${header} { ${listed.join('; ')} }`;
+}
@@ -0,0 +1,123 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { OverlayNode } from './nodes';
+
+/**
+ * What a {@link StructureVisitor} can return to control {@link walk}; returning nothing continues with the children of the visited node.
+ */
+export enum WalkSignal {
+	/** Don't visit the descendants of the visited node but continue with its next sibling */
+	Skip = 'skip',
+	/** Don't visit any more nodes */
+	Stop = 'stop',
+}
+
+/**
+ * @param parent parent of `node`; `undefined` for the node the walk started at
+ */
+export type StructureVisitor = (node: OverlayNode, parent: OverlayNode | undefined) => WalkSignal | void;
+
+/**
+ * Visits `node` and its descendants in document order (pre-order), i.e., a node before its children, e.g., a type declaration before its fields.
+ * Nodes that aren't children, e.g., those {@link OverlayNode.methods} refers to, are only visited where they're children.
+ *
+ * @returns whether all nodes were visited, i.e., `false` if `visitor` returned {@link WalkSignal.Stop}
+ */
+export function walk(node: OverlayNode, visitor: StructureVisitor): boolean {
+	return visit(node, undefined, visitor);
+}
+
+function visit(node: OverlayNode, parent: OverlayNode | undefined, visitor: StructureVisitor): boolean {
+	const signal = visitor(node, parent);
+	if (signal === WalkSignal.Stop) {
+		return false;
+	}
+	if (signal !== WalkSignal.Skip) {
+		for (const child of node.children) {
+			if (!visit(child, node, visitor)) {
+				return false;
+			}
+		}
+	}
+	return true;
+}
+
+/**
+ * @returns first of `node` and its descendants in document order that matches `predicate`, see {@link walk}; `undefined` if none matches
+ */
+export function findFirst(node: OverlayNode, predicate: (node: OverlayNode) => boolean): OverlayNode | undefined {
+	let found: OverlayNode | undefined;
+	walk(node, n => {
+		if (predicate(n)) {
+			found = n;
+			return WalkSignal.Stop;
+		}
+	});
+	return found;
+}
+
+/**
+ * @returns those of `node` and its descendants that match `predicate` in document order, see {@link walk}
+ */
+export function collect(node: OverlayNode, predicate: (node: OverlayNode) => boolean): OverlayNode[] {
+	const collected: OverlayNode[] = [];
+	walk(node, n => {
+		if (predicate(n)) {
+			collected.push(n);
+		}
+	});
+	return collected;
+}
+
+/**
+ * Visits `node` and its descendants like {@link walk} but descends only into containers, i.e., `node` and the nodes of the kinds in `containerKinds`,
+ * so that, e.g., a function is visited with its statements but a loop among them without the statements of its body.
+ *
+ * @param containerKinds e.g., `chunkContainerKinds[lang]`, which are the nodes `_getSemanticChunks` splits at their children
+ */
+export function walkContainers(node: OverlayNode, containerKinds: readonly string[], visitor: StructureVisitor): boolean {
+	return walk(node, (n, parent) => {
+		const signal = visitor(n, parent);
+		if (signal !== undefined) {
+			return signal;
+		}
+		return n === node || containerKinds.includes(n.kind) ? undefined : WalkSignal.Skip;
+	});
+}
+
+/**
+ * Entry of a flattened structure, see {@link flattenStructure}.
+ */
+export interface FlatStructureEntry {
+	/** Number of ancestors among the flattened nodes, i.e., `0` for the given nodes */
+	readonly depth: number;
+	readonly kind: string;
+	/** {@link OverlayNode.name}, or {@link OverlayNode.label} of an anonymous declaration */
+	readonly name: string | undefined;
+	/** {@link OverlayNode.detail}, i.e., the declaration without its body */
+	readonly signature: string | undefined;
+	readonly range: OverlayNode['range'];
+}
+
+/**
+ * Flattens `nodes` and their descendants in document order, see {@link walk}, e.g., to render an indented outline like ctags
+ * or to embed the skeleton of a source into a prompt.
+ * Go methods nested with `StructureOptions.nestGoMethods` have the depths of members of their receiver types.
+ *
+ * @param nodes e.g., the children of the root
+ */
+export function flattenStructure(nodes: readonly OverlayNode[]): FlatStructureEntry[] {
+	const entries: FlatStructureEntry[] = [];
+	const depths = new Map<OverlayNode, number>();
+	for (const node of nodes) {
+		walk(node, (n, parent) => {
+			const depth = parent ? depths.get(parent)! + 1 : 0;
+			depths.set(n, depth);
+			entries.push({ depth, kind: n.kind, name: n.name ?? n.label, signature: n.detail, range: n.range });
+		});
+	}
+	return entries;
+}
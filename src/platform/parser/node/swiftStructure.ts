@@ -0,0 +1,51 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { textUpTo } from './util';
+
+export const swiftStructure: StructureLanguage = {
+	describe: describeSwiftNode,
+	nodeKindOf: (capture, nodeKind) => {
+		// kind `class_declaration` is used for classes, structs, enums, actors, and extensions -> kind `struct_declaration`, `extension_declaration`, etc
+		if (nodeKind === 'class_declaration') {
+			const declarationKind = capture.node.childForFieldName('declaration_kind')?.text;
+			return declarationKind ? `${declarationKind}_declaration` : undefined;
+		}
+		// kind `property_declaration` with a getter, e.g., `var area: Double { width * height }` -> kind `computed_property_declaration`
+		if (nodeKind === 'property_declaration' && capture.node.namedChildren.some(c => c.type === 'computed_property')) {
+			return 'computed_property_declaration';
+		}
+		return undefined;
+	},
+};
+
+function describeSwiftNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode, source: string): void {
+	switch (syntaxNode.type) {
+		case 'class_declaration': // also structs, enums, actors, and extensions; for an extension, the name is the extended type
+		case 'protocol_declaration':
+		case 'function_declaration': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.childForFieldName('body'), source);
+			break;
+		}
+		case 'init_declaration': {
+			overlayNode.name = 'init';
+			overlayNode.detail = textUpTo(syntaxNode, syntaxNode.childForFieldName('body'), source);
+			break;
+		}
+		case 'property_declaration': {
+			overlayNode.name = syntaxNode.childForFieldName('name')?.text;
+			// computed properties, e.g., `var area: Double`
+			const computedProperty = syntaxNode.namedChildren.find(c => c.type === 'computed_property');
+			if (computedProperty) {
+				overlayNode.detail = textUpTo(syntaxNode, computedProperty, source);
+			}
+			break;
+		}
+	}
+}
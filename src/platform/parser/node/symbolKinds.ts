@@ -0,0 +1,453 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { isCFunctionDeclaration } from './cStructure';
+import { luaFunctionValueOf } from './luaStructure';
+import { WASMLanguage } from './treeSitterLanguages';
+import { enclosingDeclarationTypes } from './treeSitterQueries';
+
+/**
+ * Language-agnostic kinds of declared symbols modeled after LSP's `SymbolKind`, e.g., to create `vscode.DocumentSymbol`s.
+ */
+export enum SymbolKind {
+	Function = 'function',
+	Method = 'method',
+	Constructor = 'constructor',
+	Class = 'class',
+	Struct = 'struct',
+	Interface = 'interface',
+	Enum = 'enum',
+	Field = 'field',
+	Constant = 'constant',
+	Variable = 'variable',
+	Namespace = 'namespace',
+	Package = 'package',
+	Key = 'key',
+}
+
+const jsSymbolKinds: { [nodeKind: string]: SymbolKind } = {
+	function_declaration: SymbolKind.Function,
+	generator_function_declaration: SymbolKind.Function,
+	arrow_function: SymbolKind.Function,
+	function_expression: SymbolKind.Function,
+	method_definition: SymbolKind.Method,
+	constructor: SymbolKind.Constructor,
+	class_declaration: SymbolKind.Class,
+	field_definition: SymbolKind.Field,
+	public_field_definition: SymbolKind.Field,
+	lexical_declaration: SymbolKind.Variable,
+	variable_declaration: SymbolKind.Variable,
+};
+
+const tsSymbolKinds: { [nodeKind: string]: SymbolKind } = {
+	...jsSymbolKinds,
+	abstract_class_declaration: SymbolKind.Class,
+	interface_declaration: SymbolKind.Interface,
+	enum_declaration: SymbolKind.Enum,
+	enum_assignment: SymbolKind.Constant,
+	enum_member: SymbolKind.Constant,
+	method_signature: SymbolKind.Method,
+	abstract_method_signature: SymbolKind.Method,
+	property_signature: SymbolKind.Field,
+	parameter_property: SymbolKind.Field,
+	internal_module: SymbolKind.Namespace,
+	module: SymbolKind.Namespace,
+};
+
+const cSymbolKinds: { [nodeKind: string]: SymbolKind } = {
+	function_definition: SymbolKind.Function,
+	struct_specifier: SymbolKind.Struct,
+	union_specifier: SymbolKind.Struct,
+	enum_specifier: SymbolKind.Enum,
+	enum_member: SymbolKind.Constant,
+	type_definition: SymbolKind.Class,
+	preproc_def: SymbolKind.Constant,
+	preproc_function_def: SymbolKind.Function,
+};
+
+/**
+ * Symbol kinds of structure nodes by their kinds, see `OverlayNode.kind`; nodes of other kinds don't declare symbols.
+ *
+ * The symbol kinds are refined by {@link symbolKindOf}:
+ * - functions declared within a type are methods, e.g., Python's `def` within a `class`
+ * - variables declared within a type are fields, e.g., Kotlin's `val` within a `class`
+ * - variables that can't be reassigned are constants, e.g., `const` in JS/TS, `static final` fields in Java, and `readonly` in shell scripts
+ * - go: a type declaration is a struct or an interface depending on its type, or a class otherwise
+ * - hcl: a block is a variable, namespace, or field depending on its type, e.g., `variable "region"`, `module "vpc"`, or `lifecycle` within a `resource`,
+ *   or a class otherwise, e.g., `resource "aws_instance" "web"`
+ * - c/c++: a `typedef` is a struct or an enum depending on its type, or a class otherwise, e.g., `typedef unsigned long size_t;`
+ * - c/c++: a declaration of a function without its body, e.g., a prototype, is a function like its definition
+ * - python: a decorated definition is a function, method, or class depending on the definition
+ * - python: a module-level assignment, e.g., `MAX_RETRIES = 3`, is a variable
+ */
+export const symbolKindsByNodeKind: { [wasmLanguage in WASMLanguage]: { [nodeKind: string]: SymbolKind } } = {
+	[WASMLanguage.JavaScript]: jsSymbolKinds,
+	[WASMLanguage.TypeScript]: tsSymbolKinds,
+	[WASMLanguage.TypeScriptTsx]: tsSymbolKinds,
+	[WASMLanguage.Python]: {
+		function_definition: SymbolKind.Function,
+		class_definition: SymbolKind.Class,
+	},
+	[WASMLanguage.Go]: {
+		function_declaration: SymbolKind.Function,
+		method_declaration: SymbolKind.Method,
+		method_spec: SymbolKind.Method,
+		type_declaration: SymbolKind.Class,
+		field_declaration: SymbolKind.Field,
+		embedded_field: SymbolKind.Field,
+		func_literal: SymbolKind.Function,
+		anonymous_struct: SymbolKind.Struct,
+		const_declaration: SymbolKind.Constant,
+		const_spec: SymbolKind.Constant,
+		var_declaration: SymbolKind.Variable,
+		var_spec: SymbolKind.Variable,
+		package_clause: SymbolKind.Package,
+	},
+	[WASMLanguage.Ruby]: {
+		method: SymbolKind.Method,
+		singleton_method: SymbolKind.Method,
+		class: SymbolKind.Class,
+		module: SymbolKind.Namespace,
+		attribute_declaration: SymbolKind.Field,
+	},
+	[WASMLanguage.Csharp]: {
+		class_declaration: SymbolKind.Class,
+		record_declaration: SymbolKind.Class,
+		struct_declaration: SymbolKind.Struct,
+		interface_declaration: SymbolKind.Interface,
+		enum_declaration: SymbolKind.Enum,
+		enum_member: SymbolKind.Constant,
+		method_declaration: SymbolKind.Method,
+		constructor_declaration: SymbolKind.Constructor,
+		field_declaration: SymbolKind.Field,
+		property_declaration: SymbolKind.Field,
+		namespace_declaration: SymbolKind.Namespace,
+		file_scoped_namespace_declaration: SymbolKind.Namespace,
+	},
+	[WASMLanguage.C]: cSymbolKinds,
+	[WASMLanguage.Cpp]: {
+		...cSymbolKinds,
+		class_specifier: SymbolKind.Class,
+		namespace_definition: SymbolKind.Namespace,
+	},
+	[WASMLanguage.Java]: {
+		class_declaration: SymbolKind.Class,
+		record_declaration: SymbolKind.Class,
+		interface_declaration: SymbolKind.Interface,
+		annotation_type_declaration: SymbolKind.Interface,
+		enum_declaration: SymbolKind.Enum,
+		enum_constant: SymbolKind.Constant,
+		method_declaration: SymbolKind.Method,
+		constructor_declaration: SymbolKind.Constructor,
+		field_declaration: SymbolKind.Field,
+		package_declaration: SymbolKind.Package,
+	},
+	[WASMLanguage.Rust]: {
+		function_item: SymbolKind.Function,
+		function_signature_item: SymbolKind.Function,
+		method_item: SymbolKind.Method,
+		associated_function_item: SymbolKind.Function,
+		struct_item: SymbolKind.Struct,
+		union_item: SymbolKind.Struct,
+		field_declaration: SymbolKind.Field,
+		enum_item: SymbolKind.Enum,
+		trait_item: SymbolKind.Interface,
+		const_item: SymbolKind.Constant,
+		static_item: SymbolKind.Variable,
+		let_declaration: SymbolKind.Variable,
+		mod_item: SymbolKind.Namespace,
+	},
+	[WASMLanguage.Swift]: {
+		class_declaration: SymbolKind.Class,
+		actor_declaration: SymbolKind.Class,
+		struct_declaration: SymbolKind.Struct,
+		enum_declaration: SymbolKind.Enum,
+		protocol_declaration: SymbolKind.Interface,
+		function_declaration: SymbolKind.Function,
+		protocol_function_declaration: SymbolKind.Method,
+		init_declaration: SymbolKind.Constructor,
+		property_declaration: SymbolKind.Variable,
+		computed_property_declaration: SymbolKind.Variable,
+		protocol_property_declaration: SymbolKind.Field,
+	},
+	[WASMLanguage.Kotlin]: {
+		class_declaration: SymbolKind.Class,
+		object_declaration: SymbolKind.Class,
+		companion_object: SymbolKind.Class,
+		interface_declaration: SymbolKind.Interface,
+		function_declaration: SymbolKind.Function,
+		secondary_constructor: SymbolKind.Constructor,
+		property_declaration: SymbolKind.Variable,
+	},
+	[WASMLanguage.Shell]: {
+		function_definition: SymbolKind.Function,
+		variable_assignment: SymbolKind.Variable,
+		declaration_command: SymbolKind.Variable,
+	},
+	[WASMLanguage.Dart]: {
+		class_definition: SymbolKind.Class,
+		mixin_declaration: SymbolKind.Class,
+		enum_declaration: SymbolKind.Enum,
+		function_signature: SymbolKind.Function,
+		getter_signature: SymbolKind.Function,
+		setter_signature: SymbolKind.Function,
+		operator_signature: SymbolKind.Method,
+		constructor_signature: SymbolKind.Constructor,
+		constant_constructor_signature: SymbolKind.Constructor,
+		factory_constructor_signature: SymbolKind.Constructor,
+		redirecting_factory_constructor_signature: SymbolKind.Constructor,
+	},
+	[WASMLanguage.Json]: {
+		pair: SymbolKind.Key,
+	},
+	[WASMLanguage.Yaml]: {
+		pair: SymbolKind.Key,
+	},
+	[WASMLanguage.Php]: {
+		namespace_definition: SymbolKind.Namespace,
+		class_declaration: SymbolKind.Class,
+		interface_declaration: SymbolKind.Interface,
+		trait_declaration: SymbolKind.Class,
+		enum_declaration: SymbolKind.Enum,
+		function_definition: SymbolKind.Function,
+		method_declaration: SymbolKind.Method,
+		constructor: SymbolKind.Constructor,
+		property_declaration: SymbolKind.Field,
+		const_declaration: SymbolKind.Constant,
+	},
+	[WASMLanguage.Lua]: {
+		function_declaration: SymbolKind.Function,
+		variable_declaration: SymbolKind.Variable,
+		assignment_statement: SymbolKind.Variable,
+		field: SymbolKind.Field,
+	},
+	[WASMLanguage.Hcl]: {
+		block: SymbolKind.Class,
+		attribute: SymbolKind.Variable,
+	},
+	[WASMLanguage.Sql]: {
+		create_table: SymbolKind.Struct,
+		create_view: SymbolKind.Interface,
+		create_materialized_view: SymbolKind.Interface,
+		create_function: SymbolKind.Function,
+		column_definition: SymbolKind.Field,
+	},
+	[WASMLanguage.Graphql]: {
+		object_type_definition: SymbolKind.Class,
+		interface_type_definition: SymbolKind.Interface,
+		input_object_type_definition: SymbolKind.Struct,
+		enum_type_definition: SymbolKind.Enum,
+		union_type_definition: SymbolKind.Interface,
+		scalar_type_definition: SymbolKind.Class,
+		directive_definition: SymbolKind.Function,
+		schema_definition: SymbolKind.Namespace,
+		root_operation_type_definition: SymbolKind.Field,
+		field_definition: SymbolKind.Field,
+		input_value_definition: SymbolKind.Field,
+		enum_value_definition: SymbolKind.Constant,
+		operation_definition: SymbolKind.Function,
+		fragment_definition: SymbolKind.Function,
+	},
+	[WASMLanguage.Toml]: {
+		table: SymbolKind.Key,
+		table_array_element: SymbolKind.Key,
+		pair: SymbolKind.Key,
+	},
+	[WASMLanguage.Scala]: {
+		package_clause: SymbolKind.Package,
+		class_definition: SymbolKind.Class,
+		case_class_definition: SymbolKind.Class,
+		object_definition: SymbolKind.Class,
+		case_object_definition: SymbolKind.Class,
+		trait_definition: SymbolKind.Interface,
+		function_definition: SymbolKind.Function,
+		function_declaration: SymbolKind.Function,
+		val_definition: SymbolKind.Variable,
+		val_declaration: SymbolKind.Variable,
+		var_definition: SymbolKind.Variable,
+		var_declaration: SymbolKind.Variable,
+	},
+	[WASMLanguage.R]: {
+		function_definition: SymbolKind.Function,
+		assignment: SymbolKind.Variable,
+		class_definition: SymbolKind.Class,
+		generic_definition: SymbolKind.Function,
+		method_definition: SymbolKind.Method,
+	},
+	[WASMLanguage.ObjectiveC]: {
+		...cSymbolKinds,
+		class_interface: SymbolKind.Class,
+		class_implementation: SymbolKind.Class,
+		protocol_declaration: SymbolKind.Interface,
+		method_declaration: SymbolKind.Method,
+		method_definition: SymbolKind.Method,
+		property_declaration: SymbolKind.Field,
+	},
+	[WASMLanguage.Protobuf]: {
+		package: SymbolKind.Package,
+		message: SymbolKind.Struct,
+		enum: SymbolKind.Enum,
+		service: SymbolKind.Interface,
+		rpc: SymbolKind.Method,
+		field: SymbolKind.Field,
+		map_field: SymbolKind.Field,
+		oneof: SymbolKind.Field,
+		oneof_field: SymbolKind.Field,
+		enum_field: SymbolKind.Constant,
+	},
+	[WASMLanguage.Ini]: {
+		section: SymbolKind.Key,
+		pair: SymbolKind.Key,
+	},
+};
+
+/**
+ * @param nodeKind kind of the structure node created for `syntaxNode`, which may be more specific than its type, e.g., `constructor`
+ * @returns symbol kind of the structure node, see {@link symbolKindsByNodeKind}; `undefined` if it doesn't declare a symbol
+ */
+export function symbolKindOf(lang: WASMLanguage, nodeKind: string, syntaxNode: SyntaxNode): SymbolKind | undefined {
+	if (lang === WASMLanguage.Python && nodeKind === 'decorated_definition') {
+		const definition = syntaxNode.childForFieldName('definition');
+		return definition ? symbolKindOf(lang, definition.type, definition) : undefined;
+	}
+	if (lang === WASMLanguage.Python && nodeKind === 'expression_statement') {
+		return syntaxNode.parent?.type === 'module' && syntaxNode.namedChildren[0]?.type === 'assignment' ? SymbolKind.Variable : undefined;
+	}
+	if (lang === WASMLanguage.Go && nodeKind === 'type_declaration') {
+		return goTypeSymbolKind(syntaxNode);
+	}
+	if ((lang === WASMLanguage.C || lang === WASMLanguage.Cpp || lang === WASMLanguage.ObjectiveC) && nodeKind === 'type_definition') {
+		return cTypedefSymbolKind(syntaxNode);
+	}
+	if ((lang === WASMLanguage.C || lang === WASMLanguage.Cpp || lang === WASMLanguage.ObjectiveC) && nodeKind === 'declaration') {
+		// e.g., the prototype `int add(int a, int b);`, but not a variable
+		return isCFunctionDeclaration(syntaxNode) ? symbolKindOf(lang, 'function_definition', syntaxNode) : undefined;
+	}
+	if (lang === WASMLanguage.Hcl && nodeKind === 'block') {
+		return hclBlockSymbolKind(syntaxNode);
+	}
+	if (lang === WASMLanguage.Hcl && nodeKind === 'attribute') {
+		return hclAttributeSymbolKind(syntaxNode);
+	}
+	if (lang === WASMLanguage.Lua && nodeKind === 'function_declaration') {
+		// e.g., `function Account:deposit(v)`, which takes `self` implicitly
+		return syntaxNode.childForFieldName('name')?.type === 'method_index_expression' ? SymbolKind.Method : SymbolKind.Function;
+	}
+	if (lang === WASMLanguage.Lua && luaFunctionValueOf(syntaxNode)) {
+		// e.g., `M.greet = function(name) ... end` or `{ on_save = function(buf) ... end }`
+		return nodeKind === 'field' ? SymbolKind.Method : SymbolKind.Function;
+	}
+	// languages registered at runtime, see `_registerLanguage`, have no symbol kinds
+	const symbolKind = symbolKindsByNodeKind[lang]?.[nodeKind];
+	switch (symbolKind) {
+		case SymbolKind.Function:
+			return isMember(lang, syntaxNode) ? SymbolKind.Method : symbolKind;
+		case SymbolKind.Variable:
+			return isMember(lang, syntaxNode) ? SymbolKind.Field : isConstant(lang, syntaxNode) ? SymbolKind.Constant : symbolKind;
+		case SymbolKind.Field:
+			return isConstant(lang, syntaxNode) ? SymbolKind.Constant : symbolKind;
+		default:
+			return symbolKind;
+	}
+}
+
+/**
+ * @returns `true` if the nearest function or type declaration enclosing `syntaxNode` is a type, e.g., a class
+ */
+function isMember(lang: WASMLanguage, syntaxNode: SyntaxNode): boolean {
+	const types = enclosingDeclarationTypes[lang];
+	for (let node = syntaxNode.parent; node !== null; node = node.parent) {
+		if (types.function.includes(node.type)) {
+			return false;
+		}
+		if (types.type.includes(node.type)) {
+			return true;
+		}
+	}
+	return false;
+}
+
+function isConstant(lang: WASMLanguage, syntaxNode: SyntaxNode): boolean {
+	switch (lang) {
+		case WASMLanguage.JavaScript:
+		case WASMLanguage.TypeScript:
+		case WASMLanguage.TypeScriptTsx:
+			return syntaxNode.type === 'lexical_declaration' && syntaxNode.firstChild?.type === 'const';
+		case WASMLanguage.Java: {
+			const modifiers = syntaxNode.namedChildren.find(c => c.type === 'modifiers')?.children.map(c => c.type) ?? [];
+			return modifiers.includes('static') && modifiers.includes('final');
+		}
+		case WASMLanguage.Shell:
+			return syntaxNode.type === 'declaration_command' && syntaxNode.firstChild?.type === 'readonly';
+		case WASMLanguage.Scala:
+			return syntaxNode.type === 'val_definition' || syntaxNode.type === 'val_declaration';
+		default:
+			return false;
+	}
+}
+
+/**
+ * @returns `Struct` for `type Foo struct { ... }`, `Interface` for `type Foo interface { ... }`, and `Class` for other types, e.g., `type Celsius float64`;
+ * `undefined` for a group of type declarations
+ */
+function goTypeSymbolKind(typeDeclaration: SyntaxNode): SymbolKind | undefined {
+	const specs = typeDeclaration.namedChildren.filter(c => c.type === 'type_spec' || c.type === 'type_alias');
+	if (specs.length !== 1) {
+		return undefined;
+	}
+	switch (specs[0].childForFieldName('type')?.type) {
+		case 'struct_type':
+			return SymbolKind.Struct;
+		case 'interface_type':
+			return SymbolKind.Interface;
+		default:
+			return SymbolKind.Class;
+	}
+}
+
+/**
+ * @returns `Struct` for `typedef struct { ... } point_t;` or a union, `Enum` for `typedef enum { ... } color_t;`, and `Class` for other types,
+ * e.g., `typedef unsigned long size_t;`
+ */
+function cTypedefSymbolKind(typeDefinition: SyntaxNode): SymbolKind {
+	switch (typeDefinition.childForFieldName('type')?.type) {
+		case 'struct_specifier':
+		case 'union_specifier':
+			return SymbolKind.Struct;
+		case 'enum_specifier':
+			return SymbolKind.Enum;
+		default:
+			return SymbolKind.Class;
+	}
+}
+
+/**
+ * @returns `Variable` for `variable` and `output` blocks, `Namespace` for `module` blocks, `Field` for blocks within blocks, e.g., `lifecycle`,
+ * and `Class` for other blocks, e.g., `resource` and `provider` blocks
+ */
+function hclBlockSymbolKind(block: SyntaxNode): SymbolKind {
+	if (block.parent?.parent?.type === 'block') { // a block's `body`
+		return SymbolKind.Field;
+	}
+	switch (block.namedChildren[0]?.text) {
+		case 'variable':
+		case 'output':
+			return SymbolKind.Variable;
+		case 'module':
+			return SymbolKind.Namespace;
+		default:
+			return SymbolKind.Class;
+	}
+}
+
+/**
+ * @returns `Variable` for an attribute of a variables file or a `locals` block, which are values referenced by name, e.g., `local.tags`,
+ * and `Field` for the arguments of other blocks, e.g., `instance_type` of a resource
+ */
+function hclAttributeSymbolKind(attribute: SyntaxNode): SymbolKind {
+	const block = attribute.parent?.parent; // an attribute's `body`
+	return block?.type === 'block' && block.namedChildren[0]?.text !== 'locals' ? SymbolKind.Field : SymbolKind.Variable;
+}
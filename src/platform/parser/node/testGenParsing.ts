@@ -3,14 +3,15 @@
  *  Licensed under the MIT License. See License.txt in the project root for license information.
  *--------------------------------------------------------------------------------------------*/
 
-import type { QueryCapture } from 'web-tree-sitter';
+import type { QueryCapture, SyntaxNode } from 'web-tree-sitter';
 import { uniqueFilter } from '../../../util/vs/base/common/arrays';
 import { assertType } from '../../../util/vs/base/common/types';
+import { goTestRole } from './goStructure';
 import { Node, TreeSitterOffsetRange } from './nodes';
 import { _parse } from './parserWithCaching';
 import { runQueries } from './querying';
 import { WASMLanguage } from './treeSitterLanguages';
-import { testableNodeQueries, testInSuiteQueries } from './treeSitterQueries';
+import { testableNodeQueries, testDeclarationQueries, testInSuiteQueries } from './treeSitterQueries';
 
 export type TestableNode = {
 	identifier: {
@@ -180,3 +181,150 @@ export async function _findLastTest(lang: WASMLanguage, src: string): Promise<Tr
 		treeRef.dispose();
 	}
 }
+
+/**
+ * Test frameworks whose declarations {@link _getTests} recognizes; JS/TS `describe` and `it` calls are attributed to Jest
+ * unless the file imports Mocha or Vitest, since Mocha's BDD interface declares the same globals
+ */
+export type TestFramework = 'go' | 'jest' | 'mocha' | 'vitest' | 'pytest' | 'unittest';
+
+export type TestSymbol = {
+	/**
+	 * e.g., `TestSum`, `adds two numbers` for `it('adds two numbers', ...)`, or `test_add`
+	 */
+	name: string;
+	/**
+	 * names of the suites the test is nested in, outermost first, followed by its own name,
+	 * e.g., `['Calculator', 'add', 'adds two numbers']` or `['CalculatorTest', 'test_add']`
+	 */
+	path: string[];
+	/**
+	 * `suite` for a JS/TS `describe` block or a Python test class, which groups tests
+	 */
+	kind: 'test' | 'benchmark' | 'suite';
+	framework: TestFramework;
+	range: TreeSitterOffsetRange;
+};
+
+/**
+ * Get the tests declared in the source by the conventions of the language's test frameworks, i.e., Go's `TestXxx` and `BenchmarkXxx` functions,
+ * JS/TS `describe`, `it`, and `test` blocks, and Python's `test_*` functions and the test methods of `unittest.TestCase` subclasses and `Test*` classes.
+ *
+ * @returns tests and suites in source order; an empty array if the language has no conventions for tests
+ */
+export async function _getTests(language: WASMLanguage, source: string): Promise<TestSymbol[]> {
+	const queries = testDeclarationQueries[language];
+	if (!queries) {
+		return [];
+	}
+
+	const treeRef = await _parse(language, source);
+
+	try {
+		const declarations = runQueries(queries, treeRef.tree.rootNode).map(({ captures }) => ({
+			node: captures.find(c => c.name === 'test')!.node,
+			name: captures.find(c => c.name === 'name')!.node,
+		}));
+
+		switch (language) {
+			case WASMLanguage.Go:
+				return declarations.flatMap(({ node, name }) => {
+					const role = goTestRole(node);
+					return role === 'test' || role === 'benchmark'
+						? [{ name: name.text, path: [name.text], kind: role, framework: 'go' as const, range: TreeSitterOffsetRange.ofSyntaxNode(node) }]
+						: [];
+				});
+			case WASMLanguage.Python:
+				return pythonTestsOf(declarations);
+			default:
+				return jsTestsOf(declarations, jsTestFrameworkOf(source));
+		}
+	} finally {
+		treeRef.dispose();
+	}
+}
+
+const jsSuiteFunctions = new Set(['describe', 'suite', 'context']);
+
+function jsTestsOf(declarations: { node: SyntaxNode; name: SyntaxNode }[], framework: TestFramework): TestSymbol[] {
+	const tests: TestSymbol[] = [];
+	// suites come before the tests they nest, so the path of a test extends the path of the innermost suite enclosing it
+	const suitePaths = new Map<number, string[]>();
+	for (const { node, name } of declarations.sort((a, b) => a.node.startIndex - b.node.startIndex)) {
+		const fn = node.childForFieldName('function');
+		const fnName = fn?.type === 'member_expression' ? fn.childForFieldName('object')?.text : fn?.text;
+		let parentPath: string[] = [];
+		for (let ancestor = node.parent; ancestor !== null; ancestor = ancestor.parent) {
+			const path = suitePaths.get(ancestor.id);
+			if (path) {
+				parentPath = path;
+				break;
+			}
+		}
+		const path = [...parentPath, jsStringText(name)];
+		const kind = jsSuiteFunctions.has(fnName ?? '') ? 'suite' : 'test';
+		if (kind === 'suite') {
+			suitePaths.set(node.id, path);
+		}
+		tests.push({ name: path[path.length - 1], path, kind, framework, range: TreeSitterOffsetRange.ofSyntaxNode(node) });
+	}
+	return tests;
+}
+
+/**
+ * @returns text of a JS/TS string or template literal without its quotes, e.g., `adds two numbers` for `'adds two numbers'`
+ */
+function jsStringText(literal: SyntaxNode): string {
+	return literal.text.slice(1, -1);
+}
+
+function jsTestFrameworkOf(source: string): TestFramework {
+	if (/from\s+['"]vitest['"]|require\(\s*['"]vitest['"]\s*\)/.test(source)) {
+		return 'vitest';
+	}
+	// mocha's TDD interface declares `suite` and `test` instead
+	if (/from\s+['"]mocha['"]|require\(\s*['"]mocha['"]\s*\)|^\s*suite\(/m.test(source)) {
+		return 'mocha';
+	}
+	return 'jest';
+}
+
+function pythonTestsOf(declarations: { node: SyntaxNode; name: SyntaxNode }[]): TestSymbol[] {
+	const tests: TestSymbol[] = [];
+	const suites = new Map<number, TestSymbol>();
+	for (const { node, name } of declarations) {
+		// e.g., `@pytest.mark.parametrize(...)` above the function are part of it
+		const declaration = node.parent?.type === 'decorated_definition' ? node.parent : node;
+		const container = declaration.parent?.type === 'block' ? declaration.parent.parent : declaration.parent;
+		if (container?.type === 'module') {
+			tests.push({ name: name.text, path: [name.text], kind: 'test', framework: 'pytest', range: TreeSitterOffsetRange.ofSyntaxNode(declaration) });
+			continue;
+		}
+		// nested functions aren't collected, nor are methods of classes that aren't test classes, e.g., helpers of a fixture
+		const framework = container?.type === 'class_definition' ? pythonTestClassFramework(container) : undefined;
+		if (!container || !framework) {
+			continue;
+		}
+		const className = container.childForFieldName('name')?.text ?? '';
+		if (!suites.has(container.id)) {
+			const classDeclaration = container.parent?.type === 'decorated_definition' ? container.parent : container;
+			const suite: TestSymbol = { name: className, path: [className], kind: 'suite', framework, range: TreeSitterOffsetRange.ofSyntaxNode(classDeclaration) };
+			suites.set(container.id, suite);
+			tests.push(suite);
+		}
+		tests.push({ name: name.text, path: [className, name.text], kind: 'test', framework, range: TreeSitterOffsetRange.ofSyntaxNode(declaration) });
+	}
+	return tests.sort((a, b) => TreeSitterOffsetRange.compare(a.range, b.range));
+}
+
+/**
+ * @returns `unittest` for a subclass of `unittest.TestCase` or one of its subclasses, e.g., `IsolatedAsyncioTestCase`,
+ * `pytest` for another class whose name starts with `Test`, or `undefined` if the class doesn't declare tests
+ */
+function pythonTestClassFramework(classDefinition: SyntaxNode): TestFramework | undefined {
+	const superclasses = classDefinition.childForFieldName('superclasses')?.namedChildren ?? [];
+	if (superclasses.some(c => /(^|\.)\w*TestCase$/.test(c.text))) {
+		return 'unittest';
+	}
+	return classDefinition.childForFieldName('name')?.text.startsWith('Test') ? 'pytest' : undefined;
+}
@@ -0,0 +1,106 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { keyPathWith, unquote } from './util';
+
+export const tomlStructure: StructureLanguage = {
+	describe: describeTomlNode,
+	keyPathOf: (syntaxNode, overlayNode, parentPath) => {
+		if (overlayNode.kind === 'table' || overlayNode.kind === 'table_array_element') {
+			// a table is sibling of the tables its key nests in, e.g., `[tool.poetry]` of `[tool]`
+			return tomlTablePathOf(syntaxNode);
+		}
+		if (overlayNode.kind === 'pair') {
+			// e.g., `['server', 'tls', 'cert']` for `tls.cert = "..."` within `[server]`
+			const key = tomlKeyOf(syntaxNode);
+			return parentPath && key && [...parentPath, ...tomlKeysOf(key)];
+		}
+		return keyPathWith(parentPath, overlayNode.name);
+	},
+};
+
+function describeTomlNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode): void {
+	switch (overlayNode.kind) {
+		case 'table':
+		case 'table_array_element':
+		case 'pair': {
+			// a dotted key is named as written, e.g., `tool.poetry` for `[tool.poetry]`, but has a key per part in its `keyPath`
+			const key = tomlKeyOf(syntaxNode);
+			overlayNode.name = key?.type === 'dotted_key' ? key.text : key ? tomlKeysOf(key)[0] : undefined;
+			if (overlayNode.kind === 'pair') {
+				overlayNode.value = tomlValueOf(syntaxNode.namedChildren.filter(c => c.type !== 'comment').at(-1) ?? null);
+			}
+			break;
+		}
+		case 'array_element': {
+			overlayNode.name = `[${syntaxNode.parent!.namedChildren.filter(c => c.type !== 'comment').findIndex(c => c.startIndex === syntaxNode.startIndex)}]`;
+			overlayNode.value = tomlValueOf(syntaxNode);
+			break;
+		}
+	}
+}
+
+/**
+ * @returns path of a table including the indices of the arrays of tables it's in, e.g., `['fruits', '[1]', 'varieties', '[0]']`
+ */
+function tomlTablePathOf(table: SyntaxNode): string[] | undefined {
+	const key = tomlKeyOf(table);
+	if (!key) {
+		return undefined;
+	}
+	const keys = tomlKeysOf(key);
+	const siblings = table.parent!.namedChildren;
+	const index = siblings.findIndex(c => c.startIndex === table.startIndex);
+	const path: string[] = [];
+	let from = 0;
+	for (let i = 0; i < keys.length; i++) {
+		path.push(keys[i]);
+		// the elements of an array of tables a key refers to are those after the element of the enclosing array it's in
+		const prefix = keys.slice(0, i + 1);
+		const to = i === keys.length - 1 && table.type === 'table_array_element' ? index + 1 : index;
+		const elements: number[] = [];
+		for (let j = from; j < to; j++) {
+			const elementKey = siblings[j].type === 'table_array_element' ? tomlKeyOf(siblings[j]) : undefined;
+			const elementKeys = elementKey ? tomlKeysOf(elementKey) : [];
+			if (elementKeys.length === prefix.length && elementKeys.every((k, idx) => k === prefix[idx])) {
+				elements.push(j);
+			}
+		}
+		if (elements.length > 0) {
+			path.push(`[${elements.length - 1}]`);
+			from = elements.at(-1)! + 1;
+		}
+	}
+	return path;
+}
+
+function tomlKeyOf(node: SyntaxNode): SyntaxNode | undefined {
+	return node.namedChildren.find(c => c.type === 'bare_key' || c.type === 'quoted_key' || c.type === 'dotted_key');
+}
+
+/**
+ * @returns keys of a dotted key without quotes, e.g., `['site', 'google.com']` for `site."google.com"`
+ */
+function tomlKeysOf(key: SyntaxNode): string[] {
+	switch (key.type) {
+		case 'dotted_key':
+			return key.namedChildren.filter(c => c.type !== 'comment').flatMap(tomlKeysOf);
+		case 'quoted_key':
+			// a literal string, i.e., `'...'`, has no escapes
+			return [key.text.startsWith(`'`) ? key.text.slice(1, -1) : unquote(key.text)];
+		default:
+			return [key.text];
+	}
+}
+
+/**
+ * @returns text of a value as written if it's neither an array nor an inline table
+ */
+function tomlValueOf(node: SyntaxNode | null): string | undefined {
+	return node && node.type !== 'array' && node.type !== 'inline_table' ? node.text : undefined;
+}
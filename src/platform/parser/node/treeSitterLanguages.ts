@@ -14,9 +14,27 @@ export enum WASMLanguage {
 	Go = 'go',
 	Ruby = 'ruby',
 	Csharp = 'csharp',
+	C = 'c',
 	Cpp = 'cpp',
 	Java = 'java',
 	Rust = 'rust',
+	Swift = 'swift',
+	Kotlin = 'kotlin',
+	Shell = 'bash',
+	Dart = 'dart',
+	Json = 'json', // Also includes jsonc support, i.e., comments
+	Yaml = 'yaml',
+	Php = 'php',
+	Lua = 'lua',
+	Hcl = 'hcl', // Also includes Terraform
+	Sql = 'sql',
+	Graphql = 'graphql',
+	Toml = 'toml',
+	Scala = 'scala',
+	R = 'r',
+	ObjectiveC = 'objc', // Also used for Objective-C++, whose C++ constructs it parses as well as it can
+	Protobuf = 'proto', // proto2 and proto3
+	Ini = 'ini',
 }
 
 export class TreeSitterUnknownLanguageError extends Error {
@@ -36,9 +54,37 @@ const languageIdToWasmLanguageMapping: { [language: string]: WASMLanguage } = {
 	go: WASMLanguage.Go,
 	ruby: WASMLanguage.Ruby,
 	csharp: WASMLanguage.Csharp,
+	c: WASMLanguage.C,
 	cpp: WASMLanguage.Cpp,
 	java: WASMLanguage.Java,
 	rust: WASMLanguage.Rust,
+	swift: WASMLanguage.Swift,
+	kotlin: WASMLanguage.Kotlin,
+	kt: WASMLanguage.Kotlin,
+	shellscript: WASMLanguage.Shell,
+	bash: WASMLanguage.Shell,
+	sh: WASMLanguage.Shell,
+	dart: WASMLanguage.Dart,
+	json: WASMLanguage.Json,
+	jsonc: WASMLanguage.Json,
+	yaml: WASMLanguage.Yaml,
+	yml: WASMLanguage.Yaml,
+	dockercompose: WASMLanguage.Yaml,
+	php: WASMLanguage.Php,
+	lua: WASMLanguage.Lua,
+	hcl: WASMLanguage.Hcl,
+	terraform: WASMLanguage.Hcl,
+	'terraform-vars': WASMLanguage.Hcl,
+	sql: WASMLanguage.Sql,
+	graphql: WASMLanguage.Graphql,
+	toml: WASMLanguage.Toml,
+	scala: WASMLanguage.Scala,
+	r: WASMLanguage.R,
+	'objective-c': WASMLanguage.ObjectiveC,
+	'objective-cpp': WASMLanguage.ObjectiveC,
+	proto: WASMLanguage.Protobuf,
+	proto3: WASMLanguage.Protobuf,
+	ini: WASMLanguage.Ini,
 };
 
 /**
@@ -49,3 +95,132 @@ export function getWasmLanguage(languageId: string): WASMLanguage | undefined {
 		return languageIdToWasmLanguageMapping[languageId];
 	}
 }
+
+const extensionToWasmLanguageMapping: { [extension: string]: WASMLanguage } = {
+	'.py': WASMLanguage.Python,
+	'.pyw': WASMLanguage.Python,
+	'.pyi': WASMLanguage.Python,
+	'.js': WASMLanguage.JavaScript,
+	'.mjs': WASMLanguage.JavaScript,
+	'.cjs': WASMLanguage.JavaScript,
+	'.jsx': WASMLanguage.JavaScript,
+	'.ts': WASMLanguage.TypeScript,
+	'.mts': WASMLanguage.TypeScript,
+	'.cts': WASMLanguage.TypeScript,
+	'.tsx': WASMLanguage.TypeScriptTsx,
+	'.go': WASMLanguage.Go,
+	'.rb': WASMLanguage.Ruby,
+	'.cs': WASMLanguage.Csharp,
+	'.c': WASMLanguage.C,
+	'.cpp': WASMLanguage.Cpp,
+	'.cc': WASMLanguage.Cpp,
+	'.cxx': WASMLanguage.Cpp,
+	'.hpp': WASMLanguage.Cpp,
+	'.hh': WASMLanguage.Cpp,
+	'.hxx': WASMLanguage.Cpp,
+	'.java': WASMLanguage.Java,
+	'.rs': WASMLanguage.Rust,
+	'.swift': WASMLanguage.Swift,
+	'.kt': WASMLanguage.Kotlin,
+	'.kts': WASMLanguage.Kotlin,
+	'.sh': WASMLanguage.Shell,
+	'.bash': WASMLanguage.Shell,
+	'.dart': WASMLanguage.Dart,
+	'.json': WASMLanguage.Json,
+	'.jsonc': WASMLanguage.Json,
+	'.yaml': WASMLanguage.Yaml,
+	'.yml': WASMLanguage.Yaml,
+	'.php': WASMLanguage.Php,
+	'.phtml': WASMLanguage.Php,
+	'.lua': WASMLanguage.Lua,
+	'.hcl': WASMLanguage.Hcl,
+	'.tf': WASMLanguage.Hcl,
+	'.tfvars': WASMLanguage.Hcl,
+	'.sql': WASMLanguage.Sql,
+	'.graphql': WASMLanguage.Graphql,
+	'.graphqls': WASMLanguage.Graphql,
+	'.gql': WASMLanguage.Graphql,
+	'.toml': WASMLanguage.Toml,
+	'.scala': WASMLanguage.Scala,
+	'.sc': WASMLanguage.Scala,
+	'.r': WASMLanguage.R,
+	'.mm': WASMLanguage.ObjectiveC,
+	'.proto': WASMLanguage.Protobuf,
+	'.ini': WASMLanguage.Ini,
+};
+
+/** Interpreters of shebang lines without version suffixes, e.g., `python` for `python3.12` */
+const interpreterToWasmLanguageMapping: { [interpreter: string]: WASMLanguage } = {
+	python: WASMLanguage.Python,
+	node: WASMLanguage.JavaScript,
+	ruby: WASMLanguage.Ruby,
+	bash: WASMLanguage.Shell,
+	sh: WASMLanguage.Shell,
+	dash: WASMLanguage.Shell,
+	kotlin: WASMLanguage.Kotlin,
+	swift: WASMLanguage.Swift,
+	dart: WASMLanguage.Dart,
+	php: WASMLanguage.Php,
+	lua: WASMLanguage.Lua,
+	Rscript: WASMLanguage.R,
+};
+
+/**
+ * Detects the language of a file that may not have a (distinctive) extension, so that callers can resolve it before asking for its structure.
+ *
+ * Tries the extension first, then the interpreter of a shebang line, e.g., `#!/usr/bin/env python3`, then the content for extensions
+ * that are shared between languages, e.g., `.h` is C++ if it declares a class, namespace, or template; it's C otherwise, unless it declares
+ * an Objective-C interface or protocol. `.m` is only Objective-C if it looks like it, since MATLAB files share the extension.
+ *
+ * @returns `undefined` if the language can't be detected or isn't a {@link WASMLanguage}
+ */
+export function detectLanguage(fileName: string, source: string): WASMLanguage | undefined {
+	const baseName = fileName.slice(Math.max(fileName.lastIndexOf('/'), fileName.lastIndexOf('\\')) + 1);
+	const extIdx = baseName.lastIndexOf('.');
+	const extension = extIdx > 0 ? baseName.slice(extIdx).toLowerCase() : '';
+
+	if (extension in extensionToWasmLanguageMapping) {
+		return extensionToWasmLanguageMapping[extension];
+	}
+
+	const interpreter = shebangInterpreter(source);
+	if (interpreter !== undefined && interpreter in interpreterToWasmLanguageMapping) {
+		return interpreterToWasmLanguageMapping[interpreter];
+	}
+
+	if (extension === '.h') {
+		return looksLikeObjectiveC(source) ? WASMLanguage.ObjectiveC : looksLikeCpp(source) ? WASMLanguage.Cpp : WASMLanguage.C;
+	}
+
+	if (extension === '.m') {
+		return looksLikeObjectiveC(source) ? WASMLanguage.ObjectiveC : undefined;
+	}
+
+	return undefined;
+}
+
+/**
+ * @returns interpreter of the shebang line of `source` without its directory and version, e.g., `python` for `#!/usr/bin/python3.12`
+ * or `node` for `#!/usr/bin/env -S node --no-warnings`; `undefined` if there's no shebang line
+ */
+function shebangInterpreter(source: string): string | undefined {
+	const shebang = /^#!(.*)/.exec(source);
+	if (!shebang) {
+		return undefined;
+	}
+	const args = shebang[1].trim().split(/\s+/);
+	let command = args.shift()?.split('/').pop();
+	if (command === 'env') {
+		// skip options and variable assignments of `env`, e.g., `-S` or `PYTHONPATH=.`
+		command = args.find(arg => !arg.startsWith('-') && !arg.includes('='));
+	}
+	return command?.replace(/[\d.]+$/, '');
+}
+
+function looksLikeCpp(source: string): boolean {
+	return /^\s*(class|namespace|template\s*<|using\s+namespace)\b|^\s*(public|private|protected)\s*:|\w::\w/m.test(source);
+}
+
+function looksLikeObjectiveC(source: string): boolean {
+	return /^\s*(@interface|@implementation|@protocol|@import|#import)\b/m.test(source);
+}
@@ -34,9 +34,27 @@ const treeSitterQuery =
 			go: defaultBehavior,
 			ruby: defaultBehavior,
 			csharp: defaultBehavior,
+			c: defaultBehavior,
 			cpp: defaultBehavior,
 			java: defaultBehavior,
 			rust: defaultBehavior,
+			swift: defaultBehavior,
+			kotlin: defaultBehavior,
+			bash: defaultBehavior,
+			dart: defaultBehavior,
+			json: defaultBehavior,
+			yaml: defaultBehavior,
+			php: defaultBehavior,
+			lua: defaultBehavior,
+			hcl: defaultBehavior,
+			sql: defaultBehavior,
+			graphql: defaultBehavior,
+			toml: defaultBehavior,
+			scala: defaultBehavior,
+			r: defaultBehavior,
+			objc: defaultBehavior,
+			proto: defaultBehavior,
+			ini: defaultBehavior,
 		};
 	})();
 
@@ -57,7 +75,25 @@ export const allKnownQueries: LanguageQueryMap = {
 	[WASMLanguage.Java]: [],
 	[WASMLanguage.Ruby]: [],
 	[WASMLanguage.Cpp]: [],
+	[WASMLanguage.C]: [],
 	[WASMLanguage.Rust]: [],
+	[WASMLanguage.Swift]: [],
+	[WASMLanguage.Kotlin]: [],
+	[WASMLanguage.Shell]: [],
+	[WASMLanguage.Dart]: [],
+	[WASMLanguage.Json]: [],
+	[WASMLanguage.Yaml]: [],
+	[WASMLanguage.Php]: [],
+	[WASMLanguage.Lua]: [],
+	[WASMLanguage.Hcl]: [],
+	[WASMLanguage.Sql]: [],
+	[WASMLanguage.Graphql]: [],
+	[WASMLanguage.Toml]: [],
+	[WASMLanguage.Scala]: [],
+	[WASMLanguage.R]: [],
+	[WASMLanguage.ObjectiveC]: [],
+	[WASMLanguage.Protobuf]: [],
+	[WASMLanguage.Ini]: [],
 };
 /**
  * register queries
@@ -137,7 +173,7 @@ export const callExpressionQuery: LanguageQueryMap = q({
 					(simple_symbol) @symbol))
 		] @call_expression`
 	],
-	[WASMLanguage.Cpp]: [
+	...forLanguages([WASMLanguage.Cpp, WASMLanguage.C, WASMLanguage.ObjectiveC], [
 		`[
 			(function_declarator
 				(identifier) @identifier)
@@ -154,14 +190,92 @@ export const callExpressionQuery: LanguageQueryMap = q({
 						(pointer_expression
 						(identifier) @identifier))))
 		] @call_expression`
-	],
+	]),
 	[WASMLanguage.Rust]: [
 		`[
 			(call_expression (identifier) @identifier)
 			(call_expression (field_expression (identifier) (field_identifier) @identifier))
 			(call_expression (scoped_identifier (identifier) (identifier) @identifier (#not-match? @identifier "new")))
 		] @call_expression`
-	]
+	],
+	[WASMLanguage.Swift]: [
+		`[
+			(call_expression
+				(simple_identifier) @identifier)
+		] @call_expression`
+	],
+	[WASMLanguage.Kotlin]: [
+		`[
+			(call_expression
+				(simple_identifier) @identifier)
+			(call_expression
+				(navigation_expression
+					(navigation_suffix
+						(simple_identifier) @identifier)))
+		] @call_expression`
+	],
+	[WASMLanguage.Shell]: [
+		`(command
+			name: (command_name) @identifier) @call_expression`
+	],
+	// calls aren't nodes of their own in the dart grammar, i.e., `foo()` is an identifier followed by a selector with the arguments
+	[WASMLanguage.Dart]: [],
+	[WASMLanguage.Json]: [],
+	[WASMLanguage.Yaml]: [],
+	[WASMLanguage.Php]: [
+		`[
+			(function_call_expression
+				function: (name) @identifier)
+			(function_call_expression
+				function: (qualified_name
+					(name) @identifier))
+			(member_call_expression
+				name: (name) @identifier)
+			(scoped_call_expression
+				name: (name) @identifier)
+		] @call_expression`
+	],
+	[WASMLanguage.Lua]: [
+		`(function_call
+			name: [
+				(identifier) @identifier
+				(dot_index_expression
+					field: (identifier) @identifier)
+				(method_index_expression
+					method: (identifier) @identifier)
+			]) @call_expression`
+	],
+	[WASMLanguage.Hcl]: [
+		`(function_call
+			(identifier) @identifier) @call_expression`
+	],
+	[WASMLanguage.Sql]: [
+		`(invocation
+			(object_reference
+				name: (identifier) @identifier)) @call_expression`
+	],
+	[WASMLanguage.Graphql]: [],
+	[WASMLanguage.Toml]: [],
+	[WASMLanguage.Scala]: [
+		`[
+			(call_expression
+				function: (identifier) @identifier)
+			(call_expression
+				function: (field_expression
+					field: (identifier) @identifier))
+		] @call_expression`
+	],
+	[WASMLanguage.R]: [
+		`[
+			(call
+				function: (identifier) @identifier)
+			(call
+				function: (namespace_operator
+					rhs: (identifier) @identifier))
+		] @call_expression`
+	],
+	[WASMLanguage.Protobuf]: [],
+	[WASMLanguage.Ini]: [],
 });
 
 export const classDeclarationQuery: LanguageQueryMap = q({
@@ -180,6 +294,7 @@ export const classDeclarationQuery: LanguageQueryMap = q({
 	[WASMLanguage.Cpp]: [
 		`(class_specifier) @class_declaration`
 	],
+	[WASMLanguage.C]: [],
 	[WASMLanguage.Ruby]: [
 		`(class) @class_declaration`
 	],
@@ -190,7 +305,55 @@ export const classDeclarationQuery: LanguageQueryMap = q({
 	],
 	[WASMLanguage.Rust]: [
 		`(impl_item (type_identifier) @type_identifier) @class_declaration`
-	]
+	],
+	[WASMLanguage.Swift]: [
+		`(class_declaration) @class_declaration`
+	],
+	[WASMLanguage.Kotlin]: [
+		`[
+			(class_declaration)
+			(object_declaration)
+		] @class_declaration`
+	],
+	[WASMLanguage.Shell]: [],
+	[WASMLanguage.Dart]: [
+		`[
+			(class_definition)
+			(mixin_declaration)
+			(enum_declaration)
+		] @class_declaration`
+	],
+	[WASMLanguage.Json]: [],
+	[WASMLanguage.Yaml]: [],
+	[WASMLanguage.Php]: [
+		`[
+			(class_declaration)
+			(trait_declaration)
+			(enum_declaration)
+		] @class_declaration`
+	],
+	[WASMLanguage.Lua]: [],
+	[WASMLanguage.Hcl]: [],
+	[WASMLanguage.Sql]: [],
+	[WASMLanguage.Graphql]: [],
+	[WASMLanguage.Toml]: [],
+	[WASMLanguage.Scala]: [
+		`[
+			(class_definition)
+			(object_definition)
+			(trait_definition)
+		] @class_declaration`
+	],
+	[WASMLanguage.R]: [],
+	[WASMLanguage.ObjectiveC]: [
+		`[
+			(class_interface)
+			(class_implementation)
+			(protocol_declaration)
+		] @class_declaration`
+	],
+	[WASMLanguage.Protobuf]: [],
+	[WASMLanguage.Ini]: [],
 });
 
 export const typeDeclarationQuery: { [language: string]: string[] } = q({
@@ -205,7 +368,7 @@ export const typeDeclarationQuery: { [language: string]: string[] } = q({
 		`(interface_declaration
 			(identifier) @type_identifier) @type_declaration`
 	],
-	[WASMLanguage.Cpp]: [
+	...forLanguages([WASMLanguage.Cpp, WASMLanguage.C, WASMLanguage.ObjectiveC], [
 		`[
 			(struct_specifier
 				(type_identifier) @type_identifier)
@@ -214,7 +377,7 @@ export const typeDeclarationQuery: { [language: string]: string[] } = q({
 			(enum_specifier
 				(type_identifier) @type_identifier)
 		] @type_declaration`
-	],
+	]),
 	[WASMLanguage.Java]: [
 		`(interface_declaration
 			(identifier) @type_identifier) @type_declaration`
@@ -231,6 +394,14 @@ export const typeDeclarationQuery: { [language: string]: string[] } = q({
 		`(class_definition
 			(identifier) @type_identifier) @type_declaration`
 	],
+	[WASMLanguage.Swift]: [
+		`(protocol_declaration
+			name: (type_identifier) @type_identifier) @type_declaration`
+	],
+	[WASMLanguage.Kotlin]: [
+		`(type_alias
+			(type_identifier) @type_identifier) @type_declaration`
+	],
 });
 
 export const typeReferenceQuery: { [language: string]: string[] } = q({
@@ -252,9 +423,9 @@ export const typeReferenceQuery: { [language: string]: string[] } = q({
 				(identifier) @type_identifier)
 		]`
 	],
-	[WASMLanguage.Cpp]: [
+	...forLanguages([WASMLanguage.Cpp, WASMLanguage.C, WASMLanguage.ObjectiveC], [
 		`(type_identifier) @type_identifier`
-	],
+	]),
 	[WASMLanguage.Java]: [
 		`(type_identifier) @type_identifier`
 	],
@@ -264,7 +435,14 @@ export const typeReferenceQuery: { [language: string]: string[] } = q({
 			(argument_list
 				(identifier) @type_identifier)
 		]`
-	]
+	],
+	[WASMLanguage.Swift]: [
+		`(type_identifier) @type_identifier`
+	],
+	[WASMLanguage.Kotlin]: [
+		`(user_type
+			(type_identifier) @type_identifier)`
+	],
 });
 
 export const classReferenceQuery: LanguageQueryMap = q({
@@ -292,6 +470,7 @@ export const classReferenceQuery: LanguageQueryMap = q({
 				(type_identifier) @new_expression)
 		]`
 	],
+	[WASMLanguage.C]: [],
 	[WASMLanguage.Go]: [
 		`(composite_literal (type_identifier) @new_expression)`
 	],
@@ -308,6 +487,29 @@ export const classReferenceQuery: LanguageQueryMap = q({
 				(identifier) @identifier
 				(#eq? @identifier "new")))`
 	],
+	[WASMLanguage.Swift]: [],
+	[WASMLanguage.Kotlin]: [],
+	[WASMLanguage.Shell]: [],
+	[WASMLanguage.Dart]: [],
+	[WASMLanguage.Json]: [],
+	[WASMLanguage.Yaml]: [],
+	[WASMLanguage.Php]: [
+		`(object_creation_expression
+			[
+				(name)
+				(qualified_name)
+			] @new_expression)`
+	],
+	[WASMLanguage.Lua]: [],
+	[WASMLanguage.Hcl]: [],
+	[WASMLanguage.Sql]: [],
+	[WASMLanguage.Graphql]: [],
+	[WASMLanguage.Toml]: [],
+	[WASMLanguage.Scala]: [],
+	[WASMLanguage.R]: [],
+	[WASMLanguage.ObjectiveC]: [],
+	[WASMLanguage.Protobuf]: [],
+	[WASMLanguage.Ini]: [],
 });
 
 export const functionQuery: LanguageQueryMap = q({
@@ -433,7 +635,110 @@ export const functionQuery: LanguageQueryMap = q({
 			(function_item (identifier) @identifier)
 			(let_declaration (identifier) @identifier)
 		] @function`
-	]
+	],
+	swift: [
+		// function patterns defined in swift grammar:
+		// https://github.com/alex-pinkus/tree-sitter-swift/blob/main/grammar.js
+		`[
+			(function_declaration
+				name: (simple_identifier) @identifier
+				body: (function_body) @body)
+			(init_declaration
+				body: (function_body) @body)
+		] @function`,
+	],
+	kotlin: [
+		// function patterns defined in kotlin grammar:
+		// https://github.com/fwcd/tree-sitter-kotlin/blob/main/grammar.js
+		`[
+			(function_declaration
+				(simple_identifier) @identifier
+				(function_body) @body)
+			(secondary_constructor
+				(statements) @body)
+			(anonymous_initializer
+				(statements) @body)
+		] @function`,
+	],
+	bash: [
+		// function patterns defined in bash grammar:
+		// https://github.com/tree-sitter/tree-sitter-bash/blob/master/grammar.js
+		// the body is usually a `{ ... }` group but can also be a subshell, i.e., `( ... )`, among others
+		`(function_definition
+			name: (word) @identifier
+			body: (_) @body) @function`,
+	],
+	dart: [
+		// function patterns defined in dart grammar:
+		// https://github.com/UserNobody14/tree-sitter-dart/blob/master/grammar.js
+		// the body of a function or method is the next sibling of its signature
+		`(
+			[
+				(function_signature
+					name: (identifier) @identifier)
+				(method_signature
+					(function_signature
+						name: (identifier) @identifier))
+			] @function
+			.
+			(function_body) @body
+		)`,
+	],
+	json: [],
+	yaml: [],
+	php: [
+		`[
+			(function_definition
+				name: (name) @identifier
+				body: (compound_statement) @body)
+			(method_declaration
+				name: (name) @identifier
+				body: (compound_statement) @body)
+			(anonymous_function
+				body: (compound_statement) @body)
+			(arrow_function
+				body: (_) @body)
+		] @function`
+	],
+	lua: [
+		`[
+			(function_declaration
+				name: (_) @identifier
+				body: (block) @body)
+			(function_definition
+				body: (block) @body)
+		] @function`
+	],
+	hcl: [],
+	sql: [],
+	graphql: [],
+	toml: [],
+	scala: [
+		// function patterns defined in scala grammar:
+		// https://github.com/tree-sitter/tree-sitter-scala/blob/master/grammar.js
+		// abstract members, i.e., \`function_declaration\`s, have no body
+		`(function_definition
+			name: (identifier) @identifier
+			body: (_) @body) @function`,
+	],
+	r: [
+		// functions are anonymous and named after the variables they're assigned to, e.g., \`area <- function(shape) { ... }\`
+		`(function_definition
+			body: (_) @body) @function`,
+	],
+	objc: [
+		// methods have no name of their own; their selectors are made of the keywords before their parameters, see \`describeObjcNode\`
+		`[
+			(function_definition
+				(_
+					(identifier) @identifier)
+					(compound_statement) @body)
+			(method_definition
+				(compound_statement) @body)
+		] @function`,
+	],
+	proto: [],
+	ini: [],
 });
 
 export const docCommentQueries: LanguageQueryMap = q({
@@ -449,10 +754,10 @@ export const docCommentQueries: LanguageQueryMap = q({
 		treeSitterQuery.java`((block_comment) @block_comment
 			(#match? @block_comment "^\\\\/\\\\*\\\\*")) @docComment`
 	],
-	[WASMLanguage.Cpp]: [
+	...forLanguages([WASMLanguage.Cpp, WASMLanguage.C, WASMLanguage.ObjectiveC], [
 		treeSitterQuery.cpp`((comment) @comment
 			(#match? @comment "^\\\\/\\\\*\\\\*")) @docComment`
-	],
+	]),
 	[WASMLanguage.Csharp]: [
 		treeSitterQuery.csharp`(
 			((comment) @c
@@ -478,6 +783,458 @@ export const docCommentQueries: LanguageQueryMap = q({
 		`(expression_statement
 			(string) @docComment)`
 	],
+	[WASMLanguage.Swift]: [
+		treeSitterQuery.swift`((comment) @comment
+			(#match? @comment "^///"))+ @docComment`,
+		treeSitterQuery.swift`((multiline_comment) @comment
+			(#match? @comment "^\\\\/\\\\*\\\\*")) @docComment`
+	],
+	[WASMLanguage.Kotlin]: [
+		treeSitterQuery.kotlin`((multiline_comment) @comment
+			(#match? @comment "^\\\\/\\\\*\\\\*")) @docComment`
+	],
+	// note: shell has same prefix for a doc comment and line comment
+	[WASMLanguage.Shell]: [
+		treeSitterQuery.bash`((comment)+) @docComment`
+	],
+	[WASMLanguage.Dart]: [
+		treeSitterQuery.dart`((documentation_comment)+) @docComment`
+	],
+	[WASMLanguage.Json]: [],
+	[WASMLanguage.Yaml]: [],
+	[WASMLanguage.Php]: [
+		treeSitterQuery.php`((comment) @comment
+			(#match? @comment "^\\\\/\\\\*\\\\*")) @docComment`
+	],
+	[WASMLanguage.Lua]: [
+		// LuaLS annotations, e.g., `--- @param name string`
+		treeSitterQuery.lua`((comment) @comment
+			(#match? @comment "^---")) @docComment`
+	],
+	[WASMLanguage.Hcl]: [],
+	[WASMLanguage.Sql]: [],
+	[WASMLanguage.Graphql]: [],
+	[WASMLanguage.Toml]: [],
+	[WASMLanguage.Scala]: [
+		treeSitterQuery.scala`((block_comment) @comment
+			(#match? @comment "^\\\\/\\\\*\\\\*")) @docComment`
+	],
+	[WASMLanguage.R]: [
+		// roxygen comments, e.g., \`#' @param x a number\`
+		treeSitterQuery.r`((comment) @comment
+			(#match? @comment "^#'")) @docComment`
+	],
+	[WASMLanguage.Protobuf]: [],
+	[WASMLanguage.Ini]: [],
+});
+
+/**
+ * Captures `@import` with the imported `@path` and optionally the name, `@alias`, the whole module is bound to.
+ * Matches of the same `@import` and `@path` are merged, i.e., a pattern capturing `@alias` can complement a more general one.
+ */
+export const importQueries: { [language: string]: string[] } = q({
+	...forLanguages([WASMLanguage.JavaScript, WASMLanguage.TypeScript, WASMLanguage.TypeScriptTsx], [
+		// also `import type ...`
+		`[
+			(import_statement
+				source: (string) @path)
+			(import_statement
+				(import_clause
+					(namespace_import
+						(identifier) @alias))
+				source: (string) @path)
+		] @import`,
+		`([
+			(lexical_declaration
+				(variable_declarator
+					value: (call_expression
+						function: (identifier) @_fn
+						arguments: (arguments . (string) @path))))
+			(variable_declaration
+				(variable_declarator
+					value: (call_expression
+						function: (identifier) @_fn
+						arguments: (arguments . (string) @path))))
+			(expression_statement
+				(call_expression
+					function: (identifier) @_fn
+					arguments: (arguments . (string) @path)))
+		] @import
+			(#eq? @_fn "require"))`,
+		`([
+			(lexical_declaration
+				(variable_declarator
+					name: (identifier) @alias
+					value: (call_expression
+						function: (identifier) @_fn
+						arguments: (arguments . (string) @path))))
+			(variable_declaration
+				(variable_declarator
+					name: (identifier) @alias
+					value: (call_expression
+						function: (identifier) @_fn
+						arguments: (arguments . (string) @path))))
+		] @import
+			(#eq? @_fn "require"))`,
+	]),
+	[WASMLanguage.Go]: [
+		`[
+			(import_spec
+				path: (_) @path)
+			(import_spec
+				name: (_) @alias
+				path: (_) @path)
+		] @import`
+	],
+	[WASMLanguage.Python]: [
+		`[
+			(import_statement
+				name: (dotted_name) @path)
+			(import_statement
+				name: (aliased_import
+					name: (dotted_name) @path
+					alias: (identifier) @alias))
+			(import_from_statement
+				module_name: (_) @path)
+		] @import`
+	],
+	[WASMLanguage.Shell]: [
+		// e.g., `source ./lib.sh` or `. ./lib.sh`
+		`((command
+			name: (command_name) @_cmd
+			.
+			argument: (_) @path) @import
+			(#match? @_cmd "^(source|[.])$"))`
+	],
+	[WASMLanguage.Dart]: [
+		// e.g., `import 'package:http/http.dart' as http;`; exports aren't imports
+		`[
+			(import_specification
+				(configurable_uri
+					(uri) @path))
+			(import_specification
+				(configurable_uri
+					(uri) @path)
+				(identifier) @alias)
+		] @import`
+	],
+	[WASMLanguage.Php]: [
+		// e.g., `use App\Models\User as Model;`; names imported by a group, e.g., `use App\{Foo, Bar};`, aren't paths of their own
+		`(namespace_use_declaration
+			(namespace_use_clause
+				. ;; the imported name precedes its alias, e.g., Cart in Cart as CartContract
+				[
+					(name)
+					(qualified_name)
+				] @path)) @import`
+	],
+});
+
+/**
+ * Captures `@function` for the declarations of named functions and methods that are part of the API of a file, i.e., top-level ones
+ * and members of types, but not nested ones, e.g., a function declared within a function body.
+ */
+export const signatureQueries: { [language: string]: string[] } = q({
+	...forLanguages([WASMLanguage.JavaScript, WASMLanguage.TypeScript, WASMLanguage.TypeScriptTsx], [
+		`[
+			(program
+				[
+					(function_declaration)
+					(generator_function_declaration)
+				] @function)
+			(program
+				(export_statement
+					declaration: [
+						(function_declaration)
+						(generator_function_declaration)
+					] @function))
+			(class_body
+				(method_definition) @function)
+		]`
+	]),
+	[WASMLanguage.Go]: [
+		`(source_file
+			[
+				(function_declaration)
+				(method_declaration)
+			] @function)`
+	],
+	[WASMLanguage.Python]: [
+		`[
+			(module
+				(function_definition) @function)
+			(module
+				(decorated_definition
+					definition: (function_definition) @function))
+			(class_definition
+				body: (block
+					(function_definition) @function))
+			(class_definition
+				body: (block
+					(decorated_definition
+						definition: (function_definition) @function)))
+		]`
+	],
+});
+
+/**
+ * Captures `@call` with the name of the called function or method, `@callee`, its `@arguments`, and, for method calls,
+ * the expression the method is accessed on, `@receiver`.
+ */
+export const callReferenceQueries: { [language: string]: string[] } = q({
+	...forLanguages([WASMLanguage.JavaScript, WASMLanguage.TypeScript, WASMLanguage.TypeScriptTsx], [
+		`(call_expression
+			function: (identifier) @callee
+			arguments: (arguments) @arguments) @call`,
+		`(call_expression
+			function: (member_expression
+				object: (_) @receiver
+				property: (property_identifier) @callee)
+			arguments: (arguments) @arguments) @call`,
+	]),
+	[WASMLanguage.Python]: [
+		`(call
+			function: (identifier) @callee
+			arguments: (argument_list) @arguments) @call`,
+		`(call
+			function: (attribute
+				object: (_) @receiver
+				attribute: (identifier) @callee)
+			arguments: (argument_list) @arguments) @call`,
+	],
+	[WASMLanguage.Go]: [
+		`(call_expression
+			function: (identifier) @callee
+			arguments: (argument_list) @arguments) @call`,
+		`(call_expression
+			function: (selector_expression
+				operand: (_) @receiver
+				field: (field_identifier) @callee)
+			arguments: (argument_list) @arguments) @call`,
+	],
+	[WASMLanguage.Java]: [
+		`(method_invocation
+			!object
+			name: (identifier) @callee
+			arguments: (argument_list) @arguments) @call`,
+		`(method_invocation
+			object: (_) @receiver
+			name: (identifier) @callee
+			arguments: (argument_list) @arguments) @call`,
+	],
+	[WASMLanguage.Csharp]: [
+		`(invocation_expression
+			function: (identifier) @callee
+			arguments: (argument_list) @arguments) @call`,
+		`(invocation_expression
+			function: (member_access_expression
+				expression: (_) @receiver
+				name: (identifier) @callee)
+			arguments: (argument_list) @arguments) @call`,
+	],
+	[WASMLanguage.Rust]: [
+		`(call_expression
+			function: (identifier) @callee
+			arguments: (arguments) @arguments) @call`,
+		`(call_expression
+			function: (field_expression
+				value: (_) @receiver
+				field: (field_identifier) @callee)
+			arguments: (arguments) @arguments) @call`,
+	],
+	...forLanguages([WASMLanguage.Cpp, WASMLanguage.C, WASMLanguage.ObjectiveC], [
+		`(call_expression
+			function: (identifier) @callee
+			arguments: (argument_list) @arguments) @call`,
+		`(call_expression
+			function: (field_expression
+				argument: (_) @receiver
+				field: (field_identifier) @callee)
+			arguments: (argument_list) @arguments) @call`,
+	]),
+});
+
+const jsFoldingRangeQuery = `[
+	(statement_block)
+	(class_body)
+	(switch_statement)
+	(switch_case)
+	(switch_default)
+	(object)
+	(array)
+	(arguments)
+	(named_imports)
+	(template_string)
+] @fold`;
+
+/**
+ * Captures `@fold` for block-like nodes, e.g., bodies, composite literals, and grouped declarations, see `_getFoldingRanges`.
+ */
+export const foldingRangeQueries: { [language: string]: string[] } = q({
+	[WASMLanguage.JavaScript]: [
+		jsFoldingRangeQuery,
+	],
+	...forLanguages([WASMLanguage.TypeScript, WASMLanguage.TypeScriptTsx], [
+		jsFoldingRangeQuery,
+		`[
+			(interface_declaration
+				body: (_) @fold)
+			(enum_declaration
+				body: (_) @fold)
+		]`,
+	]),
+	[WASMLanguage.Go]: [
+		`[
+			(block) ;; e.g., function bodies and bodies of if and for statements
+			(field_declaration_list)
+			(interface_type)
+			(literal_value) ;; composite literals
+
+			(import_spec_list)
+			(const_declaration)
+			(var_declaration)
+
+			(expression_switch_statement)
+			(type_switch_statement)
+			(select_statement)
+			(expression_case)
+			(type_case)
+			(communication_case)
+			(default_case)
+		] @fold`
+	],
+	[WASMLanguage.Python]: [
+		`[
+			(function_definition)
+			(class_definition)
+			(if_statement)
+			(elif_clause)
+			(else_clause)
+			(for_statement)
+			(while_statement)
+			(with_statement)
+			(try_statement)
+			(except_clause)
+			(finally_clause)
+
+			(dictionary)
+			(list)
+			(argument_list)
+			(import_from_statement)
+		] @fold`
+	],
+	[WASMLanguage.Shell]: [
+		`[
+			(compound_statement) ;; e.g., function bodies and \`{ ...; }\` groups
+			(subshell)
+			(case_statement)
+		] @fold`
+	],
+	[WASMLanguage.Dart]: [
+		`[
+			(block) ;; e.g., function bodies and bodies of if and for statements
+			(class_body)
+			(extension_body)
+			(enum_body)
+		] @fold`
+	],
+	[WASMLanguage.Json]: [
+		`[
+			(object)
+			(array)
+		] @fold`
+	],
+	[WASMLanguage.Yaml]: [
+		`[
+			(block_mapping_pair)
+			(block_sequence_item)
+			(flow_mapping)
+			(flow_sequence)
+		] @fold`
+	],
+	[WASMLanguage.Php]: [
+		`[
+			(compound_statement) ;; e.g., function bodies and bodies of if and for statements
+			(declaration_list)
+			(enum_declaration_list)
+			(array_creation_expression)
+		] @fold`
+	],
+	[WASMLanguage.Lua]: [
+		`[
+			(function_declaration)
+			(function_definition)
+			(table_constructor)
+			(if_statement)
+			(for_statement)
+			(while_statement)
+			(repeat_statement)
+			(do_statement)
+		] @fold`
+	],
+	[WASMLanguage.Hcl]: [
+		`[
+			(block)
+			(object)
+			(tuple)
+			(heredoc_template)
+		] @fold`
+	],
+	[WASMLanguage.Sql]: [
+		`[
+			(statement)
+			(column_definitions)
+			(subquery)
+		] @fold`
+	],
+	[WASMLanguage.Graphql]: [
+		`[
+			(fields_definition)
+			(input_fields_definition)
+			(enum_values_definition)
+			(arguments_definition)
+			(selection_set)
+		] @fold`
+	],
+	[WASMLanguage.Toml]: [
+		`[
+			(table)
+			(table_array_element)
+			(array)
+			(inline_table)
+		] @fold`
+	],
+	[WASMLanguage.Scala]: [
+		`[
+			(template_body)
+			(block)
+		] @fold`
+	],
+	[WASMLanguage.R]: [
+		`[
+			(braced_expression)
+			(arguments)
+		] @fold`
+	],
+	[WASMLanguage.ObjectiveC]: [
+		`[
+			(compound_statement)
+			(class_interface)
+			(class_implementation)
+			(protocol_declaration)
+		] @fold`
+	],
+	[WASMLanguage.Protobuf]: [
+		`[
+			(message_body)
+			(enum_body)
+			(service)
+			(oneof)
+		] @fold`
+	],
+	[WASMLanguage.Ini]: [
+		`(section) @fold`
+	],
 });
 
 export const testableNodeQueries: LanguageQueryMap = q({
@@ -571,14 +1328,14 @@ export const testableNodeQueries: LanguageQueryMap = q({
 				) @local_function
 			]`
 	],
-	[WASMLanguage.Cpp]: [ // FIXME@ulugbekna: #7769 enrich with class/methods
+	...forLanguages([WASMLanguage.Cpp, WASMLanguage.C], [ // FIXME@ulugbekna: #7769 enrich with class/methods
 		treeSitterQuery.cpp`[
 				(function_definition
 					(_
 						(identifier) @identifier)
 				) @function
 			]`
-	],
+	]),
 	[WASMLanguage.Java]: [
 		treeSitterQuery.java`(class_declaration
 			name: (_) @class.identifier
@@ -605,7 +1362,71 @@ export const testableNodeQueries: LanguageQueryMap = q({
 					(identifier) @function.identifier
 				) @function
 			]`
-	]
+	],
+	[WASMLanguage.Swift]: [
+		treeSitterQuery.swift`[
+				(function_declaration
+					name: (simple_identifier) @function.identifier
+				) @function
+			]`
+	],
+	[WASMLanguage.Kotlin]: [
+		treeSitterQuery.kotlin`[
+				(function_declaration
+					(simple_identifier) @function.identifier
+				) @function
+			]`
+	],
+	[WASMLanguage.Shell]: [
+		treeSitterQuery.bash`[
+				(function_definition
+					name: (word) @function.identifier
+				) @function
+			]`
+	],
+	[WASMLanguage.Dart]: [
+		treeSitterQuery.dart`[
+				(function_signature
+					name: (identifier) @function.identifier
+				) @function
+			]`
+	],
+	[WASMLanguage.Json]: [],
+	[WASMLanguage.Yaml]: [],
+	[WASMLanguage.Php]: [
+		treeSitterQuery.php`[
+				(function_definition
+					name: (name) @function.identifier
+				) @function
+
+				(class_declaration
+					name: (name) @class.identifier
+					body: (declaration_list
+						(method_declaration
+							(visibility_modifier)? @method.modifiers
+							(#not-eq? @method.modifiers "private")
+							name: (name) @method.identifier
+						) @method
+					)
+				) @class
+			]`
+	],
+	[WASMLanguage.Lua]: [],
+	[WASMLanguage.Hcl]: [],
+	[WASMLanguage.Sql]: [],
+	[WASMLanguage.Graphql]: [],
+	[WASMLanguage.Toml]: [],
+	[WASMLanguage.Scala]: [
+		treeSitterQuery.scala`[
+				(function_definition
+					name: (identifier) @function.identifier
+				) @function
+			]`
+	],
+	[WASMLanguage.R]: [],
+	[WASMLanguage.ObjectiveC]: [],
+	[WASMLanguage.Protobuf]: [],
+	[WASMLanguage.Ini]: [],
 });
 
 export const symbolQueries: LanguageQueryMap = q({
@@ -624,12 +1445,12 @@ export const symbolQueries: LanguageQueryMap = q({
 			(private_property_identifier) @symbol
 		]`
 	]),
-	[WASMLanguage.Cpp]: [
+	...forLanguages([WASMLanguage.Cpp, WASMLanguage.C, WASMLanguage.ObjectiveC], [
 		treeSitterQuery.cpp`[
 			(identifier) @symbol
 			(type_identifier) @symbol
 		]`
-	],
+	]),
 	[WASMLanguage.Csharp]: [
 		treeSitterQuery.csharp`[
 			(identifier) @symbol
@@ -660,24 +1481,78 @@ export const symbolQueries: LanguageQueryMap = q({
 			(identifier) @symbol
 		]`
 	],
-});
-
-export const syntacticallyValidAtoms: LanguageQueryMap = q({
-	[WASMLanguage.TypeScript]: [
-		treeSitterQuery.typescript`
-			[
-				(comment) @comment ;; split into multiple comment kinds?
-
-				(declaration) @declaration
-
-				;; class declaration related
-				(public_field_definition) @public_field_definition
-				(method_definition) @method_definition
-				(class_declaration (_ (method_signature) @method_signature))
-				(abstract_method_signature) @abstract_method_signature
-
+	[WASMLanguage.Swift]: [
+		treeSitterQuery.swift`[
+			(simple_identifier) @symbol
+			(type_identifier) @symbol
+		]`
+	],
+	[WASMLanguage.Kotlin]: [
+		treeSitterQuery.kotlin`[
+			(simple_identifier) @symbol
+			(type_identifier) @symbol
+		]`
+	],
+	[WASMLanguage.Shell]: [
+		treeSitterQuery.bash`[
+			(command_name) @symbol
+			(variable_name) @symbol
+		]`
+	],
+	[WASMLanguage.Dart]: [
+		treeSitterQuery.dart`[
+			(identifier) @symbol
+			(type_identifier) @symbol
+		]`
+	],
+	[WASMLanguage.Json]: [],
+	[WASMLanguage.Yaml]: [],
+	[WASMLanguage.Php]: [
+		treeSitterQuery.php`[
+			(name) @symbol
+			(variable_name) @symbol
+		]`
+	],
+	[WASMLanguage.Lua]: [
+		treeSitterQuery.lua`(identifier) @symbol`
+	],
+	[WASMLanguage.Hcl]: [],
+	[WASMLanguage.Sql]: [],
+	[WASMLanguage.Graphql]: [],
+	[WASMLanguage.Toml]: [],
+	[WASMLanguage.Scala]: [
+		treeSitterQuery.scala`[
+			(identifier) @symbol
+			(type_identifier) @symbol
+		]`
+	],
+	[WASMLanguage.R]: [
+		treeSitterQuery.r`[
+			(identifier) @symbol
+		]`
+	],
+	[WASMLanguage.Protobuf]: [],
+	[WASMLanguage.Ini]: [],
+});
+
+export const syntacticallyValidAtoms: LanguageQueryMap = q({
+	[WASMLanguage.TypeScript]: [
+		treeSitterQuery.typescript`
+			[
+				(comment) @comment ;; split into multiple comment kinds?
+
+				(declaration) @declaration
+
+				;; class declaration related
+				(public_field_definition) @public_field_definition
+				(method_definition) @method_definition
+				(class_declaration (_ (method_signature) @method_signature))
+				(abstract_method_signature) @abstract_method_signature
+
 				;; enum declaration related
 				(enum_assignment) @enum_assignment
+				;; members without values; only with \`includeEnumMembers\`, see structure.ts
+				(enum_body name: (_) @enum_member)
 
 				;; interface declaration related
 				(interface_declaration (_ (method_signature) @method_signature))
@@ -690,6 +1565,11 @@ export const syntacticallyValidAtoms: LanguageQueryMap = q({
 
 				(expression_statement) @expression_statement
 
+				;; function expressions and arrow functions assigned to variables or passed as arguments, e.g., \`const f = () => { ... }\` or \`setTimeout(() => { ... })\`;
+				;; only with \`includeAnonymousFunctions\`, see structure.ts
+				(variable_declarator value: [(function_expression) (arrow_function)] @anonymous_function)
+				(arguments [(function_expression) (arrow_function)] @anonymous_function)
+
 				(for_in_statement) @for_in_statement
 				;; exclude any children found in the for loop condition
 				(for_statement condition: (_) @for_statement.exclude_captures ) @for_statement
@@ -714,7 +1594,14 @@ export const syntacticallyValidAtoms: LanguageQueryMap = q({
 				(debugger_statement) @debugger_statement
 				(return_statement) @return_statement
 			]
-		`
+		`,
+		treeSitterQuery.typescript`
+			;; constructor parameter properties, e.g., "private readonly foo: Foo"; parameters without modifiers are skipped
+			((method_definition
+				name: (property_identifier) @_name
+				parameters: (formal_parameters [(required_parameter) (optional_parameter)] @parameter_property))
+				(#eq? @_name "constructor"))
+		`,
 	],
 	[WASMLanguage.TypeScriptTsx]: [
 		treeSitterQuery.typescript`
@@ -731,6 +1618,8 @@ export const syntacticallyValidAtoms: LanguageQueryMap = q({
 
 				;; enum declaration related
 				(enum_assignment) @enum_assignment
+				;; members without values; only with \`includeEnumMembers\`, see structure.ts
+				(enum_body name: (_) @enum_member)
 
 				;; interface declaration related
 				(interface_declaration (_ (method_signature) @method_signature))
@@ -743,6 +1632,11 @@ export const syntacticallyValidAtoms: LanguageQueryMap = q({
 
 				(expression_statement) @expression_statement
 
+				;; function expressions and arrow functions assigned to variables or passed as arguments, e.g., \`const f = () => { ... }\` or \`setTimeout(() => { ... })\`;
+				;; only with \`includeAnonymousFunctions\`, see structure.ts
+				(variable_declarator value: [(function_expression) (arrow_function)] @anonymous_function)
+				(arguments [(function_expression) (arrow_function)] @anonymous_function)
+
 				(for_in_statement) @for_in_statement
 				;; exclude any children found in the for loop condition
 				(for_statement condition: (_) @for_statement.exclude_captures ) @for_statement
@@ -770,8 +1664,17 @@ export const syntacticallyValidAtoms: LanguageQueryMap = q({
 				;; jsx
 				(jsx_element) @jsx_element
 				(jsx_element (_ (jsx_expression) @jsx_expression))
+				;; only with \`includeJsxElements\`, see structure.ts
+				(jsx_self_closing_element) @jsx_self_closing_element
 			]
-		`
+		`,
+		treeSitterQuery.typescript`
+			;; constructor parameter properties, e.g., "private readonly foo: Foo"; parameters without modifiers are skipped
+			((method_definition
+				name: (property_identifier) @_name
+				parameters: (formal_parameters [(required_parameter) (optional_parameter)] @parameter_property))
+				(#eq? @_name "constructor"))
+		`,
 	],
 	[WASMLanguage.Python]: [
 		treeSitterQuery.python`
@@ -835,6 +1738,11 @@ export const syntacticallyValidAtoms: LanguageQueryMap = q({
 
 				(expression_statement) @expression_statement
 
+				;; function expressions and arrow functions assigned to variables or passed as arguments, e.g., \`const f = () => { ... }\` or \`setTimeout(() => { ... })\`;
+				;; only with \`includeAnonymousFunctions\`, see structure.ts
+				(variable_declarator value: [(function_expression) (arrow_function)] @anonymous_function)
+				(arguments [(function_expression) (arrow_function)] @anonymous_function)
+
 				(for_in_statement) @for_in_statement
 				;; exclude any children found in the for loop condition
 				(for_statement condition: (_) @for_statement.exclude_captures ) @for_statement
@@ -856,6 +1764,7 @@ export const syntacticallyValidAtoms: LanguageQueryMap = q({
 			(_statement) @statement
 			(function_declaration) @function_declaration
 			(import_declaration) @import_declaration
+			(import_spec) @import_spec
 			(method_declaration) @method_declaration
 			(package_clause) @package_clause
 
@@ -863,7 +1772,41 @@ export const syntacticallyValidAtoms: LanguageQueryMap = q({
 				initializer: (_) @for_statement.exclude_captures) @for_statement
 
 			(expression_case) @expression_case ;; e.g., case 0:
+			(communication_case) @communication_case ;; e.g., case v := <-ch:
+			;; only with \`includeGoCaseClauses\`, see structure.ts
+			(default_case) @default_case ;; e.g., default:
+			(type_case) @type_case ;; e.g., case int, string:
+
+			;; methods and embedded interfaces, classified in structure.ts because their node types differ between grammar versions
+			(interface_type (_) @interface_element)
+
+			(field_declaration_list (field_declaration) @field_declaration)
+
+			;; members of grouped declarations, e.g., \`var ( A int; B string )\`
+			(const_spec) @const_spec
+			(var_spec) @var_spec
+
+			;; closures, e.g., \`handler := func(w http.ResponseWriter, r *http.Request) { ... }\` or \`sort.Slice(s, func(i, j int) bool { ... })\`
+			(func_literal) @func_literal
+
+			;; anonymous struct types of fields, variables, and literals, e.g., \`Meta struct { ... }\` or \`cfg := struct{ Port int }{8080}\`
+			(field_declaration type: (struct_type) @anonymous_struct)
+			(var_spec type: (struct_type) @anonymous_struct)
+			(composite_literal type: (struct_type) @anonymous_struct)
+
+			(unary_expression operator: "<-") @channel_operation ;; e.g., <-ch
 		]
+		`,
+		treeSitterQuery.go`
+		((call_expression
+			function: (identifier) @_fn) @channel_operation
+			(#eq? @_fn "close"))
+		`,
+		treeSitterQuery.go`
+		((call_expression
+			function: (identifier) @_fn
+			arguments: (argument_list . (channel_type))) @channel_creation
+			(#eq? @_fn "make")) ;; e.g., make(chan int, 8)
 		`
 	],
 	[WASMLanguage.Ruby]: [
@@ -886,8 +1829,10 @@ export const syntacticallyValidAtoms: LanguageQueryMap = q({
 				(for) @for
 
 				(method) @method
+				(singleton_method) @singleton_method ;; e.g., def self.create
 
 				(class) @class
+				(singleton_class) @singleton_class ;; i.e., class << self
 
 				(module) @module
 
@@ -905,6 +1850,8 @@ export const syntacticallyValidAtoms: LanguageQueryMap = q({
 				(method_declaration) @method_declaration
 				(delegate_declaration) @delegate_declaration
 				(enum_declaration) @enum_declaration
+				;; only with \`includeEnumMembers\`, see structure.ts
+				(enum_member_declaration) @enum_member
 				(extern_alias_directive) @extern_alias_directive
 				(file_scoped_namespace_declaration) @file_scoped_namespace_declaration
 				(global_attribute) @global_attribute
@@ -914,6 +1861,7 @@ export const syntacticallyValidAtoms: LanguageQueryMap = q({
 				(record_declaration) @record_declaration
 				(struct_declaration) @struct_declaration
 				(using_directive) @using_directive
+				(property_declaration) @property_declaration
 
 				(local_declaration_statement) @local_declaration_statement
 				(expression_statement) @expression_statement
@@ -933,6 +1881,7 @@ export const syntacticallyValidAtoms: LanguageQueryMap = q({
 				(preproc_ifdef) @preproc_ifdef
 				(preproc_call) @preproc_call
 				(preproc_def) @preproc_def
+				(preproc_function_def) @preproc_function_def
 				(type_definition) @type_definition
 				(type_definition
 					type:(_) @type_definition.exclude_captures) @type_definition
@@ -948,9 +1897,13 @@ export const syntacticallyValidAtoms: LanguageQueryMap = q({
 				(namespace_definition) @namespace_definition
 
 				(enum_specifier) @enum_specifier
+				;; only with \`includeEnumMembers\`, see structure.ts
+				(enumerator) @enum_member
 
 				(struct_specifier) @struct_specifier
 
+				(union_specifier) @union_specifier
+
 				(template_declaration) @template_declaration
 
 				(function_definition) @function_definition
@@ -980,6 +1933,52 @@ export const syntacticallyValidAtoms: LanguageQueryMap = q({
 			]
 		`
 	],
+	[WASMLanguage.C]: [
+		treeSitterQuery.c`
+			[
+				(preproc_ifdef) @preproc_ifdef
+				(preproc_call) @preproc_call
+				(preproc_def) @preproc_def ;; object-like macros, e.g., \`#define BUFFER_SIZE 64\`
+				(preproc_function_def) @preproc_function_def ;; function-like macros, e.g., \`#define MIN(a, b) ((a) < (b) ? (a) : (b))\`
+				(type_definition) @type_definition
+				(type_definition
+					type:(_) @type_definition.exclude_captures) @type_definition
+
+				(declaration) @declaration
+
+				(expression_statement) @expression_statement
+
+				(comment) @comment
+
+				(preproc_include) @preproc_include
+
+				(enum_specifier) @enum_specifier
+				;; only with \`includeEnumMembers\`, see structure.ts
+				(enumerator) @enum_member
+
+				(struct_specifier) @struct_specifier
+
+				(union_specifier) @union_specifier
+
+				(function_definition) @function_definition
+
+				(return_statement) @return_statement
+
+				(for_statement) @for_statement
+				(for_statement
+					initializer:(_) @for_statement.exclude_captures) @for_statement
+
+				(while_statement) @while_statement
+				(do_statement) @do_statement
+				(if_statement) @if_statement
+
+				(labeled_statement) @labeled_statement
+				(goto_statement) @goto_statement
+
+				(break_statement) @break_statement
+			]
+		`
+	],
 	[WASMLanguage.Java]: [
 		treeSitterQuery.java`
 		[
@@ -993,7 +1992,14 @@ export const syntacticallyValidAtoms: LanguageQueryMap = q({
 
 			(block) @block.exclude_captures
 
+			;; type declarations at any depth, e.g., inner, nested, and local classes, of which top-level ones are statements as well
 			(class_declaration) @class_declaration
+			(interface_declaration) @interface_declaration
+			(enum_declaration) @enum_declaration
+			(record_declaration) @record_declaration
+			(annotation_type_declaration) @annotation_type_declaration
+
+			(enum_constant) @enum_constant
 
 			(constructor_declaration) @constructor_declaration
 
@@ -1004,47 +2010,410 @@ export const syntacticallyValidAtoms: LanguageQueryMap = q({
 		`
 	],
 	[WASMLanguage.Rust]: [
-		// treeSitterQuery.rust`
-		// [
-		// 	(line_comment) @line_comment
-
-		// 	(let_declaration) @let_declaration
-		// 	(extern_crate_declaration) @extern_crate_declaration
-		// 	(use_declaration) @use_declaration
-
-		// 	(attribute_item) @attribute_item
-		// 	(const_item) @const_item
-		// 	(enum_item) @enum_item
-		// 	(foreign_mod_item) @foreign_mod_item
-		// 	(function_item) @function_item
-		// 	(function_signature_item) @function_signature_item
-		// 	(impl_item) @impl_item
-		// 	(inner_attribute_item) @inner_attribute_item
-		// 	(mod_item) @mod_item
-		// 	(static_item) @static_item
-		// 	(struct_item) @struct_item
-		// 	(trait_item) @trait_item
-		// 	(type_item) @type_item
-		// 	(union_item) @union_item
-
-		// 	(macro_definition) @macro_definition
-
-		// 	(empty_statement) @empty_statement
-
-		// 	(compound_assignment_expr) @compound_assignment_expr
-		// 	(generic_function) @generic_function
-		// 	(metavariable) @metavariable
-
-		// 	(match_arm) @match_arm
-
-		// 	(async_block) @async_block
-		// 	(const_block) @const_block
-		// 	(unsafe_block) @unsafe_block
-
-		// 	(block) @block.exclude_captures
-		// ]
-		// `
-	]
+		treeSitterQuery.rust`
+		[
+			(line_comment) @line_comment
+			(block_comment) @block_comment
+
+			(use_declaration) @use_declaration
+			(extern_crate_declaration) @extern_crate_declaration
+
+			(const_item) @const_item
+			(static_item) @static_item
+			(type_item) @type_item
+
+			(struct_item) @struct_item
+			(union_item) @union_item
+			(field_declaration) @field_declaration
+			(enum_item) @enum_item
+			(enum_variant) @enum_variant
+			(trait_item) @trait_item
+			(impl_item) @impl_item
+
+			(function_item) @function_item
+			(function_signature_item) @function_signature_item
+
+			(mod_item) @mod_item
+			(macro_definition) @macro_definition
+		]
+		`
+	],
+	[WASMLanguage.Swift]: [
+		treeSitterQuery.swift`
+		[
+			(comment) @comment
+			(multiline_comment) @multiline_comment
+
+			(import_declaration) @import_declaration
+
+			;; class, struct, enum, actor, and extension declarations all are `class_declaration`s
+			(class_declaration) @class_declaration
+			(protocol_declaration) @protocol_declaration
+			(protocol_function_declaration) @protocol_function_declaration
+			(protocol_property_declaration) @protocol_property_declaration
+			(typealias_declaration) @typealias_declaration
+
+			(function_declaration) @function_declaration
+			(init_declaration) @init_declaration
+			(deinit_declaration) @deinit_declaration
+			(property_declaration) @property_declaration
+			(enum_entry) @enum_entry
+
+			;; statements
+			(if_statement) @if_statement
+			(guard_statement) @guard_statement
+			(for_statement) @for_statement
+			(while_statement) @while_statement
+			(switch_statement) @switch_statement
+			(control_transfer_statement) @control_transfer_statement
+		]
+		`
+	],
+	[WASMLanguage.Kotlin]: [
+		treeSitterQuery.kotlin`
+		[
+			(line_comment) @line_comment
+			(multiline_comment) @multiline_comment
+
+			(package_header) @package_header
+			(import_list) @import_list
+
+			;; class and interface declarations both are `class_declaration`s
+			(class_declaration) @class_declaration
+			(object_declaration) @object_declaration
+			(companion_object) @companion_object
+			(type_alias) @type_alias
+
+			(function_declaration) @function_declaration
+			(secondary_constructor) @secondary_constructor
+			(anonymous_initializer) @anonymous_initializer
+			(property_declaration) @property_declaration
+			(enum_entry) @enum_entry
+		]
+		`
+	],
+	[WASMLanguage.Shell]: [
+		treeSitterQuery.bash`
+		[
+			(comment) @comment
+
+			;; with or without the \`function\` keyword, and with a \`{ ... }\` or \`( ... )\` body
+			(function_definition) @function_definition
+
+			;; only top-level variables, e.g., \`VERSION=1.0\` or \`export PATH\`, are declarations of the script
+			(program (variable_assignment) @variable_assignment)
+			(program (declaration_command) @declaration_command)
+		]
+		`,
+		// includes of other scripts, e.g., `source ./lib.sh` or `. ./lib.sh`
+		treeSitterQuery.bash`
+		((command
+			name: (command_name) @_name) @source_command
+			(#match? @_name "^(source|[.])$"))
+		`
+	],
+	[WASMLanguage.Dart]: [
+		treeSitterQuery.dart`
+		[
+			(comment) @comment
+			(documentation_comment) @documentation_comment
+
+			(import_or_export) @import_or_export
+
+			(class_definition) @class_definition
+			(mixin_declaration) @mixin_declaration
+			(extension_declaration) @extension_declaration
+			(enum_declaration) @enum_declaration
+			(enum_constant) @enum_constant
+
+			;; top-level functions, getters, and setters; their bodies are their next siblings
+			(program (function_signature) @function_signature)
+			(program (getter_signature) @getter_signature)
+			(program (setter_signature) @setter_signature)
+
+			;; members are wrapped in a \`method_signature\` with their modifiers if they have a body, e.g., \`static int count() { ... }\`,
+			;; and in a \`declaration\` otherwise, e.g., \`Foo.named() : super();\` or an abstract method; fields aren't captured
+			(method_signature
+				[
+					(constructor_signature)
+					(factory_constructor_signature)
+					(function_signature)
+					(getter_signature)
+					(setter_signature)
+					(operator_signature)
+				] @member)
+			(declaration
+				[
+					(constructor_signature)
+					(constant_constructor_signature)
+					(factory_constructor_signature)
+					(redirecting_factory_constructor_signature)
+					(function_signature)
+					(getter_signature)
+					(setter_signature)
+					(operator_signature)
+				] @member)
+		]
+		`
+	],
+	[WASMLanguage.Json]: [
+		treeSitterQuery.json`
+		[
+			;; keys of objects, e.g., \`"compilerOptions": { ... }\`; comments, which jsonc allows, aren't captured
+			(pair) @pair
+
+			(array (_value) @array_element) ;; i.e., not a comment
+		]
+		`
+	],
+	[WASMLanguage.Yaml]: [
+		treeSitterQuery.yaml`
+		[
+			;; documents of a stream, e.g., separated by \`---\`, if there are several
+			(document) @document
+
+			;; keys of mappings, e.g., \`jobs:\` or \`{ name: foo }\`
+			(block_mapping_pair) @pair
+			(flow_pair) @pair
+
+			;; items of sequences, e.g., \`- run: npm test\` or \`[a, b]\`
+			(block_sequence_item) @array_element
+			(flow_sequence (flow_node) @array_element)
+		]
+		`
+	],
+	[WASMLanguage.Php]: [
+		treeSitterQuery.php`
+		[
+			(comment) @comment
+
+			;; code outside of \`<?php ... ?>\`, e.g., HTML, is \`text\` and isn't captured
+			(namespace_definition) @namespace_definition
+			(namespace_use_declaration) @namespace_use_declaration ;; e.g., \`use App\\Models\\User;\`
+
+			(class_declaration) @class_declaration
+			(interface_declaration) @interface_declaration
+			(trait_declaration) @trait_declaration
+			(enum_declaration) @enum_declaration
+			(enum_case) @enum_case
+			(function_definition) @function_definition
+
+			(method_declaration) @method_declaration
+			(property_declaration) @property_declaration
+			(const_declaration) @const_declaration
+			(use_declaration) @use_declaration ;; traits used by a class, e.g., \`use HasTimestamps;\`
+		]
+		`
+	],
+	[WASMLanguage.Lua]: [
+		treeSitterQuery.lua`
+		[
+			(comment) @comment
+
+			(function_declaration) @function_declaration ;; including \`local function\` and methods, e.g., \`function Account:deposit(v)\`
+
+			;; top-level variables, e.g., \`local M = {}\` or \`VERSION = "1.0"\`
+			(chunk (variable_declaration) @variable_declaration)
+			(chunk (assignment_statement) @assignment_statement)
+			;; functions assigned within blocks, e.g., \`local handler = function(buf) ... end\` within a function
+			(block (variable_declaration (assignment_statement (expression_list (function_definition)))) @variable_declaration)
+			(block (assignment_statement (expression_list (function_definition))) @assignment_statement)
+			;; functions of table constructors, e.g., \`{ on_save = function(buf) ... end }\`
+			(field value: (function_definition)) @field
+		]
+		`
+	],
+	[WASMLanguage.Hcl]: [
+		treeSitterQuery.hcl`
+		[
+			(comment) @comment
+
+			;; blocks at any depth, e.g., \`resource "aws_instance" "web" { ... }\` or a \`lifecycle { ... }\` within it
+			(block) @block
+
+			;; attributes at any depth, e.g., \`region = "eu-west-1"\` in a \`.tfvars\` file or the arguments of a block, but not the elements of an object,
+			;; which aren't attributes
+			(attribute) @attribute
+		]
+		`
+	],
+	[WASMLanguage.Sql]: [
+		treeSitterQuery.sql`
+		[
+			(comment) @comment
+			(marginalia) @comment
+
+			;; statements, each up to its \`;\`, e.g., \`CREATE TABLE users (...);\` or \`SELECT ... FROM users;\`
+			(statement) @statement
+
+			;; columns of a \`CREATE TABLE\`, e.g., \`email VARCHAR(255) NOT NULL\`
+			(column_definition) @column_definition
+		]
+		`
+	],
+	[WASMLanguage.Graphql]: [
+		treeSitterQuery.graphql`
+		[
+			(comment) @comment
+
+			;; type system definitions, e.g., \`type User implements Node { ... }\` or \`directive @auth on FIELD_DEFINITION\`
+			(object_type_definition) @object_type_definition
+			(interface_type_definition) @interface_type_definition
+			(input_object_type_definition) @input_object_type_definition
+			(enum_type_definition) @enum_type_definition
+			(union_type_definition) @union_type_definition
+			(scalar_type_definition) @scalar_type_definition
+			(directive_definition) @directive_definition
+
+			;; the root operation types, e.g., \`schema { query: RootQuery }\`
+			(schema_definition) @schema_definition
+			(root_operation_type_definition) @root_operation_type_definition
+
+			;; members, i.e., fields, input fields, and enum values; the arguments of a field aren't nodes of their own
+			(field_definition) @field_definition
+			(input_fields_definition (input_value_definition) @input_value_definition)
+			(enum_value_definition) @enum_value_definition
+
+			;; executable definitions, e.g., \`query GetUser($id: ID!) { ... }\` or \`fragment UserFields on User { ... }\`
+			(operation_definition) @operation_definition
+			(fragment_definition) @fragment_definition
+		]
+		`
+	],
+	[WASMLanguage.Toml]: [
+		treeSitterQuery.toml`
+		[
+			;; tables, e.g., \`[tool.poetry]\` or \`[[bin]]\`, which are siblings even if their keys nest
+			(table) @table
+			(table_array_element) @table_array_element
+
+			;; keys, e.g., \`name = "app"\` or those within \`{ x = 1, y = 2 }\`
+			(pair) @pair
+
+			;; values of arrays, i.e., not comments
+			(array [(string) (integer) (float) (boolean) (offset_date_time) (local_date_time) (local_date) (local_time) (array) (inline_table)] @array_element)
+		]
+		`
+	],
+	[WASMLanguage.Scala]: [
+		treeSitterQuery.scala`
+		[
+			(comment) @comment
+			(block_comment) @block_comment
+
+			(package_clause) @package_clause
+			(import_declaration) @import_declaration
+
+			;; case classes and objects are told apart by their \`case\` keyword, see \`StructureComputer\`
+			(class_definition) @class_definition
+			(object_definition) @object_definition
+			(trait_definition) @trait_definition
+
+			;; abstract members, e.g., \`def area: Double\` within a trait, are declarations
+			(function_definition) @function_definition
+			(function_declaration) @function_declaration
+			(val_definition) @val_definition
+			(val_declaration) @val_declaration
+			(var_definition) @var_definition
+			(var_declaration) @var_declaration
+		]
+		`
+	],
+	[WASMLanguage.R]: [
+		treeSitterQuery.r`
+		[
+			(comment) @comment
+
+			;; top-level assignments, e.g., \`area <- function(shape) { ... }\`, \`bar = function(x) x\`, or \`1 -> n\`;
+			;; they're told apart from functions by their values, see \`StructureComputer\`
+			(program
+				(binary_operator
+					lhs: [(identifier) (string)]
+					operator: ["<-" "<<-" "=" ":="]) @assignment)
+			(program
+				(binary_operator
+					operator: ["->" "->>"]
+					rhs: (identifier)) @assignment)
+
+			;; functions assigned within functions, e.g., \`helper <- function(x) { ... }\` within a body
+			(braced_expression
+				(binary_operator
+					lhs: (identifier)
+					operator: ["<-" "<<-" "="]
+					rhs: (function_definition)) @assignment)
+		]
+		`,
+		// S4 classes, generics, and methods, e.g., `setMethod("area", "Circle", function(shape) { ... })`
+		treeSitterQuery.r`
+		((call
+			function: (identifier) @_fn) @s4_call
+			(#match? @_fn "^(setClass|setRefClass|setGeneric|setMethod)$"))
+		`
+	],
+	[WASMLanguage.ObjectiveC]: [
+		treeSitterQuery.objc`
+			[
+				(comment) @comment
+
+				(preproc_include) @preproc_include
+				(preproc_def) @preproc_def
+				(type_definition) @type_definition
+				(declaration) @declaration
+
+				(enum_specifier) @enum_specifier
+				(struct_specifier) @struct_specifier
+				(function_definition) @function_definition
+
+				;; \`@interface Widget : NSObject ... @end\` and \`@implementation Widget ... @end\`, also of categories, e.g., \`@interface Widget (Drawing)\`
+				(class_interface) @class_interface
+				(class_implementation) @class_implementation
+				(protocol_declaration) @protocol_declaration
+
+				(property_declaration) @property_declaration
+				;; instance methods, e.g., \`- (void)draw;\`, and class methods, e.g., \`+ (instancetype)widget;\`
+				(method_declaration) @method_declaration
+				(method_definition) @method_definition
+			]
+		`
+	],
+	[WASMLanguage.Protobuf]: [
+		treeSitterQuery.proto`
+		[
+			(comment) @comment
+
+			;; \`syntax = "proto2";\` or \`syntax = "proto3";\`, which tells whether fields may be \`required\`
+			(syntax) @syntax
+			(package) @package
+			(import) @import
+
+			;; messages and enums at any depth, e.g., \`message Item { ... }\` within \`message Order { ... }\`
+			(message) @message
+			(enum) @enum
+			(service) @service
+			(rpc) @rpc
+
+			;; members of messages, e.g., \`repeated Item items = 3;\` or \`map<string, int32> counts = 4;\`, and the fields of their oneofs
+			(field) @field
+			(map_field) @map_field
+			(oneof) @oneof
+			(oneof_field) @oneof_field
+			(enum_field) @enum_field
+		]
+		`
+	],
+	[WASMLanguage.Ini]: [
+		treeSitterQuery.ini`
+		[
+			(comment) @comment
+
+			;; sections, e.g., \`[database]\`, which span their settings
+			(section) @section
+
+			;; settings, e.g., \`host = localhost\`, within their sections
+			(setting) @pair
+		]
+		`
+	],
 });
 
 export const coarseScopeTypes: { [wasmLanguage in WASMLanguage]: string[] } = {
@@ -1075,6 +2444,10 @@ export const coarseScopeTypes: { [wasmLanguage in WASMLanguage]: string[] } = {
 		'class_specifier',
 		'function_definition',
 	],
+	[WASMLanguage.C]: [
+		'translation_unit',
+		'function_definition',
+	],
 	[WASMLanguage.Csharp]: [
 		'compilation_unit',
 		'class_declaration',
@@ -1104,6 +2477,109 @@ export const coarseScopeTypes: { [wasmLanguage in WASMLanguage]: string[] } = {
 		'impl_item',
 		'let_declaration',
 	],
+	[WASMLanguage.Swift]: [
+		'source_file',
+		'class_declaration',
+		'protocol_declaration',
+		'function_declaration',
+		'init_declaration',
+	],
+	[WASMLanguage.Kotlin]: [
+		'source_file',
+		'class_declaration',
+		'object_declaration',
+		'companion_object',
+		'function_declaration',
+	],
+	[WASMLanguage.Shell]: [
+		'program',
+		'function_definition',
+	],
+	[WASMLanguage.Dart]: [
+		'program',
+		'class_definition',
+		'mixin_declaration',
+		'extension_declaration',
+		'enum_declaration',
+		'function_body', // signatures of functions are its previous siblings, so the body is the scope
+	],
+	[WASMLanguage.Json]: [
+		'document',
+		'object',
+		'array',
+	],
+	[WASMLanguage.Yaml]: [
+		'stream',
+		'block_mapping',
+		'block_sequence',
+		'flow_mapping',
+		'flow_sequence',
+	],
+	[WASMLanguage.Php]: [
+		'program',
+		'namespace_definition',
+		'class_declaration',
+		'interface_declaration',
+		'trait_declaration',
+		'enum_declaration',
+		'function_definition',
+		'method_declaration',
+	],
+	[WASMLanguage.Lua]: [
+		'chunk',
+		'function_declaration',
+		'function_definition',
+	],
+	[WASMLanguage.Hcl]: [
+		'config_file',
+		'block',
+	],
+	[WASMLanguage.Sql]: [
+		'program',
+		'statement',
+	],
+	[WASMLanguage.Graphql]: [
+		'source_file',
+		'object_type_definition',
+		'interface_type_definition',
+		'input_object_type_definition',
+		'enum_type_definition',
+		'operation_definition',
+		'fragment_definition',
+	],
+	[WASMLanguage.Toml]: [
+		'document',
+		'table',
+		'table_array_element',
+		'inline_table',
+		'array',
+	],
+	[WASMLanguage.Scala]: [
+		'compilation_unit',
+		'class_definition',
+		'object_definition',
+		'trait_definition',
+		'function_definition',
+	],
+	[WASMLanguage.R]: [
+		'program',
+		'function_definition',
+	],
+	[WASMLanguage.ObjectiveC]: [
+		'translation_unit',
+		'function_definition',
+		'method_definition',
+	],
+	[WASMLanguage.Protobuf]: [
+		'source_file',
+		'message',
+		'enum',
+		'service',
+	],
+	[WASMLanguage.Ini]: [
+		'document',
+		'section',
+	],
 };
 
 export const coarseScopesQuery: LanguageQueryMap = q({
@@ -1122,6 +2598,9 @@ export const coarseScopesQuery: LanguageQueryMap = q({
 	[WASMLanguage.Cpp]: [
 		coarseScopesQueryForLanguage(WASMLanguage.Cpp)
 	],
+	[WASMLanguage.C]: [
+		coarseScopesQueryForLanguage(WASMLanguage.C)
+	],
 	[WASMLanguage.Csharp]: [
 		coarseScopesQueryForLanguage(WASMLanguage.Csharp)
 	],
@@ -1137,6 +2616,57 @@ export const coarseScopesQuery: LanguageQueryMap = q({
 	[WASMLanguage.Rust]: [
 		coarseScopesQueryForLanguage(WASMLanguage.Rust)
 	],
+	[WASMLanguage.Swift]: [
+		coarseScopesQueryForLanguage(WASMLanguage.Swift)
+	],
+	[WASMLanguage.Kotlin]: [
+		coarseScopesQueryForLanguage(WASMLanguage.Kotlin)
+	],
+	[WASMLanguage.Shell]: [
+		coarseScopesQueryForLanguage(WASMLanguage.Shell)
+	],
+	[WASMLanguage.Dart]: [
+		coarseScopesQueryForLanguage(WASMLanguage.Dart)
+	],
+	[WASMLanguage.Json]: [
+		coarseScopesQueryForLanguage(WASMLanguage.Json)
+	],
+	[WASMLanguage.Yaml]: [
+		coarseScopesQueryForLanguage(WASMLanguage.Yaml)
+	],
+	[WASMLanguage.Php]: [
+		coarseScopesQueryForLanguage(WASMLanguage.Php)
+	],
+	[WASMLanguage.Lua]: [
+		coarseScopesQueryForLanguage(WASMLanguage.Lua)
+	],
+	[WASMLanguage.Hcl]: [
+		coarseScopesQueryForLanguage(WASMLanguage.Hcl)
+	],
+	[WASMLanguage.Sql]: [
+		coarseScopesQueryForLanguage(WASMLanguage.Sql)
+	],
+	[WASMLanguage.Graphql]: [
+		coarseScopesQueryForLanguage(WASMLanguage.Graphql)
+	],
+	[WASMLanguage.Toml]: [
+		coarseScopesQueryForLanguage(WASMLanguage.Toml)
+	],
+	[WASMLanguage.Scala]: [
+		coarseScopesQueryForLanguage(WASMLanguage.Scala)
+	],
+	[WASMLanguage.R]: [
+		coarseScopesQueryForLanguage(WASMLanguage.R)
+	],
+	[WASMLanguage.ObjectiveC]: [
+		coarseScopesQueryForLanguage(WASMLanguage.ObjectiveC)
+	],
+	[WASMLanguage.Protobuf]: [
+		coarseScopesQueryForLanguage(WASMLanguage.Protobuf)
+	],
+	[WASMLanguage.Ini]: [
+		coarseScopesQueryForLanguage(WASMLanguage.Ini)
+	],
 });
 
 export const fineScopeTypes: { [wasmLanguage in WASMLanguage]: string[] } = {
@@ -1167,6 +2697,13 @@ export const fineScopeTypes: { [wasmLanguage in WASMLanguage]: string[] } = {
 		'try_statement',
 		'switch_statement'
 	],
+	[WASMLanguage.C]: [
+		'for_statement',
+		'if_statement',
+		'while_statement',
+		'do_statement',
+		'switch_statement'
+	],
 	[WASMLanguage.Csharp]: [
 		'for_statement',
 		'for_each_statement',
@@ -1200,6 +2737,81 @@ export const fineScopeTypes: { [wasmLanguage in WASMLanguage]: string[] } = {
 		'loop_statement',
 		'match_expression',
 	],
+	[WASMLanguage.Swift]: [
+		'for_statement',
+		'if_statement',
+		'guard_statement',
+		'while_statement',
+		'switch_statement',
+	],
+	[WASMLanguage.Kotlin]: [
+		'for_statement',
+		'while_statement',
+		'do_while_statement',
+		'if_expression',
+		'when_expression',
+		'try_expression',
+	],
+	[WASMLanguage.Shell]: [
+		'if_statement',
+		'for_statement',
+		'c_style_for_statement',
+		'while_statement',
+		'case_statement',
+		'subshell',
+	],
+	[WASMLanguage.Dart]: [
+		'if_statement',
+		'for_statement',
+		'while_statement',
+		'do_statement',
+		'switch_statement',
+		'try_statement',
+	],
+	[WASMLanguage.Json]: [],
+	[WASMLanguage.Yaml]: [],
+	[WASMLanguage.Php]: [
+		'if_statement',
+		'for_statement',
+		'foreach_statement',
+		'while_statement',
+		'do_statement',
+		'switch_statement',
+		'try_statement',
+	],
+	[WASMLanguage.Lua]: [
+		'if_statement',
+		'for_statement',
+		'while_statement',
+		'repeat_statement',
+		'do_statement',
+	],
+	[WASMLanguage.Hcl]: [],
+	[WASMLanguage.Sql]: [],
+	[WASMLanguage.Graphql]: [],
+	[WASMLanguage.Toml]: [],
+	[WASMLanguage.Scala]: [
+		'for_expression',
+		'while_expression',
+		'if_expression',
+		'match_expression',
+		'try_expression',
+	],
+	[WASMLanguage.R]: [
+		'for_statement',
+		'while_statement',
+		'repeat_statement',
+		'if_statement',
+	],
+	[WASMLanguage.ObjectiveC]: [
+		'for_statement',
+		'if_statement',
+		'while_statement',
+		'do_statement',
+		'switch_statement'
+	],
+	[WASMLanguage.Protobuf]: [],
+	[WASMLanguage.Ini]: [],
 };
 
 export const statementTypes: { [wasmLanguage in WASMLanguage]: string[] } = {
@@ -1219,11 +2831,11 @@ export const statementTypes: { [wasmLanguage in WASMLanguage]: string[] } = {
 		'local_variable_declaration',
 		'field_declaration'
 	],
-	[WASMLanguage.Cpp]: [
+	...forLanguages([WASMLanguage.Cpp, WASMLanguage.C, WASMLanguage.ObjectiveC], [
 		'field_declaration',
 		'expression_statement',
 		'declaration'
-	],
+	]),
 	[WASMLanguage.Csharp]: [
 		'field_declaration',
 		'expression_statement'
@@ -1247,6 +2859,81 @@ export const statementTypes: { [wasmLanguage in WASMLanguage]: string[] } = {
 		'macro_definition',
 		'extern_crate_declaration'
 	],
+	[WASMLanguage.Swift]: [
+		'property_declaration',
+		'call_expression',
+	],
+	[WASMLanguage.Kotlin]: [
+		'property_declaration',
+		'assignment',
+		'call_expression',
+	],
+	[WASMLanguage.Shell]: [
+		'command',
+		'pipeline',
+		'variable_assignment',
+		'declaration_command',
+	],
+	[WASMLanguage.Dart]: [
+		'local_variable_declaration',
+		'expression_statement',
+		'return_statement',
+	],
+	[WASMLanguage.Json]: [
+		'pair',
+	],
+	[WASMLanguage.Yaml]: [
+		'block_mapping_pair',
+		'block_sequence_item',
+	],
+	[WASMLanguage.Php]: [
+		'expression_statement',
+		'return_statement',
+		'property_declaration',
+		'const_declaration',
+	],
+	[WASMLanguage.Lua]: [
+		'assignment_statement',
+		'variable_declaration',
+		'function_call',
+		'return_statement',
+	],
+	[WASMLanguage.Hcl]: [
+		'attribute',
+		'block',
+	],
+	[WASMLanguage.Sql]: [
+		'statement',
+	],
+	[WASMLanguage.Graphql]: [
+		'field_definition',
+		'input_value_definition',
+		'enum_value_definition',
+		'field',
+	],
+	[WASMLanguage.Toml]: [
+		'pair',
+	],
+	[WASMLanguage.Scala]: [
+		'val_definition',
+		'var_definition',
+		'assignment_expression',
+		'call_expression',
+	],
+	[WASMLanguage.R]: [
+		'binary_operator',
+		'call',
+	],
+	[WASMLanguage.Protobuf]: [
+		'field',
+		'map_field',
+		'oneof_field',
+		'enum_field',
+		'rpc',
+	],
+	[WASMLanguage.Ini]: [
+		'setting',
+	],
 };
 
 const semanticChunkTargetTypes: { [wasmLanguage in WASMLanguage]: string[] } = {
@@ -1281,6 +2968,10 @@ const semanticChunkTargetTypes: { [wasmLanguage in WASMLanguage]: string[] } = {
 		'namespace_definition',
 		'struct_specifier'
 	],
+	[WASMLanguage.C]: [
+		'function_definition',
+		'struct_specifier'
+	],
 	[WASMLanguage.Csharp]: [
 		'class_declaration',
 		'constructor_declaration',
@@ -1312,6 +3003,375 @@ const semanticChunkTargetTypes: { [wasmLanguage in WASMLanguage]: string[] } = {
 		'trait_item',
 		'union_item',
 	],
+	[WASMLanguage.Swift]: [
+		'class_declaration',
+		'protocol_declaration',
+		'function_declaration',
+		'init_declaration',
+	],
+	[WASMLanguage.Kotlin]: [
+		'class_declaration',
+		'object_declaration',
+		'companion_object',
+		'function_declaration',
+	],
+	[WASMLanguage.Shell]: [
+		'function_definition',
+	],
+	[WASMLanguage.Dart]: [
+		'class_definition',
+		'mixin_declaration',
+		'extension_declaration',
+		'enum_declaration',
+	],
+	[WASMLanguage.Json]: [],
+	[WASMLanguage.Yaml]: [],
+	[WASMLanguage.Php]: [
+		'class_declaration',
+		'interface_declaration',
+		'trait_declaration',
+		'enum_declaration',
+		'function_definition',
+		'method_declaration',
+	],
+	[WASMLanguage.Lua]: [
+		'function_declaration',
+	],
+	[WASMLanguage.Hcl]: [
+		'block',
+	],
+	[WASMLanguage.Sql]: [
+		'statement',
+	],
+	[WASMLanguage.Graphql]: [
+		'object_type_definition',
+		'interface_type_definition',
+		'input_object_type_definition',
+		'enum_type_definition',
+		'union_type_definition',
+		'scalar_type_definition',
+		'directive_definition',
+		'operation_definition',
+		'fragment_definition',
+	],
+	[WASMLanguage.Toml]: [
+		'table',
+		'table_array_element',
+	],
+	[WASMLanguage.Scala]: [
+		'class_definition',
+		'object_definition',
+		'trait_definition',
+		'function_definition',
+	],
+	[WASMLanguage.R]: [
+		'function_definition',
+	],
+	[WASMLanguage.ObjectiveC]: [
+		'function_definition',
+		'struct_specifier',
+		'class_interface',
+		'class_implementation',
+		'protocol_declaration',
+	],
+	[WASMLanguage.Protobuf]: [
+		'message',
+		'enum',
+		'service',
+	],
+	[WASMLanguage.Ini]: [
+		'section',
+	],
+};
+
+/**
+ * Kinds of structure nodes that `_getSemanticChunks` splits at the boundaries of their children if they're too large to be a single chunk,
+ * and that `walkContainers` descends into, e.g., declarations of types and functions; other nodes are split at line breaks,
+ * e.g., an oversized `if` statement rather than at the statements of its body. The root of a structure always is a container.
+ *
+ * @remarks These are kinds of structure nodes, which may be more specific than the types of their syntax nodes, e.g., `constructor`.
+ */
+export const chunkContainerKinds: { [wasmLanguage in WASMLanguage]: readonly string[] } = {
+	...forLanguages([WASMLanguage.TypeScript, WASMLanguage.TypeScriptTsx], [
+		'internal_module', 'module',
+		'class_declaration', 'abstract_class_declaration', 'interface_declaration', 'enum_declaration',
+		'function_declaration', 'generator_function_declaration', 'method_definition', 'constructor',
+		// with `includeAnonymousFunctions`
+		'arrow_function', 'function_expression',
+	]),
+	[WASMLanguage.JavaScript]: [
+		'class_declaration',
+		'function_declaration', 'generator_function_declaration', 'method_definition', 'constructor',
+		'arrow_function', 'function_expression',
+	],
+	[WASMLanguage.Java]: [
+		'class_declaration', 'interface_declaration', 'enum_declaration', 'record_declaration',
+		'method_declaration', 'constructor_declaration',
+	],
+	[WASMLanguage.Cpp]: [
+		'namespace_definition', 'template_declaration',
+		'class_specifier', 'struct_specifier', 'union_specifier',
+		'function_definition',
+	],
+	[WASMLanguage.C]: [
+		'struct_specifier', 'union_specifier',
+		'function_definition',
+	],
+	[WASMLanguage.Csharp]: [
+		'namespace_declaration', 'file_scoped_namespace_declaration',
+		'class_declaration', 'struct_declaration', 'interface_declaration', 'record_declaration',
+		'method_declaration', 'constructor_declaration', 'local_function_statement',
+	],
+	[WASMLanguage.Python]: [
+		'class_definition', 'function_definition', 'decorated_definition',
+	],
+	[WASMLanguage.Go]: [
+		// groups, e.g., `var ( ... )`, are split at their specs
+		'type_declaration', 'const_declaration', 'var_declaration',
+		'function_declaration', 'method_declaration', 'func_literal',
+	],
+	[WASMLanguage.Ruby]: [
+		'module', 'class', 'singleton_class',
+		'method', 'singleton_method',
+	],
+	[WASMLanguage.Rust]: [
+		'mod_item',
+		'struct_item', 'enum_item', 'union_item', 'trait_item', 'impl_item',
+		'function_item', 'method_item', 'associated_function_item',
+	],
+	[WASMLanguage.Swift]: [
+		'class_declaration', 'struct_declaration', 'enum_declaration', 'actor_declaration', 'extension_declaration', 'protocol_declaration',
+		'function_declaration', 'init_declaration',
+	],
+	[WASMLanguage.Kotlin]: [
+		'class_declaration', 'interface_declaration', 'object_declaration', 'companion_object',
+		'function_declaration', 'secondary_constructor',
+	],
+	[WASMLanguage.Shell]: [
+		'function_definition',
+	],
+	[WASMLanguage.Dart]: [
+		'class_definition', 'mixin_declaration', 'extension_declaration', 'enum_declaration',
+		'function_signature', 'method_signature',
+	],
+	// large documents are split at the keys of the objects and the elements of the arrays they nest
+	[WASMLanguage.Json]: [
+		'pair', 'array_element',
+	],
+	[WASMLanguage.Yaml]: [
+		'document', 'pair', 'array_element',
+	],
+	[WASMLanguage.Php]: [
+		'namespace_definition',
+		'class_declaration', 'interface_declaration', 'trait_declaration', 'enum_declaration',
+		'function_definition', 'method_declaration', 'constructor',
+	],
+	[WASMLanguage.Lua]: [
+		// functions and the tables they're assigned to or declared in, e.g., `local M = { ... }`
+		'function_declaration', 'variable_declaration', 'assignment_statement', 'field',
+	],
+	[WASMLanguage.Hcl]: [
+		'block',
+	],
+	[WASMLanguage.Sql]: [
+		'create_table',
+	],
+	[WASMLanguage.Graphql]: [
+		'object_type_definition', 'interface_type_definition', 'input_object_type_definition', 'enum_type_definition',
+		'operation_definition', 'fragment_definition',
+	],
+	[WASMLanguage.Toml]: [
+		'table', 'table_array_element', 'pair', 'array_element',
+	],
+	[WASMLanguage.Scala]: [
+		'package_clause',
+		'class_definition', 'case_class_definition', 'object_definition', 'case_object_definition', 'trait_definition',
+		'function_definition',
+	],
+	[WASMLanguage.R]: [
+		'function_definition',
+		'class_definition', 'generic_definition', 'method_definition',
+	],
+	[WASMLanguage.ObjectiveC]: [
+		'struct_specifier', 'class_interface', 'class_implementation', 'protocol_declaration',
+		'function_definition', 'method_definition',
+	],
+	[WASMLanguage.Protobuf]: [
+		'message', 'enum', 'service', 'oneof',
+	],
+	[WASMLanguage.Ini]: [
+		'section',
+	],
+};
+
+/**
+ * Declarations that can enclose a position, see `_getNodeContext`.
+ * `package` declarations don't enclose anything but apply to the whole file, e.g., Go's `package main`.
+ */
+export const enclosingDeclarationTypes: { [wasmLanguage in WASMLanguage]: { function: string[]; type: string[]; namespace: string[]; package: string[] } } = {
+	...forLanguages([WASMLanguage.TypeScript, WASMLanguage.TypeScriptTsx], {
+		function: ['function_declaration', 'generator_function_declaration', 'function_expression', 'arrow_function', 'method_definition'],
+		type: ['class_declaration', 'abstract_class_declaration', 'class', 'interface_declaration', 'enum_declaration', 'type_alias_declaration'],
+		namespace: ['internal_module', 'module'],
+		package: [],
+	}),
+	[WASMLanguage.JavaScript]: {
+		function: ['function_declaration', 'generator_function_declaration', 'function_expression', 'arrow_function', 'method_definition'],
+		type: ['class_declaration', 'class'],
+		namespace: [],
+		package: [],
+	},
+	[WASMLanguage.Java]: {
+		function: ['method_declaration', 'constructor_declaration'],
+		type: ['class_declaration', 'interface_declaration', 'enum_declaration', 'record_declaration'],
+		namespace: [],
+		package: ['package_declaration'],
+	},
+	[WASMLanguage.Cpp]: {
+		function: ['function_definition'],
+		type: ['class_specifier', 'struct_specifier', 'union_specifier', 'enum_specifier'],
+		namespace: ['namespace_definition'],
+		package: [],
+	},
+	[WASMLanguage.C]: {
+		function: ['function_definition'],
+		type: ['struct_specifier', 'union_specifier', 'enum_specifier'],
+		namespace: [],
+		package: [],
+	},
+	[WASMLanguage.Csharp]: {
+		function: ['method_declaration', 'constructor_declaration', 'local_function_statement'],
+		type: ['class_declaration', 'struct_declaration', 'interface_declaration', 'enum_declaration', 'record_declaration'],
+		namespace: ['namespace_declaration', 'file_scoped_namespace_declaration'],
+		package: ['file_scoped_namespace_declaration'],
+	},
+	[WASMLanguage.Python]: {
+		function: ['function_definition'],
+		type: ['class_definition'],
+		namespace: [],
+		package: [],
+	},
+	[WASMLanguage.Go]: {
+		function: ['function_declaration', 'method_declaration'],
+		type: ['type_spec', 'type_alias'],
+		namespace: [],
+		package: ['package_clause'],
+	},
+	[WASMLanguage.Ruby]: {
+		function: ['method', 'singleton_method'],
+		type: ['class'],
+		namespace: ['module'],
+		package: [],
+	},
+	[WASMLanguage.Rust]: {
+		function: ['function_item'],
+		type: ['struct_item', 'enum_item', 'union_item', 'trait_item', 'impl_item'],
+		namespace: ['mod_item'],
+		package: [],
+	},
+	[WASMLanguage.Swift]: {
+		function: ['function_declaration', 'init_declaration'],
+		type: ['class_declaration', 'protocol_declaration'],
+		namespace: [],
+		package: [],
+	},
+	[WASMLanguage.Kotlin]: {
+		function: ['function_declaration', 'secondary_constructor'],
+		type: ['class_declaration', 'object_declaration', 'companion_object'],
+		namespace: [],
+		package: ['package_header'],
+	},
+	[WASMLanguage.Shell]: {
+		function: ['function_definition'],
+		type: [],
+		namespace: [],
+		package: [],
+	},
+	[WASMLanguage.Dart]: {
+		// the body of a function is the next sibling of its signature, so no node encloses both
+		function: [],
+		type: ['class_definition', 'mixin_declaration', 'extension_declaration', 'enum_declaration'],
+		namespace: [],
+		package: [],
+	},
+	[WASMLanguage.Json]: {
+		function: [],
+		type: [],
+		namespace: [],
+		package: [],
+	},
+	[WASMLanguage.Yaml]: {
+		function: [],
+		type: [],
+		namespace: [],
+		package: [],
+	},
+	[WASMLanguage.Php]: {
+		function: ['function_definition', 'method_declaration'],
+		type: ['class_declaration', 'interface_declaration', 'trait_declaration', 'enum_declaration'],
+		namespace: ['namespace_definition'],
+		package: [],
+	},
+	[WASMLanguage.Lua]: {
+		function: ['function_declaration', 'function_definition'],
+		type: [],
+		namespace: [],
+		package: [],
+	},
+	[WASMLanguage.Hcl]: {
+		function: [],
+		type: [],
+		namespace: [],
+		package: [],
+	},
+	[WASMLanguage.Sql]: {
+		function: [],
+		type: [],
+		namespace: [],
+		package: [],
+	},
+	[WASMLanguage.Graphql]: {
+		function: [],
+		type: [],
+		namespace: [],
+		package: [],
+	},
+	[WASMLanguage.Toml]: {
+		function: [],
+		type: [],
+		namespace: [],
+		package: [],
+	},
+	[WASMLanguage.Scala]: {
+		function: ['function_definition', 'function_declaration'],
+		type: ['class_definition', 'object_definition', 'trait_definition'],
+		namespace: [],
+		package: ['package_clause'],
+	},
+	[WASMLanguage.R]: {
+		function: ['function_definition'],
+		type: [],
+		namespace: [],
+		package: [],
+	},
+	[WASMLanguage.ObjectiveC]: {
+		function: ['function_definition', 'method_definition'],
+		type: ['struct_specifier', 'union_specifier', 'enum_specifier', 'class_interface', 'class_implementation', 'protocol_declaration'],
+		namespace: [],
+		package: [],
+	},
+	[WASMLanguage.Protobuf]: {
+		function: ['rpc'],
+		type: ['message', 'enum', 'service'],
+		namespace: [],
+		package: ['package'],
+	},
+	[WASMLanguage.Ini]: {
+		function: [],
+		type: [],
+		namespace: [],
+		package: [],
+	},
 };
 
 export const semanticChunkingTargetQuery: LanguageQueryMap = q({
@@ -1330,6 +3390,9 @@ export const semanticChunkingTargetQuery: LanguageQueryMap = q({
 	[WASMLanguage.Cpp]: [
 		semanticChunkingTargetQueryForLanguage(WASMLanguage.Cpp)
 	],
+	[WASMLanguage.C]: [
+		semanticChunkingTargetQueryForLanguage(WASMLanguage.C)
+	],
 	[WASMLanguage.Csharp]: [
 		semanticChunkingTargetQueryForLanguage(WASMLanguage.Csharp)
 	],
@@ -1344,7 +3407,55 @@ export const semanticChunkingTargetQuery: LanguageQueryMap = q({
 	],
 	[WASMLanguage.Ruby]: [
 		semanticChunkingTargetQueryForLanguage(WASMLanguage.Ruby)
-	]
+	],
+	[WASMLanguage.Swift]: [
+		semanticChunkingTargetQueryForLanguage(WASMLanguage.Swift)
+	],
+	[WASMLanguage.Kotlin]: [
+		semanticChunkingTargetQueryForLanguage(WASMLanguage.Kotlin)
+	],
+	[WASMLanguage.Shell]: [
+		semanticChunkingTargetQueryForLanguage(WASMLanguage.Shell)
+	],
+	[WASMLanguage.Dart]: [
+		semanticChunkingTargetQueryForLanguage(WASMLanguage.Dart)
+	],
+	// there are no declarations to chunk by
+	[WASMLanguage.Json]: [],
+	[WASMLanguage.Yaml]: [],
+	[WASMLanguage.Php]: [
+		semanticChunkingTargetQueryForLanguage(WASMLanguage.Php)
+	],
+	[WASMLanguage.Lua]: [
+		semanticChunkingTargetQueryForLanguage(WASMLanguage.Lua)
+	],
+	[WASMLanguage.Hcl]: [
+		semanticChunkingTargetQueryForLanguage(WASMLanguage.Hcl)
+	],
+	[WASMLanguage.Sql]: [
+		semanticChunkingTargetQueryForLanguage(WASMLanguage.Sql)
+	],
+	[WASMLanguage.Graphql]: [
+		semanticChunkingTargetQueryForLanguage(WASMLanguage.Graphql)
+	],
+	[WASMLanguage.Toml]: [
+		semanticChunkingTargetQueryForLanguage(WASMLanguage.Toml)
+	],
+	[WASMLanguage.Scala]: [
+		semanticChunkingTargetQueryForLanguage(WASMLanguage.Scala)
+	],
+	[WASMLanguage.R]: [
+		semanticChunkingTargetQueryForLanguage(WASMLanguage.R)
+	],
+	[WASMLanguage.ObjectiveC]: [
+		semanticChunkingTargetQueryForLanguage(WASMLanguage.ObjectiveC)
+	],
+	[WASMLanguage.Protobuf]: [
+		semanticChunkingTargetQueryForLanguage(WASMLanguage.Protobuf)
+	],
+	[WASMLanguage.Ini]: [
+		semanticChunkingTargetQueryForLanguage(WASMLanguage.Ini)
+	],
 });
 
 
@@ -1418,5 +3529,77 @@ export const testInSuiteQueries: { [wasmLanguage in WASMLanguage]: string[] } =
 	[WASMLanguage.Ruby]: [],
 	[WASMLanguage.Csharp]: [],
 	[WASMLanguage.Cpp]: [],
-	[WASMLanguage.Rust]: []
+	[WASMLanguage.C]: [],
+	[WASMLanguage.Rust]: [],
+	[WASMLanguage.Swift]: [
+		treeSitterQuery.swift`[
+			(function_declaration
+				name: (simple_identifier) @fn
+				(#match? @fn "^test")
+			) @test
+		]`
+	],
+	[WASMLanguage.Kotlin]: [
+		treeSitterQuery.kotlin`[
+			(function_declaration
+				(modifiers
+					(annotation) @annotation
+					(#match? @annotation "^@Test"))
+			) @test
+		]`
+	],
+	[WASMLanguage.Shell]: [],
+	[WASMLanguage.Dart]: [],
+	[WASMLanguage.Json]: [],
+	[WASMLanguage.Yaml]: [],
+	[WASMLanguage.Php]: [
+		treeSitterQuery.php`[
+			(method_declaration
+				name: (name) @fn
+				(#match? @fn "^test")
+			) @test
+		]`
+	],
+	[WASMLanguage.Lua]: [],
+	[WASMLanguage.Hcl]: [],
+	[WASMLanguage.Sql]: [],
+	[WASMLanguage.Graphql]: [],
+	[WASMLanguage.Toml]: [],
+	[WASMLanguage.Scala]: [],
+	[WASMLanguage.R]: [],
+	[WASMLanguage.ObjectiveC]: [],
+	[WASMLanguage.Protobuf]: [],
+	[WASMLanguage.Ini]: [],
 };
+
+/**
+ * Test declarations by the conventions of the languages' test frameworks, see `_getTests`: the declaration is captured as `@test`
+ * and its name, e.g., the description of a JS/TS `it('adds two numbers', ...)` call, as `@name`
+ */
+export const testDeclarationQueries: { [language: string]: string[] } = q({
+	[WASMLanguage.Go]: [
+		// whether the parameters match the prefix is told by `goTestRole`
+		treeSitterQuery.go`((function_declaration
+			name: (identifier) @name) @test
+			(#match? @name "^(Test|Benchmark)"))`
+	],
+	...forLanguages([WASMLanguage.JavaScript, WASMLanguage.TypeScript, WASMLanguage.TypeScriptTsx], [
+		// also modifiers, e.g., `describe.only(...)` or `it.skip(...)`
+		treeSitterQuery.typescript`((call_expression
+			function: [
+				(identifier) @_fn
+				(member_expression
+					object: (identifier) @_fn)
+			]
+			arguments: (arguments
+				.
+				[(string) (template_string)] @name)) @test
+			(#any-of? @_fn "describe" "suite" "context" "it" "test" "specify"))`
+	]),
+	[WASMLanguage.Python]: [
+		// whether a method is a test depends on its class, see `_getTests`
+		treeSitterQuery.python`((function_definition
+			name: (identifier) @name) @test
+			(#match? @name "^test"))`
+	],
+});
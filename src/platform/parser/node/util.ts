@@ -4,6 +4,7 @@
  *--------------------------------------------------------------------------------------------*/
 
 import type { SyntaxNode } from 'web-tree-sitter';
+import { CharCode } from '../../../util/vs/base/common/charCode';
 import { WASMLanguage } from './treeSitterLanguages';
 
 /**
@@ -24,6 +25,7 @@ export function extractIdentifier(node: SyntaxNode, languageId: string): string
 		case 'javascriptreact':
 		case 'typescript':
 		case 'typescriptreact':
+		case 'c':
 		case 'cpp': {
 			const declarator = node.children.find(c => c.type.match(/declarator/));
 			if (declarator) { return declarator.children.find(c => c.type.match(/identifier/))?.text; }
@@ -71,11 +73,103 @@ export function isDocumentableNode(node: SyntaxNode, language: WASMLanguage) {
 			return node.type.match(/definition|declaration|declarator|export_statement/);
 		case WASMLanguage.Go:
 			return node.type.match(/definition|declaration|declarator|var_spec/);
+		case WASMLanguage.C:
 		case WASMLanguage.Cpp:
 			return node.type.match(/definition|declaration|class_specifier/);
 		case WASMLanguage.Ruby:
 			return node.type.match(/module|class|method|assignment/);
+		case WASMLanguage.Shell:
+			return node.type.match(/function_definition|variable_assignment|declaration_command/);
+		case WASMLanguage.Dart:
+			return node.type.match(/definition|declaration|signature/);
+		case WASMLanguage.Hcl:
+			return node.type.match(/^(block|attribute)$/);
+		case WASMLanguage.Sql:
+			return node.type.match(/^(statement|column_definition)$/);
+		case WASMLanguage.Graphql:
+			return node.type.match(/_definition$/);
+		case WASMLanguage.Lua:
+			// `function_definition`s are anonymous, e.g., `function(buf) ... end`, and documented where they're assigned
+			return node.type.match(/function_declaration|variable_declaration|assignment_statement|^field$/);
+		case WASMLanguage.R:
+			// assignments and S4 calls, e.g., `area <- function(shape) { ... }` or `setClass("Circle", ...)`, see `rNodeKind`
+			return node.type.match(/^(binary_operator|call)$/);
+		case WASMLanguage.ObjectiveC:
+			return node.type.match(/definition|declaration|class_interface|class_implementation/);
+		case WASMLanguage.Protobuf:
+			return node.type.match(/^(message|enum|service|rpc|field|map_field|oneof|oneof_field|enum_field)$/);
 		default:
 			return node.type.match(/definition|declaration|declarator/);
 	}
 }
+
+/**
+ * @returns whether only spaces and tabs precede `node` on its line; only the whitespace before it is scanned, which keeps long lines cheap
+ */
+export function isFirstOnItsLine(node: SyntaxNode, source: string): boolean {
+	for (let i = node.startIndex - 1; i >= 0; i--) {
+		const char = source.charCodeAt(i);
+		if (char === CharCode.LineFeed || char === CharCode.CarriageReturn) {
+			return true;
+		}
+		if (char !== CharCode.Space && char !== CharCode.Tab) {
+			return false;
+		}
+	}
+	return true;
+}
+
+/**
+ * @returns text of the run of comments that directly precedes `node`, each on its own line; `undefined` if there's none
+ */
+export function leadingCommentOf(node: SyntaxNode, source: string): string | undefined {
+	const comments = leadingCommentsOf(node, source);
+	return comments.length > 0 ? source.substring(comments[0].startIndex, comments[comments.length - 1].endIndex) : undefined;
+}
+
+/**
+ * @returns comments of the run that directly precedes `node` in source order, see {@link leadingCommentOf}
+ */
+export function leadingCommentsOf(node: SyntaxNode, source: string): SyntaxNode[] {
+	const comments: SyntaxNode[] = [];
+	let next = node;
+	// e.g., `comment`, `line_comment`, `block_comment`, `multiline_comment`
+	for (let prev = node.previousNamedSibling; prev !== null && prev.type.endsWith('comment'); prev = prev.previousNamedSibling) {
+		const isSeparatedByLineBreak = /^[ \t]*\r?\n[ \t]*$/.test(source.substring(prev.endIndex, next.startIndex));
+		if (!isSeparatedByLineBreak || !isFirstOnItsLine(prev, source)) {
+			break;
+		}
+		comments.unshift(prev);
+		next = prev;
+	}
+	return comments;
+}
+
+/**
+ * @returns text of `node` up to where `end` starts, without trailing whitespace; the whole text if `end` is `null`
+ */
+export function textUpTo(node: SyntaxNode, end: SyntaxNode | null, source: string): string {
+	return source.substring(node.startIndex, end ? end.startIndex : node.endIndex).trimEnd();
+}
+
+/**
+ * @returns string of a double-quoted literal, e.g., `a"b` for `"a\"b"`, or the literal without its quotes if it's malformed
+ */
+export function unquote(literal: string): string {
+	try {
+		const value = JSON.parse(literal);
+		if (typeof value === 'string') {
+			return value;
+		}
+	} catch {
+		// e.g., an unterminated string or an escape that's valid in YAML but not in JSON, e.g., `\x41`
+	}
+	return literal.replace(/^"|"$/g, '');
+}
+
+/**
+ * @returns `parentPath` followed by `key`; `undefined` if either is missing, e.g., for a YAML key that isn't a scalar
+ */
+export function keyPathWith(parentPath: readonly string[] | undefined, key: string | undefined): string[] | undefined {
+	return parentPath && key !== undefined ? [...parentPath, key] : undefined;
+}
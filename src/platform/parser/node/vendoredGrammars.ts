@@ -0,0 +1,53 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { WASMLanguage } from './treeSitterLanguages';
+
+/**
+ * Grammar package the `.wasm` file of a grammar that `@vscode/tree-sitter-wasm` doesn't ship is built from
+ */
+export interface VendoredGrammar {
+	readonly module: string;
+	readonly version: string;
+	/** directory of the grammar within the package if it isn't the package itself */
+	readonly projectPath?: string;
+}
+
+/**
+ * Directory of the vendored `.wasm` files relative to the repository root; `script/build/buildTreeSitterGrammars.ts` builds them
+ */
+export const vendoredGrammarsDir = 'src/platform/parser/node/grammars';
+
+/**
+ * Grammars whose `.wasm` files are vendored in {@link vendoredGrammarsDir}; the other languages are loaded from `@vscode/tree-sitter-wasm`
+ */
+export const vendoredGrammars: { readonly [language in WASMLanguage]?: VendoredGrammar } = {
+	[WASMLanguage.C]: { module: 'tree-sitter-c', version: '0.23.2' },
+	[WASMLanguage.Swift]: { module: 'tree-sitter-swift', version: '0.6.0' },
+	[WASMLanguage.Kotlin]: { module: 'tree-sitter-kotlin', version: '0.3.8' },
+	[WASMLanguage.Shell]: { module: 'tree-sitter-bash', version: '0.23.3' },
+	[WASMLanguage.Dart]: { module: 'tree-sitter-dart', version: '1.0.0' },
+	[WASMLanguage.Json]: { module: 'tree-sitter-json', version: '0.23.0' }, // Also includes jsonc support
+	[WASMLanguage.Yaml]: { module: '@tree-sitter-grammars/tree-sitter-yaml', version: '0.6.1' },
+	// the grammar with the HTML around `<?php ... ?>`, not `php_only`
+	[WASMLanguage.Php]: { module: 'tree-sitter-php', version: '0.23.11', projectPath: 'php' },
+	[WASMLanguage.Lua]: { module: '@tree-sitter-grammars/tree-sitter-lua', version: '0.2.0' },
+	[WASMLanguage.Hcl]: { module: '@tree-sitter-grammars/tree-sitter-hcl', version: '1.1.0' }, // Also includes Terraform
+	[WASMLanguage.Sql]: { module: '@derekstride/tree-sitter-sql', version: '0.3.5' },
+	[WASMLanguage.Graphql]: { module: 'tree-sitter-graphql', version: '0.1.0' },
+	[WASMLanguage.Toml]: { module: '@tree-sitter-grammars/tree-sitter-toml', version: '0.6.0' },
+	[WASMLanguage.Scala]: { module: 'tree-sitter-scala', version: '0.23.3' },
+	[WASMLanguage.R]: { module: '@davisvaughan/tree-sitter-r', version: '1.1.0' },
+	[WASMLanguage.ObjectiveC]: { module: 'tree-sitter-objc', version: '3.0.2' }, // Also used for Objective-C++
+	[WASMLanguage.Protobuf]: { module: 'tree-sitter-proto', version: '0.2.0' },
+	[WASMLanguage.Ini]: { module: 'tree-sitter-ini', version: '1.1.0' },
+};
+
+/**
+ * @returns name of the `.wasm` file of the grammar of `language`, e.g., `tree-sitter-c-sharp.wasm`
+ */
+export function grammarFilenameOf(language: WASMLanguage): string {
+	return `tree-sitter-${language === WASMLanguage.Csharp ? 'c-sharp' : language}.wasm`;
+}
@@ -0,0 +1,67 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import type { SyntaxNode } from 'web-tree-sitter';
+import { OverlayNode } from './nodes';
+import type { StructureLanguage } from './structureDetails';
+import { keyPathWith, unquote } from './util';
+
+export const yamlStructure: StructureLanguage = {
+	describe: describeYamlNode,
+	// a stream of a single document, i.e., without `---` separators, doesn't need a node of its own
+	skipsCapture: capture => capture.name === 'document' && capture.node.parent?.namedChildren.filter(c => c.type === 'document').length === 1,
+	keyPathOf: (_syntaxNode, overlayNode, parentPath) => keyPathWith(parentPath, overlayNode.name),
+};
+
+function describeYamlNode(syntaxNode: SyntaxNode, overlayNode: OverlayNode): void {
+	switch (overlayNode.kind) {
+		case 'pair': {
+			// a key may be missing, e.g., `: value`, or complex, e.g., `? [a, b]`, which doesn't name the pair
+			const key = syntaxNode.childForFieldName('key');
+			const keyScalar = key ? yamlScalarOf(key) : undefined;
+			overlayNode.name = keyScalar ? yamlScalarText(keyScalar) : undefined;
+			overlayNode.value = yamlValueOf(syntaxNode.childForFieldName('value'));
+			break;
+		}
+		case 'array_element': {
+			// e.g., `- run: npm test` within a block sequence or `b` within `[a, b]`
+			const items = syntaxNode.type === 'block_sequence_item'
+				? syntaxNode.parent!.namedChildren.filter(c => c.type === 'block_sequence_item')
+				: syntaxNode.parent!.namedChildren.filter(c => c.type === 'flow_node');
+			overlayNode.name = `[${items.findIndex(c => c.startIndex === syntaxNode.startIndex)}]`;
+			overlayNode.value = yamlValueOf(syntaxNode.type === 'block_sequence_item' ? syntaxNode.namedChildren.find(c => c.type !== 'comment') ?? null : syntaxNode);
+			break;
+		}
+	}
+}
+
+/**
+ * @returns text of a scalar or an alias without its anchor or tag; `undefined` for a mapping or a sequence
+ */
+function yamlValueOf(node: SyntaxNode | null): string | undefined {
+	return node ? yamlScalarOf(node)?.text : undefined;
+}
+
+/**
+ * @returns scalar or alias of a `flow_node` or `block_node`, skipping its anchor and tag, e.g., `8080` for `&port !!int 8080`
+ */
+function yamlScalarOf(node: SyntaxNode): SyntaxNode | undefined {
+	const content = node.namedChildren.filter(c => c.type !== 'anchor' && c.type !== 'tag' && c.type !== 'comment').at(-1);
+	return content?.type.endsWith('scalar') || content?.type === 'alias' ? content : undefined;
+}
+
+/**
+ * @returns value of a scalar as text, e.g., `it's` for `'it''s'`; an alias isn't resolved
+ */
+function yamlScalarText(scalar: SyntaxNode): string {
+	switch (scalar.type) {
+		case 'double_quote_scalar':
+			return unquote(scalar.text);
+		case 'single_quote_scalar':
+			return scalar.text.slice(1, -1).replace(/''/g, `'`);
+		default:
+			return scalar.text;
+	}
+}
@@ -0,0 +1,66 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { buildSymbolIndex, structureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('buildSymbolIndex', () => {
+
+	afterAll(() => _dispose());
+
+	const symbol = (n: OverlayNode) => ({ kind: n.kind, name: n.name });
+
+	test('go - methods are indexed by their names and qualified by their receiver types, with and without the package', async () => {
+		const source = await fromFixture('test.go');
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const index = buildSymbolIndex(structure!);
+
+		expect(index.get('MethodExample')?.map(symbol)).toEqual([
+			{ kind: 'method_spec', name: 'MethodExample' },
+			{ kind: 'method_declaration', name: 'MethodExample' },
+		]);
+		expect(index.get('StructExample.MethodExample')?.map(symbol)).toEqual([{ kind: 'method_declaration', name: 'MethodExample' }]);
+		expect(index.get('main.StructExample.MethodExample')).toEqual(index.get('StructExample.MethodExample'));
+		expect(index.get('InterfaceExample.MethodExample')?.map(symbol)).toEqual([{ kind: 'method_spec', name: 'MethodExample' }]);
+		expect(index.get('StructExample.Name')?.map(symbol)).toEqual([{ kind: 'field_declaration', name: 'Name' }]);
+		expect(index.get('StructExample')?.map(symbol)).toEqual([{ kind: 'type_declaration', name: 'StructExample' }]);
+	});
+
+	test('cpp - nested members are qualified by all of their named ancestors with the separator of the language', async () => {
+		const source = [
+			'namespace geo {',
+			'class Shape {',
+			'public:',
+			'	double area() const { return 0; }',
+			'};',
+			'}',
+			'',
+			'double area() { return 0; }',
+			'',
+			'namespace geo {',
+			'double Shape::perimeter() const { return 0; }',
+			'}',
+		].join('\n');
+		const structure = await structureComputer.getStructure(WASMLanguage.Cpp, source);
+
+		const index = buildSymbolIndex(structure!);
+
+		expect(index.get('area')?.map(symbol)).toEqual([
+			{ kind: 'function_definition', name: 'area' },
+			{ kind: 'function_definition', name: 'area' },
+		]);
+		expect(index.get('geo::Shape::area')).toEqual([index.get('area')![0]]);
+		expect(index.get('geo::Shape')?.map(symbol)).toEqual([{ kind: 'class_specifier', name: 'Shape' }]);
+		// defined outside of its class
+		expect(index.get('geo::Shape::perimeter')?.map(symbol)).toEqual([{ kind: 'function_definition', name: 'perimeter' }]);
+		expect(index.has('Shape::area')).toBe(false);
+		expect(index.has('geo.Shape.area')).toBe(false);
+	});
+});
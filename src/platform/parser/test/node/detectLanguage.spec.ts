@@ -0,0 +1,81 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { expect, suite, test } from 'vitest';
+import { detectLanguage, WASMLanguage } from '../../node/treeSitterLanguages';
+
+suite('detectLanguage', () => {
+
+	test('known extensions take precedence over the content', () => {
+		expect(detectLanguage('src/main.go', '#!/usr/bin/env python3\n')).toBe(WASMLanguage.Go);
+		expect(detectLanguage('C:\\src\\App.TSX', '')).toBe(WASMLanguage.TypeScriptTsx);
+		expect(detectLanguage('scripts/build.mjs', '')).toBe(WASMLanguage.JavaScript);
+	});
+
+	test('python script without an extension', () => {
+		const source = [
+			'#!/usr/bin/env python3',
+			'import sys',
+			'',
+			'def main():',
+			'    print(sys.argv)',
+			'',
+		].join('\n');
+
+		expect(detectLanguage('bin/manage', source)).toBe(WASMLanguage.Python);
+		expect(detectLanguage('bin/manage', source.replace('/usr/bin/env python3', '/usr/local/bin/python3.12'))).toBe(WASMLanguage.Python);
+	});
+
+	test('shell script with a shebang', () => {
+		expect(detectLanguage('deploy', '#!/bin/bash\nset -euo pipefail\n')).toBe(WASMLanguage.Shell);
+		expect(detectLanguage('deploy.command', '#!/usr/bin/env -S bash -e\necho hi\n')).toBe(WASMLanguage.Shell);
+		expect(detectLanguage('.envrc', '#!/bin/sh\n')).toBe(WASMLanguage.Shell);
+	});
+
+	test('header with C++ syntax is C++, a plain C header is not supported', () => {
+		const cppHeader = [
+			'#pragma once',
+			'',
+			'class Shape {',
+			'public:',
+			'    virtual double area() const = 0;',
+			'};',
+			'',
+		].join('\n');
+		const cHeader = [
+			'#ifndef SHAPE_H',
+			'#define SHAPE_H',
+			'',
+			'struct shape { double width, height; };',
+			'double shape_area(const struct shape *s);',
+			'',
+			'#endif',
+			'',
+		].join('\n');
+
+		expect(detectLanguage('include/shape.h', cppHeader)).toBe(WASMLanguage.Cpp);
+		expect(detectLanguage('include/shape.h', cHeader)).toBe(WASMLanguage.C);
+	});
+
+	test('objective-c is told apart from C headers and MATLAB by its content', () => {
+		const objcHeader = [
+			'#import <Foundation/Foundation.h>',
+			'',
+			'@interface Widget : NSObject',
+			'@end',
+			'',
+		].join('\n');
+
+		expect(detectLanguage('Widget.h', objcHeader)).toBe(WASMLanguage.ObjectiveC);
+		expect(detectLanguage('Widget.m', objcHeader.replace('@interface', '@implementation'))).toBe(WASMLanguage.ObjectiveC);
+		expect(detectLanguage('Widget.mm', '')).toBe(WASMLanguage.ObjectiveC);
+		expect(detectLanguage('solve.m', 'function x = solve(A, b)\n    x = A \\ b;\nend\n')).toBeUndefined();
+	});
+
+	test('unknown files without a shebang are not detected', () => {
+		expect(detectLanguage('README', 'Read me first.\n')).toBeUndefined();
+		expect(detectLanguage('run', '#!/usr/bin/env perl\n')).toBeUndefined();
+	});
+});
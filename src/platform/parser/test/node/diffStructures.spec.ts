@@ -0,0 +1,97 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { diffStructures, structureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('diffStructures', () => {
+
+	afterAll(() => _dispose());
+
+	async function diffSources(oldSource: string, newSource: string) {
+		const oldStructure = await structureComputer.getStructure(WASMLanguage.Go, oldSource);
+		const newStructure = await structureComputer.getStructure(WASMLanguage.Go, newSource);
+		return diffStructures(oldStructure!.children, newStructure!.children);
+	}
+
+	const symbol = (n: OverlayNode) => ({ kind: n.kind, name: n.name });
+
+	test('a renamed symbol is matched with its old name, and symbols after it move', async () => {
+		const source = await fromFixture('test.go');
+
+		const diff = await diffSources(source, source.replace(/IntExample/g, 'CountExample'));
+
+		expect(diff.renamed.map(m => [symbol(m.oldNode), symbol(m.newNode)])).toEqual([
+			[{ kind: 'var_spec', name: 'IntExample' }, { kind: 'var_spec', name: 'CountExample' }],
+		]);
+		expect(diff.removed).toEqual([]);
+		expect(diff.added).toEqual([]);
+		expect(diff.changed).toEqual([]);
+		expect(diff.moved.map(m => m.newNode.name)).toContain('main');
+		expect(diff.moved.map(m => m.newNode.name)).not.toContain('BoolExample');
+	});
+
+	test('symbols whose signatures differ are changed', async () => {
+		const source = await fromFixture('test.go');
+
+		const diff = await diffSources(source, source.replace('func (s StructExample) MethodExample() error', 'func (s StructExample) MethodExample() (err error)'));
+
+		expect(diff.changed.map(m => ({ ...symbol(m.newNode), oldDetail: m.oldNode.detail, newDetail: m.newNode.detail }))).toEqual([
+			{ kind: 'method_declaration', name: 'MethodExample', oldDetail: 'func (s StructExample) MethodExample() error', newDetail: 'func (s StructExample) MethodExample() (err error)' },
+		]);
+		// the method of the interface has the same name but a different identity
+		expect(diff.added).toEqual([]);
+		expect(diff.removed).toEqual([]);
+	});
+
+	test('identical structures have no differences', async () => {
+		const source = await fromFixture('test.go');
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		expect(diffStructures(structure!.children, structure!.children)).toEqual({ added: [], removed: [], renamed: [], moved: [], changed: [] });
+	});
+
+	test('an added symbol is added, not renamed, and symbols below it only move', async () => {
+		const source = await fromFixture('test.go');
+
+		const diff = await diffSources(source, source.replace('type StructExample struct {', 'type Celsius float64\n\ntype StructExample struct {'));
+
+		expect(diff.added.map(symbol)).toEqual([{ kind: 'type_declaration', name: 'Celsius' }]);
+		expect(diff.removed).toEqual([]);
+		expect(diff.renamed).toEqual([]);
+		expect(diff.changed).toEqual([]);
+		expect(diff.moved.map(m => m.newNode.name)).toEqual(expect.arrayContaining(['StructExample', 'Name', 'InterfaceExample', 'MethodExample', 'main']));
+		for (const { oldNode, newNode } of diff.moved) {
+			expect(newNode.range!.start.line - oldNode.range!.start.line).toBe(2);
+		}
+	});
+
+	test('a symbol moved below another one is moved, not removed and added', async () => {
+		const oldSource = ['package shapes', '', 'func Area() int {', '	return 1', '}', '', 'func Perimeter() int {', '	return 4', '}', ''].join('\n');
+		const newSource = ['package shapes', '', 'func Perimeter() int {', '	return 4', '}', '', 'func Area() int {', '	return 1', '}', ''].join('\n');
+
+		const diff = await diffSources(oldSource, newSource);
+
+		expect(diff.moved.map(m => m.newNode.name)).toEqual(['Perimeter', 'Area']);
+		expect(diff.added).toEqual([]);
+		expect(diff.removed).toEqual([]);
+		expect(diff.renamed).toEqual([]);
+	});
+
+	test('members of a renamed type are renamed along with it, and unrelated symbols aren\'t mistaken for renames', async () => {
+		const oldSource = ['package shapes', '', 'type Point struct {', '	X, Y int', '	Label string', '}', '', 'func Origin() {}', ''].join('\n');
+		const newSource = ['package shapes', '', 'type Vector struct {', '	X, Y int', '	Label string', '}', '', 'func Unit(scale int) {}', ''].join('\n');
+
+		const diff = await diffSources(oldSource, newSource);
+
+		expect(diff.renamed.map(m => [m.oldNode.name, m.newNode.name])).toEqual([['Point', 'Vector'], ['Label', 'Label']]);
+		expect(diff.removed.map(symbol)).toEqual([{ kind: 'function_declaration', name: 'Origin' }]);
+		expect(diff.added.map(symbol)).toEqual([{ kind: 'function_declaration', name: 'Unit' }]);
+	});
+});
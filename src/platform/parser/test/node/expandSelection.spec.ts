@@ -0,0 +1,75 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { TreeSitterOffsetRange } from '../../node/nodes';
+import { _dispose, _expandSelection, _shrinkSelection } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('expandSelection and shrinkSelection', () => {
+
+	afterAll(() => _dispose());
+
+	/**
+	 * @returns ranges of repeated expansions of `range` until they no longer change
+	 */
+	async function expansionsOf(source: string, range: TreeSitterOffsetRange) {
+		const expansions: TreeSitterOffsetRange[] = [];
+		while (true) {
+			const expanded = await _expandSelection(WASMLanguage.Go, source, range);
+			if (TreeSitterOffsetRange.isEqual(expanded, range)) {
+				return expansions;
+			}
+			expansions.push(expanded);
+			range = expanded;
+		}
+	}
+
+	test('expanding from within the condition of an if statement', async () => {
+
+		const source = await fromFixture('test.go');
+
+		const start = source.indexOf('err != nil');
+		const texts = (await expansionsOf(source, { startIndex: start, endIndex: start + 'err'.length }))
+			.map(r => source.substring(r.startIndex, r.endIndex));
+
+		const ifStatement = 'if err := i.MethodExample(); err != nil {\n        fmt.Println(err)\n    }';
+		const bodyStart = source.indexOf('{', source.indexOf('func main()'));
+		const body = source.substring(bodyStart, source.indexOf('\n}\n', bodyStart) + '\n}'.length);
+		expect(texts.slice(0, 2)).toEqual(['err != nil', ifStatement]);
+		expect(texts.indexOf(body)).toBeGreaterThan(1);
+		expect(texts[texts.indexOf(body) + 1]).toBe(`func main() ${body}`);
+	});
+
+	test('expansions are monotonic and stop at the root', async () => {
+
+		const source = await fromFixture('test.go');
+
+		const start = source.indexOf('fmt.Println(err)');
+		const expansions = await expansionsOf(source, { startIndex: start, endIndex: start });
+
+		for (let i = 1; i < expansions.length; i++) {
+			expect(TreeSitterOffsetRange.doesContain(expansions[i], expansions[i - 1])).toBe(true);
+			expect(TreeSitterOffsetRange.len(expansions[i])).toBeGreaterThan(TreeSitterOffsetRange.len(expansions[i - 1]));
+		}
+		const whole = { startIndex: 0, endIndex: source.length };
+		expect(await _expandSelection(WASMLanguage.Go, source, whole)).toEqual(whole);
+	});
+
+	test('shrinking is the inverse of expanding', async () => {
+
+		const source = await fromFixture('test.go');
+
+		const start = source.indexOf('err != nil');
+		const selection = { startIndex: start, endIndex: start + 'err'.length };
+		const expansions = [selection, ...await expansionsOf(source, selection)];
+
+		for (let i = expansions.length - 1; i > 0; i--) {
+			expect(await _shrinkSelection(WASMLanguage.Go, source, expansions[i], start)).toEqual(expansions[i - 1]);
+		}
+		expect(await _shrinkSelection(WASMLanguage.Go, source, selection, start)).toEqual(selection);
+	});
+});
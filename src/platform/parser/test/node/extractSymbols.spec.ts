@@ -0,0 +1,67 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose } from '../../node/parserImpl';
+import { extractSymbols, structureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('extractSymbols', () => {
+
+	afterAll(() => _dispose());
+
+	test('go - a method is extracted with its receiver and body', async () => {
+		const source = await fromFixture('test.go');
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const symbols = extractSymbols(structure!, source, 'StructExample.MethodExample', false);
+
+		expect(symbols.map(s => s.text)).toEqual([[
+			'func (s StructExample) MethodExample() error {',
+			'    if s.Name == "" {',
+			'        return errors.New("Name cannot be empty")',
+			'    }',
+			'    fmt.Println(s.Name)',
+			'    return nil',
+			'}',
+		].join('\n')]);
+		expect(source.substring(symbols[0].range.startIndex, symbols[0].range.endIndex)).toBe(symbols[0].text);
+	});
+
+	test('go - a simple name matches all of its declarations in source order', async () => {
+		const source = await fromFixture('test.go');
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const symbols = extractSymbols(structure!, source, 'MethodExample', false);
+
+		expect(symbols.map(s => s.text.split('\n')[0])).toEqual([
+			'MethodExample() error',
+			'func (s StructExample) MethodExample() error {',
+		]);
+		expect(extractSymbols(structure!, source, 'StructExample.Missing', false)).toEqual([]);
+	});
+
+	test('go - the leading comment is included on request', async () => {
+		const source = [
+			'package main',
+			'',
+			'// Add returns the sum of a and b.',
+			'// It never overflows.',
+			'func Add(a, b int) int {',
+			'	return a + b',
+			'}',
+			'',
+		].join('\n');
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		expect(extractSymbols(structure!, source, 'Add', false).map(s => s.text)).toEqual([
+			'func Add(a, b int) int {\n\treturn a + b\n}',
+		]);
+		const [withComment] = extractSymbols(structure!, source, 'Add', true);
+		expect(withComment.text).toBe('// Add returns the sum of a and b.\n// It never overflows.\nfunc Add(a, b int) int {\n\treturn a + b\n}');
+		expect(withComment.range.startIndex).toBe(source.indexOf('// Add'));
+	});
+});
@@ -0,0 +1,73 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _findLocalDefinition } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('findLocalDefinition', () => {
+
+	afterAll(() => _dispose());
+
+	/**
+	 * @returns range of `identifier`, which is searched for after `context`
+	 */
+	function rangeOf(source: string, context: string, identifier: string) {
+		const startIndex = source.indexOf(identifier, source.indexOf(context));
+		return { startIndex, endIndex: startIndex + identifier.length };
+	}
+
+	suite('go', () => {
+
+		test('a short-declared local resolves to its := declaration', async () => {
+			const source = await fromFixture('test.go');
+			const use = rangeOf(source, 'range sliceExample', 'sliceExample');
+
+			const definition = await _findLocalDefinition(WASMLanguage.Go, source, use.startIndex + 2);
+
+			expect(definition).toEqual(rangeOf(source, 'sliceExample :=', 'sliceExample'));
+		});
+
+		test('a receiver resolves to its declaration in the method header', async () => {
+			const source = await fromFixture('test.go');
+			const use = rangeOf(source, 'if s.Name', 's');
+
+			expect(await _findLocalDefinition(WASMLanguage.Go, source, use.startIndex)).toEqual(rangeOf(source, 'func (s', 's'));
+		});
+
+		test('a loop variable shadows a variable of the same name in the function', async () => {
+			const source = await fromFixture('test.go');
+			const use = rangeOf(source, 'Println(i, v)', 'i, v');
+
+			expect(await _findLocalDefinition(WASMLanguage.Go, source, use.startIndex)).toEqual(rangeOf(source, 'for i, v', 'i'));
+		});
+
+		test('package-level declarations and fields are not local', async () => {
+			const source = await fromFixture('test.go');
+
+			expect(await _findLocalDefinition(WASMLanguage.Go, source, rangeOf(source, 'BoolExample = true', 'BoolExample').startIndex)).toBeUndefined();
+			expect(await _findLocalDefinition(WASMLanguage.Go, source, rangeOf(source, 'fmt.Println(s.Name)', 'Name').startIndex)).toBeUndefined();
+		});
+	});
+
+	suite('typescript', () => {
+
+		test('a parameter resolves to its declaration in the parameter list', async () => {
+			const source = 'function greet(name: string) {\n\treturn `Hello, ${name}`;\n}\n';
+
+			const definition = await _findLocalDefinition(WASMLanguage.TypeScript, source, source.lastIndexOf('name'));
+
+			expect(definition).toEqual({ startIndex: 15, endIndex: 19 });
+		});
+
+		test('a definition resolves to itself', async () => {
+			const source = 'function greet(name: string) {\n\tconst greeting = `Hello, ${name}`;\n\treturn greeting;\n}\n';
+
+			expect(await _findLocalDefinition(WASMLanguage.TypeScript, source, source.lastIndexOf('greeting'))).toEqual(rangeOf(source, 'const', 'greeting'));
+			expect(await _findLocalDefinition(WASMLanguage.TypeScript, source, source.indexOf('greeting'))).toEqual(rangeOf(source, 'const', 'greeting'));
+		});
+	});
+});
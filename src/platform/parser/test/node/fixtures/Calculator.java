@@ -0,0 +1,57 @@
+package com.example.math;
+
+import java.util.List;
+
+@Deprecated
+public class Calculator {
+	private static final int PRECISION = 10;
+
+	private final List<String> history;
+
+	public Calculator(List<String> history) {
+		this.history = history;
+	}
+
+	public int add(int a, int b) {
+		return a + b;
+	}
+
+	public double add(double a, double b) {
+		return a + b;
+	}
+
+	@Override
+	@SuppressWarnings("unchecked")
+	public String toString() {
+		return "Calculator";
+	}
+
+	public static int sum(int... values) {
+		int total = 0;
+		for (int v : values) {
+			total += v;
+		}
+		return total;
+	}
+
+	public interface Operation {
+		double apply(double a, double b);
+	}
+
+	public enum Mode {
+		BASIC,
+		SCIENTIFIC;
+	}
+
+	record Entry(String expression, double result) {
+	}
+
+	static class Memory {
+		private double value;
+
+		class Slot {
+			void clear() {
+			}
+		}
+	}
+}
@@ -0,0 +1,8 @@
+namespace Demo;
+
+public enum Severity
+{
+    Info,
+    Warning = 2,
+    Error = Warning * 2,
+}
@@ -0,0 +1,30 @@
+import React from 'react';
+
+interface Todo {
+	id: string;
+	title: string;
+	done: boolean;
+}
+
+export function TodoList({ todos }: { todos: Todo[] }) {
+	return (
+		<>
+			<header id="todo-header">
+				<h1>Todos</h1>
+				<Counter count={todos.length} />
+			</header>
+			<ul className="todos">
+				{todos.map(todo => (
+					<li key={todo.id}>
+						<input type="checkbox" checked={todo.done} readOnly />
+						<span>{todo.title}</span>
+					</li>
+				))}
+			</ul>
+		</>
+	);
+}
+
+function Counter({ count }: { count: number }) {
+	return <span className="counter">{count}</span>;
+}
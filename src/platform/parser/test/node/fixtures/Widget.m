@@ -0,0 +1,40 @@
+#import <Foundation/Foundation.h>
+
+/**
+ * A widget with a name and a count.
+ */
+@interface Widget : NSObject <NSCopying>
+
+@property (nonatomic, copy) NSString *name;
+@property (nonatomic) NSInteger count;
+
+- (instancetype)initWithName:(NSString *)name count:(NSInteger)count;
+- (void)draw;
++ (instancetype)widgetWithName:(NSString *)name;
+
+@end
+
+@implementation Widget
+
+- (instancetype)initWithName:(NSString *)name count:(NSInteger)count {
+    self = [super init];
+    if (self) {
+        _name = [name copy];
+        _count = count;
+    }
+    return self;
+}
+
+- (void)draw {
+    NSLog(@"%@ x%ld", self.name, (long)self.count);
+}
+
++ (instancetype)widgetWithName:(NSString *)name {
+    return [[self alloc] initWithName:name count:1];
+}
+
+- (id)copyWithZone:(NSZone *)zone {
+    return [[Widget allocWithZone:zone] initWithName:self.name count:self.count];
+}
+
+@end
@@ -0,0 +1,7 @@
+package main
+
+type MyInt = int
+
+type MyCount int
+
+type Names = []string
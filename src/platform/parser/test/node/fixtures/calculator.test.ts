@@ -0,0 +1,28 @@
+import { Calculator } from './calculator';
+
+describe('Calculator', () => {
+	let calculator: Calculator;
+
+	beforeEach(() => {
+		calculator = new Calculator();
+	});
+
+	describe('add', () => {
+		it('adds two numbers', () => {
+			expect(calculator.add(1, 2)).toBe(3);
+		});
+
+		it.skip(`adds negative numbers`, () => {
+			expect(calculator.add(-1, -2)).toBe(-3);
+		});
+	});
+
+	test('clears the memory', () => {
+		calculator.clear();
+		expect(calculator.memory).toBe(0);
+	});
+});
+
+test('a calculator can be created', () => {
+	expect(new Calculator()).toBeDefined();
+});
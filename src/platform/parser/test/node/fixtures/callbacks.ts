@@ -0,0 +1,16 @@
+import { readFile } from 'fs/promises';
+
+export const parseConfig = (text: string) => {
+	return JSON.parse(text);
+};
+
+export async function loadConfigs(paths: string[]) {
+	const contents = await Promise.all(paths.map(async path => {
+		const text = await readFile(path, 'utf8');
+		return parseConfig(text);
+	}));
+	setTimeout(function retry() {
+		console.log('retrying');
+	}, 1000);
+	return contents.filter(c => c !== undefined);
+}
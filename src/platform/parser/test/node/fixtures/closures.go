@@ -0,0 +1,40 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+)
+
+var defaultHandler = func(w http.ResponseWriter, r *http.Request) {}
+
+type Server struct {
+	names []string
+}
+
+func (s *Server) Start() {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+	http.HandleFunc("/", handler)
+
+	sort.Slice(s.names, func(i, j int) bool {
+		return s.names[i] < s.names[j]
+	})
+}
+
+func main() {
+	cfg := struct {
+		Addr string
+		Port int
+	}{Addr: "localhost", Port: 8080}
+
+	go func() {
+		http.ListenAndServe(cfg.Addr, nil)
+	}()
+
+	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		check := func() bool { return true }
+		_ = func() {}
+		check()
+	})
+}
@@ -0,0 +1,22 @@
+package main
+
+import "strings"
+
+// TODO: split on any whitespace
+func words(s string) []string {
+	separator := " // not a comment"
+	url := `http://example.com` // NOTE: raw strings can contain // too
+	/* FIXME the URL
+	   isn't used */
+	_ = url
+	return strings.Split(s, separator)
+}
+
+/*
+ * HACK: the last word is dropped
+ */
+func lastWord(s string) string {
+	// note that this isn't a marker
+	w := words(s)
+	return w[len(w)-1]
+}
@@ -0,0 +1,28 @@
+package concurrency
+
+func worker(jobs <-chan int, results chan<- int) {
+	for j := range jobs {
+		results <- j * 2
+	}
+}
+
+func run() {
+	jobs := make(chan int, 8)
+	results := make(chan int, 8)
+	go worker(jobs, results)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 3; i++ {
+			jobs <- i
+		}
+		close(jobs)
+		close(done)
+	}()
+
+	select {
+	case r := <-results:
+		_ = r
+	case <-done:
+	}
+}
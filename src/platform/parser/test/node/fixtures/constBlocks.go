@@ -0,0 +1,23 @@
+package main
+
+type Weekday int
+
+const (
+	Sunday Weekday = iota
+	Monday
+	Tuesday
+	// Wednesday is mid-week.
+	Wednesday
+)
+
+const (
+	KB = 1 << (10 * (iota + 1))
+	MB
+)
+
+const Pi float64 = 3.14
+
+var (
+	name  string
+	count = 1
+)
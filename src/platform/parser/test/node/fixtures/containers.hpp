@@ -0,0 +1,31 @@
+#pragma once
+
+#include <cstddef>
+
+#define ARRAY_SIZE(a) (sizeof(a) / sizeof((a)[0]))
+
+namespace util {
+
+class Registry;
+
+template <typename T>
+T max_of(const T &a, const T &b) {
+	return (a > b) ? a : b;
+}
+
+template <typename T, std::size_t N>
+class FixedArray {
+public:
+	FixedArray();
+	std::size_t size() const { return N; }
+
+private:
+	T items_[N];
+};
+
+template <typename T>
+void clear(FixedArray<T, 4> &array);
+
+int count(const Registry &registry);
+
+} // namespace util
@@ -0,0 +1,39 @@
+import functools
+from dataclasses import dataclass
+
+MAX_RETRIES = 3
+timeout: float = 2.5
+
+
+def retry(times):
+    def decorator(fn):
+        @functools.wraps(fn)
+        async def wrapper(*args, **kwargs):
+            return await fn(*args, **kwargs)
+        return wrapper
+    return decorator
+
+
+@dataclass(frozen=True)
+class Client:
+    """Fetches resources."""
+
+    base_url: str
+
+    def url(self, path: str) -> str:
+        def join(a, b):
+            return a.rstrip("/") + "/" + b.lstrip("/")
+        return join(self.base_url, path)
+
+    @classmethod
+    def default(cls) -> "Client":
+        return cls("https://example.com")
+
+    @staticmethod
+    def version() -> str:
+        return "1.0"
+
+
+@retry(MAX_RETRIES)
+async def fetch(client: Client, path: str) -> bytes:
+    ...
@@ -0,0 +1,21 @@
+import { Component, HostListener, Input } from '@angular/core';
+import { HttpClient } from '@angular/common/http';
+import { Store } from './store';
+
+@Component({ selector: 'app-root' })
+export class AppComponent {
+	@Input() title = '';
+
+	constructor(
+		private readonly http: HttpClient,
+		public store: Store,
+		label: string,
+	) {
+		this.title = label;
+	}
+
+	@HostListener('click', ['$event'])
+	onClick(event: Event) {
+		this.store.dispatch(event);
+	}
+}
@@ -0,0 +1,40 @@
+package files
+
+import (
+	"os"
+	"sync"
+)
+
+type Store struct {
+	mu sync.Mutex
+}
+
+func (s *Store) Save(path string, data []byte) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = os.ErrInvalid
+		}
+	}()
+
+	_, err = f.Write(data)
+	return err
+}
+
+func Load(path string) ([]byte, error) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+	}()
+	wg.Wait()
+	return os.ReadFile(path)
+}
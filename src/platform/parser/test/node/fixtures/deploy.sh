@@ -0,0 +1,28 @@
+#!/usr/bin/env bash
+source ./lib/common.sh
+. "$HOME/.config/deploy.env"
+
+DEPLOY_ENV=staging
+
+# Writes the config of the given host.
+write_config() {
+	cat > "$1.conf" <<EOF
+host() {
+	name=$1
+}
+EOF
+}
+
+if [[ -n "${DEBUG:-}" ]]; then
+	debug() {
+		echo "$@" >&2
+	}
+fi
+
+case "$DEPLOY_ENV" in
+	staging)
+		function deploy {
+			write_config staging
+		}
+		;;
+esac
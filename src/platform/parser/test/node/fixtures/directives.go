@@ -0,0 +1,39 @@
+//go:build linux && amd64
+// +build linux,amd64
+
+package assets
+
+import (
+	"embed"
+	_ "unsafe"
+)
+
+//go:generate go run gen.go -out index.html
+
+// static holds the files served by the handler.
+//
+//go:embed static/*
+var static embed.FS
+
+//go:generate stringer -type=Weekday
+type Weekday int
+
+// nanotime is provided by the runtime.
+//
+//go:noescape
+//go:linkname nanotime runtime.nanotime
+func nanotime() int64
+
+// Files lists the embedded files.
+func Files() ([]string, error) {
+	// go:embed isn't a directive with a space
+	entries, err := static.ReadDir("static")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names, nil
+}
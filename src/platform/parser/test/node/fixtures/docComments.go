@@ -0,0 +1,25 @@
+package docs
+
+// ConstExample is documented.
+const ConstExample = "const"
+
+/* VarExample is documented
+   with a block comment. */
+var VarExample int
+
+// This comment is separated from StructExample by a blank line.
+
+// StructExample holds a name.
+// It spans two lines.
+type StructExample struct {
+	Name string
+}
+
+// MethodExample validates the name.
+func (s StructExample) MethodExample() error {
+	return nil
+}
+
+// Detached comment.
+
+func Undocumented() {}
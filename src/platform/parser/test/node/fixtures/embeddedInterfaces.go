@@ -0,0 +1,15 @@
+package embedded
+
+import "io"
+
+type InterfaceExample interface {
+	MethodExample() error
+}
+
+type ReadCloserExample interface {
+	// embedded interfaces
+	InterfaceExample
+	io.Reader
+
+	Close() error
+}
@@ -0,0 +1,6 @@
+enum color { RED, GREEN = 5, BLUE };
+
+typedef enum {
+	LOG_DEBUG = 1 << 0,
+	LOG_ERROR = 1 << 1,
+} log_level;
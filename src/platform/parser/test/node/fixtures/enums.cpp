@@ -0,0 +1,9 @@
+namespace http {
+
+enum class Status : int {
+	Ok = 200,
+	NotFound = 404,
+	Unknown,
+};
+
+}
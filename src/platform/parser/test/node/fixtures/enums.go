@@ -0,0 +1,14 @@
+package colors
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+
+const (
+	KB = 1 << (10 * (iota + 1))
+	MB
+)
@@ -0,0 +1,12 @@
+export enum Direction {
+	Up = 1,
+	Down,
+	'Left' = 'L',
+	Right = 'R',
+}
+
+const enum Flags {
+	None = 0,
+	Bold = 1 << 0,
+	Italic,
+}
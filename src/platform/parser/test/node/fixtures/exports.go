@@ -0,0 +1,43 @@
+package cache
+
+import "sync"
+
+const (
+	DefaultSize = 128
+	maxSize     = 1 << 20
+)
+
+var errEvicted = errorString("evicted")
+
+// Cache is a fixed-size cache.
+type Cache struct {
+	Size  int
+	mu    sync.Mutex
+	items map[string]string
+}
+
+// New returns an empty cache.
+func New(size int) *Cache {
+	return &Cache{Size: size, items: map[string]string{}}
+}
+
+// Get returns the item stored under key.
+func (c *Cache) Get(key string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.items[key]
+}
+
+func (c *Cache) evict(key string) {
+	delete(c.items, key)
+}
+
+type errorString string
+
+func (e errorString) Error() string {
+	return string(e)
+}
+
+func newDefault() *Cache {
+	return New(DefaultSize)
+}
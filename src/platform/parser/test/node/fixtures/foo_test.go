@@ -0,0 +1,62 @@
+package foo
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSum(t *testing.T) {
+	if Sum(1, 2) != 3 {
+		t.Fatal("expected 3")
+	}
+}
+
+func Test_sumOfNothing(t *testing.T) {
+	if Sum() != 0 {
+		t.Fatal("expected 0")
+	}
+}
+
+func BenchmarkSum(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Sum(1, 2)
+	}
+}
+
+func ExampleSum() {
+	fmt.Println(Sum(1, 2))
+	// Output: 3
+}
+
+func FuzzSum(f *testing.F) {
+	f.Add(1, 2)
+	f.Fuzz(func(t *testing.T, a, b int) {
+		Sum(a, b)
+	})
+}
+
+// not tests: the parameter doesn't match the prefix, there's no testing parameter, or the prefix is part of a word
+
+func TestdataPath(t *testing.T) string {
+	return "testdata"
+}
+
+func TestHelper(s string) {
+	fmt.Println(s)
+}
+
+func BenchmarkSetup(t *testing.T) {
+	t.Helper()
+}
+
+func Testify(t *testing.T) {
+	t.Helper()
+}
+
+func ExampleWithArgs(s string) {
+	fmt.Println(s)
+}
+
+func (s Suite) TestMethod(t *testing.T) {
+	t.Helper()
+}
@@ -0,0 +1,24 @@
+package generics
+
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+func Map[T, U any](s []T, f func(T) U) []U {
+	result := make([]U, 0, len(s))
+	for _, v := range s {
+		result = append(result, f(v))
+	}
+	return result
+}
+
+type List[T any] []T
+
+type Pair[K comparable, V Number] struct {
+	Key   K
+	Value V
+}
+
+func (p Pair[K, V]) Swap() Pair[K, V] {
+	return p
+}
@@ -0,0 +1,39 @@
+use std::fmt;
+
+pub struct Stack<T> {
+    items: Vec<T>,
+}
+
+impl<T> Stack<T> {
+    pub fn new() -> Self {
+        Stack { items: Vec::new() }
+    }
+
+    pub fn push(&mut self, item: T) {
+        self.items.push(item);
+    }
+}
+
+impl<T: fmt::Debug> fmt::Display for Stack<T> {
+    fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+        write!(f, "{:?}", self.items)
+    }
+}
+
+pub trait Shape {
+    fn sides() -> u32;
+    fn area(&self) -> f64;
+}
+
+impl Shape for String {
+    fn sides() -> u32 {
+        0
+    }
+
+    fn area(&self) -> f64 {
+        0.0
+    }
+}
+
+pub const MAX_DEPTH: usize = 16;
+static GREETING: &str = "hello";
@@ -0,0 +1,11 @@
+package main
+
+import "os"
+
+import (
+	"fmt"
+	str "strings"
+	. "math"
+	_ "embed"
+	`net/http`
+)
@@ -0,0 +1,20 @@
+package storage
+
+import "io"
+
+type Getter interface {
+	// Get returns the value stored under key.
+	Get(key string) ([]byte, error)
+}
+
+type Store interface {
+	Getter
+	Put(key string, value []byte) error
+}
+
+type ClosableStore interface {
+	Store
+	io.Closer
+
+	Flush() error
+}
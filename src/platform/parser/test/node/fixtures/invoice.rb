@@ -0,0 +1,20 @@
+module Shop
+  module Billing
+    class Invoice < ApplicationRecord
+      attr_accessor :total
+      attr_reader :number, :issued_on
+
+      def self.next_number
+        maximum(:number).to_i + 1
+      end
+
+      def initialize(total)
+        @total = total
+      end
+
+      def paid?
+        !paid_at.nil?
+      end
+    end
+  end
+end
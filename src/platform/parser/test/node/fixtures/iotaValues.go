@@ -0,0 +1,18 @@
+package main
+
+const (
+	A = iota
+	_
+	C
+	D = iota + 10
+)
+
+const (
+	KB = 1 << (10 * (iota + 1))
+	MB
+)
+
+const (
+	Base  = 5
+	Other = Base + iota
+)
@@ -0,0 +1,29 @@
+package main
+
+import "fmt"
+
+func findPair(rows [][]int, sum int) (int, int) {
+Outer:
+	for i, row := range rows {
+		for j, v := range row {
+			if v > sum {
+				continue Outer
+			}
+			if v == sum {
+				break Outer
+			}
+			if v < 0 {
+				goto Done
+			}
+			check := func() {
+				for {
+					break Outer
+				}
+			}
+			check()
+			fmt.Println(i, j)
+		}
+	}
+Done:
+	return -1, -1
+}
@@ -0,0 +1,19 @@
+package server
+
+// Options configures a server.
+type Options struct {
+	Host            string
+	Port            int
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	MaxHeaderBytes  int
+	TLSCertFile     string
+	TLSKeyFile      string
+	EnableHTTP2     bool
+	ShutdownTimeout time.Duration
+}
+
+type Handler interface {
+	Serve(w ResponseWriter, r *Request)
+}
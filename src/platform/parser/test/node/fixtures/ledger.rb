@@ -0,0 +1,53 @@
+module Accounts
+  class Ledger
+    attr_reader :entries
+
+    class << self
+      def open(name)
+        new(name)
+      end
+
+      private
+
+      def registry
+        @registry ||= {}
+      end
+    end
+
+    def initialize(name)
+      @name = name
+      @entries = []
+    end
+
+    def record(amount)
+      entries << normalize(amount)
+    end
+
+    protected
+
+    def balance
+      entries.sum
+    end
+
+    private
+
+    def normalize(amount)
+      amount.round(2)
+    end
+
+    public
+
+    def to_s
+      "#{@name}: #{balance}"
+    end
+
+    def summary
+      "#{entries.size} entries"
+    end
+    private :summary
+
+    private def audit
+      entries.dup
+    end
+  end
+end
@@ -0,0 +1,31 @@
+#include <stdlib.h>
+
+#define LIST_INIT { NULL, 0 }
+#define LIST_EMPTY(l) ((l)->size == 0)
+
+struct node;
+
+typedef struct list {
+	struct node *head;
+	size_t size;
+} list_t;
+
+int list_push(list_t *l, int value);
+void (*on_push)(int value);
+
+struct node {
+	int value;
+	struct node *next;
+};
+
+int list_push(list_t *l, int value) {
+	struct node *n = malloc(sizeof(struct node));
+	if (n == NULL) {
+		return -1;
+	}
+	n->value = value;
+	n->next = l->head;
+	l->head = n;
+	l->size++;
+	return 0;
+}
@@ -0,0 +1,33 @@
+-- String helpers for the editor.
+local M = {}
+
+VERSION = "1.2.0"
+
+--- Greets someone by name.
+function M.greet(name)
+  return "Hello, " .. name
+end
+
+local function trim(s)
+  return (s:gsub("^%s*(.-)%s*$", "%1"))
+end
+
+M.shout = function(text)
+  local function upper(s)
+    return s:upper()
+  end
+  return upper(trim(text)) .. "!"
+end
+
+M.handlers = {
+  on_save = function(buf)
+    print("saved " .. buf)
+  end,
+  priority = 10,
+}
+
+function M.Counter:increment(step)
+  self.count = self.count + (step or 1)
+end
+
+return M
@@ -0,0 +1,6 @@
+package main
+
+/* 😀 emoji */ var Smile = "😀"
+
+// 你好 in a comment
+func Hello() {}
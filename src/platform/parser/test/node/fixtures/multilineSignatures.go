@@ -0,0 +1,20 @@
+package multiline
+
+func Map[T any, U any](
+	s []T,
+	f func(T) U,
+) []U {
+	return nil
+}
+
+func Join(
+	parts []string,   sep   string) string {
+	return ""
+}
+
+type Handler interface {
+	Handle(
+		ctx context.Context,
+		req *Request,
+	) error
+}
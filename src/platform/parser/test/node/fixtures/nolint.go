@@ -0,0 +1,28 @@
+package store
+
+import "os"
+
+var cache = map[string]string{} //nolint:gochecknoglobals
+
+// Close closes the file, ignoring the error.
+//nolint:errcheck
+func Close(f *os.File) {
+	f.Close()
+}
+
+func Remove(name string) { //nolint
+	os.Remove(name)
+}
+
+//nolint:govet,errcheck // the result is checked by the caller
+func Open(name string) (*os.File, error) {
+	return os.Open(name)
+}
+
+//lint:ignore SA1019 the old API is still needed
+func Legacy() {}
+
+// nolint is only a directive at the start of the line above a declaration.
+func Checked() error {
+	return nil
+}
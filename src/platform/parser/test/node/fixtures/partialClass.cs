@@ -0,0 +1,39 @@
+using System;
+
+namespace Shop.Orders;
+
+public interface IOrder
+{
+    decimal Total { get; }
+}
+
+public struct Money
+{
+    public decimal Amount { get; init; }
+}
+
+public record LineItem(string Sku, int Quantity);
+
+/// <summary>An order of line items.</summary>
+public partial class Order : IOrder
+{
+    public decimal Total { get; private set; }
+
+    public void Add(LineItem item)
+    {
+        Total += item.Quantity;
+    }
+}
+
+// Building and validation live in the second part.
+public partial class Order
+{
+    public class Builder
+    {
+        public Order Build() => new Order();
+    }
+
+    private void Validate()
+    {
+    }
+}
@@ -0,0 +1,18 @@
+locals {
+  name = "web"
+  tags = {
+    Owner = "platform"
+  }
+}
+
+resource "aws_instance" "web" {
+  ami = "ami-123456"
+
+  provisioner "remote-exec" {
+    inline = ["sudo apt-get update"]
+
+    connection {
+      type = "ssh"
+    }
+  }
+}
@@ -0,0 +1,36 @@
+package iterators
+
+import "iter"
+
+func Countdown(n int) {
+	for i := range n {
+		println(n - i)
+	}
+	for range 3 {
+		println("tick")
+	}
+}
+
+func Pairs(m map[string]int) iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		for k, v := range m {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+func Sum(seq iter.Seq[int]) (total int) {
+	for v := range seq {
+		total += v
+	}
+	return total
+}
+
+func main() {
+	for k, v := range Pairs(map[string]int{"a": 1}) {
+		println(k, v)
+	}
+	for i := range 10 {}
+}
@@ -0,0 +1,48 @@
+package shapes
+
+import "strings"
+
+type Point struct {
+	X, Y int
+}
+
+type (
+	Celsius float64
+	Names   []string
+)
+
+type Stack[T any] struct {
+	items []T
+}
+
+// String formats the point.
+func (p Point) String() string {
+	return "(" + string(rune(p.X)) + ", " + string(rune(p.Y)) + ")"
+}
+
+// Move shifts the point in place.
+func (p *Point) Move(dx, dy int) {
+	p.X += dx
+	p.Y += dy
+}
+
+func (c Celsius) Fahrenheit() float64 {
+	return float64(c)*9/5 + 32
+}
+
+func (n *Names) Add(name string) {
+	*n = append(*n, name)
+}
+
+func (s *Stack[T]) Push(item T) {
+	s.items = append(s.items, item)
+}
+
+// Reset is declared for a type declared in another file of the package.
+func (b *Builder) Reset() {
+	b.parts = nil
+}
+
+func Join(names Names) string {
+	return strings.Join(names, ", ")
+}
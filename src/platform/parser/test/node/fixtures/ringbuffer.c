@@ -0,0 +1,32 @@
+#include <stddef.h>
+#include <stdlib.h>
+
+#define RING_CAPACITY 64
+#define RING_NEXT(i) (((i) + 1) % RING_CAPACITY)
+
+/* Fixed-size queue of bytes. */
+typedef struct ring {
+	unsigned char data[RING_CAPACITY];
+	size_t head;
+	size_t tail;
+} ring_t;
+
+typedef enum { RING_OK, RING_FULL, RING_EMPTY } ring_status;
+
+union word {
+	unsigned int u;
+	unsigned char bytes[4];
+};
+
+ring_t *ring_new(void) {
+	return calloc(1, sizeof(ring_t));
+}
+
+ring_status ring_push(ring_t *r, unsigned char byte) {
+	if (RING_NEXT(r->tail) == r->head) {
+		return RING_FULL;
+	}
+	r->data[r->tail] = byte;
+	r->tail = RING_NEXT(r->tail);
+	return RING_OK;
+}
@@ -0,0 +1,23 @@
+-- Accounts that can place orders
+CREATE TABLE IF NOT EXISTS users (
+	id INTEGER PRIMARY KEY,
+	email VARCHAR(255) NOT NULL UNIQUE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE orders (
+	id INTEGER PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES users (id),
+	total DECIMAL(10, 2) NOT NULL
+);
+
+INSERT INTO users (email) VALUES ('ada@example.com');
+
+SELECT u.email, count(o.id) AS order_count
+FROM users u
+LEFT JOIN orders o ON o.user_id = u.id
+GROUP BY u.email;
+
+UPDATE users SET email = 'grace@example.com' WHERE id = 1;
+
+DELETE FROM orders WHERE total = 0;
@@ -0,0 +1,43 @@
+#include "shapes.hpp"
+
+namespace geo {
+
+class Square : public Shape {
+public:
+	explicit Square(double side) : side_(side) {}
+	double area() const override;
+	double perimeter() const;
+	Square &scale(double factor);
+
+private:
+	double side_;
+};
+
+double Square::area() const {
+	return SQUARE(side_);
+}
+
+Square &Square::scale(double factor) {
+	side_ *= factor;
+	return *this;
+}
+
+Circle::Circle(double radius) : radius_(radius) {}
+
+double Circle::area() const {
+	return PI * SQUARE(radius_);
+}
+
+} // namespace geo
+
+double geo::Square::perimeter() const {
+	return 4 * side_;
+}
+
+double total_area(const geo::Shape *shapes[], int count) {
+	double total = 0;
+	for (int i = 0; i < count; i++) {
+		total += shapes[i]->area();
+	}
+	return total;
+}
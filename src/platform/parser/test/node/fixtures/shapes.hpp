@@ -0,0 +1,50 @@
+#ifndef SHAPES_HPP
+#define SHAPES_HPP
+
+#include <string>
+
+#define PI 3.14159265358979
+#define SQUARE(x) ((x) * (x))
+
+namespace geo {
+
+/** A point in the plane. */
+struct Point {
+	double x;
+	double y;
+};
+
+enum class Color { Red, Green, Blue };
+
+union Value {
+	int i;
+	double d;
+};
+
+typedef struct Point Vector;
+
+typedef double (*AreaFn)(const Point *vertices, int count);
+
+/** Base of all shapes. */
+class Shape {
+public:
+	virtual double area() const = 0;
+	std::string name() const { return name_; }
+
+protected:
+	std::string name_;
+};
+
+class Circle : public Shape {
+public:
+	explicit Circle(double radius);
+	double area() const override;
+	double radius() const { return radius_; }
+
+private:
+	double radius_;
+};
+
+} // namespace geo
+
+#endif // SHAPES_HPP
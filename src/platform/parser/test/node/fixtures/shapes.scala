@@ -0,0 +1,30 @@
+package shapes
+
+import scala.math.Pi
+
+/**
+ * A shape with an area.
+ */
+trait Shape {
+  def area: Double
+}
+
+case class Circle(radius: Double) extends Shape {
+  def area: Double = Pi * radius * radius
+}
+
+object Circle {
+  val unit = Circle(1)
+
+  def fromDiameter(diameter: Double): Circle = Circle(diameter / 2)
+}
+
+case object Origin
+
+class Canvas {
+  private var shapes: List[Shape] = Nil
+
+  def add(shape: Shape): Unit = {
+    shapes = shape :: shapes
+  }
+}
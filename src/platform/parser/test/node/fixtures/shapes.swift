@@ -0,0 +1,32 @@
+protocol Shape {
+    var area: Double { get }
+    func describe() -> String
+}
+
+struct Rectangle: Shape {
+    var width: Double
+    var height: Double
+
+    var area: Double {
+        width * height
+    }
+
+    var size: Double {
+        get { width }
+        set { width = newValue }
+    }
+
+    func describe() -> String {
+        "Rectangle"
+    }
+}
+
+extension Rectangle {
+    var isSquare: Bool {
+        width == height
+    }
+
+    func scaled(by factor: Double) -> Rectangle {
+        Rectangle(width: width * factor, height: height * factor)
+    }
+}
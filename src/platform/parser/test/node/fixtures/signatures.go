@@ -0,0 +1,18 @@
+package signatures
+
+import "io"
+
+// Fprintf writes the formatted arguments to w.
+func Fprintf(w io.Writer, format string, args ...any) (n int, err error) {
+	return 0, nil
+}
+
+func Swap(a, b int) (int, int) {
+	return b, a
+}
+
+func (r *Reader) Close() error {
+	return nil
+}
+
+func Reset() {}
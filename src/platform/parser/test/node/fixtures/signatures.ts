@@ -0,0 +1,34 @@
+import { Logger } from './logger';
+
+export async function fetchUser(id: string, retries = 3): Promise<User> {
+	return load(id, retries);
+}
+
+function load(id: string, retries: number) {
+	return { id, retries };
+}
+
+export class UserService {
+	constructor(private readonly logger: Logger) { }
+
+	@memoize
+	public find(id: string): User | undefined {
+		return undefined;
+	}
+
+	protected static create(): UserService {
+		return new UserService(new Logger());
+	}
+
+	private log(message: string): void {
+		this.logger.info(message);
+	}
+
+	#validate(user: User): boolean {
+		return user.id !== '';
+	}
+
+	async *all(): AsyncGenerator<User> {
+		yield* [];
+	}
+}
@@ -0,0 +1,13 @@
+package config
+
+import "os"
+
+const (
+	apiKey   = "sk-test\t1234" // a hardcoded secret
+	pattern  = `^\d+//\w*$`    // a raw string isn't unescaped
+	greeting = "Gr\u00fc\u00df Gott"
+)
+
+func URL(host string) string {
+	return "https://" + host + os.Getenv("BASE_PATH")
+}
@@ -0,0 +1,14 @@
+import re
+
+PATTERN = re.compile(r"\d+\.\d+")
+GREETING = "caf\u00e9\n"
+DOC = """Multi-line
+string"""
+
+
+def greet(name):
+    return f"Hello, {name}! {{braces}} stay"
+
+
+def label():
+    return f"{{not interpolated}}"
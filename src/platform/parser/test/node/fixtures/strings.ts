@@ -0,0 +1,7 @@
+const separator = '// not a comment';
+const escaped = "say \"hi\"\n";
+const path = `C:\\temp`;
+
+export function greet(name: string, count: number): string {
+	return `Hello, ${name}! You have ${count} new message${count === 1 ? '' : 's'}.`;
+}
@@ -0,0 +1,22 @@
+package models
+
+import "sync"
+
+type Base struct {
+	CreatedAt int64 `json:"created_at"`
+}
+
+type User struct {
+	*Base
+	sync.Mutex `json:"-"`
+	ID     int64               `json:"id" db:"user_id,primary"`
+	X, Y   float64
+	Tags   map[string][]string `json:"tags,omitempty" yaml:"tags"`
+	Legacy string              "xml:\"legacy\""
+}
+
+type Response struct {
+	Meta struct {
+		Page int `json:"page"`
+	} `json:"meta"`
+}
@@ -0,0 +1,42 @@
+import 'dart:convert';
+
+/// A point on a plane.
+class Point {
+  final double x;
+  final double y;
+
+  Point(this.x, this.y);
+
+  Point.origin() : this(0, 0);
+
+  factory Point.fromJson(Map<String, dynamic> json) {
+    return Point(json['x'] as double, json['y'] as double);
+  }
+
+  double get length => x * x + y * y;
+
+  @override
+  String toString() => 'Point($x, $y)';
+}
+
+mixin Describable {
+  String describe() => toString();
+}
+
+extension PointJson on Point {
+  String toJson() => jsonEncode({'x': x, 'y': y});
+}
+
+enum Direction { north, south }
+
+int _counter = 0;
+
+int get counter => _counter;
+
+set counter(int value) {
+  _counter = value;
+}
+
+void main() {
+  print(Point.fromJson({'x': 1.0, 'y': 2.0}));
+}
@@ -0,0 +1,34 @@
+package com.example
+
+import kotlin.math.max
+
+/**
+ * Greets people.
+ */
+interface Greeter {
+    fun greet(name: String): String
+}
+
+class Person(val name: String) : Greeter {
+    var age: Int = 0
+
+    override fun greet(name: String): String = "Hello, $name"
+
+    companion object {
+        const val DEFAULT_NAME = "Anonymous"
+
+        fun create(): Person = Person(DEFAULT_NAME)
+    }
+}
+
+object Registry {
+    fun register(person: Person) {
+        println(person.name)
+    }
+}
+
+fun String.shout(): String = uppercase() + "!"
+
+fun larger(a: Int, b: Int): Int {
+    return max(a, b)
+}
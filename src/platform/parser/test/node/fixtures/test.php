@@ -0,0 +1,56 @@
+<!DOCTYPE html>
+<html>
+<body>
+<?php
+
+namespace App\Models;
+
+use Illuminate\Database\Eloquent\Model;
+use App\Contracts\Cart as CartContract;
+
+/**
+ * Adds created and updated timestamps.
+ */
+trait HasTimestamps
+{
+    public function touch(): void
+    {
+        $this->updatedAt = time();
+    }
+}
+
+interface Priced extends Countable
+{
+    public function total(): float;
+}
+
+class Cart extends Model implements CartContract, Priced
+{
+    use HasTimestamps;
+
+    const MAX_ITEMS = 100;
+
+    private array $items = [];
+
+    public function __construct(array $items)
+    {
+        $this->items = $items;
+    }
+
+    public function count(): int
+    {
+        return count($this->items);
+    }
+
+    abstract protected function total(): float;
+}
+
+function cart(array $items): Cart
+{
+    return new Cart($items);
+}
+
+?>
+<p><?= cart([])->count() ?> items</p>
+</body>
+</html>
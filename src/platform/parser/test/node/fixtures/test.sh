@@ -0,0 +1,36 @@
+#!/usr/bin/env bash
+set -euo pipefail
+
+# Version of the release being built.
+readonly VERSION=1.0
+OUTPUT_DIR="${OUTPUT_DIR:-dist}"
+export PATH
+
+source ./lib.sh
+
+# Prints a greeting for the given name.
+greet() {
+	local name="$1"
+	echo "Hello, ${name}!"
+}
+
+function cleanup {
+	rm -rf "$OUTPUT_DIR"
+}
+
+function build() (
+	cd "$OUTPUT_DIR"
+	make all
+)
+
+in_subshell() (
+	TMP_DIR=$(mktemp -d)
+	echo "$TMP_DIR"
+)
+
+for target in linux darwin; do
+	greet "$target"
+done
+
+trap cleanup EXIT
+build
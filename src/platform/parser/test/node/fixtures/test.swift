@@ -0,0 +1,37 @@
+import Foundation
+
+/// Something that can be greeted.
+protocol Greeter {
+    func greet() -> String
+}
+
+struct Person: Greeter {
+    let name: String
+
+    func greet() -> String {
+        return "Hello, \(name)"
+    }
+}
+
+enum Direction {
+    case north
+    case south
+}
+
+class Counter {
+    var count = 0
+
+    init(count: Int) {
+        self.count = count
+    }
+
+    func increment() {
+        count += 1
+    }
+}
+
+extension Counter {
+    func reset() {
+        count = 0
+    }
+}
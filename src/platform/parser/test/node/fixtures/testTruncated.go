@@ -0,0 +1,49 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+)
+
+const (
+    ConstExample = "const before vars"
+)
+
+var (
+    BoolExample bool
+    IntExample  int
+)
+
+type StructExample struct {
+    Name string
+}
+
+type InterfaceExample interface {
+    MethodExample() error
+}
+
+func (s StructExample) MethodExample() error {
+    if s.Name == "" {
+        return errors.New("Name cannot be empty")
+    }
+    fmt.Println(s.Name)
+    return nil
+}
+
+func main() {
+    BoolExample = true
+    IntExample = 10
+
+    arrayExample := [3]int{1, 2, 3}
+    sliceExample := arrayExample[:2]
+
+    mapExample := map[string]int{
+        "one": 1,
+        "two": 2,
+    }
+
+    structExample := StructExample{
+        Name: "Example",
+    }
+
+    var i Interfa
\ No newline at end of file
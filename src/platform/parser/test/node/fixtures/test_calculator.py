@@ -0,0 +1,37 @@
+import unittest
+
+import pytest
+
+from calculator import Calculator
+
+
+def test_add():
+    assert Calculator().add(1, 2) == 3
+
+
+@pytest.mark.parametrize("a, b", [(1, 2), (3, 4)])
+def test_add_is_commutative(a, b):
+    assert Calculator().add(a, b) == Calculator().add(b, a)
+
+
+def helper():
+    return Calculator()
+
+
+class CalculatorTest(unittest.TestCase):
+    def setUp(self):
+        self.calculator = Calculator()
+
+    def test_clear(self):
+        self.calculator.clear()
+        self.assertEqual(self.calculator.memory, 0)
+
+
+class TestMemory:
+    def test_starts_empty(self):
+        assert Calculator().memory == 0
+
+
+class Fixtures:
+    def test_data(self):
+        return [1, 2, 3]
@@ -0,0 +1,26 @@
+package main
+
+import "errors"
+
+type StructExample struct {
+    Name string
+}
+
+// TODO: handle empty name
+func (s StructExample) MethodExample() error {
+    if s.Name == "" {
+        // FIXME(ana): wrap the error
+        return errors.New("Name cannot be empty")
+    }
+    return nil
+}
+
+// Legacy is kept for old callers.
+// HACK around the v1 API.
+func Legacy() {}
+
+// XXX - drop in v3
+func Old() {}
+
+// todos are tracked in the issue tracker; it's not a hack either
+func main() {}
@@ -0,0 +1,20 @@
+package unicode
+
+// Greeting says hi with an emoji 😀.
+func Greeting() string {
+	return "😀 hello"
+}
+
+// Cafe returns "cafe" with a combining acute accent, i.e., e + U+0301.
+func Cafe() string {
+	return "café"
+}
+
+// 你好 uses a CJK identifier.
+func 你好() string {
+	return "世界"
+}
+
+func After() string {
+	return "ascii"
+}
@@ -0,0 +1,11 @@
+package main
+
+type Before struct {
+	Name string
+}
+
+func broken() {
+	x := "unterminated
+}
+
+func After() {}
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	_ "embed"
+	. "math"
+)
+
+func main() {
+	fmt.Println(Pi)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"sync"
+)
+
+type Config struct {
+	Port int
+}
+
+func main() {
+	ch := make(chan int)
+	buf := make([]byte, 0, 64)
+	counts := make(map[string]int)
+	cfg := &Config{Port: 8080}
+	alloc := new(Config)
+	point := struct{ X, Y int }{1, 2}
+	handler := func() {}
+	grid := [...]int{1, 2, 3}
+	words := strings.Fields("a b")
+	var mu sync.Mutex
+	var done <-chan struct{}
+	var callback func(int) error
+	var table = map[string][]int{}
+	a, b := 1, 2
+	_, _, _, _, _, _, _, _, _, _, _, _ = ch, buf, counts, cfg, alloc, point, handler, grid, words, done, callback, table
+	_, _ = a, b
+	mu.Lock()
+}
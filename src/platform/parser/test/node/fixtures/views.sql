@@ -0,0 +1,11 @@
+CREATE VIEW active_users AS
+SELECT id, email FROM users WHERE created_at > '2024-01-01';
+
+CREATE UNIQUE INDEX idx_users_email ON users (email);
+
+CREATE INDEX idx_orders_user ON orders (user_id);
+
+-- a dialect-specific statement that the grammar may not know
+CREATE PROCEDURE archive_orders() BEGIN DELETE FROM orders WHERE total = 0; END;
+
+SELECT email FROM active_users;
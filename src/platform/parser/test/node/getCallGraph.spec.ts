@@ -0,0 +1,70 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _getCallGraph } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getCallGraph', () => {
+
+	afterAll(() => _dispose());
+
+	test('go - calls per function and method', async () => {
+		const source = await fromFixture('test.go');
+
+		const callGraph = await _getCallGraph(WASMLanguage.Go, source);
+
+		const callees = (name: string) => callGraph.find(e => e.caller.name === name)?.calls.map(c => c.callee);
+		expect(callees('MethodExample')).toEqual(['errors.New', 'fmt.Println']);
+		expect(new Set(callees('main'))).toEqual(new Set(['i.MethodExample', 'fmt.Println']));
+		expect(callees('main')).toHaveLength(9);
+
+		for (const { calls } of callGraph) {
+			for (const call of calls) {
+				expect(source.substring(call.startIndex, call.endIndex).startsWith(`${call.callee}(`)).toBe(true);
+			}
+		}
+	});
+
+	test('go - calls in anonymous functions belong to the enclosing function', async () => {
+		const source = [
+			'package main',
+			'',
+			'func run(ch chan int) {',
+			'\tgo func() {',
+			'\t\tclose(ch)',
+			'\t}()',
+			'\tb.Reset().WriteString("x")',
+			'}',
+			'',
+			'func idle() {}',
+		].join('\n');
+
+		const callGraph = await _getCallGraph(WASMLanguage.Go, source);
+
+		expect(callGraph.map(e => ({ caller: e.caller.name, callees: e.calls.map(c => c.callee) }))).toEqual([
+			{ caller: 'run', callees: ['close', 'b.Reset', 'b.Reset().WriteString'] },
+			{ caller: 'idle', callees: [] },
+		]);
+	});
+
+	test('typescript - nested named functions get their own calls', async () => {
+		const source = [
+			'function outer() {',
+			'\tconst inner = () => helper(1);',
+			'\tconsole.log(inner());',
+			'}',
+			'setup();',
+		].join('\n');
+
+		const callGraph = await _getCallGraph(WASMLanguage.TypeScript, source);
+
+		expect(callGraph.map(e => ({ caller: e.caller.name, callees: e.calls.map(c => c.callee) }))).toEqual([
+			{ caller: 'outer', callees: ['console.log', 'inner'] },
+			{ caller: 'inner', callees: ['helper'] },
+		]);
+	});
+});
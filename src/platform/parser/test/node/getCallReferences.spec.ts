@@ -0,0 +1,91 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _getCallReferences } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getCallReferences', () => {
+
+	afterAll(() => _dispose());
+
+	async function getCalls(language: WASMLanguage, source: string, range = { startIndex: 0, endIndex: source.length }) {
+		const calls = await _getCallReferences(language, source, range);
+		return calls.map(({ callee, receiver, argumentCount }) => ({ callee, receiver, argumentCount }));
+	}
+
+	test('go - calls within a function', async () => {
+		const source = await fromFixture('test.go');
+		const startIndex = source.indexOf('func main()');
+		const endIndex = source.indexOf('\n}\n', startIndex) + 2;
+
+		expect(await getCalls(WASMLanguage.Go, source, { startIndex, endIndex })).toEqual([
+			{ callee: 'MethodExample', receiver: 'i', argumentCount: 0 },
+			{ callee: 'Println', receiver: 'fmt', argumentCount: 1 },
+			{ callee: 'Println', receiver: 'fmt', argumentCount: 2 },
+			{ callee: 'Println', receiver: 'fmt', argumentCount: 1 },
+			{ callee: 'Println', receiver: 'fmt', argumentCount: 1 },
+			{ callee: 'Println', receiver: 'fmt', argumentCount: 1 },
+			{ callee: 'Println', receiver: 'fmt', argumentCount: 1 },
+			{ callee: 'Println', receiver: 'fmt', argumentCount: 1 },
+			{ callee: 'Println', receiver: 'fmt', argumentCount: 2 },
+		]);
+	});
+
+	test('go - builtins, nested calls, and chains', async () => {
+		const source = [
+			'package main',
+			'',
+			'func run() {',
+			'\txs := make([]int, 0, 8)',
+			'\txs = append(xs, len(xs))',
+			'\tb.Reset().WriteString("x")',
+			'}',
+		].join('\n');
+
+		expect(await getCalls(WASMLanguage.Go, source)).toEqual([
+			{ callee: 'make', receiver: undefined, argumentCount: 3 },
+			{ callee: 'append', receiver: undefined, argumentCount: 2 },
+			{ callee: 'len', receiver: undefined, argumentCount: 1 },
+			{ callee: 'Reset', receiver: 'b', argumentCount: 0 },
+			{ callee: 'WriteString', receiver: 'b.Reset()', argumentCount: 1 },
+		]);
+	});
+
+	test('typescript - each call of a chain is reported once', async () => {
+		const source = 'foo(a, b);\nobj.bar().baz(1);\nnew Foo();';
+
+		expect(await getCalls(WASMLanguage.TypeScript, source)).toEqual([
+			{ callee: 'foo', receiver: undefined, argumentCount: 2 },
+			{ callee: 'bar', receiver: 'obj', argumentCount: 0 },
+			{ callee: 'baz', receiver: 'obj.bar()', argumentCount: 1 },
+		]);
+	});
+
+	test('calls not entirely within the range are skipped', async () => {
+		const source = 'foo(bar(1));\nbaz();';
+
+		const calls = await getCalls(WASMLanguage.TypeScript, source, { startIndex: 4, endIndex: source.length });
+
+		expect(calls).toEqual([
+			{ callee: 'bar', receiver: undefined, argumentCount: 1 },
+			{ callee: 'baz', receiver: undefined, argumentCount: 0 },
+		]);
+	});
+
+	test('python - method calls with keyword arguments', async () => {
+		const source = 'result = client.get(url, timeout=5)\nprint(result)';
+
+		expect(await getCalls(WASMLanguage.Python, source)).toEqual([
+			{ callee: 'get', receiver: 'client', argumentCount: 2 },
+			{ callee: 'print', receiver: undefined, argumentCount: 1 },
+		]);
+	});
+
+	test('unsupported language yields no calls', async () => {
+		expect(await getCalls(WASMLanguage.Ruby, 'foo(1)')).toEqual([]);
+	});
+});
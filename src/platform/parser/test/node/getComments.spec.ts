@@ -0,0 +1,66 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _getComments } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getComments', () => {
+
+	afterAll(() => _dispose());
+
+	test('go - comments but not string literals that contain comment delimiters', async () => {
+		const source = await fromFixture('comments.go');
+
+		const comments = await _getComments(WASMLanguage.Go, source);
+
+		expect(comments.map(({ text, isBlock, marker }) => ({ text, isBlock, marker }))).toEqual([
+			{ text: '// TODO: split on any whitespace', isBlock: false, marker: 'TODO' },
+			{ text: '// NOTE: raw strings can contain // too', isBlock: false, marker: 'NOTE' },
+			{ text: '/* FIXME the URL\n\t   isn\'t used */', isBlock: true, marker: 'FIXME' },
+			{ text: '/*\n * HACK: the last word is dropped\n */', isBlock: true, marker: 'HACK' },
+			{ text: '// note that this isn\'t a marker', isBlock: false, marker: undefined },
+		]);
+		for (const comment of comments) {
+			expect(source.substring(comment.startIndex, comment.endIndex)).toBe(comment.text);
+		}
+	});
+
+	test('ts - doc comments and markers after the delimiters', async () => {
+		const source = [
+			`const separator = '// not a comment';`,
+			`/** TODO document the separator */`,
+			`const template = \`/* not a comment either */\`;`,
+			`//TODO(joe) without a space`,
+		].join('\n');
+
+		const comments = await _getComments(WASMLanguage.TypeScript, source);
+
+		expect(comments.map(({ text, isBlock, marker }) => ({ text, isBlock, marker }))).toEqual([
+			{ text: '/** TODO document the separator */', isBlock: true, marker: 'TODO' },
+			{ text: '//TODO(joe) without a space', isBlock: false, marker: 'TODO' },
+		]);
+	});
+
+	test('lua - dash comments use the same markers as structure annotations', async () => {
+		const source = `local n = 0 -- XXX: global in disguise\n-- todo is not a marker in lower case\n`;
+
+		const comments = await _getComments(WASMLanguage.Lua, source);
+
+		expect(comments.map(({ text, marker }) => ({ text, marker }))).toEqual([
+			{ text: '-- XXX: global in disguise', marker: 'XXX' },
+			{ text: '-- todo is not a marker in lower case', marker: undefined },
+		]);
+	});
+
+	test('python - hash comments', async () => {
+		const source = `x = "# not a comment"  # FIXME: rename x\n`;
+
+		expect(await _getComments(WASMLanguage.Python, source)).toEqual([
+			{ startIndex: 23, endIndex: source.length - 1, text: '# FIXME: rename x', isBlock: false, marker: 'FIXME' },
+		]);
+	});
+});
@@ -0,0 +1,64 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _getConcurrencyInfo } from '../../node/parserImpl';
+import { fromFixture } from './getStructure.util';
+
+suite('getConcurrencyInfo', () => {
+
+	afterAll(() => _dispose());
+
+	test('goroutines, channels, and select blocks of well-formed source', async () => {
+		const source = await fromFixture('concurrency.go');
+
+		const info = await _getConcurrencyInfo(source);
+
+		expect(info.goStatements.map(s => s.text.split('\n')[0])).toEqual([
+			'go worker(jobs, results)',
+			'go func() {',
+		]);
+		expect(info.channelMakes.map(m => m.text)).toEqual([
+			'make(chan int, 8)',
+			'make(chan int, 8)',
+			'make(chan struct{})',
+		]);
+		expect(info.channelOps.map(({ kind, channel, text }) => ({ kind, channel, text }))).toEqual([
+			{ kind: 'send', channel: 'results', text: 'results <- j * 2' },
+			{ kind: 'send', channel: 'jobs', text: 'jobs <- i' },
+			{ kind: 'close', channel: 'jobs', text: 'close(jobs)' },
+			{ kind: 'close', channel: 'done', text: 'close(done)' },
+			{ kind: 'receive', channel: 'results', text: '<-results' },
+			{ kind: 'receive', channel: 'done', text: '<-done' },
+		]);
+		expect(info.selects).toHaveLength(1);
+		expect(info.selects[0].text).toMatch(/^select \{[\s\S]*\}$/);
+		for (const construct of [...info.goStatements, ...info.channelMakes, ...info.channelOps, ...info.selects]) {
+			expect(source.substring(construct.startIndex, construct.endIndex)).toBe(construct.text);
+		}
+	});
+
+	test('constructs at the top level of a file are found within parse errors', async () => {
+		const source = await fromFixture('test.go');
+
+		const info = await _getConcurrencyInfo(source);
+
+		expect(info.channelMakes.map(m => m.text)).toEqual(['make(chan int)']);
+		expect(info.goStatements).toHaveLength(1);
+		expect(info.goStatements[0].startIndex).toBe(source.indexOf('go func() {'));
+		expect(info.goStatements[0].text).toMatch(/\}\(\)$/);
+		expect(info.channelOps.map(({ kind, channel }) => ({ kind, channel }))).toEqual([
+			{ kind: 'send', channel: 'ch' },
+			{ kind: 'close', channel: 'ch' },
+		]);
+		expect(info.selects).toEqual([]);
+	});
+
+	test('make of other types is not a channel creation', async () => {
+		const source = 'package main\n\nfunc f() {\n\ts := make([]int, 3)\n\tm := make(map[string]int)\n\t_, _ = s, m\n}\n';
+
+		expect((await _getConcurrencyInfo(source)).channelMakes).toEqual([]);
+	});
+});
@@ -0,0 +1,61 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose } from '../../node/parserImpl';
+import { ParserServiceImpl } from '../../node/parserServiceImpl';
+import { getEnclosingSymbol, structureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getEnclosingSymbol', () => {
+
+	afterAll(() => _dispose());
+
+	async function enclosingSymbolAt(source: string, text: string) {
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+		const symbol = getEnclosingSymbol(structure!, source.indexOf(text));
+		return symbol && { kind: symbol.kind, name: symbol.name };
+	}
+
+	test('offset within a loop yields the function containing the loop', async () => {
+		const source = await fromFixture('test.go');
+
+		expect(await enclosingSymbolAt(source, 'fmt.Println(i, v)')).toEqual({ kind: 'function_declaration', name: 'main' });
+		expect(await enclosingSymbolAt(source, 'fmt.Println("Ten")')).toEqual({ kind: 'function_declaration', name: 'main' });
+	});
+
+	test('offset within a method body yields the method', async () => {
+		const source = await fromFixture('test.go');
+
+		expect(await enclosingSymbolAt(source, 'errors.New')).toEqual({ kind: 'method_declaration', name: 'MethodExample' });
+	});
+
+	test('offset within a type yields the type or its method', async () => {
+		const source = await fromFixture('test.go');
+
+		expect(await enclosingSymbolAt(source, 'Name string')).toEqual({ kind: 'type_declaration', name: 'StructExample' });
+		expect(await enclosingSymbolAt(source, 'MethodExample() error')).toEqual({ kind: 'method_spec', name: 'MethodExample' });
+	});
+
+	test('offset outside any declaration yields nothing', async () => {
+		const source = await fromFixture('test.go');
+
+		expect(await enclosingSymbolAt(source, 'package main')).toBeUndefined();
+		expect(await enclosingSymbolAt(source, 'ch <- i')).toBeUndefined();
+	});
+
+	test('parser service looks up symbols in structures received from the parser', async () => {
+		const source = await fromFixture('test.go');
+		const parserService = new ParserServiceImpl(/* useWorker */ false);
+		try {
+			const structure = await parserService.getTreeSitterASTForWASMLanguage(WASMLanguage.Go, source).getStructure();
+
+			expect(parserService.getEnclosingSymbol(structure!, source.indexOf('fmt.Println(s.Name)'))?.name).toBe('MethodExample');
+		} finally {
+			parserService.dispose();
+		}
+	});
+});
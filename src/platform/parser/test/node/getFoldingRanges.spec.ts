@@ -0,0 +1,75 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _getFoldingRanges } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getFoldingRanges', () => {
+
+	afterAll(() => _dispose());
+
+	test('go - source with different syntax constructs', async () => {
+		const source = await fromFixture('test.go');
+
+		const ranges = await _getFoldingRanges(WASMLanguage.Go, source);
+
+		const lines = source.split('\n');
+		expect(ranges.map(({ startLine, endLine }) => `${lines[startLine].trim()} [${startLine}-${endLine}]`)).toEqual([
+			'import ( [2-4]',
+			'const ( [7-8]',
+			'var ( [11-13]',
+			'type StructExample struct { [16-17]',
+			'type InterfaceExample interface { [20-21]',
+			'func (s StructExample) MethodExample() error { [24-29]',
+			'if s.Name == "" { [25-26]',
+			'func main() { [32-74]',
+			'mapExample := map[string]int{ [39-41]',
+			'structExample := StructExample{ [44-45]',
+			'if err := i.MethodExample(); err != nil { [49-50]',
+			'for i, v := range sliceExample { [53-54]',
+			'if BoolExample { [57-58]',
+			'} else { [59-60]',
+			'switch IntExample { [63-69]',
+			'case 0: [64-65]',
+			'case 10: [66-67]',
+			'default: [68-69]',
+			'for key, value := range mapExample { [72-73]',
+			'go func() { [81-85]',
+			'for i := 0; i < 5; i++ { [82-83]',
+		]);
+	});
+
+	test('single-line constructs are not folded', async () => {
+		const source = 'const a = [1, 2, 3];\nfunction f() { return { a }; }\n';
+
+		expect(await _getFoldingRanges(WASMLanguage.TypeScript, source)).toEqual([]);
+	});
+
+	test('typescript - bodies and literals', async () => {
+		const source = [
+			'interface Foo {',
+			'\ta: number;',
+			'}',
+			'function f() {',
+			'\tconst o = {',
+			'\t\ta: 1,',
+			'\t};',
+			'\treturn o;',
+			'}',
+		].join('\n');
+
+		expect(await _getFoldingRanges(WASMLanguage.TypeScript, source)).toEqual([
+			{ startLine: 0, endLine: 1 },
+			{ startLine: 3, endLine: 7 },
+			{ startLine: 4, endLine: 5 },
+		]);
+	});
+
+	test('unsupported language yields no ranges', async () => {
+		expect(await _getFoldingRanges(WASMLanguage.Ruby, 'def foo\n  1\nend\n')).toEqual([]);
+	});
+});
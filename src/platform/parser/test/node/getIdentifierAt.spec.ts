@@ -0,0 +1,83 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _getIdentifierAt } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getIdentifierAt', () => {
+
+	afterAll(() => _dispose());
+
+	/**
+	 * @returns range of `identifier`, which is searched for after `context`
+	 */
+	function rangeOf(source: string, context: string, identifier: string) {
+		const startIndex = source.indexOf(identifier, source.indexOf(context));
+		return { startIndex, endIndex: startIndex + identifier.length };
+	}
+
+	suite('go', () => {
+
+		test('method in a call is a reference', async () => {
+			const source = await fromFixture('test.go');
+			const range = rangeOf(source, 'i.MethodExample()', 'MethodExample');
+
+			const identifier = await _getIdentifierAt(WASMLanguage.Go, source, range.startIndex + 3);
+
+			expect(identifier).toEqual({ name: 'MethodExample', range, kind: 'reference' });
+		});
+
+		test('method in its declaration header is a definition', async () => {
+			const source = await fromFixture('test.go');
+			const range = rangeOf(source, 'func (s StructExample) MethodExample', 'MethodExample');
+
+			const identifier = await _getIdentifierAt(WASMLanguage.Go, source, range.startIndex);
+
+			expect(identifier).toEqual({ name: 'MethodExample', range, kind: 'definition' });
+		});
+
+		test('type is a definition where it is declared and a reference elsewhere', async () => {
+			const source = await fromFixture('test.go');
+			const declaration = rangeOf(source, 'type StructExample', 'StructExample');
+			const receiver = rangeOf(source, 'func (s StructExample)', 'StructExample');
+
+			expect((await _getIdentifierAt(WASMLanguage.Go, source, declaration.startIndex))?.kind).toBe('definition');
+			expect((await _getIdentifierAt(WASMLanguage.Go, source, receiver.startIndex))?.kind).toBe('reference');
+		});
+
+		test('offset right after an identifier yields the identifier', async () => {
+			const source = await fromFixture('test.go');
+			const range = rangeOf(source, 'func (s StructExample) MethodExample', 'MethodExample');
+
+			const identifier = await _getIdentifierAt(WASMLanguage.Go, source, range.endIndex);
+
+			expect(identifier?.range).toEqual(range);
+		});
+
+		test('offset on a keyword, in a string literal, or in whitespace yields nothing', async () => {
+			const source = await fromFixture('test.go');
+
+			expect(await _getIdentifierAt(WASMLanguage.Go, source, source.indexOf('func main') + 1)).toBeUndefined();
+			expect(await _getIdentifierAt(WASMLanguage.Go, source, source.indexOf('"Example"') + 2)).toBeUndefined();
+			expect(await _getIdentifierAt(WASMLanguage.Go, source, source.indexOf('\n\nfunc main') + 1)).toBeUndefined();
+		});
+	});
+
+	suite('typescript', () => {
+
+		test('parameter is a definition in the parameter list and a reference in the body', async () => {
+			const source = 'function greet(name: string) {\n\treturn `Hello, ${name}`;\n}\n';
+
+			const parameter = await _getIdentifierAt(WASMLanguage.TypeScript, source, source.indexOf('name'));
+			const use = await _getIdentifierAt(WASMLanguage.TypeScript, source, source.lastIndexOf('name'));
+
+			expect(parameter).toEqual({ name: 'name', range: { startIndex: 15, endIndex: 19 }, kind: 'definition' });
+			expect(use?.kind).toBe('reference');
+			expect((await _getIdentifierAt(WASMLanguage.TypeScript, source, source.indexOf('greet')))?.kind).toBe('definition');
+		});
+	});
+});
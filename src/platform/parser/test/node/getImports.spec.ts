@@ -0,0 +1,124 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _getImports, _getUnusedGoImports } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getImports', () => {
+
+	afterAll(() => _dispose());
+
+	async function getImports(language: WASMLanguage, source: string) {
+		const imports = await _getImports(language, source);
+		for (const i of imports) {
+			expect(source.substring(i.startIndex, i.endIndex)).toBe(i.text);
+		}
+		return imports.map(({ text, path, alias }) => ({ text, path, alias }));
+	}
+
+	test('go - grouped imports', async () => {
+		const source = await fromFixture('test.go');
+
+		expect(await getImports(WASMLanguage.Go, source)).toEqual([
+			{ text: '"errors"', path: 'errors' },
+			{ text: '"fmt"', path: 'fmt' },
+		]);
+	});
+
+	test('go - aliased imports', async () => {
+		const source = [
+			'package main',
+			'',
+			'import f "fmt"',
+			'import _ "embed"',
+			'import "os"',
+		].join('\n');
+
+		expect(await getImports(WASMLanguage.Go, source)).toEqual([
+			{ text: 'f "fmt"', path: 'fmt', alias: 'f' },
+			{ text: '_ "embed"', path: 'embed', alias: '_' },
+			{ text: '"os"', path: 'os' },
+		]);
+	});
+
+	test('typescript', async () => {
+		const source = [
+			`import * as fs from 'fs';`,
+			`import { join } from 'path';`,
+			`import type { Foo } from './foo';`,
+			`import def from "./def";`,
+			`const cp = require('child_process');`,
+			`const { a } = require('./a');`,
+			`require('./side-effect');`,
+			`const notAnImport = load('./b');`,
+		].join('\n');
+
+		expect(await getImports(WASMLanguage.TypeScript, source)).toEqual([
+			{ text: `import * as fs from 'fs';`, path: 'fs', alias: 'fs' },
+			{ text: `import { join } from 'path';`, path: 'path' },
+			{ text: `import type { Foo } from './foo';`, path: './foo' },
+			{ text: `import def from "./def";`, path: './def' },
+			{ text: `const cp = require('child_process');`, path: 'child_process', alias: 'cp' },
+			{ text: `const { a } = require('./a');`, path: './a' },
+			{ text: `require('./side-effect');`, path: './side-effect' },
+		]);
+	});
+
+	test('python', async () => {
+		const source = [
+			'import os',
+			'import numpy as np',
+			'from . import foo',
+			'from a.b import c',
+		].join('\n');
+
+		expect(await getImports(WASMLanguage.Python, source)).toEqual([
+			{ text: 'import os', path: 'os' },
+			{ text: 'import numpy as np', path: 'numpy', alias: 'np' },
+			{ text: 'from . import foo', path: '.' },
+			{ text: 'from a.b import c', path: 'a.b' },
+		]);
+	});
+
+	test('unsupported language', async () => {
+		expect(await getImports(WASMLanguage.Ruby, `require 'json'`)).toEqual([]);
+	});
+
+	test('go - unused imports', async () => {
+		const source = await fromFixture('unusedImports.go');
+
+		const unused = await _getUnusedGoImports(source);
+
+		expect(unused.map(({ text, path }) => ({ text, path }))).toEqual([
+			{ text: '"strings"', path: 'strings' },
+		]);
+	});
+
+	test('go - unused imports by alias and qualified type', async () => {
+		const source = [
+			'package main',
+			'',
+			'import (',
+			'	f "fmt"',
+			'	"io"',
+			'	"os"',
+			'	yaml "gopkg.in/yaml.v3"',
+			'	"github.com/go-chi/chi/v5"',
+			')',
+			'',
+			'var w io.Writer = nil',
+			'',
+			'func main() {',
+			'	f.Println(chi.NewRouter())',
+			'}',
+		].join('\n');
+
+		const unused = await _getUnusedGoImports(source);
+
+		expect(unused.map(i => i.path)).toEqual(['os', 'gopkg.in/yaml.v3']);
+	});
+});
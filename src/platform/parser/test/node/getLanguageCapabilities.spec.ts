@@ -0,0 +1,56 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _getLanguageCapabilities, _getSignatures, _getTests } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { foldingRangeQueries, importQueries, signatureQueries, syntacticallyValidAtoms, testDeclarationQueries } from '../../node/treeSitterQueries';
+
+suite('getLanguageCapabilities', () => {
+
+	afterAll(() => _dispose());
+
+	test('go supports everything', () => {
+		expect(_getLanguageCapabilities(WASMLanguage.Go)).toEqual({
+			structure: true,
+			signatures: true,
+			imports: true,
+			folding: true,
+			tests: true,
+			testableNodes: true,
+			docComments: true,
+			callReferences: true,
+			typeDeclarations: true,
+			stringLiterals: true,
+		});
+	});
+
+	test('ruby has a structure but no signatures', () => {
+		const capabilities = _getLanguageCapabilities(WASMLanguage.Ruby);
+		expect(capabilities.structure).toBe(true);
+		expect(capabilities.signatures).toBe(false);
+	});
+
+	test('every language with queries for a feature reports it', () => {
+		for (const language of Object.values(WASMLanguage)) {
+			const capabilities = _getLanguageCapabilities(language);
+			expect(capabilities.structure, language).toBe(syntacticallyValidAtoms[language].length > 0);
+			expect(capabilities.signatures, language).toBe(signatureQueries[language] !== undefined);
+			expect(capabilities.imports, language).toBe(importQueries[language] !== undefined);
+			expect(capabilities.folding, language).toBe(foldingRangeQueries[language] !== undefined);
+			expect(capabilities.tests, language).toBe(testDeclarationQueries[language] !== undefined);
+		}
+	});
+
+	test('an unsupported feature yields nothing, even for source it would find something in', async () => {
+		const source = 'require "set"\n\ndef test_foo\n  1\nend\n';
+		const capabilities = _getLanguageCapabilities(WASMLanguage.Ruby);
+
+		expect(capabilities.signatures).toBe(false);
+		expect(await _getSignatures(WASMLanguage.Ruby, source)).toEqual([]);
+		expect(capabilities.tests).toBe(false);
+		expect(await _getTests(WASMLanguage.Ruby, source)).toEqual([]);
+	});
+});
@@ -0,0 +1,69 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { getMarkdownCodeBlockStructures } from '../../node/markdownCodeBlocks';
+import { LineCharacterPosition } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { descendants, fromFixture } from './getStructure.util';
+
+suite('getMarkdownCodeBlockStructures', () => {
+
+	afterAll(() => _dispose());
+
+	test('only blocks tagged with a supported language yield structures, with offsets within the document', async () => {
+		const markdown = await fromFixture('codeBlocks.md');
+		const goSource = await fromFixture('test.go');
+
+		const blocks = await getMarkdownCodeBlockStructures(markdown);
+
+		expect(blocks.map(b => b.language)).toEqual([WASMLanguage.Go]);
+		const [block] = blocks;
+		const blockStart = markdown.indexOf(goSource);
+		expect(block.startIndex).toBe(blockStart);
+		expect(markdown.slice(block.startIndex, block.endIndex)).toBe(goSource);
+
+		const expected = descendants((await structureComputer.getStructure(WASMLanguage.Go, goSource))!);
+		const actual = descendants(block.structure);
+		expect(actual.map(n => ({ kind: n.kind, name: n.name, startIndex: n.startIndex, endIndex: n.endIndex }))).toEqual(
+			expected.map(n => ({ kind: n.kind, name: n.name, startIndex: n.startIndex + blockStart, endIndex: n.endIndex + blockStart }))
+		);
+
+		const positionAt = LineCharacterPosition.converterFor(markdown);
+		for (const node of actual) {
+			expect(node.range).toEqual({ start: positionAt(node.startIndex), end: positionAt(node.endIndex) });
+		}
+	});
+
+	test('the cached structure of the code is left unchanged', async () => {
+		const markdown = await fromFixture('codeBlocks.md');
+		const goSource = await fromFixture('test.go');
+		const before = JSON.stringify(await structureComputer.getStructure(WASMLanguage.Go, goSource));
+
+		await getMarkdownCodeBlockStructures(markdown);
+
+		expect(JSON.stringify(await structureComputer.getStructure(WASMLanguage.Go, goSource))).toBe(before);
+	});
+
+	test('blocks without a language tag and unclosed blocks', async () => {
+		const markdown = [
+			'```',
+			'func untagged() {}',
+			'```',
+			'',
+			'```ts title="example.ts"',
+			'function unclosed() {}',
+			'',
+		].join('\n');
+
+		const blocks = await getMarkdownCodeBlockStructures(markdown);
+
+		expect(blocks.map(b => ({ language: b.language, code: markdown.slice(b.startIndex, b.endIndex), names: b.structure.children.map(n => n.name) }))).toEqual([
+			{ language: WASMLanguage.TypeScript, code: 'function unclosed() {}\n', names: ['unclosed'] },
+		]);
+	});
+});
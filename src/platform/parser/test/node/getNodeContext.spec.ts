@@ -0,0 +1,95 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _getNodeContext } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getNodeContext', () => {
+
+	afterAll(() => _dispose());
+
+	/**
+	 * @returns range starting at `start` and ending after `end`, which is searched for from `start`
+	 */
+	function rangeOf(source: string, start: string, end: string) {
+		const startIndex = source.indexOf(start);
+		return { startIndex, endIndex: source.indexOf(end, startIndex) + end.length };
+	}
+
+	suite('go', () => {
+
+		test('offset within a method reports the method and its receiver type', async () => {
+			const source = await fromFixture('test.go');
+
+			const context = await _getNodeContext(WASMLanguage.Go, source, source.indexOf('fmt.Println(s.Name)'));
+
+			expect(context).toEqual({
+				enclosingFunction: { name: 'MethodExample', kind: 'method_declaration', ...rangeOf(source, 'func (s StructExample)', '    return nil\n}') },
+				enclosingType: { name: 'StructExample', kind: 'type_spec', ...rangeOf(source, 'StructExample struct', '}') },
+				enclosingNamespace: { name: 'main', kind: 'package_clause', ...rangeOf(source, 'package main', 'package main') },
+			});
+		});
+
+		test('offset within a function reports no type', async () => {
+			const source = await fromFixture('test.go');
+
+			const context = await _getNodeContext(WASMLanguage.Go, source, source.indexOf('arrayExample :='));
+
+			expect(context.enclosingFunction?.name).toBe('main');
+			expect(context.enclosingType).toBeUndefined();
+			expect(context.enclosingNamespace?.name).toBe('main');
+		});
+
+		test('offset in whitespace between declarations reports the context of the preceding one', async () => {
+			const source = await fromFixture('test.go');
+
+			const context = await _getNodeContext(WASMLanguage.Go, source, source.indexOf('\n\nfunc main') + 1);
+
+			expect(context.enclosingFunction?.name).toBe('MethodExample');
+			expect(context.enclosingType?.name).toBe('StructExample');
+		});
+
+		test('offset in leading whitespace reports nothing', async () => {
+			const source = '\n\npackage main\n';
+
+			const context = await _getNodeContext(WASMLanguage.Go, source, 0);
+
+			expect(context).toEqual({});
+		});
+	});
+
+	suite('typescript', () => {
+
+		const source = [
+			'namespace NS {',
+			'\texport class Foo {',
+			'\t\tbar() {',
+			'\t\t\tconst baz = () => {',
+			'\t\t\t\t[1, 2].map(x => x * 2);',
+			'\t\t\t};',
+			'\t\t}',
+			'\t}',
+			'}',
+		].join('\n');
+
+		test('nearest named declarations are reported', async () => {
+			const context = await _getNodeContext(WASMLanguage.TypeScript, source, source.indexOf('x * 2'));
+
+			expect(context).toEqual({
+				enclosingFunction: { name: 'baz', kind: 'arrow_function', ...rangeOf(source, '() => {', '\t\t\t}') },
+				enclosingType: { name: 'Foo', kind: 'class_declaration', ...rangeOf(source, 'class Foo', '\n\t}') },
+				enclosingNamespace: { name: 'NS', kind: 'internal_module', ...rangeOf(source, 'namespace NS', '\n}') },
+			});
+		});
+
+		test('offset outside any declaration reports nothing', async () => {
+			const context = await _getNodeContext(WASMLanguage.TypeScript, 'const a = 1;', 'const a = '.length);
+
+			expect(context).toEqual({});
+		});
+	});
+});
@@ -0,0 +1,60 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { getNodeText } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { _parse } from '../../node/parserWithCaching';
+import { structureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { descendants, fromFixture } from './getStructure.util';
+
+suite('getNodeText', () => {
+
+	afterAll(() => _dispose());
+
+	test('body of a method', async () => {
+		const source = await fromFixture('test.go');
+		const treeRef = await _parse(WASMLanguage.Go, source);
+		try {
+			const method = treeRef.tree.rootNode.descendantsOfType('method_declaration')[0];
+
+			expect(getNodeText(source, method.childForFieldName('body')!)).toBe([
+				'{',
+				'    if s.Name == "" {',
+				'        return errors.New("Name cannot be empty")',
+				'    }',
+				'    fmt.Println(s.Name)',
+				'    return nil',
+				'}',
+			].join('\n'));
+		} finally {
+			treeRef.dispose();
+		}
+	});
+
+	test('comments after emoji and CJK text', async () => {
+		const source = await fromFixture('multibyteComment.go');
+		const treeRef = await _parse(WASMLanguage.Go, source);
+		try {
+			const comments = treeRef.tree.rootNode.descendantsOfType('comment');
+
+			expect(comments.map(c => getNodeText(source, c))).toEqual(['/* 😀 emoji */', '// 你好 in a comment']);
+		} finally {
+			treeRef.dispose();
+		}
+	});
+
+	test('whitespace is trimmed on request', async () => {
+		const source = await fromFixture('multibyteComment.go');
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+		const smile = descendants(structure!).find(n => n.name === 'Smile')!;
+
+		expect(getNodeText(source, smile)).toBe(' var Smile = "😀"\n');
+		expect(getNodeText(source, smile, { trim: true })).toBe('var Smile = "😀"');
+		expect(getNodeText(source, smile, { trim: 'start' })).toBe('var Smile = "😀"\n');
+		expect(getNodeText(source, smile, { trim: 'end' })).toBe(' var Smile = "😀"');
+	});
+});
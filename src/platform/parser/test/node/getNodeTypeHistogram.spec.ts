@@ -0,0 +1,41 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _getNodeTypeHistogram } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getNodeTypeHistogram', () => {
+
+	afterAll(() => _dispose());
+
+	test('counts declarations of a Go source', async () => {
+
+		const source = await fromFixture('test.go');
+
+		const histogram = await _getNodeTypeHistogram(WASMLanguage.Go, source);
+
+		expect(histogram['source_file']).toBe(1);
+		expect(histogram['function_declaration']).toBe(1);
+		expect(histogram['method_declaration']).toBe(1);
+		expect(histogram['type_declaration']).toBe(2);
+	});
+
+	test('anonymous nodes are not counted', async () => {
+
+		const histogram = await _getNodeTypeHistogram(WASMLanguage.Go, 'package main\n\nfunc main() {}\n');
+
+		expect(histogram).toEqual({
+			source_file: 1,
+			package_clause: 1,
+			package_identifier: 1,
+			function_declaration: 1,
+			identifier: 1,
+			parameter_list: 1,
+			block: 1,
+		});
+	});
+});
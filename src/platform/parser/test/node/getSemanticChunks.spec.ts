@@ -0,0 +1,126 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { TreeSitterOffsetRange } from '../../node/nodes';
+import { _dispose, _getSemanticChunks, SemanticChunk } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { chunkContainerKinds } from '../../node/treeSitterQueries';
+import { fromFixture } from './getStructure.util';
+
+suite('getSemanticChunks', () => {
+
+	afterAll(() => _dispose());
+
+	function expectContiguousCover(source: string, chunks: SemanticChunk[], maxLength: number) {
+		expect(chunks[0].startIndex).toBe(0);
+		expect(chunks[chunks.length - 1].endIndex).toBe(source.length);
+		for (let i = 0; i < chunks.length; i++) {
+			expect(chunks[i].endIndex - chunks[i].startIndex).toBeGreaterThan(0);
+			expect(chunks[i].endIndex - chunks[i].startIndex).toBeLessThanOrEqual(maxLength);
+			if (i > 0) {
+				expect(chunks[i].startIndex).toBe(chunks[i - 1].endIndex);
+			}
+		}
+	}
+
+	test('source that fits is a single chunk', async () => {
+		const source = await fromFixture('test.go');
+
+		const chunks = await _getSemanticChunks(WASMLanguage.Go, source, source.length);
+
+		expect(chunks).toEqual([{ startIndex: 0, endIndex: source.length, symbolPath: [] }]);
+	});
+
+	test('declarations that fit are kept whole and labeled', async () => {
+		const source = await fromFixture('test.go');
+		const methodStart = source.indexOf('func (s StructExample) MethodExample()');
+		const methodEnd = source.indexOf('\n}\n', methodStart) + 3;
+		// the method also spans the blank line before it
+		const maxLength = methodEnd - methodStart + 1;
+
+		const chunks = await _getSemanticChunks(WASMLanguage.Go, source, maxLength);
+
+		expectContiguousCover(source, chunks, maxLength);
+		const method = chunks.find(c => c.symbolPath.join('.') === 'StructExample.MethodExample');
+		expect(source.substring(method!.startIndex, method!.endIndex)).toContain('func (s StructExample) MethodExample() error {');
+		expect(source.substring(method!.startIndex, method!.endIndex)).toContain('    return nil\n}');
+	});
+
+	test('oversized declarations are split at statement boundaries', async () => {
+		const source = await fromFixture('test.go');
+		const mainStart = source.indexOf('func main()');
+		const mainEnd = source.indexOf('\n}\n', mainStart) + 3;
+
+		const chunks = await _getSemanticChunks(WASMLanguage.Go, source, 200);
+
+		expectContiguousCover(source, chunks, 200);
+		const mainChunks = chunks.filter(c => TreeSitterOffsetRange.doesContain({ startIndex: mainStart, endIndex: mainEnd }, c));
+		expect(mainChunks.length).toBeGreaterThan(1);
+		expect(mainChunks.every(c => c.symbolPath[0] === 'main')).toBe(true);
+		// statements aren't cut in half
+		const forStatement = source.indexOf('for key, value := range mapExample');
+		expect(chunks.some(c => c.startIndex <= forStatement && source.indexOf('}', forStatement) < c.endIndex)).toBe(true);
+	});
+
+	test('tiny maximum falls back to line breaks and still covers the source', async () => {
+		const source = await fromFixture('test.go');
+
+		for (const maxLength of [1, 7, 40]) {
+			expectContiguousCover(source, await _getSemanticChunks(WASMLanguage.Go, source, maxLength), maxLength);
+		}
+	});
+
+	test('oversized nodes that aren\'t containers are split at line breaks', async () => {
+		const source = [
+			'package main',
+			'',
+			'func main() {',
+			'\tif ready {',
+			'\t\tstart()',
+			'\t}',
+			'\tstop()',
+			'}',
+			'',
+		].join('\n');
+		// the `if` statement is larger than a chunk, but `main` is split at its statements
+		const endOfIf = source.indexOf('\t}\n') + 3;
+		const maxLength = 24;
+
+		const chunks = await _getSemanticChunks(WASMLanguage.Go, source, maxLength);
+		// without functions as containers, `main` is split at line breaks, so the closing brace of the `if` statement is packed with `stop()`
+		const chunksWithoutFunctions = await _getSemanticChunks(WASMLanguage.Go, source, maxLength, chunkContainerKinds[WASMLanguage.Go].filter(k => k !== 'function_declaration'));
+
+		expectContiguousCover(source, chunks, maxLength);
+		expectContiguousCover(source, chunksWithoutFunctions, maxLength);
+		expect(chunks.some(c => c.endIndex === endOfIf)).toBe(true);
+		expect(chunksWithoutFunctions.some(c => c.endIndex === endOfIf)).toBe(false);
+		expect(chunksWithoutFunctions.filter(c => c.symbolPath[0] === 'main').map(c => source.substring(c.startIndex, c.endIndex))).toEqual([
+			'\nfunc main() {\n',
+			'\tif ready {\n\t\tstart()\n',
+			'\t}\n\tstop()\n}\n',
+		]);
+	});
+
+	test('methods of a class are labeled with the class', async () => {
+		const source = [
+			'class Foo {',
+			'    func bar() -> Int {',
+			'        return 1',
+			'    }',
+			'',
+			'    func baz() -> Int {',
+			'        return 2',
+			'    }',
+			'}',
+			'',
+		].join('\n');
+
+		const chunks = await _getSemanticChunks(WASMLanguage.Swift, source, 60);
+
+		expectContiguousCover(source, chunks, 60);
+		expect(chunks.map(c => c.symbolPath.join('.'))).toEqual(['Foo.bar', 'Foo.baz']);
+	});
+});
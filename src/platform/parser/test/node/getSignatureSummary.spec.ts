@@ -0,0 +1,89 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { getSignatureSummary, SignatureSummaryOptions } from '../../node/structureSummary';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getSignatureSummary', () => {
+
+	afterAll(() => _dispose());
+
+	async function summariesOf(source: string, options?: SignatureSummaryOptions) {
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+		return new Map(structure!.children.filter(n => n.name !== undefined).map(n => [n.name, getSignatureSummary(n, source, options)]));
+	}
+
+	test('functions and methods are summarized by their headers, small structs and interfaces with their members', async () => {
+		const source = await fromFixture('test.go');
+
+		const summaries = await summariesOf(source);
+
+		expect(summaries.get('main')).toBe('func main()');
+		expect(summaries.get('MethodExample')).toBe('func (s StructExample) MethodExample() error');
+		expect(summaries.get('StructExample')).toBe('type StructExample struct { Name string }');
+		expect(summaries.get('InterfaceExample')).toBe('type InterfaceExample interface { MethodExample() error }');
+	});
+
+	test('large bodies are elided and headers that span lines are joined', async () => {
+		const source = [
+			'package config',
+			'',
+			'type Empty struct{}',
+			'',
+			'type Config struct {',
+			'	Host    string',
+			'	Port    int',
+			'	User    string',
+			'	Pass    string',
+			'	Timeout int',
+			'	Retries int',
+			'}',
+			'',
+			'func Load(',
+			'	path string,',
+			'	overrides map[string]string,',
+			') (*Config, error) {',
+			'	return nil, nil',
+			'}',
+		].join('\n');
+
+		const summaries = await summariesOf(source);
+
+		expect(summaries.get('Empty')).toBe('type Empty struct {}');
+		expect(summaries.get('Config')).toBe('type Config struct { ... }');
+		expect(summaries.get('Load')).toBe('func Load(path string, overrides map[string]string) (*Config, error)');
+	});
+
+	test('large bodies are truncated to the maximum number of members', async () => {
+		const source = await fromFixture('largeStruct.go');
+
+		const summaries = await summariesOf(source, { maxMembers: 3 });
+
+		expect(summaries.get('Options')).toBe([
+			'type Options struct {',
+			'	Host string',
+			'	Port int',
+			'	ReadTimeout time.Duration',
+			'	// ... (7 more)',
+			'}',
+		].join('\n'));
+		// bodies within the limit are listed as without it
+		expect(summaries.get('Handler')).toBe('type Handler interface { Serve(w ResponseWriter, r *Request) }');
+		expect((await summariesOf(source)).get('Options')).toBe('type Options struct { ... }');
+	});
+
+	test('declarations without headers have no summaries', async () => {
+		const source = await fromFixture('test.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const main = structure!.children.find(n => n.name === 'main')!;
+		expect(main.children.map(n => getSignatureSummary(n, source)).filter(s => s !== undefined)).toEqual([]);
+	});
+});
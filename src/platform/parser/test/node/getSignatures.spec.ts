@@ -0,0 +1,110 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _getSignatures } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getSignatures', () => {
+
+	afterAll(() => _dispose());
+
+	async function getSignatures(language: WASMLanguage, source: string) {
+		const signatures = await _getSignatures(language, source);
+		for (const s of signatures) {
+			expect(source.substring(s.startIndex, s.endIndex)).toContain(s.parameters);
+		}
+		return signatures.map(({ startIndex, endIndex, ...signature }) => signature);
+	}
+
+	test('go - methods have receivers and multiple results are parenthesized', async () => {
+		const source = await fromFixture('test.go');
+
+		const [methodExample] = (await getSignatures(WASMLanguage.Go, source)).filter(s => s.name === 'MethodExample');
+
+		expect(methodExample).toEqual({
+			name: 'MethodExample',
+			text: 'func (s StructExample) MethodExample() error',
+			parameters: '()',
+			returnType: 'error',
+			receiver: '(s StructExample)',
+			visibility: 'public',
+		});
+	});
+
+	test('go - results', async () => {
+		const source = await fromFixture('signatures.go');
+
+		expect(await getSignatures(WASMLanguage.Go, source)).toEqual([
+			{
+				name: 'Fprintf',
+				text: 'func Fprintf(w io.Writer, format string, args ...any) (n int, err error)',
+				parameters: '(w io.Writer, format string, args ...any)',
+				returnType: '(n int, err error)',
+				visibility: 'public',
+			},
+			{ name: 'Swap', text: 'func Swap(a, b int) (int, int)', parameters: '(a, b int)', returnType: '(int, int)', visibility: 'public' },
+			{ name: 'Close', text: 'func (r *Reader) Close() error', parameters: '()', returnType: 'error', receiver: '(r *Reader)', visibility: 'public' },
+			{ name: 'Reset', text: 'func Reset()', parameters: '()', returnType: undefined, visibility: 'public' },
+		]);
+	});
+
+	test('typescript - functions and methods with their visibility', async () => {
+		const source = await fromFixture('signatures.ts');
+
+		expect(await getSignatures(WASMLanguage.TypeScript, source)).toEqual([
+			{
+				name: 'fetchUser',
+				text: 'export async function fetchUser(id: string, retries = 3): Promise<User>',
+				parameters: '(id: string, retries = 3)',
+				returnType: 'Promise<User>',
+				visibility: 'public',
+			},
+			{ name: 'load', text: 'function load(id: string, retries: number)', parameters: '(id: string, retries: number)', returnType: undefined, visibility: 'private' },
+			{
+				name: 'constructor',
+				text: 'constructor(private readonly logger: Logger)',
+				parameters: '(private readonly logger: Logger)',
+				returnType: undefined,
+				visibility: 'public',
+			},
+			{ name: 'find', text: 'public find(id: string): User | undefined', parameters: '(id: string)', returnType: 'User | undefined', visibility: 'public' },
+			{ name: 'create', text: 'protected static create(): UserService', parameters: '()', returnType: 'UserService', visibility: 'protected' },
+			{ name: 'log', text: 'private log(message: string): void', parameters: '(message: string)', returnType: 'void', visibility: 'private' },
+			{ name: '#validate', text: '#validate(user: User): boolean', parameters: '(user: User)', returnType: 'boolean', visibility: 'private' },
+			{ name: 'all', text: 'async *all(): AsyncGenerator<User>', parameters: '()', returnType: 'AsyncGenerator<User>', visibility: 'public' },
+		]);
+	});
+
+	test('python - methods and private functions', async () => {
+		const source = [
+			'def greet(name: str) -> str:',
+			'    return name',
+			'',
+			'def _helper():',
+			'    pass',
+			'',
+			'class Greeter:',
+			'    def __init__(self, name):',
+			'        self.name = name',
+			'',
+			'    @property',
+			'    def _cached(self) -> int:',
+			'        return 1',
+		].join('\n');
+
+		expect(await getSignatures(WASMLanguage.Python, source)).toEqual([
+			{ name: 'greet', text: 'def greet(name: str) -> str', parameters: '(name: str)', returnType: 'str', visibility: 'public' },
+			{ name: '_helper', text: 'def _helper()', parameters: '()', returnType: undefined, visibility: 'private' },
+			{ name: '__init__', text: 'def __init__(self, name)', parameters: '(self, name)', returnType: undefined, visibility: 'public' },
+			{ name: '_cached', text: 'def _cached(self) -> int', parameters: '(self)', returnType: 'int', visibility: 'private' },
+		]);
+	});
+
+	test('unsupported language', async () => {
+		expect(await _getSignatures(WASMLanguage.Ruby, 'def foo; end')).toEqual([]);
+	});
+});
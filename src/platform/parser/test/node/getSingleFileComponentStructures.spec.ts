@@ -0,0 +1,76 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { LineCharacterPosition } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { getSingleFileComponentStructures, splitSingleFileComponent } from '../../node/singleFileComponents';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { descendants, fromFixture } from './getStructure.util';
+
+suite('getSingleFileComponentStructures', () => {
+
+	afterAll(() => _dispose());
+
+	test('vue - a component is split into its blocks, and templates nested in the template are part of it', async () => {
+		const source = await fromFixture('counter.vue');
+
+		const blocks = splitSingleFileComponent('vue', source);
+
+		expect(blocks.map(b => ({ kind: b.kind, lang: b.lang }))).toEqual([
+			{ kind: 'template', lang: undefined },
+			{ kind: 'script', lang: 'ts' },
+			{ kind: 'style', lang: undefined },
+		]);
+		const template = source.slice(blocks[0].startIndex, blocks[0].endIndex);
+		expect(template).toContain('<template v-if="count > 0">');
+		expect(template.trimEnd().endsWith('</div>')).toBe(true);
+		expect(source.slice(blocks[2].startIndex, blocks[2].endIndex)).toBe('\nbutton { color: red; }\n');
+	});
+
+	test('vue - a script with lang="ts" is parsed as TypeScript with offsets and positions within the file', async () => {
+		const source = await fromFixture('counter.vue');
+
+		const [script, ...rest] = await getSingleFileComponentStructures('vue', source);
+
+		expect(rest).toEqual([]);
+		expect(script.language).toBe(WASMLanguage.TypeScript);
+		expect(source.slice(script.startIndex, script.endIndex).trim()).toMatch(/^import \{ ref \} from 'vue';[\s\S]*count\.value\+\+;\n\}$/);
+
+		const increment = descendants(script.structure).find(n => n.name === 'increment')!;
+		expect(source.slice(increment.startIndex, increment.endIndex).trim()).toBe('function increment(): void {\n  count.value++;\n}');
+		const positionAt = LineCharacterPosition.converterFor(source);
+		for (const node of descendants(script.structure)) {
+			expect(node.range).toEqual({ start: positionAt(node.startIndex), end: positionAt(node.endIndex) });
+		}
+	});
+
+	test('svelte - a script without lang is parsed as JavaScript and blocks in comments are skipped', async () => {
+		const source = await fromFixture('greeting.svelte');
+
+		expect(splitSingleFileComponent('svelte', source).map(b => b.kind)).toEqual(['script', 'style']);
+
+		const [script] = await getSingleFileComponentStructures('svelte', source);
+
+		expect(script.language).toBe(WASMLanguage.JavaScript);
+		const greet = descendants(script.structure).find(n => n.name === 'greet')!;
+		expect(source.slice(greet.startIndex, greet.endIndex).trim()).toBe('function greet() {\n    alert(`Hello, ${name}!`);\n  }');
+	});
+
+	test('scripts in unsupported languages and without code in the file are skipped', async () => {
+		const source = [
+			'<script src="./legacy.js" />',
+			'<script lang="coffee">',
+			'greet = -> alert "hi"',
+			'</script>',
+			'<script lang="tsx">',
+			'export const App = () => <div />;',
+			'</script>',
+		].join('\n');
+
+		expect(splitSingleFileComponent('vue', source).map(b => b.lang)).toEqual(['coffee', 'tsx']);
+		expect((await getSingleFileComponentStructures('vue', source)).map(s => s.language)).toEqual([WASMLanguage.TypeScriptTsx]);
+	});
+});
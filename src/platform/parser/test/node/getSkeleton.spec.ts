@@ -0,0 +1,150 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _getSkeleton } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getSkeleton', () => {
+
+	afterAll(() => _dispose());
+
+	test('go - declarations are kept and function bodies are stubbed', async () => {
+		const source = await fromFixture('test.go');
+
+		const skeleton = await _getSkeleton(WASMLanguage.Go, source);
+
+		expect(skeleton).toContain([
+			'import (',
+			'    "errors"',
+			'    "fmt"',
+			')',
+			'',
+			'const (',
+			'    ConstExample = "const before vars"',
+			')',
+			'',
+			'var (',
+			'    BoolExample bool',
+			'    IntExample  int',
+			')',
+			'',
+			'type StructExample struct {',
+			'    Name string',
+			'}',
+			'',
+			'type InterfaceExample interface {',
+			'    MethodExample() error',
+			'}',
+			'',
+			'func (s StructExample) MethodExample() error { ... }',
+			'',
+			'func main() { ... }',
+		].join('\n'));
+		expect(skeleton).not.toContain('fmt.Println');
+	});
+
+	test('go - doc comments are kept', async () => {
+		const source = [
+			'package shapes',
+			'',
+			'// Area returns the area of a rectangle.',
+			'func Area(w, h float64) float64 {',
+			'	return w * h',
+			'}',
+			'',
+			'type Rect struct{ W, H float64 }',
+			'',
+			'// Scale scales r in place.',
+			'func (r *Rect) Scale(f float64) {',
+			'	r.W *= f',
+			'	r.H *= f',
+			'}',
+			'',
+		].join('\n');
+
+		expect(await _getSkeleton(WASMLanguage.Go, source)).toBe([
+			'package shapes',
+			'',
+			'// Area returns the area of a rectangle.',
+			'func Area(w, h float64) float64 { ... }',
+			'',
+			'type Rect struct{ W, H float64 }',
+			'',
+			'// Scale scales r in place.',
+			'func (r *Rect) Scale(f float64) { ... }',
+			'',
+		].join('\n'));
+	});
+
+	test('typescript - functions and methods are stubbed within their classes', async () => {
+		const source = await fromFixture('signatures.ts');
+
+		expect(await _getSkeleton(WASMLanguage.TypeScript, source)).toBe([
+			`import { Logger } from './logger';`,
+			'',
+			'export async function fetchUser(id: string, retries = 3): Promise<User> { ... }',
+			'',
+			'function load(id: string, retries: number) { ... }',
+			'',
+			'export class UserService {',
+			'	constructor(private readonly logger: Logger) { ... }',
+			'',
+			'	@memoize',
+			'	public find(id: string): User | undefined { ... }',
+			'',
+			'	protected static create(): UserService { ... }',
+			'',
+			'	private log(message: string): void { ... }',
+			'',
+			'	#validate(user: User): boolean { ... }',
+			'',
+			'	async *all(): AsyncGenerator<User> { ... }',
+			'}',
+			'',
+		].join('\n'));
+	});
+
+	test('python - bodies are stubbed with ellipses below their docstrings', async () => {
+		const source = [
+			'import os',
+			'',
+			'def greet(name: str) -> str:',
+			'    """Greets someone by name."""',
+			'    return f"Hello, {name}"',
+			'',
+			'class Greeter:',
+			'    prefix = "Hi"',
+			'',
+			'    def __init__(self, name):',
+			'        self.name = name',
+			'',
+			'    def cached(self) -> int:',
+			'        """Only a docstring."""',
+		].join('\n');
+
+		expect(await _getSkeleton(WASMLanguage.Python, source)).toBe([
+			'import os',
+			'',
+			'def greet(name: str) -> str:',
+			'    """Greets someone by name."""',
+			'    ...',
+			'',
+			'class Greeter:',
+			'    prefix = "Hi"',
+			'',
+			'    def __init__(self, name):',
+			'        ...',
+			'',
+			'    def cached(self) -> int:',
+			'        """Only a docstring."""',
+		].join('\n'));
+	});
+
+	test('unsupported language', async () => {
+		expect(await _getSkeleton(WASMLanguage.Ruby, 'def foo; end')).toBe('def foo; end');
+	});
+});
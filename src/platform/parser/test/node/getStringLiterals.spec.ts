@@ -0,0 +1,71 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _getStringLiterals } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getStringLiterals', () => {
+
+	afterAll(() => _dispose());
+
+	test('go - interpreted strings are decoded and raw strings are kept as written', async () => {
+		const source = await fromFixture('strings.go');
+
+		const literals = await _getStringLiterals(WASMLanguage.Go, source);
+
+		expect(literals.map(({ text, value, isTemplate }) => ({ text, value, isTemplate }))).toEqual([
+			{ text: '"os"', value: 'os', isTemplate: false },
+			{ text: '"sk-test\\t1234"', value: 'sk-test\t1234', isTemplate: false },
+			{ text: '`^\\d+//\\w*$`', value: '^\\d+//\\w*$', isTemplate: false },
+			{ text: '"Gr\\u00fc\\u00df Gott"', value: 'Grüß Gott', isTemplate: false },
+			{ text: '"https://"', value: 'https://', isTemplate: false },
+			{ text: '"BASE_PATH"', value: 'BASE_PATH', isTemplate: false },
+		]);
+		for (const literal of literals) {
+			expect(source.substring(literal.startIndex, literal.endIndex)).toBe(literal.text);
+		}
+	});
+
+	test('typescript - template literals with interpolations have no values', async () => {
+		const source = await fromFixture('strings.ts');
+
+		const literals = await _getStringLiterals(WASMLanguage.TypeScript, source);
+
+		expect(literals.map(({ text, value, isTemplate }) => ({ text, value, isTemplate }))).toEqual([
+			{ text: `'// not a comment'`, value: '// not a comment', isTemplate: false },
+			{ text: '"say \\"hi\\"\\n"', value: 'say "hi"\n', isTemplate: false },
+			{ text: '`C:\\\\temp`', value: 'C:\\temp', isTemplate: true },
+			{ text: '`Hello, ${name}! You have ${count} new message${count === 1 ? \'\' : \'s\'}.`', value: undefined, isTemplate: true },
+			{ text: `''`, value: '', isTemplate: false },
+			{ text: `'s'`, value: 's', isTemplate: false },
+		]);
+		const greeting = literals[3];
+		expect(greeting.interpolations.map(i => source.substring(i.startIndex, i.endIndex))).toEqual([
+			'${name}',
+			'${count}',
+			`\${count === 1 ? '' : 's'}`,
+		]);
+	});
+
+	test('python - raw strings, triple-quoted strings, and f-strings', async () => {
+		const source = await fromFixture('strings.py');
+
+		const literals = await _getStringLiterals(WASMLanguage.Python, source);
+
+		expect(literals.map(({ text, value, isTemplate, interpolations }) => ({ text, value, isTemplate, interpolations: interpolations.length }))).toEqual([
+			{ text: 'r"\\d+\\.\\d+"', value: '\\d+\\.\\d+', isTemplate: false, interpolations: 0 },
+			{ text: '"caf\\u00e9\\n"', value: 'café\n', isTemplate: false, interpolations: 0 },
+			{ text: '"""Multi-line\nstring"""', value: 'Multi-line\nstring', isTemplate: false, interpolations: 0 },
+			{ text: 'f"Hello, {name}! {{braces}} stay"', value: undefined, isTemplate: true, interpolations: 1 },
+			{ text: 'f"{{not interpolated}}"', value: '{not interpolated}', isTemplate: true, interpolations: 0 },
+		]);
+	});
+
+	test('unsupported language', async () => {
+		expect(await _getStringLiterals(WASMLanguage.Yaml, 'key: "value"\n')).toEqual([]);
+	});
+});
@@ -0,0 +1,111 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { getWasmLanguage, WASMLanguage } from '../../node/treeSitterLanguages';
+import { descendants, fromFixture } from './getStructure.util';
+
+describe('getStructure - bash', () => {
+	afterAll(() => _dispose());
+
+	function namedChildren(node: OverlayNode) {
+		return node.children
+			.filter(n => n.name !== undefined)
+			.map(n => ({ kind: n.kind, name: n.name }));
+	}
+
+	test('shell script language IDs are parsed as bash', () => {
+		expect(getWasmLanguage('shellscript')).toBe(WASMLanguage.Shell);
+		expect(getWasmLanguage('sh')).toBe(WASMLanguage.Shell);
+		expect(getWasmLanguage('bash')).toBe(WASMLanguage.Shell);
+	});
+
+	test('functions and top-level variables are declarations', async () => {
+
+		const source = await fromFixture('test.sh');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Shell, source);
+
+		expect(namedChildren(structure!)).toEqual([
+			{ kind: 'declaration_command', name: 'VERSION' },
+			{ kind: 'variable_assignment', name: 'OUTPUT_DIR' },
+			{ kind: 'declaration_command', name: 'PATH' },
+			{ kind: 'function_definition', name: 'greet' },
+			{ kind: 'function_definition', name: 'cleanup' },
+			{ kind: 'function_definition', name: 'build' },
+			{ kind: 'function_definition', name: 'in_subshell' },
+		]);
+	});
+
+	test('function details cover all forms of function definitions', async () => {
+
+		const source = await fromFixture('test.sh');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Shell, source);
+
+		const functions = structure!.children
+			.filter(n => n.kind === 'function_definition')
+			.map(n => ({ name: n.name, detail: n.detail, leadingComment: n.leadingComment }));
+
+		expect(functions).toEqual([
+			{ name: 'greet', detail: 'greet()', leadingComment: '# Prints a greeting for the given name.' },
+			{ name: 'cleanup', detail: 'function cleanup', leadingComment: undefined },
+			{ name: 'build', detail: 'function build()', leadingComment: undefined },
+			{ name: 'in_subshell', detail: 'in_subshell()', leadingComment: undefined },
+		]);
+	});
+
+	test('variables assigned within functions are not declarations of the script', async () => {
+
+		const source = await fromFixture('test.sh');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Shell, source);
+
+		const inSubshell = structure!.children.find(n => n.name === 'in_subshell')!;
+		expect(namedChildren(inSubshell)).toEqual([]);
+	});
+
+	test('outline of a script with nested functions and a heredoc', async () => {
+
+		const source = await fromFixture('deploy.sh');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Shell, source);
+
+		// the heredoc of `write_config` looks like a function and a variable, but is only text
+		expect(descendants(structure!).map(n => [n.kind, n.name ?? n.path].filter(s => s !== undefined).join(' '))).toMatchSnapshot();
+	});
+
+	test('functions nested in if and case blocks are declarations of the script', async () => {
+
+		const source = await fromFixture('deploy.sh');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Shell, source);
+
+		const functions = structure!.children
+			.filter(n => n.kind === 'function_definition')
+			.map(n => ({ name: n.name, leadingComment: n.leadingComment }));
+		expect(functions).toEqual([
+			{ name: 'write_config', leadingComment: '# Writes the config of the given host.' },
+			{ name: 'debug', leadingComment: undefined },
+			{ name: 'deploy', leadingComment: undefined },
+		]);
+		expect(structure!.children.find(n => n.name === 'write_config')!.children).toEqual([]);
+	});
+
+	test('sourced scripts are includes with their paths', async () => {
+
+		const source = await fromFixture('deploy.sh');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Shell, source);
+
+		expect(structure!.children.filter(n => n.kind === 'source_command').map(n => ({ path: n.path, name: n.name, symbolKind: n.symbolKind }))).toEqual([
+			{ path: './lib/common.sh', name: undefined, symbolKind: undefined },
+			{ path: '$HOME/.config/deploy.env', name: undefined, symbolKind: undefined },
+		]);
+	});
+});
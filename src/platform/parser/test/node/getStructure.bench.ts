@@ -0,0 +1,70 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { bench, describe } from 'vitest';
+import { LineCharacterPosition } from '../../node/nodes';
+import { _parse } from '../../node/parserWithCaching';
+import { deleteQueries } from '../../node/querying';
+import { StructureComputer, structureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+
+describe('getStructure of small sources', () => {
+
+	// distinct sources, so that neither parse trees nor structures are served from their caches
+	let count = 0;
+	const nextSource = () => `package main\n\nfunc f${count++}(a, b int) int {\n\treturn a + b\n}\n`;
+
+	bench('with the queries compiled when the grammar was loaded', async () => {
+		await structureComputer.getStructure(WASMLanguage.Go, nextSource());
+	});
+
+	bench('with the queries compiled for every source', async () => {
+		const source = nextSource();
+		const treeRef = await _parse(WASMLanguage.Go, source);
+		deleteQueries(treeRef.tree.getLanguage());
+		treeRef.dispose();
+		await structureComputer.getStructure(WASMLanguage.Go, source);
+	});
+});
+
+describe('getStructure of a single long line', () => {
+
+	// like minified or generated source, about 500KB without a single line break
+	const functions = Array.from({ length: 10_000 }, (_, i) => `func f${i}(a, b int) int { return a + b }`);
+	let count = 0;
+	const nextSource = () => `package main; var v${count++} = 0; ${functions.join('; ')}`;
+
+	bench('structure', async () => {
+		await structureComputer.getStructure(WASMLanguage.Go, nextSource());
+	});
+
+	bench('positions of all function starts', () => {
+		const source = nextSource();
+		const positionAt = LineCharacterPosition.converterFor(source);
+		for (let offset = source.indexOf('func'); offset !== -1; offset = source.indexOf('func', offset + 1)) {
+			positionAt(offset);
+		}
+	});
+});
+
+describe('getStructureForRange of a large source', () => {
+
+	// about 10,000 lines, of which a method in the middle is selected
+	const classes = Array.from({ length: 2000 }, (_, i) => `export class Service${i} {\n\tget(id: string) {\n\t\treturn id + ${i};\n\t}\n}\n`);
+	let count = 0;
+	const nextSource = () => `const version = ${count++};\n\n${classes.join('\n')}`;
+	// structures aren't cached, so that both compute them
+	const computer = new StructureComputer(0);
+
+	bench('structure of the whole source', async () => {
+		await computer.getStructure(WASMLanguage.TypeScript, nextSource());
+	});
+
+	bench('structure of the method', async () => {
+		const source = nextSource();
+		const start = source.indexOf('return id + 1000;');
+		await computer.getStructureForRange(WASMLanguage.TypeScript, source, start, start + 1);
+	});
+});
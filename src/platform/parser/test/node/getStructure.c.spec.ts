@@ -0,0 +1,90 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { descendants, fromFixture, snapshotPathInFixture, srcWithAnnotatedStructure } from './getStructure.util';
+
+describe('getStructure - c', () => {
+	afterAll(() => _dispose());
+
+	function cStruct(source: string) {
+		return srcWithAnnotatedStructure(WASMLanguage.C, source);
+	}
+
+	test('source with different syntax constructs', async () => {
+
+		const filename = 'ringbuffer.c';
+
+		const source = await fromFixture(filename);
+
+		await expect(await cStruct(source)).toMatchFileSnapshot(snapshotPathInFixture(filename));
+	});
+
+	test('macros, typedefs, and functions get names, details, and symbol kinds', async () => {
+		const source = await fromFixture('ringbuffer.c');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.C, source);
+
+		const symbols = descendants(structure!)
+			.filter(n => n.symbolKind !== undefined)
+			.map(n => [n.kind, n.name, n.symbolKind, n.detail]);
+		expect(symbols).toEqual([
+			['preproc_def', 'RING_CAPACITY', SymbolKind.Constant, '#define RING_CAPACITY 64'],
+			['preproc_function_def', 'RING_NEXT', SymbolKind.Function, '#define RING_NEXT(i)'],
+			['type_definition', 'ring_t', SymbolKind.Struct, 'typedef struct ring ring_t'],
+			['type_definition', 'ring_status', SymbolKind.Enum, 'typedef enum ring_status'],
+			['union_specifier', 'word', SymbolKind.Struct, 'union word'],
+			['function_definition', 'ring_new', SymbolKind.Function, 'ring_t *ring_new(void)'],
+			['function_definition', 'ring_push', SymbolKind.Function, 'ring_status ring_push(ring_t *r, unsigned char byte)'],
+		]);
+
+		const ring = descendants(structure!).find(n => n.name === 'ring_t');
+		expect(ring?.leadingComment).toBe('/* Fixed-size queue of bytes. */');
+	});
+
+	test('prototypes and forward declarations are told apart from definitions', async () => {
+		const source = await fromFixture('list.c');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.C, source);
+
+		const nodes = descendants(structure!);
+		expect(nodes.filter(n => n.isForwardDeclaration).map(n => [n.kind, n.name, n.detail])).toEqual([
+			['struct_specifier', 'node', 'struct node'],
+			['declaration', 'list_push', 'int list_push(list_t *l, int value)'],
+		]);
+		expect(nodes.filter(n => n.name === 'list_push').map(n => [n.kind, n.symbolKind, n.isForwardDeclaration])).toEqual([
+			['declaration', SymbolKind.Function, true],
+			['function_definition', SymbolKind.Function, undefined],
+		]);
+		// a pointer to a function is a variable
+		const onPush = nodes.find(n => n.kind === 'declaration' && source.substring(n.startIndex, n.endIndex).includes('on_push'));
+		expect(onPush?.symbolKind).toBeUndefined();
+		expect(nodes.filter(n => n.kind === 'preproc_def' || n.kind === 'preproc_function_def').map(n => n.detail)).toEqual(['#define LIST_INIT { NULL, 0 }', '#define LIST_EMPTY(l)']);
+	});
+
+	test('enumerators with includeEnumMembers', async () => {
+
+		const source = await fromFixture('enums.c');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.C, source, { includeEnumMembers: true });
+
+		const members = descendants(structure!).filter(n => n.kind === 'enum_member');
+		expect(members.map(n => ({ name: n.name, value: n.value, symbolKind: n.symbolKind }))).toEqual([
+			{ name: 'RED', value: undefined, symbolKind: SymbolKind.Constant },
+			{ name: 'GREEN', value: '5', symbolKind: SymbolKind.Constant },
+			{ name: 'BLUE', value: undefined, symbolKind: SymbolKind.Constant },
+			{ name: 'LOG_DEBUG', value: '1 << 0', symbolKind: SymbolKind.Constant },
+			{ name: 'LOG_ERROR', value: '1 << 1', symbolKind: SymbolKind.Constant },
+		]);
+		expect(descendants(structure!).find(n => n.name === 'color')!.children).toEqual(members.slice(0, 3));
+
+		const withoutOption = await structureComputer.getStructure(WASMLanguage.C, source);
+		expect(descendants(withoutOption!).some(n => n.kind === 'enum_member')).toBe(false);
+	});
+});
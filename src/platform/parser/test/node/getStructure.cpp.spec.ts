@@ -6,8 +6,10 @@
 import { outdent } from 'outdent';
 import { afterAll, describe, expect, test } from 'vitest';
 import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
 import { WASMLanguage } from '../../node/treeSitterLanguages';
-import { fromFixture, snapshotPathInFixture, srcWithAnnotatedStructure } from './getStructure.util';
+import { descendants, fromFixture, snapshotPathInFixture, srcWithAnnotatedStructure } from './getStructure.util';
 
 describe('getStructure - cpp', () => {
 	afterAll(() => _dispose());
@@ -73,4 +75,121 @@ describe('getStructure - cpp', () => {
 
 		expect(await cppStruct(source)).toMatchInlineSnapshot(`"<CLASS_SPECIFIER>class A {};</CLASS_SPECIFIER>"`);
 	});
+
+	test('header declarations get names, details, and symbol kinds', async () => {
+		const source = await fromFixture('shapes.hpp');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Cpp, source);
+
+		const symbols = descendants(structure!)
+			.filter(n => n.symbolKind !== undefined)
+			.map(n => [n.name, n.symbolKind, n.detail]);
+		expect(symbols).toEqual([
+			['SHAPES_HPP', SymbolKind.Constant, '#define SHAPES_HPP'],
+			['PI', SymbolKind.Constant, '#define PI 3.14159265358979'],
+			['SQUARE', SymbolKind.Function, '#define SQUARE(x)'],
+			['geo', SymbolKind.Namespace, 'namespace geo'],
+			['Point', SymbolKind.Struct, 'struct Point'],
+			['Color', SymbolKind.Enum, 'enum class Color'],
+			['Value', SymbolKind.Struct, 'union Value'],
+			['Vector', SymbolKind.Struct, 'typedef struct Point Vector'],
+			['AreaFn', SymbolKind.Class, 'typedef double (*AreaFn)(const Point *vertices, int count)'],
+			['Shape', SymbolKind.Class, 'class Shape'],
+			['name', SymbolKind.Method, 'std::string name() const'],
+			['Circle', SymbolKind.Class, 'class Circle : public Shape'],
+			['Circle', SymbolKind.Method, 'explicit Circle(double radius)'],
+			['radius', SymbolKind.Method, 'double radius() const'],
+		]);
+
+		const shape = descendants(structure!).find(n => n.name === 'Shape');
+		expect(shape?.leadingComment).toBe('/** Base of all shapes. */');
+	});
+
+	test('templates keep their template prefixes, and declarations without bodies are forward declarations', async () => {
+		const source = await fromFixture('containers.hpp');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Cpp, source);
+
+		const symbols = descendants(structure!)
+			.filter(n => n.symbolKind !== undefined)
+			.map(n => [n.kind, n.name, n.symbolKind, n.detail, n.isForwardDeclaration ?? false]);
+		expect(symbols).toEqual([
+			['preproc_function_def', 'ARRAY_SIZE', SymbolKind.Function, '#define ARRAY_SIZE(a)', false],
+			['namespace_definition', 'util', SymbolKind.Namespace, 'namespace util', false],
+			['class_specifier', 'Registry', SymbolKind.Class, 'class Registry', true],
+			['function_definition', 'max_of', SymbolKind.Function, 'template <typename T>\nT max_of(const T &a, const T &b)', false],
+			['class_specifier', 'FixedArray', SymbolKind.Class, 'template <typename T, std::size_t N>\nclass FixedArray', false],
+			['declaration', 'FixedArray', SymbolKind.Method, 'FixedArray()', true],
+			['function_definition', 'size', SymbolKind.Method, 'std::size_t size() const', false],
+			['declaration', 'clear', SymbolKind.Function, 'template <typename T>\nvoid clear(FixedArray<T, 4> &array)', true],
+			['declaration', 'count', SymbolKind.Function, 'int count(const Registry &registry)', true],
+		]);
+
+		const maxOf = descendants(structure!).find(n => n.name === 'max_of');
+		expect(maxOf?.typeParameters).toBe('<typename T>');
+	});
+
+	test('functions defined outside of their classes are methods of the classes declared in the same source', async () => {
+		const source = await fromFixture('shapes.cpp');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Cpp, source);
+
+		const functions = descendants(structure!)
+			.filter(n => n.kind === 'function_definition')
+			.map(n => [n.name, n.qualifier, n.symbolKind, n.detail]);
+		expect(functions).toEqual([
+			['Square', undefined, SymbolKind.Method, 'explicit Square(double side)'],
+			['area', 'Square', SymbolKind.Method, 'double Square::area() const'],
+			['scale', 'Square', SymbolKind.Method, 'Square &Square::scale(double factor)'],
+			// `Circle` is declared in the header
+			['Circle', 'Circle', SymbolKind.Function, 'Circle::Circle(double radius)'],
+			['area', 'Circle', SymbolKind.Function, 'double Circle::area() const'],
+			['perimeter', 'geo::Square', SymbolKind.Method, 'double geo::Square::perimeter() const'],
+			['total_area', undefined, SymbolKind.Function, 'double total_area(const geo::Shape *shapes[], int count)'],
+		]);
+
+		// inline definitions are children of the class, out-of-line ones are its methods
+		const square = descendants(structure!).find(n => n.kind === 'class_specifier' && n.name === 'Square');
+		expect(square?.children.filter(c => c.kind === 'function_definition').map(c => c.name)).toEqual(['Square']);
+		expect(square?.methods?.map(m => m.detail)).toEqual([
+			'double Square::area() const',
+			'Square &Square::scale(double factor)',
+			'double geo::Square::perimeter() const',
+		]);
+	});
+
+	test('names are qualified with their namespaces and classes using ::', async () => {
+		const source = await fromFixture('shapes.cpp');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Cpp, source);
+
+		const functions = descendants(structure!)
+			.filter(n => n.kind === 'function_definition')
+			.map(n => n.qualifiedName);
+		expect(functions).toEqual([
+			'geo::Square::Square',
+			'geo::Square::area',
+			'geo::Square::scale',
+			'geo::Circle::Circle',
+			'geo::Circle::area',
+			// the scope as written, outside of the namespace
+			'geo::Square::perimeter',
+			'total_area',
+		]);
+	});
+
+	test('enumerators of scoped enums with includeEnumMembers', async () => {
+
+		const source = await fromFixture('enums.cpp');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Cpp, source, { includeEnumMembers: true });
+
+		const status = descendants(structure!).find(n => n.kind === 'enum_specifier')!;
+		expect(status.name).toBe('Status');
+		expect(status.children.map(n => ({ kind: n.kind, name: n.name, value: n.value }))).toEqual([
+			{ kind: 'enum_member', name: 'Ok', value: '200' },
+			{ kind: 'enum_member', name: 'NotFound', value: '404' },
+			{ kind: 'enum_member', name: 'Unknown', value: undefined },
+		]);
+	});
 });
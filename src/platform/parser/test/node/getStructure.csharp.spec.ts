@@ -4,9 +4,12 @@
  *--------------------------------------------------------------------------------------------*/
 
 import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
 import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
 import { WASMLanguage } from '../../node/treeSitterLanguages';
-import { fromFixture, srcWithAnnotatedStructure } from './getStructure.util';
+import { descendants, fromFixture, srcWithAnnotatedStructure } from './getStructure.util';
 
 describe('getStructure - csharp', () => {
 	afterAll(() => _dispose());
@@ -15,10 +18,223 @@ describe('getStructure - csharp', () => {
 		return srcWithAnnotatedStructure(WASMLanguage.Csharp, source);
 	}
 
+	function namedChildren(node: OverlayNode) {
+		return node.children
+			.filter(n => n.name !== undefined)
+			.map(n => ({ kind: n.kind, name: n.name }));
+	}
+
 	test('source with different syntax constructs', async () => {
 
 		const source = await fromFixture('test.cs');
 
 		expect(await csharpStruct(source)).toMatchSnapshot();
 	});
+
+	test('file-scoped namespace encloses the declarations following it', async () => {
+
+		const source = await fromFixture('partialClass.cs');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Csharp, source);
+
+		expect(namedChildren(structure!)).toEqual([
+			{ kind: 'file_scoped_namespace_declaration', name: 'Shop.Orders' },
+		]);
+		const namespace = structure!.children.find(n => n.name === 'Shop.Orders')!;
+		expect(namedChildren(namespace)).toEqual([
+			{ kind: 'interface_declaration', name: 'IOrder' },
+			{ kind: 'struct_declaration', name: 'Money' },
+			{ kind: 'record_declaration', name: 'LineItem' },
+			{ kind: 'class_declaration', name: 'Order' },
+		]);
+		expect(namedChildren(namespace.children.find(n => n.name === 'IOrder')!)).toEqual([
+			{ kind: 'property_declaration', name: 'Total' },
+		]);
+		expect(namedChildren(namespace.children.find(n => n.name === 'Money')!)).toEqual([
+			{ kind: 'property_declaration', name: 'Amount' },
+		]);
+	});
+
+	test('parts of a partial class declared next to each other are merged', async () => {
+
+		const source = await fromFixture('partialClass.cs');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Csharp, source);
+
+		const namespace = structure!.children.find(n => n.name === 'Shop.Orders')!;
+		const order = namespace.children.find(n => n.name === 'Order')!;
+		expect(order.detail).toBe('public partial class Order : IOrder');
+		expect(order.leadingComment).toBe('/// <summary>An order of line items.</summary>\n// Building and validation live in the second part.');
+		// spans both parts
+		expect(order.startIndex).toBe(source.indexOf('public partial class Order : IOrder'));
+		expect(order.endIndex).toBe(source.lastIndexOf('}') + 1);
+		expect(namedChildren(order)).toEqual([
+			{ kind: 'property_declaration', name: 'Total' },
+			{ kind: 'method_declaration', name: 'Add' },
+			{ kind: 'class_declaration', name: 'Builder' },
+			{ kind: 'method_declaration', name: 'Validate' },
+		]);
+		expect(namedChildren(order.children.find(n => n.name === 'Builder')!)).toEqual([
+			{ kind: 'method_declaration', name: 'Build' },
+		]);
+	});
+
+	test('merged parts of a partial class have the attributes and comments of all parts and the declared accessibility', async () => {
+
+		const source = [
+			'[Serializable]',
+			'partial class Invoice',
+			'{',
+			'    void Draft() { }',
+			'}',
+			'',
+			'/// <summary>An invoice.</summary>',
+			'[DebuggerDisplay("{Number}")]',
+			'public partial class Invoice',
+			'{',
+			'    void Send() { }',
+			'}',
+			'',
+			'partial class Invoice',
+			'{',
+			'}',
+		].join('\n');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Csharp, source);
+
+		expect(structure!.children).toHaveLength(1);
+		const invoice = structure!.children[0];
+		// the parts that don't declare it would be `internal`
+		expect(invoice.visibility).toBe('public');
+		expect(invoice.decorators).toEqual(['[Serializable]', '[DebuggerDisplay("{Number}")]']);
+		expect(invoice.leadingComment).toBe('/// <summary>An invoice.</summary>');
+		expect(namedChildren(invoice)).toEqual([
+			{ kind: 'method_declaration', name: 'Draft' },
+			{ kind: 'method_declaration', name: 'Send' },
+		]);
+	});
+
+	test('declarations without an access modifier have the accessibility C# defaults to', async () => {
+
+		const source = [
+			'class Repository',
+			'{',
+			'    void Reset() { }',
+			'    protected internal void Load() { }',
+			'    private protected void Save() { }',
+			'    class Entry { }',
+			'}',
+			'',
+			'public interface IRepository',
+			'{',
+			'    void Reset();',
+			'    int Count { get; }',
+			'}',
+		].join('\n');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Csharp, source);
+
+		const visibilities = descendants(structure!)
+			.filter(n => n.name !== undefined)
+			.map(n => [n.name, n.visibility]);
+		expect(visibilities).toEqual([
+			['Repository', 'internal'], // a top-level type
+			['Reset', 'private'],
+			// the less restrictive of the two for `protected internal` and the more restrictive for `private protected`
+			['Load', 'protected'],
+			['Save', 'private'],
+			['Entry', 'private'],
+			['IRepository', 'public'],
+			['Reset', 'public'], // interface members
+			['Count', 'public'],
+		]);
+	});
+
+	test('auto-properties are fields with their accessors', async () => {
+
+		const source = await fromFixture('partialClass.cs');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Csharp, source);
+
+		const properties = descendants(structure!)
+			.filter(n => n.kind === 'property_declaration')
+			.map(n => ({ name: n.name, symbolKind: n.symbolKind, type: n.type, accessors: n.accessors }));
+		expect(properties).toEqual([
+			{ name: 'Total', symbolKind: SymbolKind.Field, type: 'decimal', accessors: ['get'] },
+			{ name: 'Amount', symbolKind: SymbolKind.Field, type: 'decimal', accessors: ['get', 'init'] },
+			{ name: 'Total', symbolKind: SymbolKind.Field, type: 'decimal', accessors: ['get', 'private set'] },
+		]);
+	});
+
+	test('record declarations are types with their parameters', async () => {
+
+		const source = await fromFixture('partialClass.cs');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Csharp, source);
+
+		const record = descendants(structure!).find(n => n.name === 'LineItem')!;
+		expect(record.symbolKind).toBe(SymbolKind.Class);
+		expect(record.detail).toBe('public record LineItem(string Sku, int Quantity)');
+	});
+
+	test('block-scoped namespaces are nested', async () => {
+
+		const source = [
+			'namespace Outer',
+			'{',
+			'    namespace Inner',
+			'    {',
+			'        class C',
+			'        {',
+			'        }',
+			'    }',
+			'}',
+		].join('\n');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Csharp, source);
+
+		const outer = structure!.children.find(n => n.name === 'Outer')!;
+		expect(namedChildren(outer)).toEqual([{ kind: 'namespace_declaration', name: 'Inner' }]);
+		expect(namedChildren(outer.children.find(n => n.name === 'Inner')!)).toEqual([{ kind: 'class_declaration', name: 'C' }]);
+	});
+
+	test('names are qualified with their nested namespaces and enclosing types', async () => {
+
+		const source = [
+			'namespace Company.Shapes',
+			'{',
+			'    namespace Geometry',
+			'    {',
+			'        public class Circle',
+			'        {',
+			'            public double Area() { return 0; }',
+			'        }',
+			'    }',
+			'}',
+		].join('\n');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Csharp, source);
+
+		expect(descendants(structure!).map(n => n.qualifiedName)).toEqual([
+			'Company.Shapes',
+			'Company.Shapes.Geometry',
+			'Company.Shapes.Geometry.Circle',
+			'Company.Shapes.Geometry.Circle.Area',
+		]);
+	});
+
+	test('enum members with includeEnumMembers', async () => {
+
+		const source = await fromFixture('Enums.cs');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Csharp, source, { includeEnumMembers: true });
+
+		const severity = descendants(structure!).find(n => n.kind === 'enum_declaration')!;
+		expect(severity.name).toBe('Severity');
+		expect(severity.children.map(n => ({ kind: n.kind, name: n.name, value: n.value, symbolKind: n.symbolKind }))).toEqual([
+			{ kind: 'enum_member', name: 'Info', value: undefined, symbolKind: SymbolKind.Constant },
+			{ kind: 'enum_member', name: 'Warning', value: '2', symbolKind: SymbolKind.Constant },
+			{ kind: 'enum_member', name: 'Error', value: 'Warning * 2', symbolKind: SymbolKind.Constant },
+		]);
+	});
 });
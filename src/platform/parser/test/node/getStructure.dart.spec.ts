@@ -0,0 +1,81 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+describe('getStructure - dart', () => {
+	afterAll(() => _dispose());
+
+	function namedChildren(node: OverlayNode) {
+		return node.children
+			.filter(n => n.name !== undefined)
+			.map(n => ({ kind: n.kind, name: n.name }));
+	}
+
+	test('source with different syntax constructs', async () => {
+
+		const source = await fromFixture('test.dart');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Dart, source);
+
+		expect(namedChildren(structure!)).toEqual([
+			{ kind: 'class_definition', name: 'Point' },
+			{ kind: 'mixin_declaration', name: 'Describable' },
+			{ kind: 'extension_declaration', name: 'PointJson' },
+			{ kind: 'enum_declaration', name: 'Direction' },
+			{ kind: 'getter_signature', name: 'counter' },
+			{ kind: 'setter_signature', name: 'counter' },
+			{ kind: 'function_signature', name: 'main' },
+		]);
+		const direction = structure!.children.find(n => n.name === 'Direction')!;
+		expect(namedChildren(direction)).toEqual([
+			{ kind: 'enum_constant', name: 'north' },
+			{ kind: 'enum_constant', name: 'south' },
+		]);
+	});
+
+	test('named and factory constructors are members with their names', async () => {
+
+		const source = await fromFixture('test.dart');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Dart, source);
+
+		const point = structure!.children.find(n => n.name === 'Point')!;
+		expect(point.detail).toBe('class Point');
+		expect(point.leadingComment).toBe('/// A point on a plane.');
+		expect(namedChildren(point)).toEqual([
+			{ kind: 'constructor_signature', name: 'Point' },
+			{ kind: 'constructor_signature', name: 'Point.origin' },
+			{ kind: 'factory_constructor_signature', name: 'Point.fromJson' },
+			{ kind: 'getter_signature', name: 'length' },
+			{ kind: 'function_signature', name: 'toString' },
+		]);
+
+		const fromJson = point.children.find(n => n.name === 'Point.fromJson')!;
+		expect(fromJson.symbolKind).toBe(SymbolKind.Constructor);
+		expect(fromJson.detail).toBe('factory Point.fromJson(Map<String, dynamic> json)');
+		expect(point.children.find(n => n.name === 'toString')!.symbolKind).toBe(SymbolKind.Method);
+	});
+
+	test('functions and methods span their bodies and annotations', async () => {
+
+		const source = await fromFixture('test.dart');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Dart, source);
+
+		const main = structure!.children.find(n => n.name === 'main')!;
+		expect(main.symbolKind).toBe(SymbolKind.Function);
+		expect(source.substring(main.startIndex, main.endIndex).trim()).toBe('void main() {\n  print(Point.fromJson({\'x\': 1.0, \'y\': 2.0}));\n}');
+
+		const toString = structure!.children.find(n => n.name === 'Point')!.children.find(n => n.name === 'toString')!;
+		expect(source.substring(toString.startIndex, toString.endIndex).trim()).toBe('@override\n  String toString() => \'Point($x, $y)\';');
+	});
+});
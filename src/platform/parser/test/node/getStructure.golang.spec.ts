@@ -4,9 +4,14 @@
  *--------------------------------------------------------------------------------------------*/
 
 import { afterAll, describe, expect, test } from 'vitest';
-import { _dispose } from '../../node/parserImpl';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose, _getSyntaxErrors } from '../../node/parserImpl';
+import { _parse } from '../../node/parserWithCaching';
+import { getEnclosingSymbol, getSyntaxNode, structureComputer } from '../../node/structure';
+import { findFirst } from '../../node/structureWalking';
+import { SymbolKind } from '../../node/symbolKinds';
 import { WASMLanguage } from '../../node/treeSitterLanguages';
-import { fromFixture, srcWithAnnotatedStructure } from './getStructure.util';
+import { descendants, fromFixture, srcWithAnnotatedStructure } from './getStructure.util';
 
 describe('getStructure - golang', () => {
 	afterAll(() => _dispose());
@@ -21,4 +26,1102 @@ describe('getStructure - golang', () => {
 
 		expect(await golangStruct(source)).toMatchSnapshot();
 	});
+
+	test('a leading byte order mark doesn\'t change the structure but shifts its offsets by one', async () => {
+
+		const source = await fromFixture('test.go');
+		const sourceWithBom = await fromFixture('testWithBom.go');
+		expect(sourceWithBom).toBe('\uFEFF' + source);
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+		const structureWithBom = await structureComputer.getStructure(WASMLanguage.Go, sourceWithBom);
+
+		expect(structureWithBom!.packageName).toBe('main');
+		const packageClause = structureWithBom!.children.find(n => n.kind === 'package_clause')!;
+		expect(sourceWithBom.substring(packageClause.startIndex, packageClause.endIndex).trim()).toBe('package main');
+		const main = structure!.children.find(n => n.name === 'main')!;
+		const mainWithBom = structureWithBom!.children.find(n => n.name === 'main')!;
+		expect(mainWithBom.detail).toBe(main.detail);
+		expect(mainWithBom.startIndex).toBe(main.startIndex + 1);
+		expect(mainWithBom.range!.start).toEqual(main.range!.start);
+		expect(structureWithBom!.children.map(n => n.kind)).toEqual(structure!.children.map(n => n.kind));
+	});
+
+	test('the package is noted on the root and top-level declarations are in source order', async () => {
+
+		const source = await fromFixture('test.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		expect(structure!.packageName).toBe('main');
+		// the statements after \`main\` aren't valid at the top level, so only the declarations before them are compared
+		const topLevelKinds = new Set(['package_clause', 'import_declaration', 'const_declaration', 'var_declaration', 'type_declaration', 'function_declaration', 'method_declaration']);
+		expect(structure!.children.filter(n => topLevelKinds.has(n.kind)).map(n => ({ kind: n.kind, name: n.name }))).toEqual([
+			{ kind: 'package_clause', name: undefined },
+			{ kind: 'import_declaration', name: undefined },
+			{ kind: 'const_declaration', name: 'ConstExample' },
+			{ kind: 'var_declaration', name: undefined },
+			{ kind: 'type_declaration', name: 'StructExample' },
+			{ kind: 'type_declaration', name: 'InterfaceExample' },
+			{ kind: 'method_declaration', name: 'MethodExample' },
+			{ kind: 'function_declaration', name: 'main' },
+		]);
+		expect(structure!.children.every((n, i, nodes) => i === 0 || nodes[i - 1].endIndex <= n.startIndex)).toBe(true);
+
+		expect((await structureComputer.getStructure(WASMLanguage.Go, 'func f() {}\n'))!.packageName).toBeUndefined();
+	});
+
+	test('top-level declarations are flagged as exported or not and get names qualified with the package', async () => {
+
+		const source = await fromFixture('exports.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		expect(structure!.packageName).toBe('shapes');
+		const declarations = structure!.children
+			.filter(n => n.exported !== undefined)
+			.map(n => ({ name: n.name, exported: n.exported, qualifiedName: n.qualifiedName }));
+		expect(declarations).toEqual([
+			{ name: 'Pi', exported: true, qualifiedName: 'shapes.Pi' },
+			{ name: 'defaultRadius', exported: false, qualifiedName: 'shapes.defaultRadius' },
+			{ name: 'Point', exported: true, qualifiedName: 'shapes.Point' },
+			{ name: 'shape', exported: false, qualifiedName: 'shapes.shape' },
+			{ name: 'Circle', exported: true, qualifiedName: 'shapes.Circle' },
+			{ name: 'NewCircle', exported: true, qualifiedName: 'shapes.NewCircle' },
+			{ name: 'Area', exported: true, qualifiedName: 'shapes.Circle.Area' },
+			{ name: 'scale', exported: false, qualifiedName: 'shapes.Circle.scale' },
+			{ name: 'init', exported: false, qualifiedName: 'shapes.init' },
+		]);
+
+		// a group of variables has no single name
+		const group = structure!.children.find(n => n.kind === 'var_declaration' && n.name === undefined);
+		expect(group?.exported).toBeUndefined();
+		// members aren't top-level declarations
+		const center = descendants(structure!).find(n => n.name === 'Center');
+		expect(center).toBeDefined();
+		expect(center?.exported).toBeUndefined();
+	});
+
+	test('declarations outside of function bodies are public if their names start with an upper-case letter', async () => {
+
+		const source = await fromFixture('test.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const visibilityOf = (kind: string, name: string) => descendants(structure!).find(n => n.kind === kind && n.name === name)?.visibility;
+		expect(visibilityOf('type_declaration', 'StructExample')).toBe('public');
+		expect(visibilityOf('method_declaration', 'MethodExample')).toBe('public');
+		expect(visibilityOf('function_declaration', 'main')).toBe('private');
+		expect(structure!.children.find(n => n.kind === 'package_clause')?.visibility).toBeUndefined();
+		// locals, e.g., `structExample := StructExample{...}`, aren't declarations of the package
+		const main = structure!.children.find(n => n.name === 'main')!;
+		expect(descendants(main).filter(n => n.visibility !== undefined)).toEqual([]);
+
+		const cache = await structureComputer.getStructure(WASMLanguage.Go, await fromFixture('exports.go'));
+		const fields = cache!.children.find(n => n.name === 'Cache')!;
+		expect(descendants(fields).filter(n => n.name !== undefined).map(n => [n.name, n.visibility])).toEqual([
+			['Size', 'public'],
+			['mu', 'private'],
+			['items', 'private'],
+		]);
+		expect(cache!.children.filter(n => n.kind === 'method_declaration').map(n => [n.name, n.visibility])).toEqual([
+			['Get', 'public'],
+			['evict', 'private'],
+			['Error', 'public'],
+		]);
+	});
+
+	test('members get names qualified with the package and their enclosing types', async () => {
+
+		const source = await fromFixture('test.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const qualifiedNames = descendants(structure!)
+			.filter(n => n.name === 'MethodExample' || n.name === 'Name')
+			.map(n => n.qualifiedName);
+		expect(qualifiedNames).toEqual([
+			'main.StructExample.Name',
+			'main.InterfaceExample.MethodExample',
+			'main.StructExample.MethodExample',
+		]);
+		// with nestGoMethods, a method is still qualified with its receiver type rather than twice with its parent
+		const nested = await structureComputer.getStructure(WASMLanguage.Go, source, { nestGoMethods: true });
+		expect(descendants(nested!).filter(n => n.kind === 'method_declaration').map(n => n.qualifiedName)).toEqual(['main.StructExample.MethodExample']);
+	});
+
+	test('stable IDs do not change when lines are inserted above', async () => {
+
+		const source = await fromFixture('test.go');
+		const shifted = source.replace('type StructExample', '\ntype StructExample');
+
+		const before = (await structureComputer.getStructure(WASMLanguage.Go, source))!.children.find(n => n.name === 'StructExample')!;
+		const after = (await structureComputer.getStructure(WASMLanguage.Go, shifted))!.children.find(n => n.name === 'StructExample')!;
+
+		expect(before.stableId).toBe('type_declaration:main.StructExample');
+		expect(after.stableId).toBe(before.stableId);
+		expect(after.range!.start.line).toBe(before.range!.start.line + 1);
+		expect(after.children.map(n => n.stableId)).toEqual(before.children.map(n => n.stableId));
+	});
+
+	test('stable IDs of unnamed and repeated nodes are told apart by their parents and occurrences', async () => {
+
+		const source = [
+			'package main',
+			'',
+			'func main() {',
+			'	if a {',
+			'	}',
+			'	if b {',
+			'	}',
+			'}',
+			'',
+		].join('\n');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const ids = descendants(structure!).map(n => n.stableId);
+		expect(new Set(ids).size).toBe(ids.length);
+		expect(structure!.children.find(n => n.name === 'main')!.stableId).toBe('function_declaration:main.main');
+	});
+
+	test('whitespace of multi-line signatures is normalized in details but not in ranges', async () => {
+
+		const source = await fromFixture('multilineSignatures.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const details = descendants(structure!)
+			.filter(n => n.detail !== undefined)
+			.map(n => ({ name: n.name, detail: n.detail }));
+
+		expect(details).toEqual([
+			{ name: 'Map', detail: 'func Map[T any, U any](s []T, f func(T) U) []U' },
+			{ name: 'Join', detail: 'func Join(parts []string, sep string) string' },
+			{ name: 'Handler', detail: 'type Handler interface' },
+			{ name: 'Handle', detail: 'Handle(ctx context.Context, req *Request) error' },
+		]);
+
+		const map = structure!.children.find(n => n.name === 'Map')!;
+		expect(source.substring(map.startIndex, map.endIndex)).toContain('(\n\ts []T,\n\tf func(T) U,\n) []U {');
+	});
+
+	test('type parameters are kept in declaration details', async () => {
+
+		const source = await fromFixture('generics.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const declarations = descendants(structure!)
+			.filter(n => n.name !== undefined)
+			.map(n => ({ name: n.name, detail: n.detail }));
+
+		expect(declarations).toEqual([
+			{ name: 'Map', detail: 'func Map[T any, U any](s []T, f func(T) U) []U' },
+			{ name: 'Sum', detail: 'func Sum[T int | string](values []T) T' },
+			{ name: 'Keys', detail: 'func Keys[K, V comparable](m map[K]V) []K' },
+			{ name: 'Stack', detail: 'type Stack[T any] struct' },
+			{ name: 'items', detail: 'items []T' },
+			{ name: 'Push', detail: 'func (s *Stack[T]) Push(item T)' },
+		]);
+	});
+
+	test('type parameter lists of generic functions and types', async () => {
+
+		const source = await fromFixture('genericTypes.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const declarations = structure!.children
+			.filter(n => n.name !== undefined)
+			.map(n => ({ name: n.name, typeParameters: n.typeParameters, detail: n.detail }));
+
+		expect(declarations).toEqual([
+			{ name: 'Number', typeParameters: undefined, detail: 'type Number interface' },
+			{ name: 'Map', typeParameters: '[T, U any]', detail: 'func Map[T, U any](s []T, f func(T) U) []U' },
+			{ name: 'List', typeParameters: '[T any]', detail: 'type List[T any] []T' },
+			{ name: 'Pair', typeParameters: '[K comparable, V Number]', detail: 'type Pair[K comparable, V Number] struct' },
+			{ name: 'Swap', typeParameters: undefined, detail: 'func (p Pair[K, V]) Swap() Pair[K, V]' },
+		]);
+
+		const number = structure!.children.find(n => n.name === 'Number')!;
+		expect(number.children.map(n => n.kind)).toEqual(['type_constraint']);
+	});
+
+	test('declarations get language-agnostic symbol kinds', async () => {
+
+		const source = await fromFixture('test.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const symbols = descendants(structure!)
+			.filter(n => n.symbolKind !== undefined)
+			.map(n => ({ name: n.name, symbolKind: n.symbolKind }));
+
+		expect(symbols).toEqual([
+			{ name: undefined, symbolKind: SymbolKind.Package },
+			{ name: 'ConstExample', symbolKind: SymbolKind.Constant },
+			{ name: undefined, symbolKind: SymbolKind.Variable },
+			{ name: 'BoolExample', symbolKind: SymbolKind.Variable },
+			{ name: 'IntExample', symbolKind: SymbolKind.Variable },
+			{ name: 'StructExample', symbolKind: SymbolKind.Struct },
+			{ name: 'Name', symbolKind: SymbolKind.Field },
+			{ name: 'InterfaceExample', symbolKind: SymbolKind.Interface },
+			{ name: 'MethodExample', symbolKind: SymbolKind.Method },
+			{ name: 'MethodExample', symbolKind: SymbolKind.Method },
+			{ name: 'main', symbolKind: SymbolKind.Function },
+			{ name: 'i', symbolKind: SymbolKind.Variable },
+		]);
+
+		const statements = descendants(structure!).filter(n => n.kind.endsWith('_statement'));
+		expect(statements.length).toBeGreaterThan(0);
+		expect(statements.every(n => n.symbolKind === undefined)).toBe(true);
+	});
+
+	test('test functions are labeled with their role', async () => {
+
+		const source = await fromFixture('foo_test.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const functions = structure!.children
+			.filter(n => n.kind === 'function_declaration' || n.kind === 'method_declaration')
+			.map(n => ({ name: n.name, role: n.role }));
+
+		expect(functions).toEqual([
+			{ name: 'TestSum', role: 'test' },
+			{ name: 'Test_sumOfNothing', role: 'test' },
+			{ name: 'BenchmarkSum', role: 'benchmark' },
+			{ name: 'ExampleSum', role: 'example' },
+			{ name: 'FuzzSum', role: 'fuzz' },
+			{ name: 'TestdataPath', role: undefined },
+			{ name: 'TestHelper', role: undefined },
+			{ name: 'BenchmarkSetup', role: undefined },
+			{ name: 'Testify', role: undefined },
+			{ name: 'ExampleWithArgs', role: undefined },
+			{ name: 'TestMethod', role: undefined },
+		]);
+	});
+
+	test('embedded interfaces declared in the same source get their method sets inlined', async () => {
+
+		const source = await fromFixture('interfaceMethodSets.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source, { inlineEmbeddedInterfaces: true });
+
+		const closableStore = structure!.children.find(n => n.name === 'ClosableStore')!;
+		expect(closableStore.children.map(n => ({ kind: n.kind, name: n.name, methods: n.methods?.map(m => m.name) }))).toEqual([
+			{ kind: 'embedded_interface', name: 'Store', methods: ['Get', 'Put'] },
+			{ kind: 'embedded_interface', name: 'io.Closer', methods: undefined },
+			{ kind: 'method_spec', name: 'Flush', methods: undefined },
+		]);
+
+		// inlined methods are the nodes of the interfaces declaring them
+		const getter = structure!.children.find(n => n.name === 'Getter')!;
+		expect(closableStore.children[0].methods![0]).toBe(getter.children[0]);
+	});
+
+	test('without inlineEmbeddedInterfaces, embedded interfaces have no methods', async () => {
+
+		const source = await fromFixture('interfaceMethodSets.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		expect(descendants(structure!).filter(n => n.kind === 'embedded_interface').map(n => n.methods)).toEqual([undefined, undefined, undefined]);
+	});
+
+	test('deferred calls are linked to their functions in source order', async () => {
+
+		const source = await fromFixture('defers.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const save = structure!.children.find(n => n.name === 'Save')!;
+		expect(save.defers?.map(n => ({ kind: n.kind, callee: n.callee, text: source.substring(n.startIndex, n.endIndex).trim().split('\n')[0] }))).toEqual([
+			{ kind: 'deferred_call', callee: 's.mu.Unlock', text: 'defer s.mu.Unlock()' },
+			{ kind: 'deferred_call', callee: 'f.Close', text: 'defer f.Close()' },
+			{ kind: 'deferred_call', callee: undefined, text: 'defer func() {' },
+		]);
+		// they're the same nodes as the children of the function
+		expect(save.defers).toEqual(save.children.filter(n => n.kind === 'deferred_call'));
+		expect(save.defers![0]).toBe(save.children.find(n => n.kind === 'deferred_call'));
+
+		// `wg.Done` is deferred by the function literal of the goroutine
+		const load = structure!.children.find(n => n.name === 'Load')!;
+		expect(load.defers).toBeUndefined();
+		expect(descendants(load).filter(n => n.kind === 'deferred_call').map(n => n.callee)).toEqual(['wg.Done']);
+		expect(descendants(load).find(n => n.kind === 'goroutine')?.callee).toBeUndefined();
+	});
+
+	test('parameters and results of functions and methods are listed one by one', async () => {
+		const source = await fromFixture('signatures.go');
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const signatures = structure!.children
+			.filter(n => n.kind === 'function_declaration' || n.kind === 'method_declaration')
+			.map(n => ({ name: n.name, parameters: n.parameters, results: n.results }));
+		expect(signatures).toEqual([
+			{
+				name: 'Fprintf',
+				parameters: [{ name: 'w', type: 'io.Writer' }, { name: 'format', type: 'string' }, { name: 'args', type: '...any' }],
+				results: [{ name: 'n', type: 'int' }, { name: 'err', type: 'error' }],
+			},
+			{
+				name: 'Swap',
+				parameters: [{ name: 'a', type: 'int' }, { name: 'b', type: 'int' }],
+				results: [{ type: 'int' }, { type: 'int' }],
+			},
+			// the receiver isn't a parameter
+			{ name: 'Close', parameters: [], results: [{ type: 'error' }] },
+			{ name: 'Reset', parameters: [], results: [] },
+		]);
+	});
+
+	test('methods are linked to their receiver types declared in the same source', async () => {
+
+		const source = await fromFixture('receivers.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const typeDeclarations = structure!.children.filter(n => n.kind === 'type_declaration');
+		expect(typeDeclarations.map(n => n.methods?.map(m => m.name))).toEqual([
+			['String', 'Move'],
+			['Fahrenheit', 'Add'],
+			['Push'],
+		]);
+
+		const methods = structure!.children
+			.filter(n => n.kind === 'method_declaration')
+			.map(n => ({ name: n.name, receiver: n.receiver }));
+		expect(methods).toEqual([
+			{ name: 'String', receiver: { typeName: 'Point', isPointer: false } },
+			{ name: 'Move', receiver: { typeName: 'Point', isPointer: true } },
+			{ name: 'Fahrenheit', receiver: { typeName: 'Celsius', isPointer: false } },
+			{ name: 'Add', receiver: { typeName: 'Names', isPointer: true } },
+			{ name: 'Push', receiver: { typeName: 'Stack', isPointer: true } },
+			{ name: 'Reset', receiver: { typeName: 'Builder', isPointer: true } },
+		]);
+
+		// methods stay top-level declarations; linked ones are the same nodes
+		expect(typeDeclarations[0].methods![0]).toBe(structure!.children.find(n => n.name === 'String'));
+		expect(structure!.children.find(n => n.name === 'Join')?.receiver).toBeUndefined();
+	});
+
+	test('with nestGoMethods, methods are nested under their receiver types declared in the same source', async () => {
+
+		const source = await fromFixture('receivers.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source, { nestGoMethods: true });
+
+		const declarations = (node: OverlayNode) => node.children
+			.filter(n => n.kind === 'type_declaration' || n.kind === 'method_declaration' || n.kind === 'function_declaration')
+			.map(n => ({ kind: n.kind, name: n.name }));
+		expect(declarations(structure!)).toEqual([
+			{ kind: 'type_declaration', name: 'Point' },
+			{ kind: 'type_declaration', name: undefined },
+			{ kind: 'type_declaration', name: 'Stack' },
+			// `Builder` is declared in another file, and `Join` isn't a method
+			{ kind: 'method_declaration', name: 'Reset' },
+			{ kind: 'function_declaration', name: 'Join' },
+		]);
+
+		const [point, group, stack] = structure!.children.filter(n => n.kind === 'type_declaration');
+		expect(declarations(point)).toEqual([
+			{ kind: 'method_declaration', name: 'String' },
+			{ kind: 'method_declaration', name: 'Move' },
+		]);
+		expect(point.children.at(-1)!.name).toBe('Move');
+		expect(declarations(group)).toEqual([
+			{ kind: 'method_declaration', name: 'Fahrenheit' },
+			{ kind: 'method_declaration', name: 'Add' },
+		]);
+		expect(declarations(stack)).toEqual([{ kind: 'method_declaration', name: 'Push' }]);
+
+		// nested methods keep their ranges
+		const move = point.children.find(n => n.name === 'Move')!;
+		const unnested = (await structureComputer.getStructure(WASMLanguage.Go, source))!.children.find(n => n.name === 'Move')!;
+		expect(move.startIndex).toBe(unnested.startIndex);
+		expect(move.endIndex).toBe(unnested.endIndex);
+		expect(point.methods).toEqual([point.children.find(n => n.name === 'String'), move]);
+	});
+
+	test('function literals are named after their variables or labeled like the compiler does', async () => {
+
+		const source = await fromFixture('closures.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const closures = descendants(structure!)
+			.filter(n => n.kind === 'func_literal')
+			.map(n => ({ name: n.name, label: n.label, symbolKind: n.symbolKind }));
+		expect(closures).toEqual([
+			{ name: 'defaultHandler', label: undefined, symbolKind: SymbolKind.Function },
+			{ name: 'handler', label: undefined, symbolKind: SymbolKind.Function },
+			{ name: undefined, label: '(*Server).Start.func2', symbolKind: SymbolKind.Function },
+			{ name: undefined, label: 'main.func1', symbolKind: SymbolKind.Function },
+			{ name: undefined, label: 'main.func2', symbolKind: SymbolKind.Function },
+			{ name: 'check', label: undefined, symbolKind: SymbolKind.Function },
+			{ name: undefined, label: 'main.func2.2', symbolKind: SymbolKind.Function },
+		]);
+		expect(descendants(structure!).find(n => n.name === 'handler')?.detail).toBe('func(w http.ResponseWriter, r *http.Request)');
+	});
+
+	test('function literals of goroutines are nested in their goroutines', async () => {
+
+		const source = await fromFixture('closures.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const goroutine = descendants(structure!).find(n => n.kind === 'goroutine')!;
+		expect(goroutine.children.map(n => ({ kind: n.kind, label: n.label, text: source.substring(n.startIndex, n.endIndex).split('\n')[0] }))).toEqual([
+			{ kind: 'func_literal', label: 'main.func1', text: 'func() {' },
+		]);
+		expect(goroutine.children[0].children.map(n => source.substring(n.startIndex, n.endIndex).trim())).toEqual(['http.ListenAndServe(cfg.Addr, nil)']);
+	});
+
+	test('function literals know the declarations they are in', async () => {
+
+		const source = await fromFixture('closures.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const goroutine = findFirst(structure!, n => n.kind === 'goroutine')!;
+		const closure = findFirst(goroutine, n => n.kind === 'func_literal')!;
+		expect(closure.enclosingSymbol).toBe('main');
+		expect(getEnclosingSymbol(structure!, closure.startIndex)?.name).toBe('main');
+
+		expect(descendants(structure!).filter(n => n.kind === 'func_literal').map(n => [n.name ?? n.label, n.enclosingSymbol])).toEqual([
+			['defaultHandler', undefined],
+			['handler', 'Start'],
+			['(*Server).Start.func2', 'Start'],
+			['main.func1', 'main'],
+			['main.func2', 'main'],
+			['check', 'main'],
+			['main.func2.2', 'main'],
+		]);
+	});
+
+	test('anonymous struct types get nodes of their own', async () => {
+
+		const source = await fromFixture('closures.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const anonymousStructs = descendants(structure!).filter(n => n.kind === 'anonymous_struct');
+		expect(anonymousStructs.map(n => ({ symbolKind: n.symbolKind, fields: n.children.map(c => c.name) }))).toEqual([
+			{ symbolKind: SymbolKind.Struct, fields: ['Addr', 'Port'] },
+		]);
+		// named struct types are declared by their type declarations
+		expect(structure!.children.find(n => n.name === 'Server')?.children.map(n => n.name)).toEqual(['names']);
+	});
+
+	test('concurrency constructs get dedicated kinds', async () => {
+
+		const source = await fromFixture('concurrency.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const concurrencyKinds = new Set(['goroutine', 'channel_operation', 'channel_creation', 'select_statement']);
+		const nodes = descendants(structure!)
+			.filter(n => concurrencyKinds.has(n.kind))
+			.map(n => ({ kind: n.kind, text: source.substring(n.startIndex, n.endIndex).trim().split('\n')[0] }));
+
+		expect(nodes).toEqual([
+			{ kind: 'channel_operation', text: 'results <- j * 2' },
+			{ kind: 'channel_creation', text: 'make(chan int, 8)' },
+			{ kind: 'channel_creation', text: 'make(chan int, 8)' },
+			{ kind: 'goroutine', text: 'go worker(jobs, results)' },
+			{ kind: 'channel_creation', text: 'make(chan struct{})' },
+			{ kind: 'goroutine', text: 'go func() {' },
+			{ kind: 'channel_operation', text: 'jobs <- i' },
+			{ kind: 'channel_operation', text: 'close(jobs)' },
+			{ kind: 'channel_operation', text: 'close(done)' },
+			{ kind: 'select_statement', text: 'select {' },
+			{ kind: 'channel_operation', text: '<-results' },
+			{ kind: 'channel_operation', text: '<-done' },
+		]);
+	});
+
+	test('leading doc comments are attached to declarations', async () => {
+
+		const source = await fromFixture('docComments.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const declarations = descendants(structure!)
+			.filter(n => n.name !== undefined)
+			.map(n => ({ name: n.name, leadingComment: n.leadingComment }));
+
+		expect(declarations).toEqual([
+			{ name: 'ConstExample', leadingComment: '// ConstExample is documented.' },
+			{ name: 'VarExample', leadingComment: '/* VarExample is documented\n   with a block comment. */' },
+			{ name: 'StructExample', leadingComment: '// StructExample holds a name.\n// It spans two lines.' },
+			{ name: 'Name', leadingComment: undefined },
+			{ name: 'MethodExample', leadingComment: '// MethodExample validates the name.' },
+			{ name: 'Undocumented', leadingComment: undefined },
+		]);
+	});
+
+	test('build constraints are attached to the file and directives to the declarations they precede', async () => {
+
+		const source = await fromFixture('directives.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		expect(structure!.directives).toEqual([
+			'//go:build linux && amd64',
+			'// +build linux,amd64',
+			'//go:generate go run gen.go -out index.html',
+		]);
+		const withDirectives = descendants(structure!)
+			.filter(n => n.directives !== undefined)
+			.map(n => ({ name: n.name, directives: n.directives }));
+		expect(withDirectives).toEqual([
+			{ name: 'static', directives: ['//go:embed static/*'] },
+			{ name: 'Weekday', directives: ['//go:generate stringer -type=Weekday'] },
+			{ name: 'nanotime', directives: ['//go:noescape', '//go:linkname nanotime runtime.nanotime'] },
+		]);
+		// the doc comment is kept as written, i.e., including the directive
+		expect(structure!.children.find(n => n.name === 'static')!.leadingComment).toBe('// static holds the files served by the handler.\n//\n//go:embed static/*');
+	});
+
+	test('lint suppressions are attached to the declarations they precede or trail', async () => {
+
+		const source = await fromFixture('nolint.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const suppressed = descendants(structure!)
+			.filter(n => n.lintDirectives !== undefined)
+			.map(n => ({ kind: n.kind, name: n.kind === 'function_declaration' ? n.name : undefined, lintDirectives: n.lintDirectives }));
+		expect(suppressed).toEqual([
+			{ kind: 'var_declaration', name: undefined, lintDirectives: [{ text: '//nolint:gochecknoglobals', linters: ['gochecknoglobals'] }] },
+			{ kind: 'function_declaration', name: 'Close', lintDirectives: [{ text: '//nolint:errcheck', linters: ['errcheck'] }] },
+			{ kind: 'function_declaration', name: 'Remove', lintDirectives: [{ text: '//nolint', linters: [] }] },
+			{ kind: 'function_declaration', name: 'Open', lintDirectives: [{ text: '//nolint:govet,errcheck', linters: ['govet', 'errcheck'] }] },
+			{ kind: 'function_declaration', name: 'Legacy', lintDirectives: [{ text: '//lint:ignore SA1019 the old API is still needed', linters: ['SA1019'] }] },
+		]);
+	});
+
+	test('leading comments are attached to top-level short variable declarations', async () => {
+
+		const source = await fromFixture('test.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const ch = descendants(structure!).find(n => n.kind === 'short_var_declaration' && source.substring(n.startIndex, n.endIndex).startsWith('ch :='))!;
+		expect(ch.leadingComment).toBe('// Create a channel of integers.');
+	});
+
+	test('well-formed declarations around a syntax error are kept', async () => {
+
+		const source = await fromFixture('testWithSyntaxError.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		expect(structure).toBeDefined();
+		const nodes = descendants(structure!);
+		expect(nodes.filter(n => n.kind === 'ERROR')).toEqual([]);
+		expect(nodes.map(n => n.name).filter(name => name !== undefined)).toEqual(expect.arrayContaining(['StructExample', 'InterfaceExample', 'MethodExample', 'main']));
+	});
+
+	test('complete declarations of a truncated file are kept, and the one it is cut off in is incomplete', async () => {
+
+		const source = await fromFixture('testTruncated.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const declarations = structure!.children
+			.filter(n => n.kind === 'type_declaration' || n.kind === 'method_declaration' || n.kind === 'function_declaration')
+			.map(n => ({ name: n.name, isIncomplete: n.isIncomplete }));
+		expect(declarations).toEqual([
+			{ name: 'StructExample', isIncomplete: undefined },
+			{ name: 'InterfaceExample', isIncomplete: undefined },
+			{ name: 'MethodExample', isIncomplete: undefined },
+			{ name: 'main', isIncomplete: true },
+		]);
+		const main = structure!.children.find(n => n.name === 'main')!;
+		expect(source.substring(main.startIndex, main.endIndex).trim()).toMatch(/^func main\(\) \{[\s\S]*var i Interfa$/);
+	});
+
+	test('embedded interfaces are children of the interface', async () => {
+
+		const source = await fromFixture('embeddedInterfaces.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const readCloser = descendants(structure!).find(n => n.name === 'ReadCloserExample')!;
+
+		expect(readCloser.children.map(n => ({ kind: n.kind, name: n.name }))).toEqual([
+			{ kind: 'embedded_interface', name: 'InterfaceExample' },
+			{ kind: 'embedded_interface', name: 'io.Reader' },
+			{ kind: 'method_spec', name: 'Close' },
+		]);
+	});
+
+	test('struct fields carry their types and tags', async () => {
+
+		const source = await fromFixture('structTags.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const user = descendants(structure!).find(n => n.name === 'User')!;
+
+		expect(user.children.map(n => ({ kind: n.kind, name: n.name, type: n.type, tag: n.tag }))).toEqual([
+			{ kind: 'embedded_field', name: 'Base', type: '*Base', tag: undefined },
+			{ kind: 'embedded_field', name: 'Mutex', type: 'sync.Mutex', tag: '`json:"-"`' },
+			{ kind: 'field_declaration', name: 'ID', type: 'int64', tag: '`json:"id" db:"user_id,primary"`' },
+			{ kind: 'field_declaration', name: undefined, type: 'float64', tag: undefined },
+			{ kind: 'field_declaration', name: 'Tags', type: 'map[string][]string', tag: '`json:"tags,omitempty" yaml:"tags"`' },
+			{ kind: 'field_declaration', name: 'Legacy', type: 'string', tag: '"xml:\\"legacy\\""' },
+		]);
+		expect(user.children[3].detail).toBe('X, Y float64');
+	});
+
+	test('fields of anonymous nested structs carry their tags', async () => {
+
+		const source = await fromFixture('structTags.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const meta = descendants(structure!).find(n => n.name === 'Meta')!;
+		expect(meta.tag).toBe('`json:"meta"`');
+		expect(meta.type).toBe('struct {\n\t\tPage int `json:"page"`\n\t}');
+		expect(meta.children.map(n => n.kind)).toEqual(['anonymous_struct']);
+		expect(meta.children[0].children.map(n => ({ name: n.name, type: n.type, tag: n.tag }))).toEqual([
+			{ name: 'Page', type: 'int', tag: '`json:"page"`' },
+		]);
+	});
+
+	test('struct tags round-trip exactly', async () => {
+
+		const source = await fromFixture('structTags.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const tags = descendants(structure!).map(n => n.tag).filter(tag => tag !== undefined);
+		expect(tags.length).toBe(7);
+		for (const tag of tags) {
+			expect(source).toContain(tag);
+		}
+	});
+
+	test('type aliases are told apart from defined types', async () => {
+		const source = await fromFixture('aliases.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const types = structure!.children
+			.filter(n => n.kind === 'type_declaration')
+			.map(n => ({ name: n.name, type: n.type, isAlias: n.isAlias }));
+		expect(types).toEqual([
+			{ name: 'MyInt', type: 'int', isAlias: true },
+			{ name: 'MyCount', type: 'int', isAlias: undefined },
+			{ name: 'Names', type: '[]string', isAlias: true },
+		]);
+	});
+
+	test('specs of grouped constants and variables are children of their declarations', async () => {
+		const source = await fromFixture('constBlocks.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const specsOf = (declaration: OverlayNode) => declaration.children
+			.filter(n => n.kind === 'const_spec' || n.kind === 'var_spec')
+			.map(n => ({ name: n.name, type: n.type, value: n.value, usesIota: n.usesIota }));
+		const [weekdays, sizes, pi, vars] = structure!.children.filter(n => n.kind === 'const_declaration' || n.kind === 'var_declaration');
+
+		// constants without a value repeat the preceding one, so they're sequential, too
+		expect(specsOf(weekdays)).toEqual([
+			{ name: 'Sunday', type: 'Weekday', value: 'iota', usesIota: true },
+			{ name: 'Monday', type: 'Weekday', value: 'iota', usesIota: true },
+			{ name: 'Tuesday', type: 'Weekday', value: 'iota', usesIota: true },
+			{ name: 'Wednesday', type: 'Weekday', value: 'iota', usesIota: true },
+		]);
+		expect(specsOf(sizes)).toEqual([
+			{ name: 'KB', type: undefined, value: '1 << (10 * (iota + 1))', usesIota: true },
+			{ name: 'MB', type: undefined, value: '1 << (10 * (iota + 1))', usesIota: true },
+		]);
+		expect(specsOf(vars)).toEqual([
+			{ name: 'name', type: 'string', value: undefined, usesIota: undefined },
+			{ name: 'count', type: undefined, value: '1', usesIota: undefined },
+		]);
+
+		// a single spec is represented by its declaration
+		expect(pi.children).toEqual([]);
+		expect({ name: pi.name, type: pi.type, value: pi.value, usesIota: pi.usesIota }).toEqual({ name: 'Pi', type: 'float64', value: '3.14', usesIota: undefined });
+	});
+
+	test('values of constants derived from iota are computed for simple expressions', async () => {
+		const source = await fromFixture('iotaValues.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const valuesOf = (declaration: OverlayNode) => declaration.children
+			.filter(n => n.kind === 'const_spec')
+			.map(n => ({ name: n.name, computedValue: n.computedValue }));
+		const [letters, sizes, others] = structure!.children.filter(n => n.kind === 'const_declaration');
+
+		expect(valuesOf(letters)).toEqual([
+			{ name: 'A', computedValue: 0 },
+			{ name: '_', computedValue: undefined }, // skipped
+			{ name: 'C', computedValue: 2 },
+			{ name: 'D', computedValue: 13 },
+		]);
+		expect(valuesOf(sizes)).toEqual([
+			{ name: 'KB', computedValue: 1024 },
+			{ name: 'MB', computedValue: 1048576 },
+		]);
+		// refers to another constant
+		expect(valuesOf(others)).toEqual([
+			{ name: 'Base', computedValue: undefined },
+			{ name: 'Other', computedValue: undefined },
+		]);
+	});
+
+	test('import specs are children of their import declarations', async () => {
+
+		const source = await fromFixture('imports.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const imports = structure!.children
+			.filter(n => n.kind === 'import_declaration')
+			.map(n => n.children.map(spec => ({ kind: spec.kind, path: spec.path, alias: spec.alias })));
+
+		expect(imports).toEqual([
+			[
+				{ kind: 'import_spec', path: 'os', alias: undefined },
+			],
+			[
+				{ kind: 'import_spec', path: 'fmt', alias: undefined },
+				{ kind: 'import_spec', path: 'strings', alias: 'str' },
+				{ kind: 'import_spec', path: 'math', alias: '.' },
+				{ kind: 'import_spec', path: 'embed', alias: '_' },
+				{ kind: 'import_spec', path: 'net/http', alias: undefined },
+			],
+		]);
+	});
+
+	test('case clauses are labeled after their cases, and default clauses get nodes with includeGoCaseClauses', async () => {
+
+		const source = await fromFixture('test.go');
+		const caseKinds = new Set(['expression_case', 'communication_case', 'type_case', 'default_case']);
+		const caseLabels = (structure: OverlayNode | undefined) => descendants(structure!.children.find(n => n.name === 'main')!).filter(n => caseKinds.has(n.kind)).map(n => n.label);
+
+		expect(caseLabels(await structureComputer.getStructure(WASMLanguage.Go, source))).toEqual(['case 0', 'case 10']);
+		expect(caseLabels(await structureComputer.getStructure(WASMLanguage.Go, source, { includeGoCaseClauses: true }))).toEqual(['case 0', 'case 10', 'default']);
+	});
+
+	test('with includeGoCaseClauses, select and type switch clauses are labeled after their cases', async () => {
+
+		const source = [
+			'package main',
+			'',
+			'func describe(v any, results <-chan int, done <-chan struct{}) string {',
+			'	select {',
+			'	case r := <-results:',
+			'		_ = r',
+			'	case <-done:',
+			'		return "done"',
+			'	default:',
+			'	}',
+			'	switch x := v.(type) {',
+			'	case int,',
+			'		int64:',
+			'		return "integer"',
+			'	case string:',
+			'		return x',
+			'	}',
+			'	return ""',
+			'}',
+		].join('\n');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source, { includeGoCaseClauses: true });
+
+		const fn = structure!.children.find(n => n.name === 'describe')!;
+		expect(descendants(fn).filter(n => n.kind.endsWith('_case')).map(n => [n.kind, n.label])).toEqual([
+			['communication_case', 'case r := <-results'],
+			['communication_case', 'case <-done'],
+			['default_case', 'default'],
+			['type_case', 'case int, int64'],
+			['type_case', 'case string'],
+		]);
+	});
+
+	test('with normalizeTypes, signatures that differ in whitespace and comments only are equal', async () => {
+
+		const spaced = 'package main\n\nfunc Load() (  int ,  error ) { return 0, nil }\n';
+		const compact = 'package main\n\nfunc Load() (int, error) { return 0, nil }\n';
+		const commented = 'package main\n\nfunc Load() (int /* count */, error) { return 0, nil }\n';
+		const load = async (source: string, normalizeTypes?: boolean) => {
+			const structure = await structureComputer.getStructure(WASMLanguage.Go, source, { normalizeTypes });
+			const { detail, results } = structure!.children.find(n => n.name === 'Load')!;
+			return { detail, results };
+		};
+
+		expect((await load(spaced)).detail).toBe('func Load() (int , error)');
+		expect(await load(compact, true)).toEqual({ detail: 'func Load() (int, error)', results: [{ type: 'int' }, { type: 'error' }] });
+		expect(await load(spaced, true)).toEqual(await load(compact, true));
+		expect(await load(commented, true)).toEqual(await load(compact, true));
+	});
+
+	test('with normalizeTypes, named results and parameter types have a canonical form', async () => {
+
+		const source = [
+			'package main',
+			'',
+			'func Split(s string ,sep string, // separator',
+			'	limit int) (parts []string , err error) {',
+			'	return nil, nil',
+			'}',
+			'',
+			'type Point struct {',
+			'	X, Y int `json:"x ,y"`',
+			'}',
+		].join('\n');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source, { normalizeTypes: true });
+
+		const split = structure!.children.find(n => n.name === 'Split')!;
+		expect(split.detail).toBe('func Split(s string, sep string, limit int) (parts []string, err error)');
+		expect(split.parameters).toEqual([{ name: 's', type: 'string' }, { name: 'sep', type: 'string' }, { name: 'limit', type: 'int' }]);
+		expect(split.results).toEqual([{ name: 'parts', type: '[]string' }, { name: 'err', type: 'error' }]);
+		// string literals, e.g., struct tags, are kept as they are
+		expect(descendants(structure!).find(n => n.kind === 'field_declaration')!.detail).toBe('X, Y int `json:"x ,y"`');
+	});
+
+	test('with exportedOnly, declarations with unexported names are left out', async () => {
+
+		const source = await fromFixture('exports.go');
+		const named = (node: OverlayNode) => descendants(node).filter(n => n.name !== undefined).map(n => n.name);
+
+		const all = await structureComputer.getStructure(WASMLanguage.Go, source);
+		expect(named(all!)).toEqual(expect.arrayContaining(['maxSize', 'errEvicted', 'mu', 'items', 'evict', 'errorString', 'Error', 'newDefault']));
+
+		const exported = await structureComputer.getStructure(WASMLanguage.Go, source, { exportedOnly: true });
+		expect(exported!.children.map(n => n.kind)).toEqual(['package_clause', 'import_declaration', 'const_declaration', 'type_declaration', 'function_declaration', 'method_declaration']);
+		expect(named(exported!)).toEqual(['DefaultSize', 'Cache', 'Size', 'New', 'Get']);
+		// unexported methods of exported types and all methods of unexported types
+		expect(exported!.children.find(n => n.name === 'Cache')!.methods!.map(m => m.name)).toEqual(['Get']);
+	});
+
+	test('structure nodes resolve to the syntax nodes they were computed from', async () => {
+
+		const source = await fromFixture('test.go');
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+		const method = structure!.children.find(n => n.kind === 'method_declaration' && n.name === 'MethodExample')!;
+
+		const treeRef = await _parse(WASMLanguage.Go, source);
+		try {
+			const syntaxNode = getSyntaxNode(treeRef.tree, method);
+			expect(syntaxNode?.type).toBe('method_declaration');
+			expect(syntaxNode?.childForFieldName('name')?.text).toBe('MethodExample');
+			// the structure node also spans the blank line before and the line break after the method
+			expect(syntaxNode!.startIndex).toBeGreaterThan(method.startIndex);
+			expect(syntaxNode!.endIndex).toBeLessThan(method.endIndex);
+			expect(getSyntaxNode(treeRef.tree, structure!)).toBeUndefined();
+		} finally {
+			treeRef.dispose();
+		}
+	});
+
+	test('ranges over integers and functions are parsed without errors', async () => {
+
+		const source = await fromFixture('rangeOver.go');
+
+		expect(await _getSyntaxErrors(WASMLanguage.Go, source)).toEqual([]);
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const functions = structure!.children.filter(n => n.kind === 'function_declaration');
+		expect(functions.map(n => [n.name, n.detail])).toEqual([
+			['Countdown', 'func Countdown(n int)'],
+			['Pairs', 'func Pairs(m map[string]int) iter.Seq2[string, int]'],
+			['Sum', 'func Sum(seq iter.Seq[int]) (total int)'],
+			['main', 'func main()'],
+		]);
+		const loops = (name: string) => descendants(functions.find(n => n.name === name)!).filter(n => n.kind === 'for_statement').length;
+		expect(['Countdown', 'Pairs', 'Sum', 'main'].map(loops)).toEqual([2, 1, 1, 2]);
+		const pairs = functions.find(n => n.name === 'Pairs')!;
+		expect(findFirst(pairs, n => n.kind === 'func_literal')?.enclosingSymbol).toBe('Pairs');
+	});
+
+	test('with maxDepth, nodes nested too deep are skipped', async () => {
+
+		const source = await fromFixture('test.go');
+
+		const shallow = await structureComputer.getStructure(WASMLanguage.Go, source, { maxDepth: 2 });
+
+		const main = shallow!.children.find(n => n.name === 'main')!;
+		expect(main.children).toEqual([]);
+		expect(shallow!.children.every(n => n.children.length === 0)).toBe(true);
+
+		const deeper = await structureComputer.getStructure(WASMLanguage.Go, source, { maxDepth: 3 });
+		expect(deeper!.children.find(n => n.name === 'StructExample')!.children.map(n => n.name)).toEqual(['Name']);
+		expect(deeper!.children.find(n => n.name === 'main')!.children.length).toBeGreaterThan(0);
+		expect(descendants(deeper!).every(n => n.children.every(c => c.children.length === 0))).toBe(true);
+	});
+
+	test('without maxDepth, the whole structure is computed', async () => {
+
+		const source = await fromFixture('test.go');
+
+		const full = descendants((await structureComputer.getStructure(WASMLanguage.Go, source))!);
+		const unlimited = descendants((await structureComputer.getStructure(WASMLanguage.Go, source, {}))!);
+		const shallow = descendants((await structureComputer.getStructure(WASMLanguage.Go, source, { maxDepth: 2 }))!);
+
+		expect(unlimited.length).toBe(full.length);
+		expect(shallow.length).toBeLessThan(full.length);
+	});
+
+	test('with maxDepth, a large generated file yields only top-level declarations', async () => {
+
+		// 10k lines
+		const source = ['package generated', ''].concat(Array.from({ length: 1250 }, (_, i) => [
+			`func Generated${i}(a int) int {`,
+			`	if a > ${i} {`,
+			`		return a - ${i}`,
+			`	}`,
+			`	b := a * ${i}`,
+			`	return b`,
+			`}`,
+			``,
+		].join('\n'))).join('\n');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source, { maxDepth: 2 });
+
+		expect(structure!.children.length).toBe(1 + 1250);
+		expect(descendants(structure!).length).toBe(1 + 1250);
+	});
+
+	function localTypeCategories(source: string, structure: OverlayNode): [string, OverlayNode['typeCategory']][] {
+		const main = structure.children.find(n => n.name === 'main')!;
+		return descendants(main)
+			.filter(n => n.kind === 'short_var_declaration' || n.kind === 'var_declaration')
+			.map(n => [/^(?:var\s+)?(\w+)/.exec(source.substring(n.startIndex, n.endIndex).trim())![1], n.typeCategory]);
+	}
+
+	test('local variables get the category of their types from their values or types', async () => {
+
+		const source = await fromFixture('varTypes.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		expect(localTypeCategories(source, structure!)).toEqual([
+			['ch', 'chan'],
+			['buf', 'slice'],
+			['counts', 'map'],
+			['cfg', 'pointer'],
+			['alloc', 'pointer'],
+			['point', 'struct'],
+			['handler', 'func'],
+			['grid', 'array'],
+			['words', undefined], // the result of a call
+			['mu', 'named'],
+			['done', 'chan'],
+			['callback', 'func'],
+			['table', 'map'],
+			['a', undefined], // multiple names
+		]);
+	});
+
+	test('local variables of the example get the categories of their types', async () => {
+
+		const source = await fromFixture('test.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		expect(localTypeCategories(source, structure!)).toEqual([
+			['arrayExample', 'array'],
+			['sliceExample', 'slice'],
+			['mapExample', 'map'],
+			['structExample', 'named'],
+			['i', 'named'],
+		]);
+	});
+
+	test('declarations around trailing top-level statements are kept', async () => {
+
+		const source = await fromFixture('test.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const names = descendants(structure!).map(n => n.name).filter(name => name !== undefined);
+		expect(names).toEqual(expect.arrayContaining(['main', 'StructExample', 'MethodExample']));
+	});
+
+	test('declarations on either side of an unterminated string are kept', async () => {
+
+		const source = await fromFixture('unterminatedString.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const nodes = descendants(structure!);
+		expect(nodes.filter(n => n.kind === 'ERROR')).toEqual([]);
+		expect(nodes.map(n => n.name).filter(name => name !== undefined)).toEqual(expect.arrayContaining(['Before', 'After']));
+	});
+
+	test('break, continue, and goto statements are resolved to the labeled statements they jump to', async () => {
+
+		const source = await fromFixture('labels.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const nodes = descendants(structure!);
+		const [outer, done] = nodes.filter(n => n.kind === 'labeled_statement');
+		expect(outer.label).toBe('Outer');
+		expect(outer.children.map(n => n.kind)).toEqual(['for_statement']);
+		expect(done.label).toBe('Done');
+
+		expect(nodes.filter(n => n.jumpTarget !== undefined).map(n => [source.substring(n.startIndex, n.endIndex).trim(), n.jumpTarget])).toEqual([
+			['continue Outer', { label: 'Outer', isResolved: true, startIndex: outer.startIndex }],
+			['break Outer', { label: 'Outer', isResolved: true, startIndex: outer.startIndex }],
+			['goto Done', { label: 'Done', isResolved: true, startIndex: done.startIndex }],
+			// the function literal doesn't see the labels of the function around it
+			['break Outer', { label: 'Outer', isResolved: false }],
+		]);
+	});
+
+	test('constants of iota-based groups are the members of the group', async () => {
+
+		const source = await fromFixture('enums.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const [colors, sizes] = structure!.children.filter(n => n.kind === 'const_declaration');
+		expect(colors.children.map(n => ({ kind: n.kind, name: n.name, type: n.type, value: n.value, computedValue: n.computedValue }))).toEqual([
+			{ kind: 'const_spec', name: 'Red', type: 'Color', value: 'iota', computedValue: 0 },
+			{ kind: 'const_spec', name: 'Green', type: 'Color', value: 'iota', computedValue: 1 },
+			{ kind: 'const_spec', name: 'Blue', type: 'Color', value: 'iota', computedValue: 2 },
+		]);
+		expect(sizes.children.map(n => [n.name, n.computedValue])).toEqual([['KB', 1024], ['MB', 1048576]]);
+	});
+
+	test('comments that start with TODO, FIXME, XXX, HACK, or NOTE are annotations of the declarations below them', async () => {
+
+		const source = await fromFixture('todos.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source, { includeTodoComments: true });
+
+		const annotations = descendants(structure!).filter(n => n.kind === 'todo_annotation');
+		expect(annotations.map(n => [source.substring(n.startIndex, n.endIndex), n.annotation])).toEqual([
+			['// TODO: handle empty name', { marker: 'TODO', text: 'handle empty name' }],
+			['// FIXME(ana): wrap the error', { marker: 'FIXME', text: 'wrap the error' }],
+			['// HACK around the v1 API.', { marker: 'HACK', text: 'around the v1 API.' }],
+			['// XXX - drop in v3', { marker: 'XXX', text: 'drop in v3' }],
+		]);
+
+		const method = structure!.children.find(n => n.name === 'MethodExample')!;
+		expect(method.annotations).toEqual([annotations[0]]);
+		expect(method.annotations![0]).toBe(annotations[0]);
+		// the comment within the body is followed by a statement, which doesn't declare a symbol
+		expect(descendants(method).filter(n => n.annotations !== undefined)).toEqual([]);
+		expect(structure!.children.find(n => n.name === 'Legacy')!.annotations).toEqual([annotations[2]]);
+		expect(structure!.children.find(n => n.name === 'Old')!.annotations).toEqual([annotations[3]]);
+		// markers are only recognized in upper case at the start of a comment, like those of `_getComments`
+		expect(structure!.children.find(n => n.name === 'main')!.annotations).toBeUndefined();
+
+		const withoutOption = await structureComputer.getStructure(WASMLanguage.Go, source);
+		expect(descendants(withoutOption!).filter(n => n.kind === 'todo_annotation')).toEqual([]);
+	});
 });
@@ -0,0 +1,162 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
+import { detectLanguage, WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+describe('getStructure - graphql', () => {
+	afterAll(() => _dispose());
+
+	type Outline = { kind: string; name?: string; detail?: string; symbolKind?: SymbolKind; children?: Outline[] };
+
+	function outlineOf(node: OverlayNode): Outline[] {
+		return node.children.filter(c => c.kind !== 'comment').map(c => {
+			const children = outlineOf(c);
+			return { kind: c.kind, name: c.name, detail: c.detail, symbolKind: c.symbolKind, ...(children.length > 0 ? { children } : {}) };
+		});
+	}
+
+	test('type system definitions have their fields as children', async () => {
+
+		const source = await fromFixture('schema.graphql');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Graphql, source);
+
+		expect(outlineOf(structure!)).toEqual([
+			{ kind: 'scalar_type_definition', name: 'DateTime', detail: 'scalar DateTime', symbolKind: SymbolKind.Class },
+			{ kind: 'directive_definition', name: 'auth', detail: 'directive @auth(requires: Role = ADMIN) on OBJECT | FIELD_DEFINITION', symbolKind: SymbolKind.Function },
+			{
+				kind: 'interface_type_definition', name: 'Node', detail: 'interface Node', symbolKind: SymbolKind.Interface, children: [
+					{ kind: 'field_definition', name: 'id', detail: 'id: ID!', symbolKind: SymbolKind.Field },
+				]
+			},
+			{
+				kind: 'object_type_definition', name: 'User', detail: 'type User implements Node @auth(requires: USER)', symbolKind: SymbolKind.Class, children: [
+					{ kind: 'field_definition', name: 'id', detail: 'id: ID!', symbolKind: SymbolKind.Field },
+					{ kind: 'field_definition', name: 'name', detail: 'name: String!', symbolKind: SymbolKind.Field },
+					{ kind: 'field_definition', name: 'posts', detail: 'posts(first: Int = 10, after: String): [Post!]!', symbolKind: SymbolKind.Field },
+					{ kind: 'field_definition', name: 'createdAt', detail: 'createdAt: DateTime', symbolKind: SymbolKind.Field },
+				]
+			},
+			{
+				kind: 'object_type_definition', name: 'Post', detail: 'type Post implements Node', symbolKind: SymbolKind.Class, children: [
+					{ kind: 'field_definition', name: 'id', detail: 'id: ID!', symbolKind: SymbolKind.Field },
+					{ kind: 'field_definition', name: 'title', detail: 'title: String!', symbolKind: SymbolKind.Field },
+					{ kind: 'field_definition', name: 'author', detail: 'author: User!', symbolKind: SymbolKind.Field },
+				]
+			},
+			{ kind: 'union_type_definition', name: 'SearchResult', detail: 'union SearchResult = User | Post', symbolKind: SymbolKind.Interface },
+			{
+				kind: 'enum_type_definition', name: 'Role', detail: 'enum Role', symbolKind: SymbolKind.Enum, children: [
+					{ kind: 'enum_value_definition', name: 'ADMIN', detail: 'ADMIN', symbolKind: SymbolKind.Constant },
+					{ kind: 'enum_value_definition', name: 'USER', detail: 'USER', symbolKind: SymbolKind.Constant },
+				]
+			},
+			{
+				kind: 'input_object_type_definition', name: 'CreatePostInput', detail: 'input CreatePostInput', symbolKind: SymbolKind.Struct, children: [
+					{ kind: 'input_value_definition', name: 'title', detail: 'title: String!', symbolKind: SymbolKind.Field },
+					{ kind: 'input_value_definition', name: 'authorId', detail: 'authorId: ID!', symbolKind: SymbolKind.Field },
+				]
+			},
+		]);
+	});
+
+	test('the arguments of a field are its parameters', async () => {
+
+		const source = await fromFixture('schema.graphql');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Graphql, source);
+
+		const user = structure!.children.find(n => n.name === 'User')!;
+		expect(user.children.find(n => n.name === 'posts')!.parameters).toEqual([
+			{ name: 'first', type: 'Int' },
+			{ name: 'after', type: 'String' },
+		]);
+		expect(user.children.find(n => n.name === 'name')!.parameters).toBeUndefined();
+	});
+
+	test('operations and fragments are outlined with their names', async () => {
+
+		const source = await fromFixture('operations.graphql');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Graphql, source);
+
+		expect(outlineOf(structure!)).toEqual([
+			{ kind: 'fragment_definition', name: 'UserFields', detail: 'fragment UserFields on User', symbolKind: SymbolKind.Function },
+			{ kind: 'operation_definition', name: 'GetUser', detail: 'query GetUser($id: ID!)', symbolKind: SymbolKind.Function },
+			{ kind: 'operation_definition', name: 'CreatePost', detail: 'mutation CreatePost($input: CreatePostInput!)', symbolKind: SymbolKind.Function },
+		]);
+	});
+
+	test('fields carry their types, and directives are kept in the details of types, fields, and enum values', async () => {
+
+		const source = await fromFixture('catalog.graphql');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Graphql, source);
+
+		const product = structure!.children.find(n => n.name === 'Product')!;
+		expect(product.detail).toBe('type Product implements Node & Timestamped @cacheControl(maxAge: 60)');
+		expect(product.interfaces).toEqual(['Node', 'Timestamped']);
+		expect(product.children.map(n => ({ name: n.name, type: n.type, detail: n.detail }))).toEqual([
+			{ name: 'id', type: 'ID!', detail: 'id: ID!' },
+			{ name: 'name', type: 'String!', detail: 'name: String!' },
+			{ name: 'price', type: 'Float!', detail: 'price(currency: Currency = USD): Float! @deprecated(reason: "Use prices.")' },
+			{ name: 'createdAt', type: 'String!', detail: 'createdAt: String!' },
+		]);
+
+		const currency = structure!.children.find(n => n.name === 'Currency')!;
+		expect(currency.children.map(n => ({ kind: n.kind, name: n.name, detail: n.detail }))).toEqual([
+			{ kind: 'enum_value_definition', name: 'USD', detail: 'USD' },
+			{ kind: 'enum_value_definition', name: 'EUR', detail: 'EUR @deprecated' },
+		]);
+	});
+
+	test('root operation types are those of the schema definition', async () => {
+
+		const source = await fromFixture('catalog.graphql');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Graphql, source);
+
+		expect(outlineOf(structure!)[0]).toEqual({
+			kind: 'schema_definition', name: undefined, detail: 'schema', symbolKind: SymbolKind.Namespace, children: [
+				{ kind: 'root_operation_type_definition', name: 'query', detail: 'query: RootQuery', symbolKind: SymbolKind.Field },
+				{ kind: 'root_operation_type_definition', name: 'mutation', detail: 'mutation: RootMutation', symbolKind: SymbolKind.Field },
+			]
+		});
+		const operationTypes = structure!.children
+			.filter(n => n.kind === 'object_type_definition')
+			.map(n => [n.name, n.operationType]);
+		expect(operationTypes).toEqual([
+			['Product', undefined],
+			['RootQuery', 'query'],
+			['RootMutation', 'mutation'],
+			// named like a root operation type, but the schema definition says otherwise
+			['Query', undefined],
+		]);
+	});
+
+	test('without a schema definition, root operation types are those of the default names', async () => {
+
+		const source = 'type Query {\n  me: User\n}\n\ntype Subscription {\n  posted: Post\n}\n\ntype User {\n  id: ID!\n}\n';
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Graphql, source);
+
+		expect(structure!.children.map(n => [n.name, n.operationType])).toEqual([
+			['Query', 'query'],
+			['Subscription', 'subscription'],
+			['User', undefined],
+		]);
+	});
+
+	test('graphql files are detected by their extensions', () => {
+		expect(detectLanguage('api/schema.graphql', '')).toBe(WASMLanguage.Graphql);
+		expect(detectLanguage('src/queries/user.gql', '')).toBe(WASMLanguage.Graphql);
+	});
+});
@@ -0,0 +1,148 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
+import { detectLanguage, WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture, srcWithAnnotatedStructure } from './getStructure.util';
+
+describe('getStructure - hcl', () => {
+	afterAll(() => _dispose());
+
+	type Outline = { name?: string; detail?: string; symbolKind?: SymbolKind; children?: Outline[] };
+
+	function outlineOf(node: OverlayNode): Outline[] {
+		return node.children.filter(c => c.kind !== 'comment').map(c => {
+			const children = outlineOf(c);
+			return { name: c.name, detail: c.detail, symbolKind: c.symbolKind, ...(children.length > 0 ? { children } : {}) };
+		});
+	}
+
+	test('blocks are labeled with their types and labels, and nested blocks are nested', async () => {
+
+		const source = await fromFixture('main.tf');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Hcl, source);
+
+		expect(outlineOf(structure!)).toEqual([
+			{
+				name: 'terraform', detail: 'terraform', symbolKind: SymbolKind.Class, children: [
+					{
+						name: 'required_providers', detail: 'required_providers', symbolKind: SymbolKind.Field, children: [
+							{ name: 'aws', detail: 'aws = { source = "hashicorp/aws" version = "~> 5.0" }', symbolKind: SymbolKind.Field },
+						]
+					},
+				]
+			},
+			{
+				name: 'aws', detail: 'provider "aws"', symbolKind: SymbolKind.Class, children: [
+					{ name: 'region', detail: 'region = var.region', symbolKind: SymbolKind.Field },
+				]
+			},
+			{
+				name: 'region', detail: 'variable "region"', symbolKind: SymbolKind.Variable, children: [
+					{ name: 'type', detail: 'type = string', symbolKind: SymbolKind.Field },
+					{ name: 'default', detail: 'default = "us-east-1"', symbolKind: SymbolKind.Field },
+				]
+			},
+			{
+				name: 'aws_instance.web', detail: 'resource "aws_instance" "web"', symbolKind: SymbolKind.Class, children: [
+					{ name: 'ami', detail: 'ami = data.aws_ami.ubuntu.id', symbolKind: SymbolKind.Field },
+					{ name: 'instance_type', detail: 'instance_type = "t3.micro"', symbolKind: SymbolKind.Field },
+					{
+						name: 'root_block_device', detail: 'root_block_device', symbolKind: SymbolKind.Field, children: [
+							{ name: 'volume_size', detail: 'volume_size = 20', symbolKind: SymbolKind.Field },
+						]
+					},
+					{
+						name: 'lifecycle', detail: 'lifecycle', symbolKind: SymbolKind.Field, children: [
+							{ name: 'create_before_destroy', detail: 'create_before_destroy = true', symbolKind: SymbolKind.Field },
+						]
+					},
+				]
+			},
+			{
+				name: 'aws_ami.ubuntu', detail: 'data "aws_ami" "ubuntu"', symbolKind: SymbolKind.Class, children: [
+					{ name: 'most_recent', detail: 'most_recent = true', symbolKind: SymbolKind.Field },
+				]
+			},
+			{
+				name: 'vpc', detail: 'module "vpc"', symbolKind: SymbolKind.Namespace, children: [
+					{ name: 'source', detail: 'source = "terraform-aws-modules/vpc/aws"', symbolKind: SymbolKind.Field },
+				]
+			},
+			{
+				name: 'public_ip', detail: 'output "public_ip"', symbolKind: SymbolKind.Variable, children: [
+					{ name: 'value', detail: 'value = aws_instance.web.public_ip', symbolKind: SymbolKind.Field },
+				]
+			},
+		]);
+
+		const web = structure!.children.find(n => n.name === 'aws_instance.web')!;
+		expect(web.leadingComment).toBe('# The web server');
+	});
+
+	test('blocks nested at any depth, e.g., a provisioner of a resource, and the values of locals', async () => {
+
+		const source = await fromFixture('provisioner.tf');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Hcl, source);
+
+		expect(outlineOf(structure!)).toEqual([
+			{
+				name: 'locals', detail: 'locals', symbolKind: SymbolKind.Class, children: [
+					{ name: 'name', detail: 'name = "web"', symbolKind: SymbolKind.Variable },
+					{ name: 'tags', detail: 'tags = { Owner = "platform" }', symbolKind: SymbolKind.Variable },
+				]
+			},
+			{
+				name: 'aws_instance.web', detail: 'resource "aws_instance" "web"', symbolKind: SymbolKind.Class, children: [
+					{ name: 'ami', detail: 'ami = "ami-123456"', symbolKind: SymbolKind.Field },
+					{
+						name: 'remote-exec', detail: 'provisioner "remote-exec"', symbolKind: SymbolKind.Field, children: [
+							{ name: 'inline', detail: 'inline = ["sudo apt-get update"]', symbolKind: SymbolKind.Field },
+							{
+								name: 'connection', detail: 'connection', symbolKind: SymbolKind.Field, children: [
+									{ name: 'type', detail: 'type = "ssh"', symbolKind: SymbolKind.Field },
+								]
+							},
+						]
+					},
+				]
+			},
+		]);
+	});
+
+	test('top-level attributes of a variables file are leaves', async () => {
+
+		const source = await fromFixture('terraform.tfvars');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Hcl, source);
+
+		expect(outlineOf(structure!)).toEqual([
+			{ name: 'region', detail: 'region = "eu-west-1"', symbolKind: SymbolKind.Variable },
+			{ name: 'instance_type', detail: 'instance_type = "t3.small"', symbolKind: SymbolKind.Variable },
+			{ name: 'tags', detail: 'tags = { Owner = "platform" }', symbolKind: SymbolKind.Variable },
+		]);
+		expect(await srcWithAnnotatedStructure(WASMLanguage.Hcl, source)).toMatchInlineSnapshot(`
+			"<ATTRIBUTE>region        = "eu-west-1"
+			</ATTRIBUTE><ATTRIBUTE-1>instance_type = "t3.small"
+			</ATTRIBUTE-1><COMMENT>
+			# tags of all resources
+			</COMMENT><ATTRIBUTE-2>tags = {
+			  Owner = "platform"
+			}</ATTRIBUTE-2>
+			"
+		`);
+	});
+
+	test('terraform files are detected by their extensions', () => {
+		expect(detectLanguage('infra/main.tf', '')).toBe(WASMLanguage.Hcl);
+		expect(detectLanguage('infra/prod.tfvars', '')).toBe(WASMLanguage.Hcl);
+	});
+});
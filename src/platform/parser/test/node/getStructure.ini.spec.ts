@@ -0,0 +1,55 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
+import { detectLanguage, WASMLanguage } from '../../node/treeSitterLanguages';
+import { descendants, fromFixture } from './getStructure.util';
+
+describe('getStructure - ini', () => {
+	afterAll(() => _dispose());
+
+	test('sections span their settings, which are named by their keys', async () => {
+
+		const source = await fromFixture('settings.ini');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Ini, source);
+
+		const sections = structure!.children.filter(n => n.kind !== 'comment');
+		expect(sections.map(n => ({ kind: n.kind, name: n.name, children: n.children.filter(c => c.kind !== 'comment').map(c => c.name) }))).toEqual([
+			{ kind: 'section', name: 'database', children: ['host', 'port'] },
+			{ kind: 'section', name: 'database.replica', children: ['host', 'read_only'] },
+			{ kind: 'section', name: 'logging', children: ['level', 'file'] },
+		]);
+		expect(source.substring(sections[2].startIndex, sections[2].endIndex).trim()).toBe('[logging]\nlevel = debug\nfile = /var/log/app.log');
+		expect(sections[0].symbolKind).toBe(SymbolKind.Key);
+		expect(sections[0].children[0].symbolKind).toBe(SymbolKind.Key);
+	});
+
+	test('settings have key paths through their sections and values as written', async () => {
+
+		const source = await fromFixture('settings.ini');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Ini, source);
+
+		expect(descendants(structure!).filter(n => n.kind !== 'comment').map(n => `${n.keyPath?.join('/')}${n.value !== undefined ? `=${n.value}` : ''}`)).toEqual([
+			'database',
+			'database/host=localhost',
+			'database/port=5432',
+			'database.replica',
+			'database.replica/host=replica.internal',
+			'database.replica/read_only=true',
+			'logging',
+			'logging/level=debug',
+			'logging/file=/var/log/app.log',
+		]);
+	});
+
+	test('ini files are detected by their extension', () => {
+		expect(detectLanguage('setup.ini', '')).toBe(WASMLanguage.Ini);
+	});
+});
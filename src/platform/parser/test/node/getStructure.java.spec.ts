@@ -4,9 +4,12 @@
  *--------------------------------------------------------------------------------------------*/
 
 import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
 import { _dispose } from '../../node/parserImpl';
+import { StructureComputer, structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
 import { WASMLanguage } from '../../node/treeSitterLanguages';
-import { fromFixture, snapshotPathInFixture, srcWithAnnotatedStructure } from './getStructure.util';
+import { descendants, fromFixture, snapshotPathInFixture, srcWithAnnotatedStructure } from './getStructure.util';
 
 describe('getStructure - java', () => {
 	afterAll(() => _dispose());
@@ -24,4 +27,112 @@ describe('getStructure - java', () => {
 		await expect(await javaSrcWithStructure(source)).toMatchFileSnapshot(snapshotPathInFixture(filename));
 	});
 
+	type Outline = { label?: string; symbolKind?: SymbolKind; decorators?: string[]; children?: Outline[] };
+
+	function outlineOf(node: OverlayNode): Outline[] {
+		return node.children.filter(c => c.symbolKind !== undefined).map(c => {
+			const children = outlineOf(c);
+			return {
+				label: c.label ?? c.name,
+				symbolKind: c.symbolKind,
+				...(c.decorators ? { decorators: c.decorators } : {}),
+				...(children.length > 0 ? { children } : {}),
+			};
+		});
+	}
+
+	test('nested types nest, annotations are decorators, and overloads are labeled with their parameter types', async () => {
+
+		const source = await fromFixture('Calculator.java');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Java, source);
+
+		expect(outlineOf(structure!)).toEqual([
+			{ label: 'com.example.math', symbolKind: SymbolKind.Package },
+			{
+				label: 'Calculator', symbolKind: SymbolKind.Class, decorators: ['@Deprecated'], children: [
+					{ label: 'PRECISION', symbolKind: SymbolKind.Constant },
+					{ label: 'history', symbolKind: SymbolKind.Field },
+					{ label: 'Calculator(List<String>)', symbolKind: SymbolKind.Constructor },
+					{ label: 'add(int, int)', symbolKind: SymbolKind.Method },
+					{ label: 'add(double, double)', symbolKind: SymbolKind.Method },
+					{ label: 'toString()', symbolKind: SymbolKind.Method, decorators: ['@Override', '@SuppressWarnings("unchecked")'] },
+					{ label: 'sum(int...)', symbolKind: SymbolKind.Method },
+					{ label: 'Operation', symbolKind: SymbolKind.Interface, children: [{ label: 'apply(double, double)', symbolKind: SymbolKind.Method }] },
+					{
+						label: 'Mode', symbolKind: SymbolKind.Enum, children: [
+							{ label: 'BASIC', symbolKind: SymbolKind.Constant },
+							{ label: 'SCIENTIFIC', symbolKind: SymbolKind.Constant },
+						]
+					},
+					{ label: 'Entry', symbolKind: SymbolKind.Class },
+					{
+						label: 'Memory', symbolKind: SymbolKind.Class, children: [
+							{ label: 'value', symbolKind: SymbolKind.Field },
+							{ label: 'Slot', symbolKind: SymbolKind.Class, children: [{ label: 'clear()', symbolKind: SymbolKind.Method }] },
+						]
+					},
+				]
+			},
+		]);
+	});
+
+	test('overloads get distinct stable IDs that an edit of another method leaves unchanged', async () => {
+
+		const source = await fromFixture('Calculator.java');
+		const edited = source.replace('return "Calculator";', 'return "Calculator(" + history.size() + ")";');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Java, source);
+		const namedIds = (node: OverlayNode) => descendants(node).filter(n => n.name !== undefined).map(n => n.stableId);
+
+		const [add, addDouble] = descendants(structure!).filter(n => n.name === 'add');
+		expect(add.stableId).toMatch(/^method_declaration:.*Calculator\.add$/);
+		expect(addDouble.stableId).toBe(`${add.stableId}#2`);
+		// computed again from scratch rather than served from the cache
+		expect(namedIds((await new StructureComputer(0).getStructure(WASMLanguage.Java, source))!)).toEqual(namedIds(structure!));
+		expect(namedIds((await structureComputer.getStructure(WASMLanguage.Java, edited))!)).toEqual(namedIds(structure!));
+	});
+
+	test('details of declarations omit their annotations and bodies', async () => {
+
+		const source = await fromFixture('Calculator.java');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Java, source);
+
+		const calculator = structure!.children.find(n => n.name === 'Calculator')!;
+		const byLabel = new Map(calculator.children.map(c => [c.label ?? c.name, c]));
+		expect(calculator.detail).toBe('public class Calculator');
+		expect(byLabel.get('toString()')!.detail).toBe('public String toString()');
+		expect(byLabel.get('add(double, double)')!.parameters).toEqual([{ name: 'a', type: 'double' }, { name: 'b', type: 'double' }]);
+		expect(byLabel.get('Operation')!.children[0].detail).toBe('double apply(double a, double b)');
+	});
+
+	test('types and members are package-private without an access modifier unless they are members of interfaces', async () => {
+
+		const source = await fromFixture('Calculator.java');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Java, source);
+
+		expect(descendants(structure!).filter(n => n.visibility !== undefined).map(n => [n.label ?? n.name, n.visibility])).toEqual([
+			['Calculator', 'public'],
+			['PRECISION', 'private'],
+			['history', 'private'],
+			['Calculator(List<String>)', 'public'],
+			['add(int, int)', 'public'],
+			['add(double, double)', 'public'],
+			['toString()', 'public'],
+			['sum(int...)', 'public'],
+			['Operation', 'public'],
+			['apply(double, double)', 'public'],
+			['Mode', 'public'],
+			['BASIC', 'public'],
+			['SCIENTIFIC', 'public'],
+			['Entry', 'package'],
+			['Memory', 'package'],
+			['value', 'private'],
+			['Slot', 'package'],
+			['clear()', 'package'],
+		]);
+	});
+
 });
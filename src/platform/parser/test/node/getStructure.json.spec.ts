@@ -0,0 +1,125 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { defaultMaxStructureDepth, structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+describe('getStructure - json', () => {
+	afterAll(() => _dispose());
+
+	function keys(node: OverlayNode): unknown[] {
+		return node.children.map(n => n.children.length === 0 ? `${n.name}=${n.value}` : { [n.name!]: keys(n) });
+	}
+
+	test('keys and array elements form a hierarchy, with comments tolerated', async () => {
+
+		const source = await fromFixture('tsconfig.jsonc');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Json, source);
+
+		expect(keys(structure!)).toEqual([
+			{
+				compilerOptions: [
+					'target="es2022"',
+					'strict=true',
+					{ paths: [{ '@/*': ['[0]="./src/*"'] }] },
+				],
+			},
+			{ include: ['[0]="src"', '[1]="test"'] },
+			{ references: [{ '[0]': ['path="./tsconfig.node.json"'] }] },
+			// duplicate keys are kept as they are
+			{ include: ['[0]="scripts"'] },
+		]);
+	});
+
+	test('keys are symbols but array elements are not', async () => {
+
+		const source = await fromFixture('tsconfig.jsonc');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Json, source);
+
+		const [compilerOptions, include] = structure!.children;
+		expect(compilerOptions.symbolKind).toBe(SymbolKind.Key);
+		expect(include.children[0].kind).toBe('array_element');
+		expect(include.children[0].symbolKind).toBeUndefined();
+		// a trailing comment on the same line belongs to the key
+		const strict = compilerOptions.children.find(n => n.name === 'strict')!;
+		expect(source.substring(strict.startIndex, strict.endIndex)).toBe('\t\t"strict": true, // catches more bugs\n');
+	});
+
+	test('keys and array elements have the types of their values', async () => {
+
+		const source = await fromFixture('tsconfig.jsonc');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Json, source);
+
+		const [compilerOptions, include, references] = structure!.children;
+		expect(compilerOptions.children.map(n => [n.name, n.valueType])).toEqual([
+			['target', 'string'],
+			['strict', 'boolean'],
+			['paths', 'object'],
+		]);
+		expect(include.valueType).toBe('array');
+		expect(references.children.map(n => [n.name, n.valueType])).toEqual([['[0]', 'object']]);
+	});
+
+	test('trailing commas are tolerated', async () => {
+
+		const source = await fromFixture('tsconfig.base.jsonc');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Json, source);
+
+		const [compilerOptions, exclude] = structure!.children;
+		expect(structure!.children.map(n => [n.name, n.valueType])).toEqual([
+			['compilerOptions', 'object'],
+			['exclude', 'array'],
+		]);
+		// from the line of the key up to the line break after its value and comma
+		expect(compilerOptions.range).toEqual({ start: { line: 2, character: 0 }, end: { line: 9, character: 0 } });
+		expect(source.substring(exclude.startIndex, exclude.endIndex)).toBe('\t"exclude": [\n\t\t"node_modules", // dependencies\n\t\t"dist",\n\t],\n');
+
+		expect(compilerOptions.children.map(n => [n.name, n.valueType])).toEqual([
+			['module', 'string'],
+			['noEmit', 'boolean'],
+			['lib', 'array'],
+			['baseUrl', 'null'],
+			['maxNodeModuleJsDepth', 'number'],
+		]);
+		const lib = compilerOptions.children.find(n => n.name === 'lib')!;
+		expect(lib.children.map(n => `${n.name}=${n.value}`)).toEqual(['[0]="es2023"', '[1]="dom"']);
+		expect(exclude.children.map(n => `${n.name}=${n.value}`)).toEqual(['[0]="node_modules"', '[1]="dist"']);
+	});
+
+	test('deeply nested arrays are truncated at the default depth, keeping the keys above it', async () => {
+
+		// e.g., a generated file; thousands of levels would exhaust the stack of recursive consumers of the structure
+		const depth = 5000;
+		const source = `{ "name": "generated", "values": ${'['.repeat(depth)}1${']'.repeat(depth)} }`;
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Json, source);
+
+		expect(structure!.isTruncated).toBe(true);
+		expect(structure!.children.map(n => n.name)).toEqual(['name', 'values']);
+		let levels = 1;
+		for (let node = structure!; node.children.length > 0; node = node.children[node.children.length - 1]) {
+			levels++;
+		}
+		expect(levels).toBe(defaultMaxStructureDepth);
+	});
+
+	test('sources within the default depth aren\'t truncated', async () => {
+
+		const source = await fromFixture('tsconfig.jsonc');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Json, source);
+
+		expect(structure!.isTruncated).toBeUndefined();
+	});
+});
@@ -0,0 +1,74 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture, srcWithAnnotatedStructure } from './getStructure.util';
+
+describe('getStructure - kotlin', () => {
+	afterAll(() => _dispose());
+
+	function namedChildren(node: OverlayNode) {
+		return node.children
+			.filter(n => n.name !== undefined)
+			.map(n => ({ kind: n.kind, name: n.name }));
+	}
+
+	test('source with different syntax constructs', async () => {
+
+		const source = await fromFixture('test.kt');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Kotlin, source);
+
+		expect(namedChildren(structure!)).toEqual([
+			{ kind: 'interface_declaration', name: 'Greeter' },
+			{ kind: 'class_declaration', name: 'Person' },
+			{ kind: 'object_declaration', name: 'Registry' },
+			{ kind: 'function_declaration', name: 'String.shout' },
+			{ kind: 'function_declaration', name: 'larger' },
+		]);
+	});
+
+	test('annotated source with different syntax constructs', async () => {
+
+		const source = await fromFixture('test.kt');
+
+		expect(await srcWithAnnotatedStructure(WASMLanguage.Kotlin, source)).toMatchSnapshot();
+	});
+
+	test('companion object members are grouped under their enclosing class', async () => {
+
+		const source = await fromFixture('test.kt');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Kotlin, source);
+
+		const person = structure!.children.find(n => n.name === 'Person')!;
+		expect(person.detail).toBe('class Person(val name: String) : Greeter');
+		expect(namedChildren(person)).toEqual([
+			{ kind: 'property_declaration', name: 'age' },
+			{ kind: 'function_declaration', name: 'greet' },
+			{ kind: 'companion_object', name: 'Companion' },
+		]);
+
+		const companion = person.children.find(n => n.kind === 'companion_object')!;
+		expect(namedChildren(companion)).toEqual([
+			{ kind: 'property_declaration', name: 'DEFAULT_NAME' },
+			{ kind: 'function_declaration', name: 'create' },
+		]);
+	});
+
+	test('extension functions keep their receiver type in the detail', async () => {
+
+		const source = await fromFixture('test.kt');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Kotlin, source);
+
+		const shout = structure!.children.find(n => n.name === 'String.shout')!;
+		expect(shout.detail).toBe('fun String.shout(): String');
+	});
+});
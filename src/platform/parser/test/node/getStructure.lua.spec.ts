@@ -0,0 +1,49 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { descendants, fromFixture, srcWithAnnotatedStructure } from './getStructure.util';
+
+describe('getStructure - lua', () => {
+	afterAll(() => _dispose());
+
+	test('module table with functions, methods, and table fields', async () => {
+
+		const source = await fromFixture('module.lua');
+
+		expect(await srcWithAnnotatedStructure(WASMLanguage.Lua, source)).toMatchSnapshot();
+	});
+
+	test('functions are named as declared, including the `:` of methods, and nested functions are nested', async () => {
+
+		const source = await fromFixture('module.lua');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Lua, source);
+
+		expect(descendants(structure!).filter(n => n.kind !== 'comment').map(n => ({ kind: n.kind, name: n.name, symbolKind: n.symbolKind }))).toEqual([
+			{ kind: 'variable_declaration', name: 'M', symbolKind: SymbolKind.Variable },
+			{ kind: 'assignment_statement', name: 'VERSION', symbolKind: SymbolKind.Variable },
+			{ kind: 'function_declaration', name: 'M.greet', symbolKind: SymbolKind.Function },
+			{ kind: 'function_declaration', name: 'trim', symbolKind: SymbolKind.Function },
+			{ kind: 'assignment_statement', name: 'M.shout', symbolKind: SymbolKind.Function },
+			{ kind: 'function_declaration', name: 'upper', symbolKind: SymbolKind.Function },
+			{ kind: 'assignment_statement', name: 'M.handlers', symbolKind: SymbolKind.Variable },
+			{ kind: 'field', name: 'on_save', symbolKind: SymbolKind.Method },
+			{ kind: 'function_declaration', name: 'M.Counter:increment', symbolKind: SymbolKind.Method },
+		]);
+
+		const shout = structure!.children.find(n => n.name === 'M.shout')!;
+		expect(shout.detail).toBe('M.shout = function(text)');
+		expect(shout.children.map(n => n.name)).toEqual(['upper']);
+
+		const greet = structure!.children.find(n => n.name === 'M.greet')!;
+		expect(greet.detail).toBe('function M.greet(name)');
+		expect(greet.leadingComment).toBe('--- Greets someone by name.');
+	});
+});
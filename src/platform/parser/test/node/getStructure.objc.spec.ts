@@ -0,0 +1,82 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+describe('getStructure - objective-c', () => {
+	afterAll(() => _dispose());
+
+	function namedChildren(node: OverlayNode) {
+		return node.children
+			.filter(n => n.name !== undefined)
+			.map(n => ({ kind: n.kind, label: n.label, symbolKind: n.symbolKind }));
+	}
+
+	test('an interface and its implementation', async () => {
+
+		const source = await fromFixture('Widget.m');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.ObjectiveC, source);
+
+		expect(structure!.children.filter(n => n.name !== undefined).map(n => ({ kind: n.kind, name: n.name, symbolKind: n.symbolKind }))).toEqual([
+			{ kind: 'class_interface', name: 'Widget', symbolKind: SymbolKind.Class },
+			{ kind: 'class_implementation', name: 'Widget', symbolKind: SymbolKind.Class },
+		]);
+
+		const [iface] = structure!.children.filter(n => n.kind === 'class_interface');
+		expect(iface.detail).toBe('@interface Widget : NSObject <NSCopying>');
+		expect(iface.superclass).toBe('NSObject');
+		expect(iface.interfaces).toEqual(['NSCopying']);
+		expect(iface.leadingComment).toBe('/**\n * A widget with a name and a count.\n */');
+	});
+
+	test('properties and instance and class methods are nested in the interface', async () => {
+
+		const source = await fromFixture('Widget.m');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.ObjectiveC, source);
+
+		const iface = structure!.children.find(n => n.kind === 'class_interface')!;
+		expect(iface.children.filter(n => n.kind === 'property_declaration').map(n => ({ name: n.name, type: n.type, symbolKind: n.symbolKind }))).toEqual([
+			{ name: 'name', type: 'NSString', symbolKind: SymbolKind.Field },
+			{ name: 'count', type: 'NSInteger', symbolKind: SymbolKind.Field },
+		]);
+		expect(namedChildren(iface).filter(n => n.kind === 'method_declaration')).toEqual([
+			{ kind: 'method_declaration', label: '-initWithName:count:', symbolKind: SymbolKind.Method },
+			{ kind: 'method_declaration', label: '-draw', symbolKind: SymbolKind.Method },
+			{ kind: 'method_declaration', label: '+widgetWithName:', symbolKind: SymbolKind.Method },
+		]);
+	});
+
+	test('method selectors are reconstructed from their keywords', async () => {
+
+		const source = await fromFixture('Widget.m');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.ObjectiveC, source);
+
+		const impl = structure!.children.find(n => n.kind === 'class_implementation')!;
+		expect(namedChildren(impl)).toEqual([
+			{ kind: 'method_definition', label: '-initWithName:count:', symbolKind: SymbolKind.Method },
+			{ kind: 'method_definition', label: '-draw', symbolKind: SymbolKind.Method },
+			{ kind: 'method_definition', label: '+widgetWithName:', symbolKind: SymbolKind.Method },
+			{ kind: 'method_definition', label: '-copyWithZone:', symbolKind: SymbolKind.Method },
+		]);
+
+		const init = impl.children[0];
+		expect(init).toMatchObject({
+			name: 'initWithName:count:',
+			qualifiedName: 'Widget.initWithName:count:',
+			detail: '- (instancetype)initWithName:(NSString *)name count:(NSInteger)count',
+			type: 'instancetype',
+			parameters: [{ name: 'name', type: 'NSString *' }, { name: 'count', type: 'NSInteger' }],
+		});
+	});
+});
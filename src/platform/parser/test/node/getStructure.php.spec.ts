@@ -0,0 +1,79 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+describe('getStructure - php', () => {
+	afterAll(() => _dispose());
+
+	function declarations(node: OverlayNode) {
+		return node.children
+			.filter(n => n.kind !== 'comment')
+			.map(n => ({ kind: n.kind, name: n.name }));
+	}
+
+	test('declarations are nested within the namespace, and HTML around the code is ignored', async () => {
+
+		const source = await fromFixture('test.php');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Php, source);
+
+		expect(declarations(structure!)).toEqual([
+			{ kind: 'namespace_definition', name: 'App\\Models' },
+		]);
+		const namespace = structure!.children[0];
+		expect(namespace.symbolKind).toBe(SymbolKind.Namespace);
+		expect(declarations(namespace)).toEqual([
+			{ kind: 'namespace_use_declaration', name: undefined },
+			{ kind: 'namespace_use_declaration', name: undefined },
+			{ kind: 'trait_declaration', name: 'HasTimestamps' },
+			{ kind: 'interface_declaration', name: 'Priced' },
+			{ kind: 'class_declaration', name: 'Cart' },
+			{ kind: 'function_definition', name: 'cart' },
+		]);
+
+		const cart = namespace.children.find(n => n.name === 'Cart')!;
+		expect(cart.children.map(n => ({ kind: n.kind, name: n.name, symbolKind: n.symbolKind }))).toEqual([
+			{ kind: 'use_declaration', name: undefined, symbolKind: undefined },
+			{ kind: 'const_declaration', name: 'MAX_ITEMS', symbolKind: SymbolKind.Constant },
+			{ kind: 'property_declaration', name: '$items', symbolKind: SymbolKind.Field },
+			{ kind: 'constructor', name: '__construct', symbolKind: SymbolKind.Constructor },
+			{ kind: 'method_declaration', name: 'count', symbolKind: SymbolKind.Method },
+			{ kind: 'method_declaration', name: 'total', symbolKind: SymbolKind.Method },
+		]);
+		expect(declarations(namespace.children.find(n => n.name === 'HasTimestamps')!)).toEqual([
+			{ kind: 'method_declaration', name: 'touch' },
+		]);
+	});
+
+	test('imports, base types, and signatures are described', async () => {
+
+		const source = await fromFixture('test.php');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Php, source);
+
+		const namespace = structure!.children[0];
+		expect(namespace.children.filter(n => n.kind === 'namespace_use_declaration').map(n => ({ path: n.path, alias: n.alias }))).toEqual([
+			{ path: 'Illuminate\\Database\\Eloquent\\Model', alias: undefined },
+			{ path: 'App\\Contracts\\Cart', alias: 'CartContract' },
+		]);
+
+		const cart = namespace.children.find(n => n.name === 'Cart')!;
+		expect({ superclass: cart.superclass, interfaces: cart.interfaces, detail: cart.detail }).toEqual({
+			superclass: 'Model',
+			interfaces: ['CartContract', 'Priced'],
+			detail: 'class Cart extends Model implements CartContract, Priced',
+		});
+		expect(namespace.children.find(n => n.name === 'Priced')?.interfaces).toEqual(['Countable']);
+		expect(cart.children.find(n => n.name === 'total')?.detail).toBe('abstract protected function total(): float');
+		expect(namespace.children.find(n => n.name === 'HasTimestamps')?.leadingComment).toBe('/**\n * Adds created and updated timestamps.\n */');
+	});
+});
@@ -0,0 +1,123 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
+import { detectLanguage, WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+describe('getStructure - proto', () => {
+	afterAll(() => _dispose());
+
+	type Outline = { kind: string; name?: string; symbolKind?: SymbolKind; children?: Outline[] };
+
+	function outlineOf(node: OverlayNode): Outline[] {
+		return node.children.filter(c => c.kind !== 'comment').map(c => {
+			const children = outlineOf(c);
+			return { kind: c.kind, name: c.name, symbolKind: c.symbolKind, ...(children.length > 0 ? { children } : {}) };
+		});
+	}
+
+	test('services, messages, and enums nest their rpcs, fields, and nested messages', async () => {
+
+		const source = await fromFixture('orders.proto');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Protobuf, source);
+
+		expect(outlineOf(structure!)).toEqual([
+			{ kind: 'syntax', name: undefined, symbolKind: undefined },
+			{ kind: 'package', name: 'shop.v1', symbolKind: SymbolKind.Package },
+			{ kind: 'import', name: undefined, symbolKind: undefined },
+			{
+				kind: 'service', name: 'OrderService', symbolKind: SymbolKind.Interface, children: [
+					{ kind: 'rpc', name: 'GetOrder', symbolKind: SymbolKind.Method },
+					{ kind: 'rpc', name: 'WatchOrders', symbolKind: SymbolKind.Method },
+				]
+			},
+			{
+				kind: 'message', name: 'Order', symbolKind: SymbolKind.Struct, children: [
+					{
+						kind: 'message', name: 'Item', symbolKind: SymbolKind.Struct, children: [
+							{ kind: 'field', name: 'sku', symbolKind: SymbolKind.Field },
+							{ kind: 'field', name: 'quantity', symbolKind: SymbolKind.Field },
+						]
+					},
+					{ kind: 'field', name: 'id', symbolKind: SymbolKind.Field },
+					{ kind: 'field', name: 'status', symbolKind: SymbolKind.Field },
+					{ kind: 'field', name: 'items', symbolKind: SymbolKind.Field },
+					{ kind: 'map_field', name: 'labels', symbolKind: SymbolKind.Field },
+					{ kind: 'field', name: 'created_at', symbolKind: SymbolKind.Field },
+					{
+						kind: 'oneof', name: 'payment', symbolKind: SymbolKind.Field, children: [
+							{ kind: 'oneof_field', name: 'card_token', symbolKind: SymbolKind.Field },
+							{ kind: 'oneof_field', name: 'voucher', symbolKind: SymbolKind.Field },
+						]
+					},
+				]
+			},
+			{
+				kind: 'enum', name: 'Status', symbolKind: SymbolKind.Enum, children: [
+					{ kind: 'enum_field', name: 'STATUS_UNSPECIFIED', symbolKind: SymbolKind.Constant },
+					{ kind: 'enum_field', name: 'PENDING', symbolKind: SymbolKind.Constant },
+				]
+			},
+			{
+				kind: 'message', name: 'GetOrderRequest', symbolKind: SymbolKind.Struct, children: [
+					{ kind: 'field', name: 'id', symbolKind: SymbolKind.Field },
+				]
+			},
+			{ kind: 'message', name: 'WatchOrdersRequest', symbolKind: SymbolKind.Struct },
+		]);
+
+		const service = structure!.children.find(n => n.name === 'OrderService')!;
+		expect(service.leadingComment).toBe('// Places and tracks orders.');
+		expect(structure!.children.find(n => n.kind === 'import')!.path).toBe('google/protobuf/timestamp.proto');
+	});
+
+	test('rpcs carry their request and response types, and fields their types and numbers', async () => {
+
+		const source = await fromFixture('orders.proto');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Protobuf, source);
+
+		const [getOrder, watchOrders] = structure!.children.find(n => n.name === 'OrderService')!.children;
+		expect(getOrder).toMatchObject({ detail: 'rpc GetOrder(GetOrderRequest) returns (Order)', parameters: [{ type: 'GetOrderRequest' }], results: [{ type: 'Order' }] });
+		expect(watchOrders).toMatchObject({ parameters: [{ type: 'WatchOrdersRequest' }], results: [{ type: 'stream Order' }] });
+
+		const order = structure!.children.find(n => n.name === 'Order')!;
+		expect(order.children.slice(1, 6).map(n => ({ name: n.name, type: n.type, fieldNumber: n.fieldNumber }))).toEqual([
+			{ name: 'id', type: 'string', fieldNumber: 1 },
+			{ name: 'status', type: 'Status', fieldNumber: 2 },
+			{ name: 'items', type: 'Item', fieldNumber: 3 },
+			{ name: 'labels', type: 'map<string, string>', fieldNumber: 4 },
+			{ name: 'created_at', type: 'google.protobuf.Timestamp', fieldNumber: 5 },
+		]);
+		expect(order.children[3].detail).toBe('repeated Item items = 3');
+
+		const status = structure!.children.find(n => n.name === 'Status')!;
+		expect(status.children.map(n => n.value)).toEqual(['0', '1']);
+	});
+
+	test('proto2 sources are told apart by their syntax, and their fields keep their labels and defaults', async () => {
+
+		const source = await fromFixture('legacy.proto');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Protobuf, source);
+
+		expect(structure!.children.find(n => n.kind === 'syntax')!.value).toBe('proto2');
+		const account = structure!.children.find(n => n.name === 'Account')!;
+		expect(account.children.map(n => ({ name: n.name, type: n.type, fieldNumber: n.fieldNumber, detail: n.detail }))).toEqual([
+			{ name: 'name', type: 'string', fieldNumber: 1, detail: 'required string name = 1' },
+			{ name: 'age', type: 'int32', fieldNumber: 2, detail: 'optional int32 age = 2 [default = 18]' },
+		]);
+	});
+
+	test('proto files are detected by their extension', () => {
+		expect(detectLanguage('api/orders.proto', '')).toBe(WASMLanguage.Protobuf);
+	});
+});
@@ -4,9 +4,12 @@
  *--------------------------------------------------------------------------------------------*/
 
 import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
 import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
 import { WASMLanguage } from '../../node/treeSitterLanguages';
-import { fromFixture, snapshotPathInFixture, srcWithAnnotatedStructure } from './getStructure.util';
+import { descendants, fromFixture, snapshotPathInFixture, srcWithAnnotatedStructure } from './getStructure.util';
 
 describe('getStructure - python', () => {
 
@@ -16,6 +19,11 @@ describe('getStructure - python', () => {
 		return srcWithAnnotatedStructure(WASMLanguage.Python, source);
 	}
 
+	function depthOf(root: OverlayNode, node: OverlayNode): number {
+		const parent = descendants(root).concat(root).find(n => n.children.includes(node));
+		return parent === root ? 1 : 1 + depthOf(root, parent!);
+	}
+
 	test('py source with different syntax constructs', async () => {
 
 		const source = await fromFixture('test.py');
@@ -31,4 +39,38 @@ describe('getStructure - python', () => {
 
 		await expect(await pySrcWithStructure(source)).toMatchFileSnapshot(snapshotPathInFixture(file));
 	});
+
+	test('classes and functions nest under their parents with their decorators', async () => {
+
+		const source = await fromFixture('decorated.py');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Python, source);
+
+		const declarations = descendants(structure!)
+			.filter(n => n.symbolKind !== undefined)
+			.map(n => ({ kind: n.kind, name: n.name, symbolKind: n.symbolKind, decorators: n.decorators, depth: depthOf(structure!, n) }));
+
+		expect(declarations).toEqual([
+			{ kind: 'expression_statement', name: 'MAX_RETRIES', symbolKind: SymbolKind.Variable, decorators: undefined, depth: 1 },
+			{ kind: 'expression_statement', name: 'timeout', symbolKind: SymbolKind.Variable, decorators: undefined, depth: 1 },
+			{ kind: 'function_definition', name: 'retry', symbolKind: SymbolKind.Function, decorators: undefined, depth: 1 },
+			{ kind: 'function_definition', name: 'decorator', symbolKind: SymbolKind.Function, decorators: undefined, depth: 2 },
+			{ kind: 'decorated_definition', name: 'wrapper', symbolKind: SymbolKind.Function, decorators: ['@functools.wraps(fn)'], depth: 3 },
+			{ kind: 'function_definition', name: 'wrapper', symbolKind: SymbolKind.Function, decorators: undefined, depth: 4 },
+			{ kind: 'decorated_definition', name: 'Client', symbolKind: SymbolKind.Class, decorators: ['@dataclass(frozen=True)'], depth: 1 },
+			{ kind: 'class_definition', name: 'Client', symbolKind: SymbolKind.Class, decorators: undefined, depth: 2 },
+			{ kind: 'function_definition', name: 'url', symbolKind: SymbolKind.Method, decorators: undefined, depth: 3 },
+			{ kind: 'function_definition', name: 'join', symbolKind: SymbolKind.Function, decorators: undefined, depth: 4 },
+			{ kind: 'decorated_definition', name: 'default', symbolKind: SymbolKind.Method, decorators: ['@classmethod'], depth: 3 },
+			{ kind: 'function_definition', name: 'default', symbolKind: SymbolKind.Method, decorators: undefined, depth: 4 },
+			{ kind: 'decorated_definition', name: 'version', symbolKind: SymbolKind.Method, decorators: ['@staticmethod'], depth: 3 },
+			{ kind: 'function_definition', name: 'version', symbolKind: SymbolKind.Method, decorators: undefined, depth: 4 },
+			{ kind: 'decorated_definition', name: 'fetch', symbolKind: SymbolKind.Function, decorators: ['@retry(MAX_RETRIES)'], depth: 1 },
+			{ kind: 'function_definition', name: 'fetch', symbolKind: SymbolKind.Function, decorators: undefined, depth: 2 },
+		]);
+
+		const fetch = structure!.children.find(n => n.name === 'fetch')!;
+		expect(fetch.detail).toBe('async def fetch(client: Client, path: str) -> bytes');
+		expect(structure!.children.find(n => n.name === 'timeout')?.type).toBe('float');
+	});
 });
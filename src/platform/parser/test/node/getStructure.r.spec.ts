@@ -0,0 +1,76 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture, srcWithAnnotatedStructure } from './getStructure.util';
+
+describe('getStructure - r', () => {
+	afterAll(() => _dispose());
+
+	function namedChildren(node: OverlayNode) {
+		return node.children
+			.filter(n => n.name !== undefined)
+			.map(n => ({ kind: n.kind, name: n.name, symbolKind: n.symbolKind }));
+	}
+
+	test('assigned functions and S4 declarations are named', async () => {
+
+		const source = await fromFixture('analysis.R');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.R, source);
+
+		expect(namedChildren(structure!)).toEqual([
+			{ kind: 'assignment', name: 'threshold', symbolKind: SymbolKind.Variable },
+			{ kind: 'function_definition', name: 'mean_above', symbolKind: SymbolKind.Function },
+			{ kind: 'function_definition', name: 'scale_by', symbolKind: SymbolKind.Function },
+			{ kind: 'assignment', name: 'max_iterations', symbolKind: SymbolKind.Variable },
+			{ kind: 'class_definition', name: 'Circle', symbolKind: SymbolKind.Class },
+			{ kind: 'generic_definition', name: 'area', symbolKind: SymbolKind.Function },
+			{ kind: 'method_definition', name: 'area', symbolKind: SymbolKind.Method },
+		]);
+	});
+
+	test('annotated source with assigned functions and S4 declarations', async () => {
+
+		const source = await fromFixture('analysis.R');
+
+		expect(await srcWithAnnotatedStructure(WASMLanguage.R, source)).toMatchSnapshot();
+	});
+
+	test('functions are described by their assignments', async () => {
+
+		const source = await fromFixture('analysis.R');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.R, source);
+
+		const meanAbove = structure!.children.find(n => n.name === 'mean_above')!;
+		expect(meanAbove.detail).toBe('mean_above <- function(x, cutoff = threshold)');
+		expect(meanAbove.leadingComment).toBe(`#' Mean of the values above a cutoff.\n#' @param x a numeric vector`);
+		expect(namedChildren(meanAbove)).toEqual([
+			{ kind: 'function_definition', name: 'helper', symbolKind: SymbolKind.Function },
+		]);
+
+		expect(structure!.children.find(n => n.name === 'scale_by')!.detail).toBe('scale_by = function(x, factor)');
+		expect(structure!.children.find(n => n.name === 'threshold')!.value).toBe('0.5');
+		expect(structure!.children.find(n => n.name === 'max_iterations')!.value).toBe('10');
+	});
+
+	test('S4 methods are labeled with their signatures', async () => {
+
+		const source = await fromFixture('analysis.R');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.R, source);
+
+		const method = structure!.children.find(n => n.kind === 'method_definition')!;
+		expect(method.label).toBe('area(Circle)');
+		expect(method.detail).toBe('setMethod("area", "Circle", function(shape)');
+		expect(structure!.children.find(n => n.kind === 'generic_definition')!.detail).toBe('setGeneric("area", function(shape)');
+	});
+});
@@ -5,8 +5,10 @@
 
 import { afterAll, describe, expect, test } from 'vitest';
 import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
 import { WASMLanguage } from '../../node/treeSitterLanguages';
-import { fromFixture, srcWithAnnotatedStructure } from './getStructure.util';
+import { descendants, fromFixture, snapshotPathInFixture, srcWithAnnotatedStructure } from './getStructure.util';
 
 describe('getStructure - ruby', () => {
 	afterAll(() => _dispose());
@@ -21,4 +23,71 @@ describe('getStructure - ruby', () => {
 
 		expect(await rubyStruct(source)).toMatchSnapshot();
 	});
+
+	test('nested modules and a class with a superclass, attributes, and a singleton method', async () => {
+
+		const file = 'invoice.rb';
+
+		const source = await fromFixture(file);
+
+		await expect(await rubyStruct(source)).toMatchFileSnapshot(snapshotPathInFixture(file));
+	});
+
+	test('declarations are named and singleton methods are told apart from instance methods', async () => {
+
+		const source = await fromFixture('invoice.rb');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Ruby, source);
+
+		const declarations = descendants(structure!)
+			.filter(n => n.symbolKind !== undefined)
+			.map(n => ({ kind: n.kind, name: n.name, symbolKind: n.symbolKind, detail: n.detail }));
+
+		expect(declarations).toEqual([
+			{ kind: 'module', name: 'Shop', symbolKind: SymbolKind.Namespace, detail: 'module Shop' },
+			{ kind: 'module', name: 'Billing', symbolKind: SymbolKind.Namespace, detail: 'module Billing' },
+			{ kind: 'class', name: 'Invoice', symbolKind: SymbolKind.Class, detail: 'class Invoice < ApplicationRecord' },
+			{ kind: 'attribute_declaration', name: 'total', symbolKind: SymbolKind.Field, detail: 'attr_accessor :total' },
+			{ kind: 'attribute_declaration', name: undefined, symbolKind: SymbolKind.Field, detail: 'attr_reader :number, :issued_on' },
+			{ kind: 'singleton_method', name: 'next_number', symbolKind: SymbolKind.Method, detail: 'def self.next_number' },
+			{ kind: 'method', name: 'initialize', symbolKind: SymbolKind.Method, detail: 'def initialize(total)' },
+			{ kind: 'method', name: 'paid?', symbolKind: SymbolKind.Method, detail: 'def paid?' },
+		]);
+
+		expect(descendants(structure!).find(n => n.kind === 'class')?.superclass).toBe('ApplicationRecord');
+	});
+
+	test('methods are tagged with the visibility of the last preceding private or protected line', async () => {
+
+		const source = await fromFixture('ledger.rb');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Ruby, source);
+
+		const methods = descendants(structure!).filter(n => n.kind === 'method').map(n => [n.name, n.visibility]);
+		expect(methods).toEqual([
+			['open', undefined],
+			['registry', 'private'],
+			['initialize', undefined],
+			['record', undefined],
+			['balance', 'protected'],
+			['normalize', 'private'],
+			['to_s', undefined],
+			['summary', 'private'],
+			['audit', 'private'],
+		]);
+	});
+
+	test('singleton classes are nested in their classes and hold their singleton methods', async () => {
+
+		const source = await fromFixture('ledger.rb');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Ruby, source);
+
+		const ledger = descendants(structure!).find(n => n.name === 'Ledger')!;
+		const singletonClass = ledger.children.find(n => n.kind === 'singleton_class')!;
+		expect(singletonClass.label).toBe('class << self');
+		expect(singletonClass.children.filter(n => n.kind === 'method').map(n => n.name)).toEqual(['open', 'registry']);
+		expect(descendants(structure!).find(n => n.name === 'Accounts')!.children.map(n => n.name)).toEqual(['Ledger']);
+	});
 });
+
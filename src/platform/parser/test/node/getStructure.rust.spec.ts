@@ -5,8 +5,10 @@
 
 import { afterAll, describe, expect, test } from 'vitest';
 import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
 import { WASMLanguage } from '../../node/treeSitterLanguages';
-import { fromFixture, snapshotPathInFixture, srcWithAnnotatedStructure } from './getStructure.util';
+import { descendants, fromFixture, snapshotPathInFixture, srcWithAnnotatedStructure } from './getStructure.util';
 
 describe('getStructure - rust', () => {
 	afterAll(() => _dispose());
@@ -24,4 +26,51 @@ describe('getStructure - rust', () => {
 		await expect(await rustStruct(source)).toMatchFileSnapshot(snapshotPathInFixture(file));
 	});
 
+	test('functions of impl blocks are methods or associated functions of the target type', async () => {
+		const source = await fromFixture('impls.rs');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Rust, source);
+
+		const impls = structure!.children
+			.filter(n => n.kind === 'impl_item')
+			.map(n => ({ name: n.name, trait: n.trait, functions: n.children.map(c => [c.kind, c.name, c.trait]) }));
+		expect(impls).toEqual([
+			{ name: 'Stack', trait: undefined, functions: [['associated_function_item', 'new', undefined], ['method_item', 'push', undefined]] },
+			{ name: 'Stack', trait: 'fmt::Display', functions: [['method_item', 'fmt', 'fmt::Display']] },
+			{ name: 'String', trait: 'Shape', functions: [['associated_function_item', 'sides', 'Shape'], ['method_item', 'area', 'Shape']] },
+		]);
+
+		// functions of impl blocks for local types are grouped under the types; `String` is declared elsewhere
+		const stack = structure!.children.find(n => n.kind === 'struct_item');
+		expect(stack?.methods?.map(m => m.name)).toEqual(['new', 'push', 'fmt']);
+		expect(stack?.methods?.[0]).toBe(structure!.children[2].children[0]);
+
+		const trait = structure!.children.find(n => n.kind === 'trait_item');
+		expect(trait?.children.map(c => [c.kind, c.name])).toEqual([['associated_function_item', 'sides'], ['method_item', 'area']]);
+	});
+
+	test('declarations get language-agnostic symbol kinds', async () => {
+		const source = await fromFixture('impls.rs');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Rust, source);
+
+		const symbols = descendants(structure!)
+			.filter(n => n.symbolKind !== undefined)
+			.map(n => [n.name, n.symbolKind]);
+		expect(symbols).toEqual([
+			['Stack', SymbolKind.Struct],
+			['items', SymbolKind.Field],
+			['new', SymbolKind.Method],
+			['push', SymbolKind.Method],
+			['fmt', SymbolKind.Method],
+			['Shape', SymbolKind.Interface],
+			['sides', SymbolKind.Method],
+			['area', SymbolKind.Method],
+			['sides', SymbolKind.Method],
+			['area', SymbolKind.Method],
+			['MAX_DEPTH', SymbolKind.Constant],
+			['GREETING', SymbolKind.Variable],
+		]);
+	});
+
 });
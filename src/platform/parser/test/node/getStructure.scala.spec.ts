@@ -0,0 +1,84 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture, srcWithAnnotatedStructure } from './getStructure.util';
+
+describe('getStructure - scala', () => {
+	afterAll(() => _dispose());
+
+	function namedChildren(node: OverlayNode) {
+		return node.children
+			.filter(n => n.name !== undefined)
+			.map(n => ({ kind: n.kind, name: n.name, symbolKind: n.symbolKind }));
+	}
+
+	test('source with different syntax constructs', async () => {
+
+		const source = await fromFixture('shapes.scala');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Scala, source);
+
+		expect(namedChildren(structure!)).toEqual([
+			{ kind: 'package_clause', name: 'shapes', symbolKind: SymbolKind.Package },
+			{ kind: 'trait_definition', name: 'Shape', symbolKind: SymbolKind.Interface },
+			{ kind: 'case_class_definition', name: 'Circle', symbolKind: SymbolKind.Class },
+			{ kind: 'object_definition', name: 'Circle', symbolKind: SymbolKind.Class },
+			{ kind: 'case_object_definition', name: 'Origin', symbolKind: SymbolKind.Class },
+			{ kind: 'class_definition', name: 'Canvas', symbolKind: SymbolKind.Class },
+		]);
+	});
+
+	test('annotated source with different syntax constructs', async () => {
+
+		const source = await fromFixture('shapes.scala');
+
+		expect(await srcWithAnnotatedStructure(WASMLanguage.Scala, source)).toMatchSnapshot();
+	});
+
+	test('members are nested in their classes, objects, and traits', async () => {
+
+		const source = await fromFixture('shapes.scala');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Scala, source);
+
+		const shape = structure!.children.find(n => n.name === 'Shape')!;
+		expect(shape.leadingComment).toBe('/**\n * A shape with an area.\n */');
+		expect(namedChildren(shape)).toEqual([
+			{ kind: 'function_declaration', name: 'area', symbolKind: SymbolKind.Method },
+		]);
+
+		const object = structure!.children.find(n => n.kind === 'object_definition')!;
+		expect(namedChildren(object)).toEqual([
+			{ kind: 'val_definition', name: 'unit', symbolKind: SymbolKind.Field },
+			{ kind: 'function_definition', name: 'fromDiameter', symbolKind: SymbolKind.Method },
+		]);
+		expect(object.children[1].detail).toBe('def fromDiameter(diameter: Double): Circle');
+		expect(object.children[1].type).toBe('Circle');
+
+		const canvas = structure!.children.find(n => n.name === 'Canvas')!;
+		expect(canvas.children[0]).toMatchObject({ kind: 'var_definition', name: 'shapes', type: 'List[Shape]' });
+	});
+
+	test('case classes are linked to their companion objects', async () => {
+
+		const source = await fromFixture('shapes.scala');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Scala, source);
+
+		const circle = structure!.children.find(n => n.kind === 'case_class_definition')!;
+		expect(circle.detail).toBe('case class Circle(radius: Double) extends Shape');
+		expect(circle.companion).toBe(structure!.children.find(n => n.kind === 'object_definition'));
+
+		// neither a trait nor a class without an object of the same name has a companion
+		expect(structure!.children.find(n => n.name === 'Shape')!.companion).toBeUndefined();
+		expect(structure!.children.find(n => n.name === 'Canvas')!.companion).toBeUndefined();
+	});
+});
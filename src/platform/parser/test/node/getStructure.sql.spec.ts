@@ -0,0 +1,96 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
+import { detectLanguage, WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture, srcWithAnnotatedStructure } from './getStructure.util';
+
+describe('getStructure - sql', () => {
+	afterAll(() => _dispose());
+
+	type Outline = { kind: string; name?: string; detail?: string; symbolKind?: SymbolKind; children?: Outline[] };
+
+	function outlineOf(node: OverlayNode): Outline[] {
+		return node.children.filter(c => c.kind !== 'comment').map(c => {
+			const children = outlineOf(c);
+			return { kind: c.kind, name: c.name, detail: c.detail, symbolKind: c.symbolKind, ...(children.length > 0 ? { children } : {}) };
+		});
+	}
+
+	test('statements are top-level nodes and tables have their columns as children', async () => {
+
+		const source = await fromFixture('schema.sql');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Sql, source);
+
+		expect(outlineOf(structure!)).toEqual([
+			{
+				kind: 'create_table', name: 'users', detail: 'CREATE TABLE IF NOT EXISTS users', symbolKind: SymbolKind.Struct, children: [
+					{ kind: 'column_definition', name: 'id', detail: 'id INTEGER PRIMARY KEY', symbolKind: SymbolKind.Field },
+					{ kind: 'column_definition', name: 'email', detail: 'email VARCHAR(255) NOT NULL UNIQUE', symbolKind: SymbolKind.Field },
+					{ kind: 'column_definition', name: 'created_at', detail: 'created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP', symbolKind: SymbolKind.Field },
+				]
+			},
+			{
+				kind: 'create_table', name: 'orders', detail: 'CREATE TABLE orders', symbolKind: SymbolKind.Struct, children: [
+					{ kind: 'column_definition', name: 'id', detail: 'id INTEGER PRIMARY KEY', symbolKind: SymbolKind.Field },
+					{ kind: 'column_definition', name: 'user_id', detail: 'user_id INTEGER NOT NULL REFERENCES users (id)', symbolKind: SymbolKind.Field },
+					{ kind: 'column_definition', name: 'total', detail: 'total DECIMAL(10, 2) NOT NULL', symbolKind: SymbolKind.Field },
+				]
+			},
+			{ kind: 'insert', name: 'users', detail: 'INSERT INTO users', symbolKind: undefined },
+			{ kind: 'select', name: undefined, detail: 'SELECT u.email, count(o.id) AS order_count', symbolKind: undefined },
+			{ kind: 'update', name: 'users', detail: 'UPDATE users', symbolKind: undefined },
+			{ kind: 'delete', name: 'orders', detail: 'DELETE FROM orders', symbolKind: undefined },
+		]);
+
+		const users = structure!.children.find(n => n.name === 'users')!;
+		expect(users.leadingComment).toBe('-- Accounts that can place orders');
+	});
+
+	test('statements on the same line are separate nodes that include their semicolons', async () => {
+
+		const source = 'SELECT 1; SELECT 2;\n';
+
+		expect(await srcWithAnnotatedStructure(WASMLanguage.Sql, source)).toMatchInlineSnapshot(`
+			"<SELECT>SELECT 1;</SELECT><SELECT-1> SELECT 2;</SELECT-1>
+			"
+		`);
+	});
+
+	test('statements are labeled by their kinds and columns have their types', async () => {
+
+		const source = await fromFixture('schema.sql');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Sql, source);
+
+		const statements = structure!.children.filter(c => c.kind !== 'comment');
+		expect(statements.map(n => n.label)).toEqual(['CREATE TABLE', 'CREATE TABLE', 'INSERT', 'SELECT', 'UPDATE', 'DELETE']);
+		expect(statements[0].children.map(c => [c.name, c.type])).toEqual([['id', 'INTEGER'], ['email', 'VARCHAR(255)'], ['created_at', 'TIMESTAMP']]);
+	});
+
+	test('views and indexes are named, and statements the grammar doesn\'t know don\'t hide the others', async () => {
+
+		const source = await fromFixture('views.sql');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Sql, source);
+
+		const statements = structure!.children.filter(c => c.kind !== 'comment');
+		expect(statements.find(n => n.kind === 'create_view')).toMatchObject({ name: 'active_users', label: 'CREATE VIEW', symbolKind: SymbolKind.Interface });
+		expect(statements.filter(n => n.kind === 'create_index').map(n => ({ name: n.name, detail: n.detail, label: n.label }))).toEqual([
+			{ name: 'idx_users_email', detail: 'CREATE UNIQUE INDEX idx_users_email', label: 'CREATE INDEX' },
+			{ name: 'idx_orders_user', detail: 'CREATE INDEX idx_orders_user', label: 'CREATE INDEX' },
+		]);
+		expect(statements.at(-1)).toMatchObject({ kind: 'select', label: 'SELECT', detail: 'SELECT email' });
+	});
+
+	test('sql files are detected by their extension', () => {
+		expect(detectLanguage('db/schema.sql', '')).toBe(WASMLanguage.Sql);
+	});
+});
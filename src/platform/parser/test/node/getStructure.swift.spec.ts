@@ -0,0 +1,78 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture, srcWithAnnotatedStructure } from './getStructure.util';
+
+describe('getStructure - swift', () => {
+	afterAll(() => _dispose());
+
+	function namedChildren(node: OverlayNode) {
+		return node.children
+			.filter(n => n.name !== undefined)
+			.map(n => ({ kind: n.kind, name: n.name }));
+	}
+
+	test('source with different syntax constructs', async () => {
+
+		const source = await fromFixture('test.swift');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Swift, source);
+
+		expect(namedChildren(structure!)).toEqual([
+			{ kind: 'protocol_declaration', name: 'Greeter' },
+			{ kind: 'struct_declaration', name: 'Person' },
+			{ kind: 'enum_declaration', name: 'Direction' },
+			{ kind: 'class_declaration', name: 'Counter' },
+			{ kind: 'extension_declaration', name: 'Counter' },
+		]);
+	});
+
+	test('extension members are nested under the extension of their type', async () => {
+
+		const source = await fromFixture('test.swift');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Swift, source);
+
+		const extension = structure!.children.find(n => n.kind === 'extension_declaration')!;
+
+		expect(extension.detail).toBe('extension Counter');
+		expect(namedChildren(extension)).toEqual([
+			{ kind: 'function_declaration', name: 'reset' },
+		]);
+	});
+
+	test('protocol, conforming struct, and extension', async () => {
+
+		const source = await fromFixture('shapes.swift');
+
+		expect(await srcWithAnnotatedStructure(WASMLanguage.Swift, source)).toMatchSnapshot();
+	});
+
+	test('computed properties are distinguished from stored properties', async () => {
+
+		const source = await fromFixture('shapes.swift');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Swift, source);
+
+		const [, rectangle, extension] = structure!.children;
+		expect(rectangle.children.map(n => ({ kind: n.kind, name: n.name, detail: n.detail }))).toEqual([
+			{ kind: 'property_declaration', name: 'width', detail: undefined },
+			{ kind: 'property_declaration', name: 'height', detail: undefined },
+			{ kind: 'computed_property_declaration', name: 'area', detail: 'var area: Double' },
+			{ kind: 'computed_property_declaration', name: 'size', detail: 'var size: Double' },
+			{ kind: 'function_declaration', name: 'describe', detail: 'func describe() -> String' },
+		]);
+		expect(extension.name).toBe('Rectangle');
+		expect(namedChildren(extension)).toEqual([
+			{ kind: 'computed_property_declaration', name: 'isSquare' },
+			{ kind: 'function_declaration', name: 'scaled' },
+		]);
+	});
+});
@@ -0,0 +1,90 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
+import { detectLanguage, WASMLanguage } from '../../node/treeSitterLanguages';
+import { descendants, fromFixture } from './getStructure.util';
+
+describe('getStructure - toml', () => {
+	afterAll(() => _dispose());
+
+	test('tables and keys have key paths from the root, with indices of arrays of tables', async () => {
+
+		const source = await fromFixture('manifest.toml');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Toml, source);
+
+		expect(descendants(structure!).map(n => `${n.keyPath?.join('/')}${n.value !== undefined ? `=${n.value}` : ''}`)).toEqual([
+			'title="example"',
+			'package',
+			'package/name="app"',
+			'package/version="0.1.0"',
+			'package/authors',
+			'package/authors/[0]="Ada"',
+			'package/authors/[1]="Grace"',
+			'package/metadata',
+			'package/metadata/docs/rs',
+			'package/metadata/docs/rs/all-features=true',
+			'dependencies',
+			'dependencies/serde',
+			'dependencies/serde/version="1.0"',
+			'dependencies/serde/features',
+			'dependencies/serde/features/[0]="derive"',
+			'bin/[0]',
+			'bin/[0]/name="app"',
+			'bin/[1]',
+			'bin/[1]/name="cli"',
+			'bin/[1]/targets/[0]',
+			'bin/[1]/targets/[0]/os="linux"',
+		]);
+	});
+
+	test('tables are siblings that span their keys, and dotted keys are named as written', async () => {
+
+		const source = await fromFixture('manifest.toml');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Toml, source);
+
+		expect(structure!.children.map(n => ({ kind: n.kind, name: n.name }))).toEqual([
+			{ kind: 'pair', name: 'title' },
+			{ kind: 'table', name: 'package' },
+			{ kind: 'table', name: 'package.metadata' },
+			{ kind: 'table', name: 'dependencies' },
+			{ kind: 'table_array_element', name: 'bin' },
+			{ kind: 'table_array_element', name: 'bin' },
+			{ kind: 'table_array_element', name: 'bin.targets' },
+		]);
+		const [, pkg, metadata] = structure!.children;
+		expect(source.substring(pkg.startIndex, pkg.endIndex).trim()).toBe('[package]\nname = "app"\nversion = "0.1.0"\nauthors = ["Ada", "Grace"]');
+		expect(source.substring(metadata.startIndex, metadata.endIndex).trim()).toBe('[package.metadata]\ndocs.rs = { all-features = true }');
+		expect(pkg.symbolKind).toBe(SymbolKind.Key);
+
+		const version = pkg.children[1];
+		expect(source.substring(version.startIndex, version.endIndex)).toBe('version = "0.1.0"\n');
+
+		// the keys of an inline table include the separators that follow them
+		const serdeVersion = structure!.children[3].children[0].children[0];
+		expect(source.substring(serdeVersion.startIndex, serdeVersion.endIndex)).toBe(' version = "1.0",');
+	});
+
+	test('quoted keys are unquoted in key paths', async () => {
+
+		const source = `[site."google.com"]\n'literal\\key' = 1\n`;
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Toml, source);
+
+		expect(descendants(structure!).map(n => [n.name, n.keyPath])).toEqual([
+			['site."google.com"', ['site', 'google.com']],
+			['literal\\key', ['site', 'google.com', 'literal\\key']],
+		]);
+	});
+
+	test('toml files are detected by their extension', () => {
+		expect(detectLanguage('pyproject.toml', '')).toBe(WASMLanguage.Toml);
+	});
+});
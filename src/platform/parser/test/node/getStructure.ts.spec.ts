@@ -7,8 +7,10 @@ import { outdent } from 'outdent';
 import { afterAll, describe, expect, it } from 'vitest';
 import { resolve } from '../../../../util/vs/base/common/path';
 import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
 import { WASMLanguage } from '../../node/treeSitterLanguages';
-import { fromFixture, srcWithAnnotatedStructure } from './getStructure.util';
+import { descendants, fromFixture, srcWithAnnotatedStructure } from './getStructure.util';
 
 describe('getStructure - typescript', () => {
 
@@ -18,6 +20,83 @@ describe('getStructure - typescript', () => {
 		return srcWithAnnotatedStructure(WASMLanguage.TypeScript, source);
 	}
 
+	it('leading doc comments are attached to declarations and members', async () => {
+		const source = outdent`
+				/**
+				 * Adds numbers.
+				 */
+				export function add(a: number, b: number) {
+					return a + b;
+				}
+
+				// Not about Calculator.
+
+				class Calculator {
+					// The current value.
+					value = 0;
+
+					/** Resets the value. */
+					reset() {
+						this.value = 0;
+					}
+				}
+				`;
+		const structure = await structureComputer.getStructure(WASMLanguage.TypeScript, source);
+
+		const declarations = descendants(structure!)
+			.filter(n => ['function_declaration', 'class_declaration', 'public_field_definition', 'method_definition'].includes(n.kind))
+			.map(n => ({ kind: n.kind, leadingComment: n.leadingComment }));
+
+		expect(declarations).toEqual([
+			{ kind: 'function_declaration', leadingComment: '/**\n * Adds numbers.\n */' },
+			{ kind: 'class_declaration', leadingComment: undefined },
+			{ kind: 'public_field_definition', leadingComment: '// The current value.' },
+			{ kind: 'method_definition', leadingComment: '/** Resets the value. */' },
+		]);
+	});
+
+	it('declarations get language-agnostic symbol kinds', async () => {
+		const source = outdent`
+				export const MAX = 10;
+				let counter = 0;
+
+				interface Shape {
+					area(): number;
+				}
+
+				enum Color { Red, Green }
+
+				class Circle {
+					radius = 1;
+
+					constructor(radius: number) {
+						this.radius = radius;
+					}
+
+					area() {
+						return Math.PI * this.radius ** 2;
+					}
+				}
+				`;
+		const structure = await structureComputer.getStructure(WASMLanguage.TypeScript, source);
+
+		const symbols = descendants(structure!)
+			.filter(n => n.symbolKind !== undefined)
+			.map(n => ({ kind: n.kind, symbolKind: n.symbolKind }));
+
+		expect(symbols).toEqual([
+			{ kind: 'lexical_declaration', symbolKind: SymbolKind.Constant },
+			{ kind: 'lexical_declaration', symbolKind: SymbolKind.Variable },
+			{ kind: 'interface_declaration', symbolKind: SymbolKind.Interface },
+			{ kind: 'method_signature', symbolKind: SymbolKind.Method },
+			{ kind: 'enum_declaration', symbolKind: SymbolKind.Enum },
+			{ kind: 'class_declaration', symbolKind: SymbolKind.Class },
+			{ kind: 'public_field_definition', symbolKind: SymbolKind.Field },
+			{ kind: 'constructor', symbolKind: SymbolKind.Constructor },
+			{ kind: 'method_definition', symbolKind: SymbolKind.Method },
+		]);
+	});
+
 	it('`export` keyword should not be visible', async () => {
 		const source = outdent`
 				export function add(a, b) {
@@ -147,6 +226,110 @@ describe('getStructure - typescript', () => {
 		`);
 	});
 
+	it('decorators are attached to decorated declarations', async () => {
+		const source = await fromFixture('decorated.ts');
+		const structure = await structureComputer.getStructure(WASMLanguage.TypeScript, source);
+
+		const decorated = descendants(structure!)
+			.filter(n => n.decorators !== undefined)
+			.map(n => ({ kind: n.kind, decorators: n.decorators, text: source.substring(n.startIndex, n.endIndex).trim().split('\n')[0] }));
+
+		expect(decorated).toEqual([
+			{ kind: 'class_declaration', decorators: [`@Component({ selector: 'app-root' })`], text: `@Component({ selector: 'app-root' })` },
+			{ kind: 'public_field_definition', decorators: ['@Input()'], text: `@Input() title = '';` },
+			{ kind: 'method_definition', decorators: [`@HostListener('click', ['$event'])`], text: `@HostListener('click', ['$event'])` },
+		]);
+	});
+
+	it('constructor parameter properties are fields', async () => {
+		const source = await fromFixture('decorated.ts');
+		const structure = await structureComputer.getStructure(WASMLanguage.TypeScript, source);
+
+		const parameterProperties = descendants(structure!)
+			.filter(n => n.kind === 'parameter_property')
+			.map(n => ({ name: n.name, detail: n.detail, symbolKind: n.symbolKind }));
+
+		expect(parameterProperties).toEqual([
+			{ name: 'http', detail: 'private readonly http: HttpClient', symbolKind: SymbolKind.Field },
+			{ name: 'store', detail: 'public store: Store', symbolKind: SymbolKind.Field },
+		]);
+	});
+
+	it('top-level declarations are public if exported and class members by their accessibility modifiers', async () => {
+		const source = await fromFixture('signatures.ts');
+		const structure = await structureComputer.getStructure(WASMLanguage.TypeScript, source);
+
+		const visibilities = descendants(structure!)
+			.filter(n => n.visibility !== undefined)
+			.map(n => [n.kind, n.visibility]);
+
+		expect(visibilities).toEqual([
+			['function_declaration', 'public'],
+			['function_declaration', 'private'],
+			['class_declaration', 'public'],
+			['constructor', 'public'],
+			['parameter_property', 'private'],
+			['method_definition', 'public'],
+			['method_definition', 'protected'],
+			['method_definition', 'private'],
+			['method_definition', 'private'], // `#validate`
+			['method_definition', 'public'],
+		]);
+	});
+
+	it('javascript class members are private if their names are private', async () => {
+		const source = [
+			'class Counter {',
+			'	#count = 0;',
+			'	step = 1;',
+			'	#increment() { this.#count += this.step; }',
+			'	static #create() { return new Counter(); }',
+			'	get value() { return this.#count; }',
+			'}',
+		].join('\n');
+		const structure = await structureComputer.getStructure(WASMLanguage.JavaScript, source);
+
+		const visibilities = descendants(structure!)
+			.filter(n => n.visibility !== undefined)
+			.map(n => [n.kind, n.visibility]);
+
+		expect(visibilities).toEqual([
+			['class_declaration', 'private'],
+			['field_definition', 'private'],
+			['field_definition', 'public'],
+			['method_definition', 'private'],
+			['method_definition', 'private'],
+			['method_definition', 'public'],
+		]);
+	});
+
+	it('with includeAnonymousFunctions, arrow functions and function expressions are nested in their enclosing declarations', async () => {
+		const source = await fromFixture('callbacks.ts');
+		const structure = await structureComputer.getStructure(WASMLanguage.TypeScript, source, { includeAnonymousFunctions: true });
+
+		const functions = (node: typeof structure) => descendants(node!)
+			.filter(n => n.kind === 'arrow_function' || n.kind === 'function_expression')
+			.map(n => ({ kind: n.kind, name: n.name, label: n.label, detail: n.detail, symbolKind: n.symbolKind }));
+
+		expect(functions(structure)).toEqual([
+			{ kind: 'arrow_function', name: 'parseConfig', label: undefined, detail: '(text: string) =>', symbolKind: SymbolKind.Function },
+			{ kind: 'arrow_function', name: undefined, label: '<anonymous>', detail: 'async path =>', symbolKind: SymbolKind.Function },
+			{ kind: 'function_expression', name: 'retry', label: undefined, detail: 'function retry()', symbolKind: SymbolKind.Function },
+			{ kind: 'arrow_function', name: undefined, label: '<anonymous>', detail: 'c =>', symbolKind: SymbolKind.Function },
+		]);
+		// the statements of a callback's body are its children
+		const loadConfigs = structure!.children.find(n => n.kind === 'function_declaration')!;
+		expect(functions(loadConfigs).map(n => n.detail)).toEqual(['async path =>', 'function retry()', 'c =>']);
+		const callback = descendants(loadConfigs).find(n => n.detail === 'async path =>')!;
+		expect(callback.children.map(n => source.substring(n.startIndex, n.endIndex).trim())).toEqual([
+			`const text = await readFile(path, 'utf8');`,
+			'return parseConfig(text);',
+		]);
+
+		// without the option, they don't get nodes of their own
+		expect(functions(await structureComputer.getStructure(WASMLanguage.TypeScript, source))).toEqual([]);
+	});
+
 	it('from tree-sitter repo', async () => {
 		const source = outdent`
 			declare module Foo {
@@ -253,4 +436,24 @@ describe('getStructure - typescript', () => {
 			expect(await tsSrcWithStructure(source)).toMatchSnapshot();
 		});
 	});
+
+	it('enum members with includeEnumMembers', async () => {
+		const source = await fromFixture('enums.ts');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.TypeScript, source, { includeEnumMembers: true });
+
+		const [direction, flags] = structure!.children;
+		expect(direction).toMatchObject({ kind: 'enum_declaration', name: 'Direction' });
+		expect(direction.children.map(n => ({ kind: n.kind, name: n.name, value: n.value, symbolKind: n.symbolKind }))).toEqual([
+			{ kind: 'enum_assignment', name: 'Up', value: '1', symbolKind: SymbolKind.Constant },
+			{ kind: 'enum_member', name: 'Down', value: undefined, symbolKind: SymbolKind.Constant },
+			{ kind: 'enum_assignment', name: 'Left', value: `'L'`, symbolKind: SymbolKind.Constant },
+			{ kind: 'enum_assignment', name: 'Right', value: `'R'`, symbolKind: SymbolKind.Constant },
+		]);
+		expect(flags.children.map(n => [n.name, n.value])).toEqual([['None', '0'], ['Bold', '1 << 0'], ['Italic', undefined]]);
+
+		// members without values are nodes only with the option
+		const withoutOption = await structureComputer.getStructure(WASMLanguage.TypeScript, source);
+		expect(withoutOption!.children[0].children.map(n => n.name)).toEqual(['Up', 'Left', 'Right']);
+	});
 });
@@ -4,9 +4,11 @@
  *--------------------------------------------------------------------------------------------*/
 
 import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
 import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
 import { WASMLanguage } from '../../node/treeSitterLanguages';
-import { fromFixture, srcWithAnnotatedStructure } from './getStructure.util';
+import { descendants, fromFixture, srcWithAnnotatedStructure } from './getStructure.util';
 
 describe('getStructure - tsx', () => {
 	afterAll(() => _dispose());
@@ -28,4 +30,63 @@ describe('getStructure - tsx', () => {
 
 		expect(await tsxSrcWithStructure(source)).toMatchSnapshot();
 	});
+
+	interface JsxOutline {
+		kind: string;
+		label: string | undefined;
+		children: JsxOutline[];
+	}
+
+	function jsxOutline(node: OverlayNode): JsxOutline[] {
+		return node.children.flatMap(c => c.kind.startsWith('jsx_')
+			? [{ kind: c.kind, label: c.label, children: jsxOutline(c) }]
+			: jsxOutline(c));
+	}
+
+	test('jsx elements are nested by the element hierarchy under their components', async () => {
+
+		const source = await fromFixture('TodoList.tsx');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.TypeScriptTsx, source, { includeJsxElements: true });
+
+		const [todoList, counter] = structure!.children.filter(n => n.kind === 'function_declaration');
+		expect(todoList.name).toBe('TodoList');
+		expect(jsxOutline(todoList)).toEqual([
+			{
+				kind: 'jsx_fragment', label: '<>', children: [
+					{
+						kind: 'jsx_element', label: 'header id="todo-header"', children: [
+							{ kind: 'jsx_element', label: 'h1', children: [] },
+							{ kind: 'jsx_self_closing_element', label: 'Counter', children: [] },
+						]
+					},
+					{
+						kind: 'jsx_element', label: 'ul', children: [
+							{
+								kind: 'jsx_element', label: 'li key={todo.id}', children: [
+									{ kind: 'jsx_self_closing_element', label: 'input', children: [] },
+									{ kind: 'jsx_element', label: 'span', children: [] },
+								]
+							},
+						]
+					},
+				]
+			},
+		]);
+		expect(counter.name).toBe('Counter');
+		expect(jsxOutline(counter)).toEqual([{ kind: 'jsx_element', label: 'span', children: [] }]);
+	});
+
+	test('jsx elements without includeJsxElements', async () => {
+
+		const source = await fromFixture('TodoList.tsx');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.TypeScriptTsx, source);
+
+		// neither self-closing elements nor fragments stand out, and expression containers get nodes of their own
+		const kinds = descendants(structure!).map(n => n.kind);
+		expect(kinds).not.toContain('jsx_self_closing_element');
+		expect(kinds).not.toContain('jsx_fragment');
+		expect(kinds).toContain('jsx_expression');
+	});
 });
@@ -40,6 +40,13 @@ function treeToFlatList(
 	return result;
 }
 
+/**
+ * @returns all descendants of `node` in document order (pre-order)
+ */
+export function descendants(node: OverlayNode): OverlayNode[] {
+	return node.children.flatMap(child => [child, ...descendants(child)]);
+}
+
 export async function srcWithAnnotatedStructure(
 	language: WASMLanguage,
 	source: string
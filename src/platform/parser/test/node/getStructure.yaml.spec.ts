@@ -0,0 +1,98 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { SymbolKind } from '../../node/symbolKinds';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { descendants, fromFixture } from './getStructure.util';
+
+describe('getStructure - yaml', () => {
+	afterAll(() => _dispose());
+
+	function keys(node: OverlayNode): unknown[] {
+		return node.children.map(n => n.children.length === 0 ? `${n.name}=${n.value}` : { [n.name!]: keys(n) });
+	}
+
+	test('keys and sequence items form a hierarchy, with anchors, aliases, and duplicate keys', async () => {
+
+		const source = await fromFixture('ci.yaml');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Yaml, source);
+
+		expect(keys(structure!)).toEqual([
+			'name=CI',
+			{ defaults: ['runs-on=ubuntu-latest', 'timeout-minutes=10'] },
+			{
+				jobs: [
+					{
+						build: [
+							'<<=*defaults',
+							{ steps: [{ '[0]': ['uses=actions/checkout@v4'] }, { '[1]': ['name=Test', 'run=npm test'] }] },
+						],
+					},
+					{ lint: ['<<=*defaults', { steps: ['[0]=checkout', '[1]="lint"'] }] },
+					{ build: ['runs-on=*defaults'] },
+				],
+			},
+		]);
+		expect(structure!.children[0].symbolKind).toBe(SymbolKind.Key);
+	});
+
+	test('keys and sequence items have key paths from the root in block and flow styles', async () => {
+
+		const source = await fromFixture('compose.yaml');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Yaml, source);
+
+		expect(descendants(structure!).map(n => n.keyPath?.join('/'))).toEqual([
+			'services',
+			'services/web',
+			'services/web/image',
+			'services/web/ports',
+			'services/web/ports/[0]',
+			'services/web/ports/[1]',
+			'services/web/environment',
+			'services/web/environment/DEBUG',
+			'services/web/environment/LEVEL',
+			'services/worker',
+			'services/worker/command',
+			'services/worker/command/[0]',
+			'services/worker/command/[1]',
+			'services/worker/command/[1]/queue',
+			'services/worker/command/[1]/retries',
+			'services/worker/command/[1]/retries/[0]',
+			'services/worker/command/[1]/retries/[1]',
+		]);
+
+		const [ports, environment] = structure!.children[0].children[0].children.slice(1);
+		expect(source.substring(ports.startIndex, ports.endIndex)).toBe('    ports: ["80:80", "443:443"]\n');
+		const debug = environment.children[0];
+		expect(source.substring(debug.startIndex, debug.endIndex)).toBe(' DEBUG: "false"');
+	});
+
+	test('keys of documents of a stream start at their document', async () => {
+
+		const source = 'kind: Service\n---\nspec: { replicas: 2 }\n';
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Yaml, source);
+
+		expect(descendants(structure!).filter(n => n.kind === 'pair').map(n => n.keyPath)).toEqual([['kind'], ['spec'], ['spec', 'replicas']]);
+	});
+
+	test('documents of a stream get nodes of their own if there are several', async () => {
+
+		const source = 'apiVersion: v1\nkind: Service\n---\napiVersion: apps/v1\nkind: Deployment\n';
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Yaml, source);
+
+		expect(structure!.children.map(n => ({ kind: n.kind, keys: n.children.map(c => c.name) }))).toEqual([
+			{ kind: 'document', keys: ['apiVersion', 'kind'] },
+			{ kind: 'document', keys: ['apiVersion', 'kind'] },
+		]);
+	});
+});
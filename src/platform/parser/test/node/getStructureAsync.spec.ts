@@ -0,0 +1,85 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, afterEach, beforeEach, expect, suite, test } from 'vitest';
+import { timeout } from '../../../../util/vs/base/common/async';
+import { CancellationToken, CancellationTokenSource } from '../../../../util/vs/base/common/cancellation';
+import { CancellationError } from '../../../../util/vs/base/common/errors';
+import { _dispose } from '../../node/parserImpl';
+import { StructureComputer, structureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+
+suite('getStructureAsync', () => {
+
+	afterAll(() => _dispose());
+
+	// 10k lines
+	const source = ['package generated', ''].concat(Array.from({ length: 1250 }, (_, i) => [
+		`func Generated${i}(a int) int {`,
+		`	if a > ${i} {`,
+		`		return a - ${i}`,
+		`	}`,
+		`	b := a * ${i}`,
+		`	return b`,
+		`}`,
+		``,
+	].join('\n'))).join('\n');
+
+	let computer: StructureComputer;
+
+	beforeEach(() => {
+		computer = new StructureComputer();
+	});
+
+	afterEach(() => {
+		StructureComputer.YIELD_INTERVAL_MS = 10;
+	});
+
+	/**
+	 * @returns token that is cancelled once it's been checked `checks` times; it counts all checks, including those after the cancellation
+	 */
+	function cancelledAfter(checks: number) {
+		const state = { checks: 0 };
+		const token: CancellationToken = {
+			get isCancellationRequested() {
+				return ++state.checks > checks;
+			},
+			onCancellationRequested: CancellationToken.None.onCancellationRequested,
+		};
+		return { token, state };
+	}
+
+	test('computes the same structure as getStructure', async () => {
+		StructureComputer.YIELD_INTERVAL_MS = 0;
+
+		const structure = await computer.getStructureAsync(WASMLanguage.Go, source, CancellationToken.None);
+
+		expect(structure).toEqual(await structureComputer.getStructure(WASMLanguage.Go, source));
+	});
+
+	test('cancellation midway rejects without a partial structure and stops the computation', async () => {
+		StructureComputer.YIELD_INTERVAL_MS = 0;
+		const { token, state } = cancelledAfter(100);
+
+		await expect(computer.getStructureAsync(WASMLanguage.Go, source, token)).rejects.toBeInstanceOf(CancellationError);
+
+		// the token is checked for every top-level declaration, so it's no longer checked once the computation stopped
+		const checks = state.checks;
+		await timeout(10);
+		expect(checks).toBe(101);
+		expect(state.checks).toBe(checks);
+	});
+
+	test('cancelled computations are not cached', async () => {
+		const source = 'package main\n\nfunc main() {}\n';
+		const cts = new CancellationTokenSource();
+		cts.cancel();
+
+		await expect(computer.getStructureAsync(WASMLanguage.Go, source, cts.token)).rejects.toBeInstanceOf(CancellationError);
+
+		const structure = await computer.getStructureAsync(WASMLanguage.Go, source, CancellationToken.None);
+		expect(structure?.children.map(n => n.kind)).toEqual(['package_clause', 'function_declaration']);
+	});
+});
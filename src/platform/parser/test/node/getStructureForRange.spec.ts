@@ -0,0 +1,60 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _getStructureForRange } from '../../node/parserImpl';
+import { getStructureInRange, StructureComputer, structureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getStructureForRange', () => {
+
+	afterAll(() => _dispose());
+
+	test('range of a function yields just that function', async () => {
+		const source = await fromFixture('test.go');
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+		const main = structure!.children.find(n => n.name === 'main')!;
+
+		const partial = await _getStructureForRange(WASMLanguage.Go, source, main.startIndex, main.endIndex);
+
+		expect(partial!.children).toEqual([main]);
+		expect(partial!.packageName).toBe('main');
+	});
+
+	test('range within a function yields the statements it overlaps', async () => {
+		const source = await fromFixture('test.go');
+		const start = source.indexOf('for i, v := range');
+
+		const partial = await _getStructureForRange(WASMLanguage.Go, source, start, start + 1);
+
+		expect(partial!.children.map(n => n.name)).toEqual(['main']);
+		expect(partial!.children[0].children.map(n => n.kind)).toEqual(['for_statement']);
+	});
+
+	test('in a large source, the structure of the declarations around the range is that of the whole source', async () => {
+		const source = Array.from({ length: 2000 }, (_, i) => `export class Service${i} {\n\tget(id: string) {\n\t\treturn id + ${i};\n\t}\n}\n`).join('\n');
+		expect(source.length).toBeGreaterThan(StructureComputer.MIN_LENGTH_FOR_RANGE_RESTRICTION);
+		const start = source.indexOf('return id + 1000;');
+		const end = source.indexOf('class Service1001');
+
+		// with caching disabled, the structure of the whole source isn't cached, so that only the declarations around the range are computed
+		const partial = await new StructureComputer(0).getStructureForRange(WASMLanguage.TypeScript, source, start, end);
+		const full = await new StructureComputer(0).getStructure(WASMLanguage.TypeScript, source);
+
+		expect(partial).toEqual(getStructureInRange(full!, start, end, source));
+		expect(partial!.children.map(n => source.substring(n.startIndex, n.endIndex).split('\n')[0])).toEqual(['export class Service1000 {', 'export class Service1001 {']);
+		expect(partial!.children[0].children).toHaveLength(1);
+	});
+
+	test('range between declarations yields an empty structure', async () => {
+		const source = await fromFixture('test.go');
+		const blankLine = source.indexOf('func main') - 1;
+
+		const partial = await _getStructureForRange(WASMLanguage.Go, source, blankLine, blankLine + 1);
+
+		expect(partial!.children).toEqual([]);
+	});
+});
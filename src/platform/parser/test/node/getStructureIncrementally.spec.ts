@@ -0,0 +1,124 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { OverlayNode, TreeSitterEdit, TreeSitterPoint } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { _parse, _reparseWithCache } from '../../node/parserWithCaching';
+import { recomputedRangeOf, StructureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+function pointAt(source: string, offset: number): TreeSitterPoint {
+	const lines = source.substring(0, offset).split('\n');
+	return { row: lines.length - 1, column: lines[lines.length - 1].length };
+}
+
+/**
+ * Replaces `[startIndex, oldEndIndex)` of `source` with `text`.
+ */
+function applyEdit(source: string, startIndex: number, oldEndIndex: number, text: string): { newSource: string; edit: TreeSitterEdit } {
+	const newSource = source.substring(0, startIndex) + text + source.substring(oldEndIndex);
+	const newEndIndex = startIndex + text.length;
+	return {
+		newSource,
+		edit: {
+			startIndex,
+			oldEndIndex,
+			newEndIndex,
+			startPosition: pointAt(source, startIndex),
+			oldEndPosition: pointAt(source, oldEndIndex),
+			newEndPosition: pointAt(newSource, newEndIndex),
+		}
+	};
+}
+
+/**
+ * @returns pseudo-random numbers in `[0, 1)` that are the same for the same seed, so that failures can be reproduced
+ */
+function randomNumbers(seed: number): () => number {
+	let state = seed;
+	return () => {
+		state = (state * 1103515245 + 12345) % 2147483648;
+		return state / 2147483648;
+	};
+}
+
+suite('getStructureIncrementally', () => {
+
+	afterAll(() => _dispose());
+
+	/**
+	 * Computes the structure of `newSource` incrementally and from scratch with caching disabled, so that neither is served from the cache.
+	 */
+	async function assertSameAsFullComputation(lang: WASMLanguage, previousSource: string, previousStructure: OverlayNode, newSource: string, edits: TreeSitterEdit[]): Promise<OverlayNode> {
+		const incremental = await new StructureComputer(0).getStructureIncrementally(lang, newSource, { previousSource, edits, structure: previousStructure });
+		const full = await new StructureComputer(0).getStructure(lang, newSource);
+		expect(incremental).toEqual(full);
+		return incremental!;
+	}
+
+	test('a declaration that is deleted', async () => {
+		const source = await fromFixture('test.go');
+		const structure = await new StructureComputer(0).getStructure(WASMLanguage.Go, source);
+
+		const start = source.indexOf('type InterfaceExample');
+		const end = source.indexOf('func (s StructExample)');
+		const { newSource, edit } = applyEdit(source, start, end, '');
+		const incremental = await assertSameAsFullComputation(WASMLanguage.Go, source, structure!, newSource, [edit]);
+
+		expect(incremental.children.map(n => n.name)).not.toContain('InterfaceExample');
+		// the method is still linked to its type, which is declared before the edit
+		const struct = incremental.children.find(n => n.name === 'StructExample')!;
+		expect(struct.methods).toEqual([incremental.children.find(n => n.kind === 'method_declaration')]);
+	});
+
+	test('a declaration that is split in two', async () => {
+		const source = await fromFixture('test.go');
+		const structure = await new StructureComputer(0).getStructure(WASMLanguage.Go, source);
+
+		const offset = source.indexOf('    fmt.Println(s.Name)');
+		const { newSource, edit } = applyEdit(source, offset, offset, '    return nil\n}\n\nfunc (s StructExample) Split() error {\n');
+		const incremental = await assertSameAsFullComputation(WASMLanguage.Go, source, structure!, newSource, [edit]);
+
+		expect(incremental.children.filter(n => n.kind === 'method_declaration').map(n => n.name)).toEqual(['MethodExample', 'Split']);
+	});
+
+	test('the structure is the same as a full computation after random edits', async () => {
+		const snippets = ['', 'x', '\n', '{', '}', '(', '"', '/*', '*/', '// note\n', 'func extra() {}\n', '\n\nfunction split() {\n', 'return nil\n}\n'];
+		const sources: [WASMLanguage, string][] = [
+			[WASMLanguage.Go, await fromFixture('test.go')],
+			[WASMLanguage.TypeScript, await fromFixture('signatures.ts')],
+		];
+		for (const [lang, initialSource] of sources) {
+			const random = randomNumbers(42);
+			let source = initialSource;
+			let structure = (await new StructureComputer(0).getStructure(lang, source))!;
+			for (let i = 0; i < 50; i++) {
+				const start = Math.floor(random() * source.length);
+				const end = Math.min(source.length, start + Math.floor(random() * 20));
+				const { newSource, edit } = applyEdit(source, start, end, snippets[Math.floor(random() * snippets.length)]);
+				structure = await assertSameAsFullComputation(lang, source, structure, newSource, [edit]);
+				source = newSource;
+			}
+		}
+	});
+
+	test('only the declarations around a small edit are computed again', async () => {
+		const source = Array.from({ length: 50 }, (_, i) => `function foo${i}(a: number): number {\n\treturn a + ${i};\n}\n`).join('\n');
+		const offset = source.indexOf('a + 25;');
+		const { newSource, edit } = applyEdit(source, offset + 'a '.length, offset + 'a +'.length, '*');
+
+		(await _parse(WASMLanguage.TypeScript, source)).dispose();
+		const { treeRef, changedRanges } = await _reparseWithCache(WASMLanguage.TypeScript, newSource, { previousSource: source, edits: [edit] });
+		try {
+			expect(changedRanges).toBeDefined();
+			const range = recomputedRangeOf(treeRef.tree, source, newSource, changedRanges!);
+			expect(newSource.substring(range.startIndex, range.endIndex).match(/function foo\d+/g)).toEqual(['function foo24', 'function foo25', 'function foo26']);
+		} finally {
+			treeRef.dispose();
+		}
+	});
+});
@@ -0,0 +1,86 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { timeout } from '../../../../util/vs/base/common/async';
+import { CancellationToken } from '../../../../util/vs/base/common/cancellation';
+import { CancellationError } from '../../../../util/vs/base/common/errors';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { StructureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+
+suite('getStructureStream', () => {
+
+	afterAll(() => _dispose());
+
+	const source = ['package generated', ''].concat(Array.from({ length: 500 }, (_, i) => [
+		`type Generated${i} struct{}`,
+		``,
+		`func (g Generated${i}) Value(a int) int {`,
+		`	return a * ${i}`,
+		`}`,
+		``,
+	].join('\n'))).join('\n');
+
+	/**
+	 * @returns token that is cancelled once it's been checked `checks` times; it counts all checks, including those after the cancellation
+	 */
+	function cancelledAfter(checks: number) {
+		const state = { checks: 0 };
+		const token: CancellationToken = {
+			get isCancellationRequested() {
+				return ++state.checks > checks;
+			},
+			onCancellationRequested: CancellationToken.None.onCancellationRequested,
+		};
+		return { token, state };
+	}
+
+	test('top-level nodes are streamed in source order', async () => {
+		const computer = new StructureComputer();
+
+		const nodes: OverlayNode[] = [];
+		for await (const node of computer.getStructureStream(WASMLanguage.Go, source)) {
+			nodes.push(node);
+		}
+
+		const structure = await computer.getStructure(WASMLanguage.Go, source);
+		expect(nodes).toEqual(structure!.children);
+		expect(nodes.map(n => n.name ?? n.kind).slice(0, 5)).toEqual(['package_clause', 'Generated0', 'Value', 'Generated1', 'Value']);
+	});
+
+	test('ending the iteration early stops the computation', async () => {
+		const { token, state } = cancelledAfter(Infinity);
+
+		const names: string[] = [];
+		for await (const node of new StructureComputer().getStructureStream(WASMLanguage.Go, source, token)) {
+			names.push(node.name ?? node.kind);
+			if (names.length === 3) {
+				break;
+			}
+		}
+
+		const checks = state.checks;
+		await timeout(10);
+		expect(names).toEqual(['package_clause', 'Generated0', 'Value']);
+		expect(state.checks).toBe(checks);
+	});
+
+	test('cancellation midway rejects the iteration', async () => {
+		const { token } = cancelledAfter(100);
+
+		const names: string[] = [];
+		const iterate = async () => {
+			for await (const node of new StructureComputer().getStructureStream(WASMLanguage.Go, source, token)) {
+				names.push(node.name ?? node.kind);
+			}
+		};
+
+		await expect(iterate()).rejects.toBeInstanceOf(CancellationError);
+		expect(names.length).toBeGreaterThan(0);
+		expect(names.length).toBeLessThan(1 + 2 * 500);
+	});
+});
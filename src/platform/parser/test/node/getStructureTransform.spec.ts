@@ -0,0 +1,108 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { StructureComputer, StructureTransform, transformStructure } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { descendants, fromFixture } from './getStructure.util';
+
+suite('getStructure with a transform', () => {
+
+	afterAll(() => _dispose());
+
+	const named = (node: OverlayNode) => descendants(node).filter(n => n.name !== undefined).map(n => n.name);
+
+	const dropUnexported: StructureTransform = node => node.name !== undefined && !/^\p{Lu}/u.test(node.name) ? 'dropWithChildren' : 'keep';
+
+	const visibilityPrefixes: { [visibility: string]: string } = { public: '+', private: '-', protected: '#' };
+	const labelByVisibility: StructureTransform = node => {
+		const prefix = node.visibility && visibilityPrefixes[node.visibility];
+		if (node.name !== undefined && prefix) {
+			node.name = `${prefix}${node.name}`;
+		}
+	};
+
+	test('go - unexported declarations are dropped with their children', async () => {
+		const source = await fromFixture('exports.go');
+
+		const structure = await new StructureComputer(0).getStructure(WASMLanguage.Go, source, { transform: dropUnexported });
+
+		// unlike with `exportedOnly`, methods are kept by their own names, e.g., `Error` of the unexported `errorString`
+		expect(named(structure!)).toEqual(['DefaultSize', 'Cache', 'Size', 'New', 'Get', 'Error']);
+		const cache = structure!.children.find(n => n.name === 'Cache')!;
+		expect(cache.methods!.map(m => m.name)).toEqual(['Get']);
+		// the type of `Error` was dropped, so there's nothing to link it to
+		expect(structure!.children.find(n => n.name === 'Error')!.kind).toBe('method_declaration');
+	});
+
+	test('typescript - the members of a dropped class are nested in its parent instead', async () => {
+		const source = await fromFixture('signatures.ts');
+
+		const structure = await new StructureComputer(0).getStructure(WASMLanguage.TypeScript, source, { transform: node => node.kind === 'class_declaration' ? 'drop' : undefined });
+
+		expect(descendants(structure!).map(n => n.kind)).not.toContain('class_declaration');
+		expect(structure!.children.filter(n => n.kind === 'method_definition').map(n => n.name)).toEqual(['find', 'create', 'log', '#validate', 'all']);
+		// the children of the members stay theirs
+		expect(structure!.children.find(n => n.kind === 'constructor')!.children.map(n => n.kind)).toContain('parameter_property');
+	});
+
+	test('typescript - nodes are relabeled, including exported ones', async () => {
+		const source = await fromFixture('signatures.ts');
+
+		const structure = await new StructureComputer(0).getStructure(WASMLanguage.TypeScript, source, { transform: labelByVisibility });
+
+		expect(structure!.children.filter(n => n.kind === 'function_declaration' || n.kind === 'class_declaration').map(n => n.name)).toEqual(['+fetchUser', '-load', '+UserService']);
+		const service = structure!.children.find(n => n.kind === 'class_declaration')!;
+		expect(service.children.filter(n => n.kind === 'method_definition').map(n => n.name)).toEqual(['+find', '#create', '-log', '-#validate', '+all']);
+	});
+
+	test('structures computed with a transform are not cached', async () => {
+		const source = await fromFixture('signatures.ts');
+		const computer = new StructureComputer();
+
+		const relabeled = await computer.getStructure(WASMLanguage.TypeScript, source, { transform: labelByVisibility });
+		const unexported = await computer.getStructure(WASMLanguage.TypeScript, source, { transform: node => node.visibility === 'public' ? 'dropWithChildren' : undefined });
+
+		expect(relabeled!.children.map(n => n.name)).toContain('+fetchUser');
+		expect(unexported!.children.filter(n => n.kind === 'function_declaration' || n.kind === 'class_declaration').map(n => n.name)).toEqual(['load']);
+		expect(computer.getCacheStats()).toEqual({ hits: 0, misses: 2, size: 0 });
+		// and a structure without one is computed as usual
+		expect(named((await computer.getStructure(WASMLanguage.TypeScript, source))!)).toContain('fetchUser');
+	});
+
+	test('a computed structure is transformed the same way', async () => {
+		const source = await fromFixture('exports.go');
+		const computer = new StructureComputer(0);
+
+		const structure = (await computer.getStructure(WASMLanguage.Go, source))!;
+		transformStructure(structure, dropUnexported);
+
+		const transformed = await computer.getStructure(WASMLanguage.Go, source, { transform: dropUnexported });
+		expect(named(structure)).toEqual(named(transformed!));
+		expect(structure.children.find(n => n.name === 'Cache')!.methods!.map(m => m.name)).toEqual(['Get']);
+	});
+
+	test('links are matched by stable ID, so those of a structure passed as JSON are unlinked and relinked as well', async () => {
+		const source = await fromFixture('defers.go');
+		const structure: OverlayNode = JSON.parse(JSON.stringify(await new StructureComputer(0).getStructure(WASMLanguage.Go, source)));
+
+		transformStructure(structure, node => node.callee === 'f.Close' ? 'dropWithChildren' : undefined);
+
+		const save = structure.children.find(n => n.name === 'Save')!;
+		expect(save.defers!.map(n => n.callee)).toEqual(['s.mu.Unlock', undefined]);
+		// the links are the nodes of the structure rather than their copies
+		expect(save.defers![0]).toBe(save.children.find(n => n.kind === 'deferred_call'));
+	});
+
+	test('scala - a companion that is dropped is unlinked', async () => {
+		const source = await fromFixture('shapes.scala');
+
+		const structure = await new StructureComputer(0).getStructure(WASMLanguage.Scala, source, { transform: node => node.kind === 'object_definition' ? 'dropWithChildren' : undefined });
+
+		expect(structure!.children.find(n => n.kind === 'case_class_definition')!.companion).toBeUndefined();
+	});
+});
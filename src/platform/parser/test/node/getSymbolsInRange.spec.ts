@@ -0,0 +1,58 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose } from '../../node/parserImpl';
+import { getSymbolsInRange, structureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getSymbolsInRange', () => {
+
+	afterAll(() => _dispose());
+
+	async function symbolsIn(source: string, startOffset: number, endOffset: number) {
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+		return getSymbolsInRange(structure!, startOffset, endOffset, source).map(n => ({ kind: n.kind, name: n.name }));
+	}
+
+	test('range spanning declarations yields them and their members in order', async () => {
+		const source = await fromFixture('test.go');
+
+		const symbols = await symbolsIn(source, source.indexOf('type InterfaceExample'), source.indexOf('func (s StructExample)') + 'func'.length);
+
+		expect(symbols).toEqual([
+			{ kind: 'type_declaration', name: 'InterfaceExample' },
+			{ kind: 'method_spec', name: 'MethodExample' },
+			{ kind: 'method_declaration', name: 'MethodExample' },
+		]);
+	});
+
+	test('range within a body yields the enclosing declarations', async () => {
+		const source = await fromFixture('test.go');
+
+		const start = source.indexOf('errors.New');
+
+		expect(await symbolsIn(source, start, start + 1)).toEqual([{ kind: 'method_declaration', name: 'MethodExample' }]);
+	});
+
+	test('range within whitespace between declarations yields nothing', async () => {
+		const source = await fromFixture('test.go');
+
+		const start = source.indexOf('}\n\nfunc (s StructExample)') + 1;
+
+		expect(await symbolsIn(source, start, start + 2)).toEqual([]);
+	});
+
+	test('offsets outside of the source are clamped', async () => {
+		const source = await fromFixture('test.go');
+
+		const all = await symbolsIn(source, 0, source.length);
+
+		expect(all.length).toBeGreaterThan(0);
+		expect(await symbolsIn(source, -10, source.length + 10)).toEqual(all);
+		expect(await symbolsIn(source, source.length + 10, source.length + 20)).toEqual([]);
+	});
+});
@@ -0,0 +1,42 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _getSyntaxErrors } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getSyntaxErrors', () => {
+
+	afterAll(() => _dispose());
+
+	test('a well-formed source has no errors', async () => {
+		expect(await _getSyntaxErrors(WASMLanguage.Go, 'package main\n\nfunc main() {}\n')).toEqual([]);
+	});
+
+	test('go - statements at the top level are errors after the declarations', async () => {
+		const source = await fromFixture('test.go');
+
+		const errors = await _getSyntaxErrors(WASMLanguage.Go, source);
+
+		expect(errors.length).toBeGreaterThan(0);
+		const trailingStatements = source.indexOf('// Create a channel of integers.');
+		expect(errors.every(e => e.startIndex >= trailingStatements)).toBe(true);
+		expect(errors.some(e => e.startIndex <= source.indexOf('ch := make(chan int)') && source.indexOf('ch := make(chan int)') < e.endIndex)).toBe(true);
+		expect(errors.some(e => e.endIndex > source.indexOf('go func() {'))).toBe(true);
+		expect(errors.every(e => e.kind === 'missing' || e.message.startsWith('unexpected '))).toBe(true);
+	});
+
+	test('go - a token the parser inserted to recover is missing', async () => {
+		const source = 'package main\n\nfunc main() {\n\tprintln("unterminated")\n';
+
+		const errors = await _getSyntaxErrors(WASMLanguage.Go, source);
+
+		const missing = errors.find(e => e.kind === 'missing');
+		expect(missing?.message).toBe('missing "}"');
+		expect(missing!.startIndex).toBe(missing!.endIndex);
+		expect(missing!.startIndex).toBeGreaterThan(source.indexOf('println'));
+	});
+});
@@ -0,0 +1,90 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _getTests } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('getTests', () => {
+
+	afterAll(() => _dispose());
+
+	test('go - test and benchmark functions', async () => {
+		const source = await fromFixture('foo_test.go');
+
+		const tests = await _getTests(WASMLanguage.Go, source);
+
+		expect(tests.map(t => ({ name: t.name, kind: t.kind, framework: t.framework }))).toEqual([
+			{ name: 'TestSum', kind: 'test', framework: 'go' },
+			{ name: 'Test_sumOfNothing', kind: 'test', framework: 'go' },
+			{ name: 'BenchmarkSum', kind: 'benchmark', framework: 'go' },
+		]);
+		expect(source.substring(tests[0].range.startIndex, tests[0].range.endIndex)).toMatch(/^func TestSum\(t \*testing\.T\) \{[\s\S]*\}$/);
+	});
+
+	test('typescript - nested describe blocks give the paths of their tests', async () => {
+		const source = await fromFixture('calculator.test.ts');
+
+		const tests = await _getTests(WASMLanguage.TypeScript, source);
+
+		expect(tests.map(t => ({ path: t.path, kind: t.kind }))).toEqual([
+			{ path: ['Calculator'], kind: 'suite' },
+			{ path: ['Calculator', 'add'], kind: 'suite' },
+			{ path: ['Calculator', 'add', 'adds two numbers'], kind: 'test' },
+			{ path: ['Calculator', 'add', 'adds negative numbers'], kind: 'test' },
+			{ path: ['Calculator', 'clears the memory'], kind: 'test' },
+			{ path: ['a calculator can be created'], kind: 'test' },
+		]);
+		expect(tests.every(t => t.framework === 'jest')).toBe(true);
+		expect(source.substring(tests[2].range.startIndex, tests[2].range.endIndex)).toBe([
+			`it('adds two numbers', () => {`,
+			'			expect(calculator.add(1, 2)).toBe(3);',
+			'		})',
+		].join('\n'));
+	});
+
+	test('typescript - mocha suites', async () => {
+		const source = [
+			`import * as assert from 'assert';`,
+			`import { suite, test } from 'mocha';`,
+			'',
+			`suite('Calculator', () => {`,
+			`	test('adds two numbers', () => {`,
+			'		assert.strictEqual(1 + 2, 3);',
+			'	});',
+			'});',
+			'',
+		].join('\n');
+
+		const tests = await _getTests(WASMLanguage.TypeScript, source);
+
+		expect(tests.map(t => ({ name: t.name, path: t.path, kind: t.kind, framework: t.framework }))).toEqual([
+			{ name: 'Calculator', path: ['Calculator'], kind: 'suite', framework: 'mocha' },
+			{ name: 'adds two numbers', path: ['Calculator', 'adds two numbers'], kind: 'test', framework: 'mocha' },
+		]);
+	});
+
+	test('python - test functions and the methods of test classes', async () => {
+		const source = await fromFixture('test_calculator.py');
+
+		const tests = await _getTests(WASMLanguage.Python, source);
+
+		expect(tests.map(t => ({ path: t.path, kind: t.kind, framework: t.framework }))).toEqual([
+			{ path: ['test_add'], kind: 'test', framework: 'pytest' },
+			{ path: ['test_add_is_commutative'], kind: 'test', framework: 'pytest' },
+			{ path: ['CalculatorTest'], kind: 'suite', framework: 'unittest' },
+			{ path: ['CalculatorTest', 'test_clear'], kind: 'test', framework: 'unittest' },
+			{ path: ['TestMemory'], kind: 'suite', framework: 'pytest' },
+			{ path: ['TestMemory', 'test_starts_empty'], kind: 'test', framework: 'pytest' },
+		]);
+		// the decorators of a parametrized test are part of it
+		expect(source.substring(tests[1].range.startIndex).startsWith('@pytest.mark.parametrize')).toBe(true);
+	});
+
+	test('unsupported language', async () => {
+		expect(await _getTests(WASMLanguage.Ruby, `test 'adds' do\nend\n`)).toEqual([]);
+	});
+});
@@ -0,0 +1,236 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterEach, beforeAll, beforeEach, expect, MockInstance, suite, test, vi } from 'vitest';
+import { GrammarLoadError, LanguageLoader } from '../../node/languageLoader';
+import { _preloadLanguages, ParserWithCaching } from '../../node/parserWithCaching';
+import { structureComputer } from '../../node/structure';
+import { TreeSitterUnknownLanguageError, WASMLanguage } from '../../node/treeSitterLanguages';
+import { syntacticallyValidAtoms } from '../../node/treeSitterQueries';
+import Parser = require('web-tree-sitter');
+
+suite('LanguageLoader', () => {
+
+	beforeAll(() => Parser.init());
+
+	let loadSpy: MockInstance;
+
+	beforeEach(() => {
+		loadSpy = vi.spyOn(Parser.Language, 'load');
+	});
+
+	afterEach(() => {
+		loadSpy.mockRestore();
+	});
+
+	test('grammars are loaded on first use only', async () => {
+		const loader = new LanguageLoader();
+
+		const first = await loader.loadLanguage(WASMLanguage.TypeScript);
+		const second = await loader.loadLanguage(WASMLanguage.TypeScript);
+
+		expect(second).toBe(first);
+		expect(loadSpy).toHaveBeenCalledTimes(1);
+		expect(loader.isResident(WASMLanguage.TypeScript)).toBe(true);
+		expect(loader.isResident(WASMLanguage.Python)).toBe(false);
+	});
+
+	test('least recently used grammars are no longer resident beyond the limit', async () => {
+		const onDidUnload = vi.fn();
+		const loader = new LanguageLoader(2, onDidUnload);
+
+		await loader.loadLanguage(WASMLanguage.TypeScript);
+		await loader.loadLanguage(WASMLanguage.JavaScript);
+		await loader.loadLanguage(WASMLanguage.TypeScript);
+		await loader.loadLanguage(WASMLanguage.Python);
+
+		expect(loader.isResident(WASMLanguage.TypeScript)).toBe(true);
+		expect(loader.isResident(WASMLanguage.JavaScript)).toBe(false);
+		expect(loader.isResident(WASMLanguage.Python)).toBe(true);
+		expect(onDidUnload.mock.calls).toEqual([[WASMLanguage.JavaScript]]);
+	});
+
+	test('a grammar that is used again after it was no longer resident is not loaded again', async () => {
+		const querySpy = vi.spyOn(Parser.Language.prototype, 'query');
+		try {
+			const loader = new LanguageLoader(1);
+
+			const first = await loader.loadLanguage(WASMLanguage.TypeScript);
+			await loader.loadLanguage(WASMLanguage.Python);
+			expect(loader.isResident(WASMLanguage.TypeScript)).toBe(false);
+			querySpy.mockClear();
+
+			const second = await loader.loadLanguage(WASMLanguage.TypeScript);
+
+			expect(second).toBe(first);
+			expect(loadSpy).toHaveBeenCalledTimes(2);
+			// only the queries, which were deleted, are compiled again
+			expect(querySpy).toHaveBeenCalledTimes(syntacticallyValidAtoms[WASMLanguage.TypeScript].length);
+		} finally {
+			querySpy.mockRestore();
+		}
+	});
+
+	test('pinned grammars stay resident until unpinned', async () => {
+		const loader = new LanguageLoader(1);
+
+		const pin = loader.pin(WASMLanguage.TypeScript);
+		await loader.loadLanguage(WASMLanguage.Python);
+
+		expect(loader.isResident(WASMLanguage.TypeScript)).toBe(true);
+		expect(loader.isResident(WASMLanguage.Python)).toBe(true);
+
+		pin.dispose();
+
+		expect(loader.isResident(WASMLanguage.TypeScript)).toBe(false);
+		expect(loader.isResident(WASMLanguage.Python)).toBe(true);
+	});
+
+	test('a grammar that is resident again yields identical parses', async () => {
+		const parser = new ParserWithCaching(undefined, /* maxResidentLanguages */ 1);
+		try {
+			const source = 'class Foo { bar(): number { return 1; } }';
+
+			const before = await parser.parse(WASMLanguage.TypeScript, source);
+			const expected = before.tree.rootNode.toString();
+			before.dispose();
+
+			(await parser.parse(WASMLanguage.Python, 'def foo(): pass')).dispose();
+			parser.clearCache();
+
+			const after = await parser.parse(WASMLanguage.TypeScript, source);
+			expect(after.tree.rootNode.toString()).toBe(expected);
+			after.dispose();
+
+			expect(loadSpy).toHaveBeenCalledTimes(2);
+		} finally {
+			parser.dispose();
+		}
+	});
+
+	test('a grammar loaded again by another parser, like that of a replaced parser worker, yields identical parses', async () => {
+		const first = new ParserWithCaching();
+		const second = new ParserWithCaching();
+		try {
+			const source = 'class Foo { bar(): number { return 1; } }';
+
+			const before = await first.parse(WASMLanguage.TypeScript, source);
+			const after = await second.parse(WASMLanguage.TypeScript, source);
+
+			expect(after.tree.rootNode.toString()).toBe(before.tree.rootNode.toString());
+			expect(loadSpy).toHaveBeenCalledTimes(2);
+			expect(second.loadedLanguageCount).toBe(1);
+			before.dispose();
+			after.dispose();
+		} finally {
+			first.dispose();
+			second.dispose();
+		}
+	});
+
+	test('concurrent parses of more languages than the limit all succeed', async () => {
+		const parser = new ParserWithCaching(undefined, /* maxResidentLanguages */ 1);
+		try {
+			const treeRefs = await Promise.all([
+				parser.parse(WASMLanguage.TypeScript, 'const a = 1;'),
+				parser.parse(WASMLanguage.Python, 'a = 1'),
+				parser.parse(WASMLanguage.Go, 'package main'),
+			]);
+
+			expect(treeRefs.map(t => t.tree.rootNode.hasError)).toEqual([false, false, false]);
+			treeRefs.forEach(t => t.dispose());
+		} finally {
+			parser.dispose();
+		}
+	});
+
+	test('a failed load is retried once', async () => {
+		const loader = new LanguageLoader();
+		loadSpy.mockRejectedValueOnce(new Error('EIO: i/o error, read'));
+
+		const language = await loader.loadLanguage(WASMLanguage.TypeScript);
+
+		expect(language).toBeDefined();
+		expect(loadSpy).toHaveBeenCalledTimes(2);
+	});
+
+	test('a grammar that failed to load is loaded again on next use', async () => {
+		const parser = new ParserWithCaching();
+		try {
+			loadSpy.mockRejectedValueOnce(new Error('EIO: i/o error, read')).mockRejectedValueOnce(new Error('EIO: i/o error, read'));
+
+			await expect(parser.parse(WASMLanguage.Go, 'package main')).rejects.toThrow(GrammarLoadError);
+
+			const treeRef = await parser.parse(WASMLanguage.Go, 'package main');
+			expect(treeRef.tree.rootNode.hasError).toBe(false);
+			treeRef.dispose();
+			expect(loadSpy).toHaveBeenCalledTimes(3);
+		} finally {
+			parser.dispose();
+		}
+	});
+
+	test('preloaded grammars are not loaded again by the first parse', async () => {
+		try {
+			await Promise.all([_preloadLanguages([WASMLanguage.Go]), _preloadLanguages([WASMLanguage.Go])]);
+			await _preloadLanguages([WASMLanguage.Go]);
+			expect(loadSpy).toHaveBeenCalledTimes(1);
+			loadSpy.mockClear();
+
+			const structure = await structureComputer.getStructure(WASMLanguage.Go, 'package main\n\nfunc main() {}\n');
+
+			expect(structure?.children.map(n => n.kind)).toContain('function_declaration');
+			expect(loadSpy).not.toHaveBeenCalled();
+		} finally {
+			ParserWithCaching.INSTANCE.dispose();
+		}
+	});
+
+	test('structure queries are compiled once the grammar is resident and deleted when it no longer is', async () => {
+		const querySpy = vi.spyOn(Parser.Language.prototype, 'query');
+		try {
+			const loader = new LanguageLoader(1);
+
+			await loader.loadLanguage(WASMLanguage.TypeScript);
+			await loader.loadLanguage(WASMLanguage.TypeScript);
+
+			const queries = querySpy.mock.results.map(r => r.value as Parser.Query);
+			expect(queries).toHaveLength(syntacticallyValidAtoms[WASMLanguage.TypeScript].length);
+			const deleteSpies = queries.map(q => vi.spyOn(q, 'delete'));
+
+			await loader.loadLanguage(WASMLanguage.Python);
+
+			expect(loader.isResident(WASMLanguage.TypeScript)).toBe(false);
+			expect(deleteSpies.map(s => s.mock.calls.length)).toEqual(queries.map(() => 1));
+		} finally {
+			querySpy.mockRestore();
+		}
+	});
+
+	test('the structure queries of a preloaded grammar are not compiled again', async () => {
+		try {
+			await _preloadLanguages([WASMLanguage.Go]);
+			const querySpy = vi.spyOn(Parser.Language.prototype, 'query');
+			try {
+				await structureComputer.getStructure(WASMLanguage.Go, 'package main\n\nfunc a() {}\n');
+				await structureComputer.getStructure(WASMLanguage.Go, 'package main\n\nfunc b() {}\n');
+
+				expect(querySpy).not.toHaveBeenCalled();
+			} finally {
+				querySpy.mockRestore();
+			}
+		} finally {
+			ParserWithCaching.INSTANCE.dispose();
+		}
+	});
+
+	test('languages without a grammar are rejected without loading anything', async () => {
+		const loader = new LanguageLoader();
+
+		await expect(loader.loadLanguage('cobol' as WASMLanguage)).rejects.toThrow(TreeSitterUnknownLanguageError);
+		expect(loadSpy).not.toHaveBeenCalled();
+		expect(loader.isResident('cobol' as WASMLanguage)).toBe(false);
+	});
+});
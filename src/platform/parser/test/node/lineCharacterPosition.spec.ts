@@ -0,0 +1,105 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { LineCharacterPosition } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { descendants, fromFixture } from './getStructure.util';
+
+function positionOf(source: string, offset: number) {
+	const lines = source.substring(0, offset).split('\r\n');
+	return { line: lines.length - 1, character: lines[lines.length - 1].length };
+}
+
+/** scans `source` up to `offset` for every conversion, like `vscode.TextDocument.positionAt` semantically */
+function naivePositionOf(source: string, offset: number) {
+	let line = 0;
+	let character = 0;
+	for (let i = 0; i < offset; i++) {
+		if (source[i] === '\r' && source[i + 1] === '\n') {
+			if (i + 1 === offset) {
+				break; // within `\r\n`, i.e., at the end of the line
+			}
+			i++;
+		}
+		if (source[i] === '\r' || source[i] === '\n') {
+			line++;
+			character = 0;
+		} else {
+			character++;
+		}
+	}
+	return { line, character };
+}
+
+suite('LineCharacterPosition', () => {
+
+	afterAll(() => _dispose());
+
+	test('\\r\\n is a single line break', () => {
+		const positionAt = LineCharacterPosition.converterFor('ab\r\ncd\r\n');
+
+		expect(positionAt(0)).toEqual({ line: 0, character: 0 });
+		expect(positionAt(2)).toEqual({ line: 0, character: 2 });
+		expect(positionAt(4)).toEqual({ line: 1, character: 0 });
+		expect(positionAt(5)).toEqual({ line: 1, character: 1 });
+		expect(positionAt(8)).toEqual({ line: 2, character: 0 });
+	});
+
+	test('offset within \\r\\n is at the end of its line', () => {
+		const positionAt = LineCharacterPosition.converterFor('ab\r\ncd');
+
+		expect(positionAt(3)).toEqual({ line: 0, character: 2 });
+	});
+
+	test('lone \\r and mixed line breaks', () => {
+		const positionAt = LineCharacterPosition.converterFor('a\rb\nc\r\nd');
+
+		expect([0, 2, 4, 7].map(positionAt)).toEqual([
+			{ line: 0, character: 0 },
+			{ line: 1, character: 0 },
+			{ line: 2, character: 0 },
+			{ line: 3, character: 0 },
+		]);
+	});
+
+	test('positions of all offsets are those of a naive scan', async () => {
+		const source = (await fromFixture('test.go')).replace(/\n\n/g, '\r\n\n').replace(/\{\n/g, '{\r');
+		const positionAt = LineCharacterPosition.converterFor(source);
+
+		for (let offset = 0; offset <= source.length; offset++) {
+			expect(positionAt(offset)).toEqual(naivePositionOf(source, offset));
+		}
+	});
+
+	test('structure of a source with \\r\\n line breaks', async () => {
+		const source = (await fromFixture('test.go')).replace(/\r?\n/g, '\r\n');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		// `"fmt"` is on the 5th line, which is only line index 4 if `\r\n` counts once
+		expect(LineCharacterPosition.converterFor(source)(source.indexOf('"fmt"'))).toEqual({ line: 4, character: 4 });
+
+		for (const node of descendants(structure!)) {
+			expect(node.range).toEqual({ start: positionOf(source, node.startIndex), end: positionOf(source, node.endIndex) });
+		}
+	});
+
+	test('structure positions are the same for \\n and \\r\\n line breaks', async () => {
+		const lfSource = (await fromFixture('decorated.ts')).replace(/\r\n/g, '\n');
+		const crlfSource = lfSource.replace(/\n/g, '\r\n');
+
+		const positionsOf = async (source: string) => {
+			const structure = await structureComputer.getStructure(WASMLanguage.TypeScript, source);
+			return descendants(structure!).map(n => ({ kind: n.kind, range: n.range }));
+		};
+
+		const lfPositions = await positionsOf(lfSource);
+		expect(lfPositions.length).toBeGreaterThan(0);
+		expect(await positionsOf(crlfSource)).toEqual(lfPositions);
+	});
+});
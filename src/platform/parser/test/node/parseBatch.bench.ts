@@ -0,0 +1,49 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import * as fs from 'fs';
+import * as path from 'path';
+import { afterAll, bench, describe, vi } from 'vitest';
+import type { WorkerOptions } from 'worker_threads';
+import { BatchParseFile } from '../../node/parserService';
+import { ParserServiceImpl } from '../../node/parserServiceImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+
+/** The workers run the bundled parser, so the extension must be built first, e.g., with `npm run build` */
+const builtWorkerPath = path.join(__dirname, '../../../../../dist/worker2.js');
+
+vi.mock('../../../../util/node/worker', async importOriginal => {
+	const worker = await importOriginal<typeof import('../../../../util/node/worker')>();
+	return {
+		...worker,
+		WorkerWithRpcProxy: class<T> extends worker.WorkerWithRpcProxy<T> {
+			constructor(_workerPath: string, options?: WorkerOptions) {
+				super(builtWorkerPath, options);
+			}
+		},
+	};
+});
+
+describe.skipIf(!fs.existsSync(builtWorkerPath))('structures of 32 Go sources', () => {
+
+	const service = new ParserServiceImpl(/* useWorker */ true, /* batchWorkerCount */ 4);
+
+	afterAll(() => service.dispose());
+
+	// distinct sources, so that neither parse trees nor structures are served from their caches
+	let count = 0;
+	const functions = Array.from({ length: 200 }, (_, i) => `func f${i}(a, b int) int {\n\tif a > b {\n\t\treturn a - b\n\t}\n\treturn a + b\n}\n`).join('\n');
+	const nextFiles = (): BatchParseFile[] => Array.from({ length: 32 }, () => ({ language: WASMLanguage.Go, source: `package main\n\nvar v${count++} = 0\n\n${functions}` }));
+
+	bench('parseBatch on 4 workers', async () => {
+		await service.parseBatch(nextFiles());
+	});
+
+	bench('getStructure of one source after another', async () => {
+		for (const file of nextFiles()) {
+			await service.getTreeSitterASTForWASMLanguage(file.language, file.source).getStructure();
+		}
+	});
+});
@@ -0,0 +1,70 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test, vi } from 'vitest';
+import { _dispose } from '../../node/parserImpl';
+import { ParserServiceImpl } from '../../node/parserServiceImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+
+const workerCalls = vi.hoisted(() => [] as string[]);
+
+vi.mock('../../../../util/node/worker', async () => {
+	const parser = await import('../../node/parserImpl');
+	return {
+		// the parser runs right here, with arguments and results cloned like the messages to and from a worker
+		WorkerWithRpcProxy: class {
+			readonly proxy = new Proxy({}, {
+				get: (_target, fn: string) => async (...args: unknown[]) => {
+					workerCalls.push(fn);
+					return structuredClone(await (parser as any)[fn](...structuredClone(args)));
+				},
+			});
+			terminate() { }
+		},
+	};
+});
+
+suite('parseBatch', () => {
+
+	afterAll(() => _dispose());
+
+	test('structures are in the order of the files and a failing file yields an error in its place', async () => {
+		const service = new ParserServiceImpl(false);
+
+		const results = await service.parseBatch([
+			{ language: WASMLanguage.Go, source: 'package main\n\nfunc main() {}\n' },
+			{ language: 'cobol' as WASMLanguage, source: 'IDENTIFICATION DIVISION.' },
+			{ language: WASMLanguage.Python, source: 'def main():\n\tpass\n' },
+		]);
+
+		expect(results).toHaveLength(3);
+		expect('structure' in results[0] && results[0].structure?.children.map(n => n.kind)).toContain('function_declaration');
+		expect(results[1]).toEqual({ error: expect.any(String) });
+		expect('structure' in results[2] && results[2].structure?.children.map(n => n.kind)).toEqual(['function_definition']);
+
+		service.dispose();
+	});
+
+	test('with workers, the files of a language are parsed after it is resolved once per worker', async () => {
+		const service = new ParserServiceImpl(true, 1);
+		const files = [
+			{ language: WASMLanguage.Go, source: 'package main\n\nfunc a() {}\n' },
+			{ language: WASMLanguage.Python, source: 'def b():\n\tpass\n' },
+			{ language: WASMLanguage.Go, source: 'package main\n\nfunc c() {}\n' },
+			{ language: WASMLanguage.Python, source: 'def d():\n\tpass\n' },
+		];
+		workerCalls.length = 0;
+
+		const results = await service.parseBatch(files);
+		await service.parseBatch(files);
+
+		expect(results.map(r => 'structure' in r ? r.structure?.children.find(n => n.name !== undefined)?.name : r.error)).toEqual(['a', 'b', 'c', 'd']);
+		// the worker keeps the languages it resolved for the next batch
+		expect(workerCalls.filter(fn => fn !== '_getStructure')).toEqual(['_preloadLanguages', '_preloadLanguages']);
+		expect(workerCalls.filter(fn => fn === '_getStructure')).toHaveLength(8);
+
+		service.dispose();
+	});
+});
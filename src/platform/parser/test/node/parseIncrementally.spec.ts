@@ -0,0 +1,142 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterEach, beforeEach, expect, suite, test } from 'vitest';
+import { TreeSitterEdit, TreeSitterOffsetRange, TreeSitterPoint, TreeSitterSourceEdits } from '../../node/nodes';
+import { ParserWithCaching } from '../../node/parserWithCaching';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import Parser = require('web-tree-sitter');
+
+function pointAt(source: string, offset: number): TreeSitterPoint {
+	const lines = source.substring(0, offset).split('\n');
+	return { row: lines.length - 1, column: lines[lines.length - 1].length };
+}
+
+/**
+ * Replaces `[startIndex, oldEndIndex)` of `source` with `text`.
+ */
+function applyEdit(source: string, startIndex: number, oldEndIndex: number, text: string): { newSource: string; edit: TreeSitterEdit } {
+	const newSource = source.substring(0, startIndex) + text + source.substring(oldEndIndex);
+	const newEndIndex = startIndex + text.length;
+	return {
+		newSource,
+		edit: {
+			startIndex,
+			oldEndIndex,
+			newEndIndex,
+			startPosition: pointAt(source, startIndex),
+			oldEndPosition: pointAt(source, oldEndIndex),
+			newEndPosition: pointAt(newSource, newEndIndex),
+		}
+	};
+}
+
+function countNodes(node: Parser.SyntaxNode, predicate: (n: Parser.SyntaxNode) => boolean): number {
+	return (predicate(node) ? 1 : 0) + node.children.reduce((sum, child) => sum + countNodes(child, predicate), 0);
+}
+
+function serialize(node: Parser.SyntaxNode): string[] {
+	return [
+		`${node.type} [${node.startIndex}, ${node.endIndex}] (${node.startPosition.row}:${node.startPosition.column}-${node.endPosition.row}:${node.endPosition.column})`,
+		...node.children.flatMap(serialize),
+	];
+}
+
+suite('parse incrementally', () => {
+
+	let incrementalParser: ParserWithCaching;
+	let fullParser: ParserWithCaching;
+
+	beforeEach(() => {
+		incrementalParser = new ParserWithCaching();
+		fullParser = new ParserWithCaching();
+	});
+
+	afterEach(() => {
+		incrementalParser.dispose();
+		fullParser.dispose();
+	});
+
+	const source = Array.from({ length: 50 }, (_, i) => `function foo${i}(a: number): number {\n\treturn a + ${i};\n}\n`).join('\n');
+
+	async function assertSameAsFullParse(newSource: string, previous: TreeSitterSourceEdits) {
+		(await incrementalParser.parse(WASMLanguage.TypeScript, previous.previousSource)).dispose();
+
+		const incrementalRef = await incrementalParser.parse(WASMLanguage.TypeScript, newSource, previous);
+		const fullRef = await fullParser.parse(WASMLanguage.TypeScript, newSource);
+		try {
+			expect(incrementalRef.tree.rootNode.text).toBe(newSource);
+			expect(serialize(incrementalRef.tree.rootNode)).toEqual(serialize(fullRef.tree.rootNode));
+			expect(incrementalRef.tree.rootNode.toString()).toBe(fullRef.tree.rootNode.toString());
+		} finally {
+			incrementalRef.dispose();
+			fullRef.dispose();
+		}
+	}
+
+	test('insertion', async () => {
+		const offset = source.indexOf('return a + 10;');
+		const { newSource, edit } = applyEdit(source, offset, offset, 'const b = a * 2;\n\t');
+		await assertSameAsFullParse(newSource, { previousSource: source, edits: [edit] });
+	});
+
+	test('deletion spanning multiple lines', async () => {
+		const start = source.indexOf('function foo20');
+		const end = source.indexOf('function foo23');
+		const { newSource, edit } = applyEdit(source, start, end, '');
+		await assertSameAsFullParse(newSource, { previousSource: source, edits: [edit] });
+	});
+
+	test('multiple edits introducing a syntax error', async () => {
+		const first = applyEdit(source, source.indexOf('foo3('), source.indexOf('foo3(') + 'foo3'.length, 'renamed');
+		const offset = first.newSource.indexOf('return a + 40;');
+		const second = applyEdit(first.newSource, offset, offset + 'return'.length, 'retur (');
+		await assertSameAsFullParse(second.newSource, { previousSource: source, edits: [first.edit, second.edit] });
+	});
+
+	test('falls back to a full parse if the previous tree is not cached', async () => {
+		const offset = source.indexOf('return a + 10;');
+		const { newSource, edit } = applyEdit(source, offset, offset, 'const b = a * 2;\n\t');
+
+		const incrementalRef = await incrementalParser.parse(WASMLanguage.TypeScript, newSource, { previousSource: source, edits: [edit] });
+		const fullRef = await fullParser.parse(WASMLanguage.TypeScript, newSource);
+		try {
+			expect(serialize(incrementalRef.tree.rootNode)).toEqual(serialize(fullRef.tree.rootNode));
+		} finally {
+			incrementalRef.dispose();
+			fullRef.dispose();
+		}
+	});
+
+	test('a single-token edit in a large file changes only a small part of the tree', async () => {
+		// 2000 lines
+		const largeSource = Array.from({ length: 500 }, (_, i) => `function foo${i}(a: number): number {\n\treturn a + ${i};\n}\n`).join('');
+		const offset = largeSource.indexOf('a + 250;');
+		const { newSource, edit } = applyEdit(largeSource, offset + 'a '.length, offset + 'a +'.length, '*');
+
+		(await incrementalParser.parse(WASMLanguage.TypeScript, largeSource)).dispose();
+
+		const { treeRef, changedRanges } = await incrementalParser.reparse(WASMLanguage.TypeScript, newSource, { previousSource: largeSource, edits: [edit] });
+		const fullRef = await fullParser.parse(WASMLanguage.TypeScript, newSource);
+		try {
+			expect(serialize(treeRef.tree.rootNode)).toEqual(serialize(fullRef.tree.rootNode));
+
+			expect(changedRanges).toBeDefined();
+			const allNodes = countNodes(fullRef.tree.rootNode, () => true);
+			const changedNodes = countNodes(treeRef.tree.rootNode, n => changedRanges!.some(r => TreeSitterOffsetRange.doesContain(r, n)));
+			expect(changedNodes).toBeLessThan(allNodes / 100);
+			expect(changedRanges!.reduce((sum, r) => sum + TreeSitterOffsetRange.len(r), 0)).toBeLessThan(newSource.length / 100);
+		} finally {
+			treeRef.dispose();
+			fullRef.dispose();
+		}
+	});
+
+	test('no changed ranges are reported for a full parse', async () => {
+		const { treeRef, changedRanges } = await incrementalParser.reparse(WASMLanguage.TypeScript, source, { previousSource: 'const a = 1;', edits: [] });
+		treeRef.dispose();
+		expect(changedRanges).toBeUndefined();
+	});
+});
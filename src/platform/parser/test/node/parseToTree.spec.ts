@@ -0,0 +1,94 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { ParsedTreeNode, _parseToTree } from '../../node/parsedTree';
+import { _dispose } from '../../node/parserImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+
+suite('parseToTree', () => {
+
+	afterAll(() => _dispose());
+
+	function countNodes(node: ParsedTreeNode): number {
+		return node.namedChildren.reduce((count, child) => count + countNodes(child), 1);
+	}
+
+	test('the tree can be traversed until the handle is disposed', async () => {
+		const source = await fromFixture('test.go');
+
+		const handle = await _parseToTree(WASMLanguage.Go, source);
+		const root = handle.rootNode;
+		const firstDeclaration = root.namedChildren[0];
+
+		expect(root.type).toBe('source_file');
+		expect(root.range).toEqual({ startIndex: 0, endIndex: source.length });
+		expect(root.hasError).toBe(false);
+		expect(firstDeclaration.type).toBe('package_clause');
+		expect(firstDeclaration.text).toBe('package main');
+
+		handle.dispose();
+
+		expect(handle.isDisposed).toBe(true);
+		expect(() => root.namedChildren).toThrow();
+		expect(() => firstDeclaration.text).toThrow();
+		expect(() => handle.rootNode).toThrow();
+		// disposing again is a no-op
+		handle.dispose();
+	});
+
+	test('descendants of types are found in document order, optionally within a range', async () => {
+		const source = await fromFixture('test.go');
+
+		const handle = await _parseToTree(WASMLanguage.Go, source);
+		try {
+			const main = handle.rootNode.namedChildren.find(n => n.type === 'function_declaration' && n.text.startsWith('func main()'))!;
+			const calls = [
+				'i.MethodExample()',
+				'fmt.Println(err)',
+				'fmt.Println(i, v)',
+				'fmt.Println("BoolExample is true")',
+				'fmt.Println("BoolExample is false")',
+				'fmt.Println("Zero")',
+				'fmt.Println("Ten")',
+				'fmt.Println("Default")',
+				'fmt.Println(key, value)',
+			];
+
+			expect(main.descendantsOfType(['call_expression']).map(n => n.text)).toEqual(calls);
+			// calls after `main`, e.g., `make(chan int)`, are outside of its range
+			expect(handle.descendantsOfType(['call_expression'], main.range).map(n => n.text)).toEqual(calls);
+			expect(main.descendantsOfType(['call_expression', 'short_var_declaration']).map(n => n.type)[0]).toBe('short_var_declaration');
+		} finally {
+			handle.dispose();
+		}
+	});
+
+	test('parsing many sources and disposing their handles does not grow the WASM heap', async () => {
+		const source = await fromFixture('test.go');
+
+		const parseAndDispose = async (round: string) => {
+			for (let i = 0; i < 100; i++) {
+				const handle = await _parseToTree(WASMLanguage.Go, `${source}\n// ${round} ${i}\n`);
+				try {
+					expect(countNodes(handle.rootNode)).toBeGreaterThan(100);
+				} finally {
+					handle.dispose();
+				}
+			}
+		};
+
+		// more sources than the cache of parse trees holds, so the heap has grown to its steady size
+		await parseAndDispose('warm-up');
+		const before = process.memoryUsage().arrayBuffers; // includes the memory of the WASM module
+		for (let round = 0; round < 5; round++) {
+			await parseAndDispose(`round ${round}`);
+		}
+
+		// a leaked tree of the source takes tens of KB, i.e., leaking the trees of 500 sources would take MBs
+		expect(process.memoryUsage().arrayBuffers - before).toBeLessThan(2 * 1024 * 1024);
+	});
+});
@@ -0,0 +1,95 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterEach, beforeAll, beforeEach, expect, MockInstance, suite, test, vi } from 'vitest';
+import { LanguageLoader } from '../../node/languageLoader';
+import { ParserPool } from '../../node/parserPool';
+import { ParserWithCaching } from '../../node/parserWithCaching';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import Parser = require('web-tree-sitter');
+
+suite('ParserPool', () => {
+
+	let tsLanguage: Parser.Language;
+
+	beforeAll(async () => {
+		await Parser.init();
+		tsLanguage = await new LanguageLoader().loadLanguage(WASMLanguage.TypeScript);
+	});
+
+	let setLanguageSpy: MockInstance;
+	let deleteSpy: MockInstance;
+
+	beforeEach(() => {
+		setLanguageSpy = vi.spyOn(Parser.prototype, 'setLanguage');
+		deleteSpy = vi.spyOn(Parser.prototype, 'delete');
+	});
+
+	afterEach(() => {
+		setLanguageSpy.mockRestore();
+		deleteSpy.mockRestore();
+	});
+
+	test('released parser is reused without setting the language again', () => {
+		const pool = new ParserPool();
+		try {
+			const first = pool.acquire(WASMLanguage.TypeScript, tsLanguage);
+			pool.release(WASMLanguage.TypeScript, first);
+			const second = pool.acquire(WASMLanguage.TypeScript, tsLanguage);
+			pool.release(WASMLanguage.TypeScript, second);
+
+			expect(second).toBe(first);
+			expect(setLanguageSpy).toHaveBeenCalledTimes(1);
+		} finally {
+			pool.dispose();
+		}
+	});
+
+	test('acquired parsers are never shared', () => {
+		const pool = new ParserPool();
+		try {
+			const first = pool.acquire(WASMLanguage.TypeScript, tsLanguage);
+			const second = pool.acquire(WASMLanguage.TypeScript, tsLanguage);
+
+			expect(second).not.toBe(first);
+
+			pool.release(WASMLanguage.TypeScript, first);
+			pool.release(WASMLanguage.TypeScript, second);
+			expect(pool.idleCount(WASMLanguage.TypeScript)).toBe(2);
+		} finally {
+			pool.dispose();
+		}
+	});
+
+	test('parsers released beyond the cap are deleted', () => {
+		const pool = new ParserPool(2);
+		try {
+			const parsers = [1, 2, 3].map(() => pool.acquire(WASMLanguage.TypeScript, tsLanguage));
+			for (const parser of parsers) {
+				pool.release(WASMLanguage.TypeScript, parser);
+			}
+
+			expect(pool.idleCount(WASMLanguage.TypeScript)).toBe(2);
+			expect(deleteSpy).toHaveBeenCalledTimes(1);
+			expect(pool.idleCount(WASMLanguage.JavaScript)).toBe(0);
+		} finally {
+			pool.dispose();
+		}
+		expect(deleteSpy).toHaveBeenCalledTimes(3);
+	});
+
+	test('parsing many sources sets the language only once', async () => {
+		const parser = new ParserWithCaching();
+		try {
+			for (let i = 0; i < 10; i++) {
+				(await parser.parse(WASMLanguage.TypeScript, `const a${i} = ${i};`)).dispose();
+			}
+
+			expect(setLanguageSpy).toHaveBeenCalledTimes(1);
+		} finally {
+			parser.dispose();
+		}
+	});
+});
@@ -0,0 +1,101 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, describe, expect, test, vi } from 'vitest';
+import { CancellationToken } from '../../../../util/vs/base/common/cancellation';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { ParserServiceImpl } from '../../node/parserServiceImpl';
+import { StructureTransform } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { descendants, fromFixture } from './getStructure.util';
+
+vi.mock('../../../../util/node/worker', async () => {
+	const parser = await import('../../node/parserImpl');
+	return {
+		// the parser runs right here, but arguments and results are cloned like the messages to and from a worker, which can't hold functions
+		WorkerWithRpcProxy: class {
+			readonly proxy = new Proxy({}, {
+				get: (_target, fn: string) => async (...args: unknown[]) => structuredClone(await (parser as any)[fn](...structuredClone(args))),
+			});
+			terminate() { }
+		},
+	};
+});
+
+describe.each([false, true])('parser service with a transform (useWorker: %s)', useWorker => {
+	const service = new ParserServiceImpl(useWorker, 2);
+
+	afterAll(() => {
+		service.dispose();
+		_dispose();
+	});
+
+	const named = (node: OverlayNode) => descendants(node).filter(n => n.name !== undefined).map(n => n.name);
+
+	const dropUnexported: StructureTransform = node => node.name !== undefined && !/^\p{Lu}/u.test(node.name) ? 'dropWithChildren' : 'keep';
+
+	test('getStructure', async () => {
+		const source = await fromFixture('exports.go');
+
+		const structure = await service.getTreeSitterASTForWASMLanguage(WASMLanguage.Go, source).getStructure({ transform: dropUnexported });
+
+		expect(named(structure!)).toEqual(['DefaultSize', 'Cache', 'Size', 'New', 'Get', 'Error']);
+		expect(structure!.children.find(n => n.name === 'Cache')!.methods!.map(m => m.name)).toEqual(['Get']);
+	});
+
+	test('a structure without a transform is not transformed by an earlier one', async () => {
+		const source = await fromFixture('exports.go');
+		const ast = service.getTreeSitterASTForWASMLanguage(WASMLanguage.Go, source);
+
+		await ast.getStructure({ transform: dropUnexported });
+
+		expect(named((await ast.getStructure())!)).toContain('newDefault');
+	});
+
+	test('getStructureAsync and getStructureIncrementally', async () => {
+		const source = 'package main\n\nfunc Run() {}\n\nfunc run() {}\n';
+		const transform: StructureTransform = node => {
+			if (node.name !== undefined) {
+				node.name = `> ${node.name}`;
+			}
+		};
+
+		const structure = await service.getStructureAsync(WASMLanguage.Go, source, CancellationToken.None, { transform });
+		expect(structure!.children.map(n => n.name)).toEqual([undefined, '> Run', '> run']);
+
+		const newSource = source + '\nfunc Stop() {}\n';
+		const offset = source.length;
+		const point = { row: 5, column: 0 };
+		const incremental = await service.getStructureIncrementally(WASMLanguage.Go, newSource, {
+			previousSource: source,
+			edits: [{ startIndex: offset, oldEndIndex: offset, newEndIndex: newSource.length, startPosition: point, oldEndPosition: point, newEndPosition: { row: 7, column: 0 } }],
+			structure: structure!,
+		}, { transform });
+		// the names of the nodes of the previous structure aren't transformed twice
+		expect(incremental!.children.map(n => n.name)).toEqual([undefined, '> Run', '> run', '> Stop']);
+	});
+
+	test('parseBatch', async () => {
+		const results = await service.parseBatch([
+			{ language: WASMLanguage.Go, source: 'package main\n\nfunc _hidden() {}\n\nfunc Shown() {}\n' },
+			{ language: WASMLanguage.Python, source: 'def main():\n\tpass\n\ndef _helper():\n\tpass\n' },
+		], { transform: node => node.name?.startsWith('_') ? 'dropWithChildren' : undefined });
+
+		expect(results.map(r => 'structure' in r ? r.structure?.children.filter(n => n.name !== undefined).map(n => n.name) : r.error)).toEqual([['Shown'], ['main']]);
+	});
+
+	test('getMarkdownCodeBlockStructures and getSingleFileComponentStructures', async () => {
+		const transform: StructureTransform = node => node.kind === 'function_declaration' ? 'dropWithChildren' : undefined;
+
+		const blocks = await service.getMarkdownCodeBlockStructures('```go\npackage main\n\nfunc main() {}\n\ntype T struct{}\n```\n', { transform });
+		expect(blocks.map(b => b.structure.children.map(n => n.kind))).toEqual([['package_clause', 'type_declaration']]);
+
+		const scripts = await service.getSingleFileComponentStructures('vue', await fromFixture('counter.vue'), { transform });
+		expect(scripts).toHaveLength(1);
+		expect(named(scripts[0].structure)).not.toContain('increment');
+		expect(scripts[0].structure.children).not.toEqual([]);
+	});
+});
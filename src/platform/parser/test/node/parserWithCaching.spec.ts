@@ -0,0 +1,196 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, afterEach, beforeEach, expect, MockInstance, suite, test, vi } from 'vitest';
+import { CancellationToken } from '../../../../util/vs/base/common/cancellation';
+import { _dispose, _tryParse } from '../../node/parserImpl';
+import { ParseAbortedError, ParserWithCaching, ParseTimeoutError } from '../../node/parserWithCaching';
+import { structureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import Parser = require('web-tree-sitter');
+
+suite('ParserWithCaching', () => {
+
+	afterAll(() => _dispose());
+
+	let parseSpy: MockInstance;
+
+	beforeEach(() => {
+		ParserWithCaching.INSTANCE.clearCache();
+		structureComputer.clearCache();
+		parseSpy = vi.spyOn(Parser.prototype, 'parse');
+	});
+
+	afterEach(() => {
+		parseSpy.mockRestore();
+	});
+
+	const source = 'function foo() { return 1; }';
+
+	test('parses the same source only once', async () => {
+		const parser = new ParserWithCaching();
+		try {
+			const first = await parser.parse(WASMLanguage.TypeScript, source);
+			const second = await parser.parse(WASMLanguage.TypeScript, source);
+
+			expect(parseSpy).toHaveBeenCalledTimes(1);
+			expect(second.tree).toBe(first.tree);
+
+			first.dispose();
+			second.dispose();
+		} finally {
+			parser.dispose();
+		}
+	});
+
+	test('same source in different languages is parsed separately', async () => {
+		const parser = new ParserWithCaching();
+		try {
+			(await parser.parse(WASMLanguage.TypeScript, source)).dispose();
+			(await parser.parse(WASMLanguage.JavaScript, source)).dispose();
+
+			expect(parseSpy).toHaveBeenCalledTimes(2);
+		} finally {
+			parser.dispose();
+		}
+	});
+
+	test('evicts least recently used trees beyond the cache size', async () => {
+		const parser = new ParserWithCaching(2);
+		try {
+			for (const src of ['const a = 1;', 'const b = 2;', 'const c = 3;', 'const a = 1;']) {
+				(await parser.parse(WASMLanguage.TypeScript, src)).dispose();
+			}
+
+			expect(parseSpy).toHaveBeenCalledTimes(4);
+		} finally {
+			parser.dispose();
+		}
+	});
+
+	test('clearCache drops cached trees', async () => {
+		const parser = new ParserWithCaching();
+		try {
+			(await parser.parse(WASMLanguage.TypeScript, source)).dispose();
+			parser.clearCache();
+			(await parser.parse(WASMLanguage.TypeScript, source)).dispose();
+
+			expect(parseSpy).toHaveBeenCalledTimes(2);
+		} finally {
+			parser.dispose();
+		}
+	});
+
+	test('concurrent structure requests share a single parse and structure', async () => {
+		const [first, second] = await Promise.all([
+			structureComputer.getStructure(WASMLanguage.TypeScript, source),
+			structureComputer.getStructure(WASMLanguage.TypeScript, source),
+		]);
+
+		expect(parseSpy).toHaveBeenCalledTimes(1);
+		expect(first).toBeDefined();
+		expect(second).toBe(first);
+	});
+
+	suite('budget', () => {
+
+		const largeSource = Array.from({ length: 5000 }, (_, i) => `function f${i}(a: number) { return a * ${i}; }`).join('\n');
+
+		test('parse exceeding its timeout is aborted and not cached', async () => {
+			const parser = new ParserWithCaching();
+			try {
+				const error = await parser.parse(WASMLanguage.TypeScript, largeSource, undefined, { timeoutMicros: 1 }).catch(e => e);
+				expect(error).toBeInstanceOf(ParseAbortedError);
+				expect((error as ParseAbortedError).reason).toBe('timedOut');
+
+				// the halted parse isn't resumed but started over
+				const treeRef = await parser.parse(WASMLanguage.TypeScript, largeSource);
+				expect(treeRef.tree.rootNode.hasError).toBe(false);
+				expect(treeRef.tree.rootNode.namedChildCount).toBe(5000);
+				treeRef.dispose();
+
+				expect(parseSpy).toHaveBeenCalledTimes(2);
+			} finally {
+				parser.dispose();
+			}
+		});
+
+		test('cancelled parse is aborted before parsing', async () => {
+			const parser = new ParserWithCaching();
+			try {
+				const error = await parser.parse(WASMLanguage.TypeScript, source, undefined, { token: CancellationToken.Cancelled }).catch(e => e);
+
+				expect(error).toBeInstanceOf(ParseAbortedError);
+				expect((error as ParseAbortedError).reason).toBe('cancelled');
+				expect(parseSpy).not.toHaveBeenCalled();
+			} finally {
+				parser.dispose();
+			}
+		});
+
+		// e.g., `[[[ ... ]]]`, which makes the parser's stack as deep as the nesting
+		const deeplyNestedSource = `const a = ${'['.repeat(100000)}${']'.repeat(100000)};`;
+
+		test('deeply nested parse exceeding its timeout in milliseconds throws a timeout error and resets the parser', async () => {
+			const parser = new ParserWithCaching();
+			try {
+				const error = await parser.parse(WASMLanguage.JavaScript, deeplyNestedSource, undefined, { timeoutMs: 1 }).catch(e => e);
+				expect(error).toBeInstanceOf(ParseTimeoutError);
+				expect(error).toBeInstanceOf(ParseAbortedError);
+				expect((error as ParseTimeoutError).timeoutMs).toBe(1);
+
+				// the parser that timed out is reused for the next parse, which mustn't resume the halted one
+				const treeRef = await parser.parse(WASMLanguage.JavaScript, source);
+				expect(treeRef.tree.rootNode.hasError).toBe(false);
+				expect(treeRef.tree.rootNode.text).toBe(source);
+				treeRef.dispose();
+			} finally {
+				parser.dispose();
+			}
+		});
+
+		test('structure whose parse times out is rejected and not cached', async () => {
+			const error = await structureComputer.getStructure(WASMLanguage.JavaScript, deeplyNestedSource, { timeoutMs: 1 }).catch(e => e);
+			expect(error).toBeInstanceOf(ParseTimeoutError);
+
+			// without a time budget, the structure is computed rather than the timeout served from the cache
+			const structure = await structureComputer.getStructure(WASMLanguage.JavaScript, deeplyNestedSource);
+			expect(structure).toBeDefined();
+		});
+
+		test('tryParse reports the outcome instead of throwing', async () => {
+			expect(await _tryParse(WASMLanguage.TypeScript, largeSource, 1)).toBe('timedOut');
+			expect(await _tryParse(WASMLanguage.TypeScript, source)).toBe('parsed');
+		});
+	});
+
+	suite('leading byte order marks and shebang lines', () => {
+
+		test('a byte order mark is skipped without shifting offsets', async () => {
+			const treeRef = await ParserWithCaching.INSTANCE.parse(WASMLanguage.Go, '\uFEFFpackage main\n\nfunc main() {}\n');
+			try {
+				expect(treeRef.tree.rootNode.hasError).toBe(false);
+				const packageClause = treeRef.tree.rootNode.namedChildren[0];
+				expect(packageClause.type).toBe('package_clause');
+				expect(packageClause.startIndex).toBe(1);
+				expect(packageClause.startPosition).toEqual({ row: 0, column: 1 });
+			} finally {
+				treeRef.dispose();
+			}
+		});
+
+		test('a shebang line is a comment in languages whose grammars don\'t know shebangs', async () => {
+			const treeRef = await ParserWithCaching.INSTANCE.parse(WASMLanguage.Go, '#!/usr/bin/env gorun\npackage main\n\nfunc main() {}\n');
+			try {
+				expect(treeRef.tree.rootNode.hasError).toBe(false);
+				expect(treeRef.tree.rootNode.namedChildren.map(c => c.type)).toEqual(['comment', 'package_clause', 'function_declaration']);
+				expect(treeRef.tree.rootNode.namedChildren[1].startPosition).toEqual({ row: 1, column: 0 });
+			} finally {
+				treeRef.dispose();
+			}
+		});
+	});
+});
+
@@ -0,0 +1,86 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test, vi } from 'vitest';
+import { _dispose } from '../../node/parserImpl';
+import { ParserServiceImpl } from '../../node/parserServiceImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+
+const workers = vi.hoisted(() => [] as { languages: Set<string>; terminated: boolean }[]);
+
+vi.mock('../../../../util/node/worker', async () => {
+	const parser = await import('../../node/parserImpl');
+	return {
+		// the parser runs right here, but each worker counts the grammars it loaded as if it had a parser of its own
+		WorkerWithRpcProxy: class {
+			readonly languages = new Set<string>();
+			terminated = false;
+			readonly proxy = new Proxy({}, {
+				get: (_target, fn: string) => async (...args: unknown[]) => {
+					if (fn === '_getLoadedLanguageCount') {
+						return this.languages.size;
+					}
+					if (typeof args[0] === 'string') {
+						this.languages.add(args[0]);
+					}
+					return structuredClone(await (parser as any)[fn](...structuredClone(args)));
+				},
+			});
+			constructor() {
+				workers.push(this);
+			}
+			terminate() {
+				this.terminated = true;
+			}
+		},
+	};
+});
+
+suite('parser worker recycling', () => {
+
+	afterAll(() => _dispose());
+
+	test('the worker is replaced once it loaded more grammars than the limit, and the new one loads them again', async () => {
+		const service = new ParserServiceImpl(true, 1, /* maxWorkerLanguages */ 2);
+		const goSource = 'package main\n\nfunc main() {}\n';
+		try {
+			const before = await service.getTreeSitterASTForWASMLanguage(WASMLanguage.Go, goSource).getStructure();
+			await service.getTreeSitterASTForWASMLanguage(WASMLanguage.Python, 'def main():\n\tpass\n').getStructure();
+			expect(workers.map(w => w.terminated)).toEqual([false]);
+
+			await service.getTreeSitterASTForWASMLanguage(WASMLanguage.TypeScript, 'function main() {}\n').getStructure();
+			// the check of the loaded grammars is answered after the call
+			await vi.waitFor(() => expect(workers[0].terminated).toBe(true));
+
+			const after = await service.getTreeSitterASTForWASMLanguage(WASMLanguage.Go, goSource).getStructure();
+			expect(after).toEqual(before);
+			expect(workers.map(w => w.terminated)).toEqual([true, false]);
+			expect([...workers[1].languages]).toEqual([WASMLanguage.Go]);
+		} finally {
+			service.dispose();
+			workers.length = 0;
+		}
+	});
+
+	test('a replaced worker answers the calls that are pending before it is terminated', async () => {
+		const service = new ParserServiceImpl(true, 1, /* maxWorkerLanguages */ 1);
+		try {
+			const sources: [WASMLanguage, string][] = [
+				[WASMLanguage.Go, 'package main\n\nfunc main() {}\n'],
+				[WASMLanguage.Python, 'def main():\n\tpass\n'],
+				[WASMLanguage.Rust, 'fn main() {}\n'],
+			];
+			const structures = await Promise.all(sources.map(([language, source]) => service.getTreeSitterASTForWASMLanguage(language, source).getStructure()));
+
+			expect(structures.map(s => s?.children.find(n => n.name !== undefined)?.name)).toEqual(['main', 'main', 'main']);
+			await vi.waitFor(() => expect(workers[0].terminated).toBe(true));
+			// all calls were made before the worker was replaced
+			expect(workers).toHaveLength(1);
+		} finally {
+			service.dispose();
+			workers.length = 0;
+		}
+	});
+});
@@ -0,0 +1,102 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { promises as fs } from 'fs';
+import { afterAll, expect, suite, test, vi } from 'vitest';
+import { grammarPathOf } from '../../node/languageLoader';
+import { _dispose, _getStructure, _registerLanguage, _runQuery } from '../../node/parserImpl';
+import { ParserServiceImpl } from '../../node/parserServiceImpl';
+import { TreeSitterQueryError } from '../../node/querying';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { fromFixture } from './getStructure.util';
+import Parser = require('web-tree-sitter');
+
+suite('registerLanguage', () => {
+
+	afterAll(() => _dispose());
+
+	const goWasmPath = grammarPathOf(WASMLanguage.Go);
+
+	test('a grammar registered under a new ID is parsed with its structure query', async () => {
+		await _registerLanguage({ id: 'go-functions', wasmPath: goWasmPath, structureQuery: '[(function_declaration) (method_declaration)] @declaration' });
+		const language = 'go-functions' as WASMLanguage;
+		const source = await fromFixture('test.go');
+
+		const structure = await _getStructure(language, source);
+
+		expect(structure!.children.map(n => n.kind)).toEqual(['method_declaration', 'function_declaration']);
+		expect(source.substring(structure!.children[1].startIndex, structure!.children[1].endIndex)).toMatch(/^func main\(\) \{/);
+		expect((await _runQuery(language, source, '(package_clause (package_identifier) @name)')).map(c => c.text)).toEqual(['main']);
+	});
+
+	test('the grammar loaded to validate a registration is the one that is parsed with', async () => {
+		const loadSpy = vi.spyOn(Parser.Language, 'load');
+		try {
+			await _registerLanguage({ id: 'go-loaded-once', wasmPath: goWasmPath, structureQuery: '(function_declaration) @function' });
+
+			const structure = await _getStructure('go-loaded-once' as WASMLanguage, 'package main\n\nfunc main() {}\n');
+
+			expect(structure!.children.map(n => n.kind)).toEqual(['function_declaration']);
+			expect(loadSpy).toHaveBeenCalledTimes(1);
+		} finally {
+			loadSpy.mockRestore();
+		}
+	});
+
+	test('a grammar can be registered from the bytes of its .wasm file', async () => {
+		const wasmBytes = new Uint8Array(await fs.readFile(goWasmPath));
+		await _registerLanguage({ id: 'go-bytes', wasmBytes, structureQuery: '(type_declaration) @type' });
+		const source = await fromFixture('test.go');
+
+		const structure = await _getStructure('go-bytes' as WASMLanguage, source);
+
+		expect(structure!.children.map(n => n.kind)).toEqual(['type_declaration', 'type_declaration']);
+		// the same bytes register the same language, and changing the caller's copy doesn't change the registration
+		wasmBytes.fill(0);
+		await expect(_registerLanguage({ id: 'go-bytes', wasmBytes: new Uint8Array(await fs.readFile(goWasmPath)), structureQuery: '(type_declaration) @type' })).resolves.toBeUndefined();
+		await expect(_registerLanguage({ id: 'go-bytes', wasmPath: goWasmPath, structureQuery: '(type_declaration) @type' })).rejects.toThrow(/already registered/);
+	});
+
+	test('registering the same language again is a no-op, registering it differently fails', async () => {
+		const registration = { id: 'go-types', wasmPath: goWasmPath, structureQuery: '(type_declaration) @type' };
+		await _registerLanguage(registration);
+		await _registerLanguage({ ...registration });
+
+		await expect(_registerLanguage({ ...registration, structureQuery: '(function_declaration) @function' })).rejects.toThrow(/already registered/);
+	});
+
+	test('registration fails for built-in languages, missing grammars, and malformed queries', async () => {
+		await expect(_registerLanguage({ id: 'go', wasmPath: goWasmPath, structureQuery: '(function_declaration) @function' })).rejects.toThrow(/built-in/);
+		await expect(_registerLanguage({ id: 'missing', wasmPath: '/does/not/exist.wasm', structureQuery: '(function_declaration) @function' })).rejects.toThrow(/failed to load its grammar from \/does\/not\/exist\.wasm/);
+		await expect(_registerLanguage({ id: 'malformed', wasmPath: goWasmPath, structureQuery: '(no_such_node) @node' })).rejects.toThrow(TreeSitterQueryError);
+		await expect(_registerLanguage({ id: 'garbage', wasmBytes: new Uint8Array([1, 2, 3]), structureQuery: '(function_declaration) @function' })).rejects.toThrow(/failed to load its grammar from 3 bytes of \.wasm/);
+		await expect(_registerLanguage({ id: 'ungrammatical', structureQuery: '(function_declaration) @function' })).rejects.toThrow(/exactly one of wasmPath and wasmBytes/);
+
+		// failed registrations don't register the language
+		await expect(_registerLanguage({ id: 'malformed', wasmPath: goWasmPath, structureQuery: '(function_declaration) @function' })).resolves.toBeUndefined();
+	});
+
+	test('the parser service resolves aliases of registered languages', async () => {
+		const parserService = new ParserServiceImpl(false);
+
+		const language = await parserService.registerLanguage({ id: 'go-fork', aliases: ['gofork'], wasmPath: goWasmPath, structureQuery: '(function_declaration) @function' });
+
+		expect(language).toBe('go-fork');
+		const ast = parserService.getTreeSitterAST({ languageId: 'gofork', getText: () => 'package main\n\nfunc main() {}\n' });
+		expect((await ast!.getStructure())!.children.map(n => n.kind)).toEqual(['function_declaration']);
+		parserService.dispose();
+	});
+
+	test('the parser service resolves the extensions of registered languages', async () => {
+		const parserService = new ParserServiceImpl(false);
+
+		const language = await parserService.registerLanguage({ id: 'go-dsl', extensions: ['.godsl', 'gdsl'], wasmPath: goWasmPath, structureQuery: '(function_declaration) @function' });
+
+		expect(parserService.resolveLanguage('rules.godsl', '')).toBe(language);
+		expect(parserService.resolveLanguage('src/RULES.GDSL', '')).toBe(language);
+		expect(parserService.resolveLanguage('main.go', '')).toBe(WASMLanguage.Go);
+		parserService.dispose();
+	});
+});
@@ -0,0 +1,76 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { ParserServiceImpl } from '../../node/parserServiceImpl';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+
+suite('resolveLanguage', () => {
+
+	const parserService = new ParserServiceImpl(false);
+
+	afterAll(() => parserService.dispose());
+
+	test('a registered extension takes precedence over the built-in extensions', () => {
+		const registration = parserService.registerExtensionLanguage('.ts', WASMLanguage.JavaScript);
+
+		expect(parserService.resolveLanguage('src/app.TS', '')).toBe(WASMLanguage.JavaScript);
+		expect(parserService.resolveLanguage('src/app.tsx', '')).toBe(WASMLanguage.TypeScriptTsx);
+
+		registration.dispose();
+		expect(parserService.resolveLanguage('src/app.ts', '')).toBe(WASMLanguage.TypeScript);
+	});
+
+	test('the longest registered extension matches', () => {
+		const registrations = [
+			parserService.registerExtensionLanguage('tmpl', WASMLanguage.Python),
+			parserService.registerExtensionLanguage('.go.tmpl', WASMLanguage.Go),
+		];
+
+		expect(parserService.resolveLanguage('templates\\main.go.tmpl', '')).toBe(WASMLanguage.Go);
+		expect(parserService.resolveLanguage('templates/setup.tmpl', '')).toBe(WASMLanguage.Python);
+		// a file named like the extension has none
+		expect(parserService.resolveLanguage('templates/.go.tmpl', '')).toBe(WASMLanguage.Python);
+
+		registrations.forEach(r => r.dispose());
+		expect(parserService.resolveLanguage('templates/main.go.tmpl', '')).toBeUndefined();
+	});
+
+	test('an explicit language takes precedence over a registered extension', () => {
+		const registration = parserService.registerExtensionLanguage('.go.tmpl', WASMLanguage.Go);
+
+		expect(parserService.resolveLanguage('main.go.tmpl', '', WASMLanguage.Python)).toBe(WASMLanguage.Python);
+
+		registration.dispose();
+	});
+
+	test('registering an extension again replaces its language', () => {
+		const first = parserService.registerExtensionLanguage('.h', WASMLanguage.Go);
+		const second = parserService.registerExtensionLanguage('.H', WASMLanguage.Cpp);
+
+		expect(parserService.resolveLanguage('include/vector.h', '')).toBe(WASMLanguage.Cpp);
+
+		// the replaced registration doesn't remove the replacing one
+		first.dispose();
+		expect(parserService.resolveLanguage('include/vector.h', '')).toBe(WASMLanguage.Cpp);
+
+		second.dispose();
+		expect(parserService.resolveLanguage('include/vector.h', '')).toBe(WASMLanguage.C);
+	});
+
+	test('a document of a language without a grammar is parsed as the language its file resolves to', async () => {
+		const registration = parserService.registerExtensionLanguage('.go.tmpl', WASMLanguage.Go);
+		const document = { languageId: 'plaintext', uri: { path: '/repo/templates/main.go.tmpl' }, getText: () => 'package main\n\nfunc main() {}\n' };
+
+		const structure = await parserService.getTreeSitterAST(document)?.getStructure();
+		expect(structure?.children.map(n => n.kind)).toContain('function_declaration');
+		// the language of the document takes precedence, and one without a file isn't resolved
+		expect(parserService.getTreeSitterAST({ ...document, languageId: 'python' })).toBeDefined();
+		expect(parserService.getTreeSitterAST({ languageId: 'plaintext', getText: document.getText })).toBeUndefined();
+
+		registration.dispose();
+		expect(parserService.getTreeSitterAST(document)).toBeUndefined();
+	});
+});
@@ -0,0 +1,121 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test, vi } from 'vitest';
+import { _dispose, _getStructure, _runQuery } from '../../node/parserImpl';
+import { ParserServiceImpl } from '../../node/parserServiceImpl';
+import { TreeSitterQueryError } from '../../node/querying';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { syntacticallyValidAtoms } from '../../node/treeSitterQueries';
+import { fromFixture } from './getStructure.util';
+import Parser = require('web-tree-sitter');
+
+suite('runQuery', () => {
+
+	afterAll(() => _dispose());
+
+	const source = [
+		'// TODO: remove',
+		'export function foo() { return 1; }',
+		'function bar() { /* TODO: rename */ }',
+	].join('\n');
+
+	test('returns captures with names, ranges, and text', async () => {
+		const captures = await _runQuery(WASMLanguage.TypeScript, source, '(function_declaration name: (identifier) @name)');
+		expect(captures).toEqual([
+			{ name: 'name', text: 'foo', startIndex: 32, endIndex: 35 },
+			{ name: 'name', text: 'bar', startIndex: 61, endIndex: 64 },
+		]);
+		for (const capture of captures) {
+			expect(source.substring(capture.startIndex, capture.endIndex)).toBe(capture.text);
+		}
+	});
+
+	test('supports predicates', async () => {
+		const captures = await _runQuery(WASMLanguage.TypeScript, source, '((comment) @todo (#match? @todo "TODO"))');
+		expect(captures.map(c => c.text)).toEqual(['// TODO: remove', '/* TODO: rename */']);
+	});
+
+	test('compiles a query only once', async () => {
+		const querySpy = vi.spyOn(Parser.Language.prototype, 'query');
+		try {
+			const query = '(export_statement) @export';
+			await _runQuery(WASMLanguage.TypeScript, source, query);
+			await _runQuery(WASMLanguage.TypeScript, source, query);
+			expect(querySpy.mock.calls.filter(([q]) => q === query)).toHaveLength(1);
+		} finally {
+			querySpy.mockRestore();
+		}
+	});
+
+	test('the least recently used queries of a language are deleted once there are too many', async () => {
+		const query = Parser.Language.prototype.query;
+		const querySpy = vi.spyOn(Parser.Language.prototype, 'query').mockImplementation(function (this: Parser.Language, source: string) {
+			const compiled = query.call(this, source);
+			vi.spyOn(compiled, 'delete');
+			return compiled;
+		});
+		try {
+			// more than are kept per language
+			const queries = Array.from({ length: 150 }, (_, i) => `((identifier) @id (#eq? @id "v${i}"))`);
+			for (const q of queries) {
+				await _runQuery(WASMLanguage.TypeScript, source, q);
+			}
+			const compiled = querySpy.mock.results.map(r => r.value as Parser.Query);
+			expect(compiled[0].delete).toHaveBeenCalledTimes(1);
+			expect(compiled[compiled.length - 1].delete).not.toHaveBeenCalled();
+
+			// an evicted query is compiled again, a recently used one isn't
+			await _runQuery(WASMLanguage.TypeScript, source, queries[0]);
+			await _runQuery(WASMLanguage.TypeScript, source, queries[queries.length - 1]);
+			expect(querySpy.mock.calls.filter(([q]) => q === queries[0])).toHaveLength(2);
+			expect(querySpy.mock.calls.filter(([q]) => q === queries[queries.length - 1])).toHaveLength(1);
+		} finally {
+			querySpy.mockRestore();
+		}
+	});
+
+	test('the structure queries are kept however many queries are run', async () => {
+		const structureQueries = syntacticallyValidAtoms[WASMLanguage.TypeScript];
+		await _getStructure(WASMLanguage.TypeScript, 'const a = 1;');
+		const querySpy = vi.spyOn(Parser.Language.prototype, 'query');
+		try {
+			for (let i = 0; i < 150; i++) {
+				await _runQuery(WASMLanguage.TypeScript, source, `((identifier) @id (#eq? @id "w${i}"))`);
+			}
+
+			const structure = await _getStructure(WASMLanguage.TypeScript, 'function f() {}');
+
+			expect(structure!.children.map(n => n.kind)).toEqual(['function_declaration']);
+			expect(querySpy.mock.calls.filter(([q]) => structureQueries.includes(q))).toEqual([]);
+		} finally {
+			querySpy.mockRestore();
+		}
+	});
+
+	test('go - call expressions without a dedicated structure query', async () => {
+		const goSource = await fromFixture('test.go');
+		const captures = await _runQuery(WASMLanguage.Go, goSource, '(call_expression function: (selector_expression field: (field_identifier) @fn) (#eq? @fn "New"))');
+		expect(captures).toEqual([
+			{ name: 'fn', text: 'New', startIndex: goSource.indexOf('New('), endIndex: goSource.indexOf('New(') + 'New'.length },
+		]);
+	});
+
+	test('is available on the AST of a document', async () => {
+		const parserService = new ParserServiceImpl(/* useWorker */ false);
+		try {
+			const ast = parserService.getTreeSitterAST({ languageId: 'typescript', getText: () => source })!;
+			const captures = await ast.runQuery('(function_declaration name: (identifier) @name)');
+			expect(captures.map(c => c.text)).toEqual(['foo', 'bar']);
+		} finally {
+			parserService.dispose();
+		}
+	});
+
+	test('rejects malformed queries with a descriptive error', async () => {
+		await expect(_runQuery(WASMLanguage.TypeScript, source, '(function_declaration')).rejects.toThrow(TreeSitterQueryError);
+		await expect(_runQuery(WASMLanguage.TypeScript, source, '(not_a_node_type) @x')).rejects.toThrow(/Invalid tree-sitter query: .*not_a_node_type/);
+	});
+});
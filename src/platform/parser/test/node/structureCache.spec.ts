@@ -0,0 +1,76 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { CancellationToken } from '../../../../util/vs/base/common/cancellation';
+import { grammarVersionOf, GRAMMARS_VERSION } from '../../node/languageLoader';
+import { _dispose } from '../../node/parserImpl';
+import { contentKey } from '../../node/parserWithCaching';
+import { StructureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { vendoredGrammars } from '../../node/vendoredGrammars';
+
+suite('StructureComputer cache', () => {
+
+	afterAll(() => _dispose());
+
+	const source = 'package main\n\nfunc main() {}\n';
+
+	test('repeated requests for the same source are served from the cache', async () => {
+		const computer = new StructureComputer();
+
+		const first = await computer.getStructure(WASMLanguage.Go, source);
+		const second = await computer.getStructureAsync(WASMLanguage.Go, source, CancellationToken.None);
+		const third = await computer.getStructure(WASMLanguage.Go, source);
+
+		expect(second).toBe(first);
+		expect(third).toBe(first);
+		expect(computer.getCacheStats()).toEqual({ hits: 2, misses: 1, size: 1 });
+	});
+
+	test('different sources, languages, and options are cached separately', async () => {
+		const computer = new StructureComputer();
+
+		await computer.getStructure(WASMLanguage.Go, source);
+		await computer.getStructure(WASMLanguage.Go, source + '\n');
+		await computer.getStructure(WASMLanguage.Go, source, { maxDepth: 2 });
+		await computer.getStructure(WASMLanguage.TypeScript, 'function main() {}\n');
+
+		expect(computer.getCacheStats()).toEqual({ hits: 0, misses: 4, size: 4 });
+	});
+
+	test('evicts least recently used structures beyond the cache size', async () => {
+		const computer = new StructureComputer(2);
+
+		for (const name of ['a', 'b', 'c', 'a']) {
+			await computer.getStructure(WASMLanguage.Go, `package main\n\nfunc ${name}() {}\n`);
+		}
+
+		expect(computer.getCacheStats()).toEqual({ hits: 0, misses: 4, size: 2 });
+	});
+
+	test('cache size 0 disables caching', async () => {
+		const computer = new StructureComputer(0);
+
+		const first = await computer.getStructure(WASMLanguage.Go, source);
+		const second = await computer.getStructure(WASMLanguage.Go, source);
+
+		expect(second).not.toBe(first);
+		expect(second).toEqual(first);
+		expect(computer.getCacheStats()).toEqual({ hits: 0, misses: 2, size: 0 });
+	});
+
+	test('cache keys incorporate the grammars version', () => {
+		expect(GRAMMARS_VERSION).toMatch(/^\d+\.\d+\.\d+/);
+		expect(contentKey(WASMLanguage.Go, source)).toContain(`:@vscode/tree-sitter-wasm@${GRAMMARS_VERSION}:`);
+	});
+
+	test('cache keys of vendored grammars incorporate the versions of their packages', () => {
+		const swift = vendoredGrammars[WASMLanguage.Swift]!;
+		expect(grammarVersionOf(WASMLanguage.Swift)).toBe(`${swift.module}@${swift.version}`);
+		expect(contentKey(WASMLanguage.Swift, source)).toContain(`:${swift.module}@${swift.version}:`);
+		expect(contentKey(WASMLanguage.Swift, source)).not.toContain('@vscode/tree-sitter-wasm');
+	});
+});
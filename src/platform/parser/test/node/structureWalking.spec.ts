@@ -0,0 +1,120 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { OverlayNode } from '../../node/nodes';
+import { _dispose } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { collect, findFirst, flattenStructure, walk, walkContainers, WalkSignal } from '../../node/structureWalking';
+import { SymbolKind } from '../../node/symbolKinds';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { chunkContainerKinds } from '../../node/treeSitterQueries';
+import { fromFixture } from './getStructure.util';
+
+suite('structureWalking', () => {
+
+	afterAll(() => _dispose());
+
+	async function structureOf(fixture: string, nestGoMethods = true) {
+		const source = await fromFixture(fixture);
+		return (await structureComputer.getStructure(WASMLanguage.Go, source, { nestGoMethods }))!;
+	}
+
+	const isFunction = (n: OverlayNode) => n.symbolKind === SymbolKind.Function || n.symbolKind === SymbolKind.Method;
+
+	test('collect finds nested nodes in document order', async () => {
+		const structure = await structureOf('receivers.go');
+
+		expect(collect(structure, isFunction).map(n => n.name)).toEqual(['String', 'Move', 'Fahrenheit', 'Add', 'Push', 'Reset', 'Join']);
+	});
+
+	test('skip prevents visiting the children of a node', async () => {
+		const structure = await structureOf('receivers.go');
+
+		const visited: (string | undefined)[] = [];
+		const completed = walk(structure, node => {
+			if (isFunction(node)) {
+				visited.push(node.name);
+			}
+			if (node.kind === 'type_declaration' && node.name === 'Point') {
+				return WalkSignal.Skip;
+			}
+		});
+
+		expect(completed).toBe(true);
+		expect(visited).toEqual(['Fahrenheit', 'Add', 'Push', 'Reset', 'Join']);
+	});
+
+	test('stop ends the walk', async () => {
+		const structure = await structureOf('receivers.go');
+
+		const visited: OverlayNode[] = [];
+		const completed = walk(structure, node => {
+			visited.push(node);
+			return node.name === 'Point' ? WalkSignal.Stop : undefined;
+		});
+
+		expect(completed).toBe(false);
+		expect(visited.at(-1)!.name).toBe('Point');
+		expect(visited.filter(isFunction)).toEqual([]);
+	});
+
+	test('visitors are given the parents of nodes', async () => {
+		const structure = await structureOf('receivers.go');
+
+		const parents = new Map<OverlayNode, OverlayNode | undefined>();
+		walk(structure, (node, parent) => void parents.set(node, parent));
+
+		const move = findFirst(structure, n => n.name === 'Move')!;
+		expect(parents.get(move)?.name).toBe('Point');
+		expect(parents.get(structure)).toBeUndefined();
+	});
+
+	test('findFirst returns the node itself or undefined', async () => {
+		const structure = await structureOf('receivers.go');
+
+		expect(findFirst(structure, n => n === structure)).toBe(structure);
+		expect(findFirst(structure, n => n.name === 'Missing')).toBeUndefined();
+	});
+
+	test('walkContainers descends into containers only', async () => {
+		const structure = await structureOf('test.go');
+		const containerKinds = chunkContainerKinds[WASMLanguage.Go];
+
+		const parentKinds = new Set<string>();
+		let visited = 0;
+		walkContainers(structure, containerKinds, (_, parent) => {
+			parentKinds.add(parent?.kind ?? 'none');
+			visited++;
+		});
+
+		// e.g., the statements of `main` but not those within its `if` and `for` statements
+		expect([...parentKinds].every(kind => kind === 'none' || kind === 'root' || containerKinds.includes(kind))).toBe(true);
+		expect(parentKinds).toContain('function_declaration');
+		expect(visited).toBeLessThan(collect(structure, () => true).length);
+		expect(collect(structure, n => n.kind === 'if_statement')[0].children.length).toBeGreaterThan(0);
+	});
+
+	test('flattenStructure yields nodes in source order with their depths', async () => {
+		const structure = await structureOf('receivers.go', false);
+
+		const flattened = flattenStructure(structure.children);
+
+		expect(flattened.filter(e => e.name !== undefined).map(e => [e.depth, e.name])).toEqual([
+			[0, 'Point'], [0, 'Stack'], [1, 'items'], [0, 'String'], [0, 'Move'], [0, 'Fahrenheit'], [0, 'Add'], [0, 'Push'], [0, 'Reset'], [0, 'Join'],
+		]);
+		expect(flattened.every((e, i) => i === 0 || flattened[i - 1].range!.start.line <= e.range!.start.line)).toBe(true);
+		const move = flattened.find(e => e.name === 'Move')!;
+		expect(move).toMatchObject({ kind: 'method_declaration', signature: 'func (p *Point) Move(dx, dy int)' });
+	});
+
+	test('flattenStructure yields nested Go methods as members of their receiver types', async () => {
+		const structure = await structureOf('receivers.go');
+
+		expect(flattenStructure(structure.children).filter(e => e.name !== undefined).map(e => [e.depth, e.name])).toEqual([
+			[0, 'Point'], [1, 'String'], [1, 'Move'], [1, 'Fahrenheit'], [1, 'Add'], [0, 'Stack'], [1, 'items'], [1, 'Push'], [0, 'Reset'], [0, 'Join'],
+		]);
+	});
+});
@@ -0,0 +1,94 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterAll, expect, suite, test } from 'vitest';
+import { _dispose, _runQuery } from '../../node/parserImpl';
+import { structureComputer } from '../../node/structure';
+import { WASMLanguage } from '../../node/treeSitterLanguages';
+import { descendants, fromFixture } from './getStructure.util';
+
+function positionOf(source: string, offset: number) {
+	const lines = source.substring(0, offset).split('\n');
+	return { line: lines.length - 1, character: lines[lines.length - 1].length };
+}
+
+suite('offsets are in UTF-16 code units', () => {
+
+	afterAll(() => _dispose());
+
+	test('structure of a source with emoji, combining characters, and CJK text', async () => {
+
+		const source = await fromFixture('unicode.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const functions = descendants(structure!)
+			.filter(n => n.kind === 'function_declaration')
+			.map(n => ({ name: n.name, detail: n.detail, text: source.substring(n.startIndex, n.endIndex).trim() }));
+
+		expect(functions).toEqual([
+			{ name: 'Greeting', detail: 'func Greeting() string', text: 'func Greeting() string {\n\treturn "😀 hello"\n}' },
+			{ name: 'Cafe', detail: 'func Cafe() string', text: 'func Cafe() string {\n\treturn "café"\n}' },
+			{ name: '你好', detail: 'func 你好() string', text: 'func 你好() string {\n\treturn "世界"\n}' },
+			{ name: 'After', detail: 'func After() string', text: 'func After() string {\n\treturn "ascii"\n}' },
+		]);
+	});
+
+	test('line and character positions of structure nodes', async () => {
+
+		const source = await fromFixture('multibyteComment.go');
+
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+		const nodes = descendants(structure!);
+		for (const node of nodes) {
+			expect(node.range).toEqual({ start: positionOf(source, node.startIndex), end: positionOf(source, node.endIndex) });
+		}
+
+		// `/* 😀 emoji */` is 14 UTF-16 code units but 16 UTF-8 bytes long
+		const smile = nodes.find(n => n.name === 'Smile')!;
+		expect(smile.range!.start).toEqual({ line: 2, character: 14 });
+		expect(source.substring(smile.startIndex, smile.endIndex)).toBe(' var Smile = "😀"\n');
+		expect(smile.range!.end).toEqual({ line: 3, character: 0 });
+
+		const hello = nodes.find(n => n.name === 'Hello')!;
+		expect(hello.range).toEqual({ start: { line: 5, character: 0 }, end: { line: 5, character: 'func Hello() {}'.length } });
+	});
+
+	test('byte and line/character ranges of structure nodes', async () => {
+
+		for (const fixture of ['unicode.go', 'multibyteComment.go']) {
+			const source = await fromFixture(fixture);
+
+			const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+
+			for (const node of descendants(structure!)) {
+				expect(node.range).toEqual({ start: positionOf(source, node.startIndex), end: positionOf(source, node.endIndex) });
+				expect(node.byteRange).toEqual({
+					start: Buffer.byteLength(source.substring(0, node.startIndex)),
+					end: Buffer.byteLength(source.substring(0, node.endIndex)),
+				});
+			}
+		}
+
+		// `/* 😀 emoji */` is 14 UTF-16 code units but 16 UTF-8 bytes long
+		const source = await fromFixture('multibyteComment.go');
+		const structure = await structureComputer.getStructure(WASMLanguage.Go, source);
+		const smile = descendants(structure!).find(n => n.name === 'Smile')!;
+		expect(smile.byteRange!.start - Buffer.byteLength(source.substring(0, source.indexOf('/* 😀')))).toBe(16);
+	});
+
+	test('query captures', async () => {
+
+		const source = 'const a = "😀"; const b = "é"; const c = "世界"; const d = "ascii";';
+
+		const captures = await _runQuery(WASMLanguage.TypeScript, source, '(string) @string');
+
+		expect(captures.map(c => c.text)).toEqual(['"😀"', '"é"', '"世界"', '"ascii"']);
+		for (const capture of captures) {
+			expect(source.substring(capture.startIndex, capture.endIndex)).toBe(capture.text);
+		}
+	});
+});
@@ -380,6 +380,18 @@ const languages = Object.freeze({
 	},
 	'kotlin': {
 		lineComment: { start: '//' },
+		aliases: [
+			'Kotlin',
+			'kotlin'
+		],
+		extensions: [
+			'.kt',
+			'.kts'
+		],
+		blockComment: [
+			'/*',
+			'*/'
+		],
 		markdownLanguageIds: ['kotlin', 'kt']
 	},
 	'latex': {
@@ -0,0 +1,93 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import { afterEach, expect, suite, test } from 'vitest';
+import { WorkerWithRpcProxy } from '../worker';
+import { WorkerPool } from '../workerPool';
+
+type BusyWorker = {
+	spin(iterations: number): number;
+	fail(message: string): never;
+	crash(): never;
+};
+
+// answers RPC requests like a worker of `WorkerWithRpcProxy` would
+const busyWorkerSource = `
+const { parentPort } = require('worker_threads');
+const api = {
+	spin(iterations) {
+		let x = 0;
+		for (let i = 0; i < iterations; i++) {
+			x = (x + i * i) % 1000003;
+		}
+		return x;
+	},
+	fail(message) {
+		throw new Error(message);
+	},
+	crash() {
+		process.exit(1);
+	},
+};
+parentPort.on('message', ({ id, fn, args }) => {
+	try {
+		parentPort.postMessage({ id, res: api[fn](...args) });
+	} catch (err) {
+		parentPort.postMessage({ id, err });
+	}
+});
+`;
+
+suite('WorkerPool', () => {
+
+	const pools: WorkerPool<BusyWorker>[] = [];
+	let createdWorkers = 0;
+
+	afterEach(() => {
+		pools.forEach(p => p.dispose());
+		pools.length = 0;
+		createdWorkers = 0;
+	});
+
+	function createPool(size: number) {
+		const pool = new WorkerPool<BusyWorker>(() => {
+			createdWorkers++;
+			return new WorkerWithRpcProxy<BusyWorker>(busyWorkerSource, { eval: true });
+		}, size);
+		pools.push(pool);
+		return pool;
+	}
+
+	test('results are in the order of the items', async () => {
+		const pool = createPool(3);
+
+		const results = await pool.map([5_000_000, 10, 1_000, 10], (proxy, n) => proxy.spin(n));
+
+		expect(results.map(r => r.status)).toEqual(['fulfilled', 'fulfilled', 'fulfilled', 'fulfilled']);
+		expect(results[1]).toEqual(results[3]);
+	});
+
+	test('a failing item does not fail the others', async () => {
+		const pool = createPool(2);
+
+		const results = await pool.map(['a', 'fail', 'b', 'c'], (proxy, item) => item === 'fail' ? proxy.fail('bad item') : proxy.spin(10));
+
+		expect(results.map(r => r.status)).toEqual(['fulfilled', 'rejected', 'fulfilled', 'fulfilled']);
+		expect((results[1] as PromiseRejectedResult).reason.message).toBe('bad item');
+	});
+
+	test('rejects pools without workers', () => {
+		expect(() => createPool(0)).toThrow();
+	});
+
+	test('a worker is kept after a failed call and replaced after it crashed', async () => {
+		const pool = createPool(1);
+
+		const results = await pool.map(['fail', 'a', 'crash', 'b'], (proxy, item) => item === 'fail' ? proxy.fail('bad item') : item === 'crash' ? proxy.crash() : proxy.spin(10));
+
+		expect(results.map(r => r.status)).toEqual(['rejected', 'fulfilled', 'rejected', 'fulfilled']);
+		expect(createdWorkers).toBe(2);
+	});
+});
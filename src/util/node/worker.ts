@@ -79,9 +79,17 @@ export function createRpcProxy<ProxyType>(remoteCall: (name: string, args: any[]
 export class WorkerWithRpcProxy<WorkerProxyType, HostProxyType = {}> {
 	private readonly worker: Worker;
 	private readonly responseHandler = new RcpResponseHandler();
+	private _hasFailed = false;
 
 	public readonly proxy: RpcProxy<WorkerProxyType>;
 
+	/**
+	 * Whether the worker thread threw an uncaught error or exited with a non-zero code, which failed its pending calls.
+	 */
+	public get hasFailed(): boolean {
+		return this._hasFailed;
+	}
+
 	constructor(workerPath: string, workerOptions?: WorkerOptions, host?: HostProxyType) {
 		this.worker = new Worker(workerPath, workerOptions);
 		this.worker.on('message', async (msg: RpcRequest | RpcResponse) => {
@@ -125,6 +133,7 @@ export class WorkerWithRpcProxy<WorkerProxyType, HostProxyType = {}> {
 	 * Handle an unexpected error by logging it and rejecting all handlers.
 	 */
 	private handleError(err: Error) {
+		this._hasFailed = true;
 		this.responseHandler.handleError(err);
 	}
 }
@@ -0,0 +1,78 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) Microsoft Corporation. All rights reserved.
+ *  Licensed under the MIT License. See License.txt in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+import * as os from 'os';
+import { IDisposable } from '../vs/base/common/lifecycle';
+import { RpcProxy, WorkerWithRpcProxy } from './worker';
+
+/**
+ * @returns half of the CPUs, so that the extension host and the rest of the machine stay responsive
+ */
+export function defaultWorkerCount(): number {
+	return Math.max(1, Math.floor(os.cpus().length / 2));
+}
+
+/**
+ * Fixed-size pool of workers that processes items in parallel. Workers are created on demand and kept until the pool is disposed.
+ */
+export class WorkerPool<WorkerProxyType> implements IDisposable {
+
+	private readonly _workers: (WorkerWithRpcProxy<WorkerProxyType> | undefined)[] = [];
+
+	constructor(
+		private readonly _createWorker: (index: number) => WorkerWithRpcProxy<WorkerProxyType>,
+		readonly size = defaultWorkerCount(),
+	) {
+		if (size < 1) {
+			throw new Error(`Worker pool needs at least one worker, got ${size}`);
+		}
+	}
+
+	/**
+	 * Calls `fn` for each item with the proxy of an idle worker.
+	 *
+	 * A failed call doesn't fail the other items; its worker is replaced only if it crashed, see {@link WorkerWithRpcProxy.hasFailed}.
+	 *
+	 * @returns the outcomes in the order of `items`
+	 */
+	async map<T, R>(items: readonly T[], fn: (proxy: RpcProxy<WorkerProxyType>, item: T) => Promise<R>): Promise<PromiseSettledResult<R>[]> {
+		const results = new Array<PromiseSettledResult<R>>(items.length);
+		let next = 0;
+		const runWorker = async (index: number) => {
+			while (next < items.length) {
+				const i = next++;
+				try {
+					results[i] = { status: 'fulfilled', value: await fn(this._worker(index).proxy, items[i]) };
+				} catch (reason) {
+					results[i] = { status: 'rejected', reason };
+					const worker = this._workers[index];
+					if (worker?.hasFailed) {
+						worker.terminate();
+						this._workers[index] = undefined;
+					}
+				}
+			}
+		};
+		const workerCount = Math.min(this.size, items.length);
+		await Promise.all(Array.from({ length: workerCount }, (_, index) => runWorker(index)));
+		return results;
+	}
+
+	private _worker(index: number): WorkerWithRpcProxy<WorkerProxyType> {
+		let worker = this._workers[index];
+		if (!worker) {
+			worker = this._createWorker(index);
+			this._workers[index] = worker;
+		}
+		return worker;
+	}
+
+	dispose(): void {
+		for (const worker of this._workers) {
+			worker?.terminate();
+		}
+		this._workers.length = 0;
+	}
+}
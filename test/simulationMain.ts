@@ -204,8 +204,8 @@ async function prepareTestEnvironment(opts: SimulationOptions, jsonOutputPrinter
 	// Configure caching
 	if (opts.parallelism > 1) {
 		// To get good cache behavior, we must increase the cache size considerably
-		ParserWithCaching.CACHE_SIZE_PER_LANGUAGE = Math.max(5, 2 * opts.parallelism);
-		structureComputer.setCacheSize(Math.max(5, 2 * opts.parallelism));
+		ParserWithCaching.CACHE_SIZE = Math.max(50, 2 * opts.parallelism);
+		structureComputer.setCacheSize(Math.max(50, 2 * opts.parallelism));
 	}
 	fileSystemServiceReadAsJSON.enable();
 